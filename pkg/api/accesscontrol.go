@@ -28,6 +28,7 @@ var (
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
 	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersAccessControl = ac.Scope("provisioners", "accesscontrol")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
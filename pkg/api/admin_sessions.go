@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/auth"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /admin/sessions admin_users adminSearchSessions
+//
+// Search the active sessions of all users.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users.authtoken:read` and scope `global.users:*`.
+//
+// Responses:
+// 200: adminSearchSessionsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminSearchSessions(c *contextmodel.ReqContext) response.Response {
+	perPage := c.QueryInt("perpage")
+	if perPage <= 0 || perPage > 1000 {
+		perPage = 1000
+	}
+	page := c.QueryInt("page")
+	if page < 1 {
+		page = 1
+	}
+
+	query := &auth.SearchSessionsQuery{
+		UserID:    c.QueryInt64("userId"),
+		ClientIP:  c.Query("ip"),
+		UserAgent: c.Query("userAgent"),
+		Page:      page,
+		Limit:     perPage,
+	}
+
+	var err error
+	if query.SeenAfter, err = parseOptionalTime(c.Query("seenAfter")); err != nil {
+		return response.Error(http.StatusBadRequest, "seenAfter must be an RFC 3339 timestamp", err)
+	}
+	if query.SeenBefore, err = parseOptionalTime(c.Query("seenBefore")); err != nil {
+		return response.Error(http.StatusBadRequest, "seenBefore must be an RFC 3339 timestamp", err)
+	}
+
+	result, err := hs.AuthTokenService.SearchSessions(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search sessions", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /admin/sessions/revoke admin_users adminRevokeSessions
+//
+// Revoke sessions in bulk.
+//
+// Revokes the sessions of a user, of the users who logged in with an auth module, or the sessions issued before a time. Criteria are combined, and the session of the caller is kept.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users.authtoken:write` and scope `global.users:*`.
+//
+// Responses:
+// 200: adminRevokeSessionsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminRevokeSessions(c *contextmodel.ReqContext) response.Response {
+	cmd := auth.RevokeSessionsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if c.UserToken != nil {
+		cmd.ExceptTokenID = c.UserToken.Id
+	}
+
+	count, err := hs.AuthTokenService.RevokeSessions(c.Req.Context(), &cmd)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoRevokeCriteria) {
+			return response.Error(http.StatusBadRequest, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to revoke sessions", err)
+	}
+
+	return response.JSON(http.StatusOK, RevokeSessionsResponse{Message: "Sessions revoked", Count: count})
+}
+
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+type RevokeSessionsResponse struct {
+	Message string `json:"message"`
+	Count   int64  `json:"count"`
+}
+
+// swagger:parameters adminSearchSessions
+type AdminSearchSessionsParams struct {
+	// in:query
+	// required:false
+	UserID int64 `json:"userId"`
+	// IP address the sessions were last used from.
+	// in:query
+	// required:false
+	IP string `json:"ip"`
+	// Matches the sessions whose user agent contains it.
+	// in:query
+	// required:false
+	UserAgent string `json:"userAgent"`
+	// in:query
+	// required:false
+	SeenAfter string `json:"seenAfter"`
+	// in:query
+	// required:false
+	SeenBefore string `json:"seenBefore"`
+	// in:query
+	// required:false
+	// default:1000
+	PerPage int `json:"perpage"`
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:parameters adminRevokeSessions
+type AdminRevokeSessionsParams struct {
+	// in:body
+	// required:true
+	Body auth.RevokeSessionsCommand `json:"body"`
+}
+
+// swagger:response adminSearchSessionsResponse
+type AdminSearchSessionsResponse struct {
+	// in:body
+	Body *auth.SearchSessionsResult `json:"body"`
+}
+
+// swagger:response adminRevokeSessionsResponse
+type AdminRevokeSessionsResponse struct {
+	// in:body
+	Body RevokeSessionsResponse `json:"body"`
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_AdminSearchSessions(t *testing.T) {
+	var query *auth.SearchSessionsQuery
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.AuthTokenService = &authtest.FakeUserAuthTokenService{
+			SearchSessionsProvider: func(ctx context.Context, q *auth.SearchSessionsQuery) (*auth.SearchSessionsResult, error) {
+				query = q
+				return &auth.SearchSessionsResult{Sessions: []*auth.Session{}}, nil
+			},
+		}
+	})
+
+	t.Run("should search with the filters", func(t *testing.T) {
+		req := server.NewGetRequest("/api/admin/sessions?userId=2&ip=10.0.0.1&userAgent=curl&seenBefore=2024-01-02T15:04:05Z&perpage=10&page=2")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionUsersAuthTokenList, Scope: accesscontrol.ScopeGlobalUsersAll},
+		})))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		require.NotNil(t, query)
+		assert.EqualValues(t, 2, query.UserID)
+		assert.Equal(t, "10.0.0.1", query.ClientIP)
+		assert.Equal(t, "curl", query.UserAgent)
+		assert.Equal(t, int64(1704207845), query.SeenBefore.Unix())
+		assert.True(t, query.SeenAfter.IsZero())
+		assert.Equal(t, 10, query.Limit)
+		assert.Equal(t, 2, query.Page)
+	})
+
+	t.Run("should reject invalid timestamps", func(t *testing.T) {
+		req := server.NewGetRequest("/api/admin/sessions?seenAfter=yesterday")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionUsersAuthTokenList, Scope: accesscontrol.ScopeGlobalUsersAll},
+		})))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should require permissions on all users", func(t *testing.T) {
+		req := server.NewGetRequest("/api/admin/sessions")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionUsersAuthTokenList, Scope: "global.users:id:2"},
+		})))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func TestAPI_AdminRevokeSessions(t *testing.T) {
+	type testCase struct {
+		desc         string
+		body         string
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should revoke the sessions of an auth module",
+			body:         `{"authModule": "oauth_generic_oauth"}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should require a criteria",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.AuthTokenService = &authtest.FakeUserAuthTokenService{
+					RevokeSessionsProvider: func(ctx context.Context, cmd *auth.RevokeSessionsCommand) (int64, error) {
+						if cmd.UserID == 0 && cmd.AuthModule == "" && cmd.IssuedBefore.IsZero() {
+							return 0, auth.ErrNoRevokeCriteria
+						}
+						return 3, nil
+					},
+				}
+			})
+
+			req := server.NewRequest(http.MethodPost, "/api/admin/sessions/revoke", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+				{Action: accesscontrol.ActionUsersAuthTokenUpdate, Scope: accesscontrol.ScopeGlobalUsersAll},
+			})))
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+		})
+	}
+}
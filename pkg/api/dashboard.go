@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
 	pref "github.com/grafana/grafana/pkg/services/preference"
+	provisioningdashboards "github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -422,6 +423,15 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		allowUiUpdate = hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
 	}
 
+	// Dashboards in folders synced with git are rejected before they are saved if they conflict with the repository,
+	// the changes would be overwritten by the next pull otherwise.
+	if err := hs.ProvisioningService.CheckDashboardGitConflict(ctx, dash); err != nil {
+		if errors.Is(err, provisioningdashboards.ErrGitSyncConflict) {
+			return response.Error(http.StatusConflict, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to check dashboard against git", err)
+	}
+
 	dashItem := &dashboards.SaveDashboardDTO{
 		Dashboard: dash,
 		Message:   cmd.Message,
@@ -465,6 +475,13 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
 	}
 
+	// Dashboards in folders synced with git are committed only once they are saved, so that the repository only
+	// receives changes that passed the permission, version and validation checks. The dashboard is saved at this
+	// point, so errors, including conflicts with changes made since the check above, are not reported as conflicts.
+	if err := hs.ProvisioningService.SaveDashboardToGit(ctx, dashboard, c.SignedInUser, cmd.Message); err != nil {
+		return response.Error(http.StatusInternalServerError, "Dashboard was saved but could not be committed to git", err)
+	}
+
 	// Clear permission cache for the user who's created the dashboard, so that new permissions are fetched for their next call
 	// Required for cases when caller wants to immediately interact with the newly created object
 	if newDashboard {
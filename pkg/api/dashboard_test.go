@@ -50,6 +50,7 @@ import (
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	provisioningdashboards "github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/api"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
@@ -923,6 +924,92 @@ func postDashboardScenario(t *testing.T, desc string, url string, routePattern s
 	})
 }
 
+func TestPostDashboardCommitsToGitAfterSave(t *testing.T) {
+	cmd := dashboards.SaveDashboardCommand{
+		OrgID: 1,
+		Dashboard: simplejson.NewFromAny(map[string]any{
+			"title": "Dash",
+		}),
+	}
+
+	setup := func(t *testing.T, dashboardService dashboards.DashboardService) (*scenarioContext, *provisioning.ProvisioningServiceMock) {
+		provisioningService := provisioning.NewProvisioningServiceMock(context.Background())
+		hs := HTTPServer{
+			Cfg:                  setting.NewCfg(),
+			ProvisioningService:  provisioningService,
+			QuotaService:         quotatest.New(false, nil),
+			pluginStore:          &pluginstore.FakePluginStore{},
+			LibraryPanelService:  &mockLibraryPanelService{},
+			DashboardService:     dashboardService,
+			Features:             featuremgmt.WithFeatures(),
+			accesscontrolService: actest.FakeService{},
+			log:                  log.New("test-logger"),
+		}
+
+		sc := setupScenarioContext(t, "/api/dashboards")
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			sc.context = c
+			sc.context.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID}
+			return hs.PostDashboard(c)
+		})
+		sc.m.Post("/api/dashboards", sc.defaultHandler)
+		return sc, provisioningService
+	}
+
+	t.Run("should not commit a dashboard that could not be saved", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(nil, dashboards.ErrDashboardUpdateAccessDenied)
+		sc, provisioningService := setup(t, dashboardService)
+
+		callPostDashboard(sc)
+		assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+		assert.Empty(t, provisioningService.Calls.SaveDashboardToGit)
+	})
+
+	t.Run("should commit the saved dashboard", func(t *testing.T) {
+		saved := &dashboards.Dashboard{ID: 2, UID: "uid", Title: "Dash", Slug: "dash", Version: 3}
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(saved, nil)
+		sc, provisioningService := setup(t, dashboardService)
+
+		callPostDashboard(sc)
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+		require.Len(t, provisioningService.Calls.SaveDashboardToGit, 1)
+		assert.Same(t, saved, provisioningService.Calls.SaveDashboardToGit[0])
+	})
+
+	t.Run("should not save a dashboard that conflicts with git", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		sc, provisioningService := setup(t, dashboardService)
+		provisioningService.CheckDashboardGitConflictFunc = func(context.Context, *dashboards.Dashboard) error {
+			return fmt.Errorf("failed to commit dashboard to git: %w", provisioningdashboards.ErrGitSyncConflict)
+		}
+
+		callPostDashboard(sc)
+		assert.Equal(t, http.StatusConflict, sc.resp.Code)
+		dashboardService.AssertNotCalled(t, "SaveDashboard", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, provisioningService.Calls.SaveDashboardToGit)
+	})
+
+	t.Run("should not report a conflict for a saved dashboard", func(t *testing.T) {
+		saved := &dashboards.Dashboard{ID: 2, UID: "uid", Title: "Dash", Slug: "dash", Version: 3}
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(saved, nil)
+		sc, provisioningService := setup(t, dashboardService)
+		provisioningService.SaveDashboardToGitFunc = func(context.Context, *dashboards.Dashboard, identity.Requester, string) error {
+			return provisioningdashboards.ErrGitSyncConflict
+		}
+
+		callPostDashboard(sc)
+		assert.Equal(t, http.StatusInternalServerError, sc.resp.Code)
+	})
+}
+
 func postDiffScenario(t *testing.T, desc string, url string, routePattern string, cmd dtos.CalculateDiffOptions,
 	role org.RoleType, fn scenarioFunc, sqlmock db.DB, fakeDashboardVersionService *dashvertest.FakeDashboardVersionService) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
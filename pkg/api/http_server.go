@@ -599,6 +599,9 @@ func (hs *HTTPServer) configureHttps() error {
 		MinVersion:   minTlsVersion,
 		CipherSuites: tlsCiphers,
 	}
+	if err := hs.configureClientCertificates(tlsCfg); err != nil {
+		return err
+	}
 
 	hs.httpSrv.TLSConfig = tlsCfg
 	hs.httpSrv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
@@ -628,12 +631,43 @@ func (hs *HTTPServer) configureHttp2() error {
 		CipherSuites: tlsCiphers,
 		NextProtos:   []string{"h2", "http/1.1"},
 	}
+	if err := hs.configureClientCertificates(tlsCfg); err != nil {
+		return err
+	}
 
 	hs.httpSrv.TLSConfig = tlsCfg
 
 	return nil
 }
 
+// configureClientCertificates makes the server ask for client certificates when mutual TLS authentication is enabled.
+// Certificates are optional at the TLS level so other authentication methods keep working; the ones that are presented
+// must be signed by the configured CA.
+func (hs *HTTPServer) configureClientCertificates(tlsCfg *tls.Config) error {
+	if !hs.Cfg.MTLSAuthEnabled {
+		return nil
+	}
+
+	if hs.Cfg.MTLSAuthCACertFile == "" {
+		return errors.New("mutual TLS authentication requires a ca_cert_file")
+	}
+
+	// nolint:gosec
+	caCert, err := os.ReadFile(hs.Cfg.MTLSAuthCACertFile)
+	if err != nil {
+		return fmt.Errorf("could not read mutual TLS CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return fmt.Errorf("no valid certificates found in %q", hs.Cfg.MTLSAuthCACertFile)
+	}
+
+	tlsCfg.ClientCAs = pool
+	tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return nil
+}
+
 func (hs *HTTPServer) applyRoutes() {
 	// start with middlewares & static routes
 	hs.addMiddlewaresAndStaticRoutes()
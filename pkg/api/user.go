@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login"
@@ -513,6 +514,16 @@ func (hs *HTTPServer) ChangeUserPassword(c *contextmodel.ReqContext) response.Re
 		return response.Error(http.StatusInternalServerError, "Failed to change user password", err)
 	}
 
+	// log the user out of their other devices, which may be used by whoever knew the previous password
+	revokeCmd := auth.RevokeSessionsCommand{UserID: userID}
+	if c.UserToken != nil {
+		revokeCmd.ExceptTokenID = c.UserToken.Id
+	}
+	if _, err := hs.AuthTokenService.RevokeSessions(c.Req.Context(), &revokeCmd); err != nil {
+		return response.Error(http.StatusExpectationFailed,
+			"User password changed but unable to revoke user sessions", err)
+	}
+
 	return response.Success("User password changed")
 }
 
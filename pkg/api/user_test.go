@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/login/social/socialtest"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -37,6 +39,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user/userimpl"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestUserAPIEndpoint_userLoggedIn(t *testing.T) {
@@ -444,3 +447,30 @@ func updateSignedInUserScenario(t *testing.T, ctx updateUserContext, hs *HTTPSer
 		ctx.fn(sc)
 	})
 }
+
+func TestAPI_SignedInUser_ExchangedToken(t *testing.T) {
+	server := SetupAPITestServer(t)
+
+	// identities of exchanged tokens only have the permissions of the token, which aren't checked by these routes
+	usr := userWithPermissions(1, []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:uid:1"}})
+	usr.AuthenticatedBy = login.TokenExchangeModule
+
+	for _, tt := range []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{method: http.MethodPut, url: "/api/user", body: `{"login": "admin", "email": "admin@example.com"}`},
+		{method: http.MethodPost, url: "/api/user/using/2", body: `{}`},
+		{method: http.MethodPut, url: "/api/user/password", body: `{"oldPassword": "old", "newPassword": "newpassword"}`},
+		{method: http.MethodPut, url: "/api/user/preferences", body: `{"theme": "light"}`},
+	} {
+		t.Run(fmt.Sprintf("should return 403 for %s %s", tt.method, tt.url), func(t *testing.T) {
+			req := server.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, usr))
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		})
+	}
+}
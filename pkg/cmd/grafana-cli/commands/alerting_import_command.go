@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const prometheusRulesImportPath = "/api/ruler/grafana/api/v1/import/prometheus"
+
+var errMissingRuleFiles = errors.New("missing rule files, usage: import-prometheus-rules <rule file>...")
+
+// importPrometheusRulesCommand reads Prometheus rule files and imports their rule groups to a Grafana server
+// as Grafana-managed rule groups.
+func importPrometheusRulesCommand(c utils.CommandLine) error {
+	if c.Args().Len() == 0 {
+		return errMissingRuleFiles
+	}
+	folderUID, datasourceUID := c.String("folder-uid"), c.String("datasource-uid")
+	if folderUID == "" || datasourceUID == "" {
+		return errors.New("--folder-uid and --datasource-uid are required")
+	}
+
+	groups, err := readPrometheusRuleFiles(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	result, err := importPrometheusRules(c.String("url"), c.String("token"), folderUID, datasourceUID, c.Bool("dry-run"), groups)
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		logger.Info("Dry run, no rules were saved\n")
+	}
+	for _, group := range result.Groups {
+		logger.Infof("Group %s: %d created, %d updated, %d deleted\n", group.Name, len(group.Created), len(group.Updated), len(group.Deleted))
+	}
+	if len(result.SkippedRecordingRules) > 0 {
+		logger.Infof("Skipped recording rules: %s\n", strings.Join(result.SkippedRecordingRules, ", "))
+	}
+	return nil
+}
+
+// readPrometheusRuleFiles reads the rule groups of all the files.
+func readPrometheusRuleFiles(paths []string) (apimodels.PrometheusRuleGroups, error) {
+	groups := apimodels.PrometheusRuleGroups{}
+	for _, path := range paths {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning since the path is passed by the user running the command.
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return groups, fmt.Errorf("failed to read rule file %s: %w", path, err)
+		}
+		file := apimodels.PrometheusRuleGroups{}
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return groups, fmt.Errorf("failed to parse rule file %s: %w", path, err)
+		}
+		groups.Groups = append(groups.Groups, file.Groups...)
+	}
+	return groups, nil
+}
+
+func importPrometheusRules(serverURL, token, folderUID, datasourceUID string, dryRun bool, groups apimodels.PrometheusRuleGroups) (*apimodels.PrometheusRulesImportResult, error) {
+	u, err := url.Parse(strings.TrimSuffix(serverURL, "/") + prometheusRulesImportPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	u.RawQuery = url.Values{
+		"folder_uid":     {folderUID},
+		"datasource_uid": {datasourceUID},
+		"dry_run":        {strconv.FormatBool(dryRun)},
+	}.Encode()
+
+	body, err := json.Marshal(groups)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the rules to %s: %w", serverURL, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("failed to import the rules, status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	result := &apimodels.PrometheusRulesImportResult{}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return nil, fmt.Errorf("failed to parse the response: %w", err)
+	}
+	return result, nil
+}
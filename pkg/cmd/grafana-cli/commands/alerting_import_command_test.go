@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/commandstest"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const prometheusRuleFile = `
+groups:
+  - name: node
+    interval: 30s
+    rules:
+      - record: instance:node_cpu:rate5m
+        expr: rate(node_cpu_seconds_total[5m])
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: critical
+        annotations:
+          summary: "{{ $labels.instance }} is down"
+`
+
+func TestImportPrometheusRulesCommand(t *testing.T) {
+	var received apimodels.PrometheusRuleGroups
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, prometheusRulesImportPath, r.URL.Path)
+		assert.Equal(t, "folder", r.URL.Query().Get("folder_uid"))
+		assert.Equal(t, "mimir", r.URL.Query().Get("datasource_uid"))
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(apimodels.PrometheusRulesImportResult{
+			Groups: []apimodels.PrometheusRuleGroupImportResult{{Name: "node", Created: []string{"InstanceDown"}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(prometheusRuleFile), 0600))
+
+	t.Run("should require rule files", func(t *testing.T) {
+		c, err := commandstest.NewCliContext(map[string]string{"folder-uid": "folder", "datasource-uid": "mimir"})
+		require.NoError(t, err)
+		require.ErrorIs(t, importPrometheusRulesCommand(c), errMissingRuleFiles)
+	})
+
+	t.Run("should parse the rule files", func(t *testing.T) {
+		groups, err := readPrometheusRuleFiles([]string{path})
+		require.NoError(t, err)
+		require.Len(t, groups.Groups, 1)
+		group := groups.Groups[0]
+		assert.Equal(t, "node", group.Name)
+		assert.Equal(t, 30*time.Second, time.Duration(group.Interval))
+		require.Len(t, group.Rules, 2)
+		assert.Equal(t, "InstanceDown", group.Rules[1].Alert)
+		assert.Equal(t, 5*time.Minute, time.Duration(group.Rules[1].For))
+	})
+
+	t.Run("should send the rule groups to the server", func(t *testing.T) {
+		groups, err := readPrometheusRuleFiles([]string{path})
+		require.NoError(t, err)
+
+		result, err := importPrometheusRules(server.URL+"/", "secret", "folder", "mimir", false, groups)
+		require.NoError(t, err)
+		assert.Equal(t, groups, received)
+		require.Len(t, result.Groups, 1)
+		assert.Equal(t, []string{"InstanceDown"}, result.Groups[0].Created)
+	})
+
+	t.Run("should return the error of the server", func(t *testing.T) {
+		_, err := importPrometheusRules(server.URL, "", "folder", "mimir", false, apimodels.PrometheusRuleGroups{})
+		require.Error(t, err)
+	})
+}
@@ -225,6 +225,40 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "import-prometheus-rules",
+		Usage:  "import-prometheus-rules <rule file>... imports Prometheus rule files as Grafana-managed alert rules",
+		Action: runPluginCommand(importPrometheusRulesCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "The URL of the Grafana server",
+				Value: "http://localhost:3000",
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "A service account token used to authenticate to the Grafana server",
+				EnvVars: []string{"GRAFANA_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:     "folder-uid",
+				Usage:    "The UID of the folder to import the rule groups into",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "datasource-uid",
+				Usage:    "The UID of the Prometheus data source queried by the imported rules",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Convert and validate the rules without saving them",
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -236,4 +270,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana Alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
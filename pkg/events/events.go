@@ -25,6 +25,7 @@ type UserCreated struct {
 	Name      string    `json:"name"`
 	Login     string    `json:"login"`
 	Email     string    `json:"email"`
+	OrgID     int64     `json:"org_id"`
 }
 
 type SignUpStarted struct {
@@ -45,6 +46,20 @@ type UserUpdated struct {
 	Name      string    `json:"name"`
 	Login     string    `json:"login"`
 	Email     string    `json:"email"`
+	OrgID     int64     `json:"org_id"`
+	// OrgIDs are the organizations the user is a member of.
+	OrgIDs []int64 `json:"-"`
+}
+
+type UserDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	Id        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Login     string    `json:"login"`
+	Email     string    `json:"email"`
+	OrgID     int64     `json:"org_id"`
+	// OrgIDs are the organizations the user was a member of.
+	OrgIDs []int64 `json:"-"`
 }
 
 type DataSourceDeleted struct {
@@ -71,6 +86,14 @@ type DataSourceCreated struct {
 	OrgID     int64     `json:"org_id"`
 }
 
+type DataSourceUpdated struct {
+	Timestamp time.Time `json:"timestamp"`
+	Name      string    `json:"name"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+}
+
 type FolderTitleUpdated struct {
 	Timestamp time.Time `json:"timestamp"`
 	Title     string    `json:"name"`
@@ -78,3 +101,76 @@ type FolderTitleUpdated struct {
 	UID       string    `json:"uid"`
 	OrgID     int64     `json:"org_id"`
 }
+
+// DashboardSaved is published when a dashboard or a folder stored in the dashboard table is created or updated.
+type DashboardSaved struct {
+	Timestamp time.Time `json:"timestamp"`
+	Title     string    `json:"title"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+	FolderUID string    `json:"folder_uid"`
+	IsFolder  bool      `json:"is_folder"`
+	Version   int       `json:"version"`
+	Created   bool      `json:"created"`
+	UserID    int64     `json:"user_id"`
+}
+
+// DashboardDeleted is published when a dashboard or a folder stored in the dashboard table is deleted.
+type DashboardDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	Title     string    `json:"title"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+	FolderUID string    `json:"folder_uid"`
+	IsFolder  bool      `json:"is_folder"`
+}
+
+type AlertRuleCreated struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Title        string    `json:"title"`
+	UID          string    `json:"uid"`
+	OrgID        int64     `json:"org_id"`
+	NamespaceUID string    `json:"namespace_uid"`
+	RuleGroup    string    `json:"rule_group"`
+}
+
+type AlertRuleUpdated struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Title        string    `json:"title"`
+	UID          string    `json:"uid"`
+	OrgID        int64     `json:"org_id"`
+	NamespaceUID string    `json:"namespace_uid"`
+	RuleGroup    string    `json:"rule_group"`
+	Version      int64     `json:"version"`
+}
+
+type AlertRuleDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+}
+
+type TeamCreated struct {
+	Timestamp time.Time `json:"timestamp"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+}
+
+type TeamUpdated struct {
+	Timestamp time.Time `json:"timestamp"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	ID        int64     `json:"id"`
+	OrgID     int64     `json:"org_id"`
+}
+
+type TeamDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	ID        int64     `json:"id"`
+	OrgID     int64     `json:"org_id"`
+}
@@ -174,6 +174,12 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 				return newRes, err
 			}
 			newRes.Values = append(newRes.Values, num)
+		case mathexp.Histogram: // histograms are reduced over their total observation count
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, num)
 		case mathexp.Number: // if incoming vars is just a number, any reduce op is just a noop, add it as it is
 			value := v.GetFloat64Value()
 			if gr.seriesMapper != nil {
@@ -194,7 +200,7 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 		case mathexp.NoData:
 			newRes.Values = append(newRes.Values, v.New())
 		default:
-			return newRes, fmt.Errorf("can only reduce type series, got type %v", val.Type())
+			return newRes, fmt.Errorf("can only reduce type series or histogram, got type %v", val.Type())
 		}
 	}
 	return newRes, nil
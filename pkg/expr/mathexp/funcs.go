@@ -1,6 +1,7 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
@@ -68,6 +69,21 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"histogram_quantile": {
+		Args:   []parse.ReturnType{parse.TypeScalar, parse.TypeHistogramSet},
+		Return: parse.TypeSeriesSet,
+		F:      histogramQuantileFunc,
+	},
+	"histogram_count": {
+		Args:   []parse.ReturnType{parse.TypeHistogramSet},
+		Return: parse.TypeSeriesSet,
+		F:      histogramCountFunc,
+	},
+	"histogram_fraction": {
+		Args:   []parse.ReturnType{parse.TypeScalar, parse.TypeScalar, parse.TypeHistogramSet},
+		Return: parse.TypeSeriesSet,
+		F:      histogramFractionFunc,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +323,161 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// histogramQuantileFunc returns a series of the φ-quantile (0 ≤ φ ≤ 1) for each histogram in HistogramSet.
+func histogramQuantileFunc(e *State, phi Results, varSet Results) (Results, error) {
+	q, err := scalarArg(phi)
+	if err != nil {
+		return Results{}, err
+	}
+	return perHistogram(e, varSet, func(buckets []HistogramBucket) float64 {
+		return histogramQuantile(q, buckets)
+	})
+}
+
+// histogramCountFunc returns a series of the total number of observations for each histogram in HistogramSet.
+func histogramCountFunc(e *State, varSet Results) (Results, error) {
+	return perHistogram(e, varSet, histogramCount)
+}
+
+// histogramFractionFunc returns a series of the fraction of observations between lower and upper
+// for each histogram in HistogramSet.
+func histogramFractionFunc(e *State, lowerArg, upperArg Results, varSet Results) (Results, error) {
+	lower, err := scalarArg(lowerArg)
+	if err != nil {
+		return Results{}, err
+	}
+	upper, err := scalarArg(upperArg)
+	if err != nil {
+		return Results{}, err
+	}
+	return perHistogram(e, varSet, func(buckets []HistogramBucket) float64 {
+		return histogramFraction(lower, upper, buckets)
+	})
+}
+
+// perHistogram calls fn for every point in time of each histogram in varSet, and returns the results as series.
+// Classic histogram bucket series, i.e. series with a "le" label, are grouped into histograms first.
+func perHistogram(e *State, varSet Results, fn func(buckets []HistogramBucket) float64) (Results, error) {
+	newRes := Results{}
+	var bucketSeries []Series
+	for _, val := range varSet.Values {
+		switch v := val.(type) {
+		case Histogram:
+			newRes.Values = append(newRes.Values, v.perPoint(e.RefID, fn))
+		case Series:
+			if _, ok := v.GetLabels()[bucketLabel]; !ok {
+				return newRes, fmt.Errorf("expected a histogram or series with a %q label, got series %s", bucketLabel, v.GetLabels())
+			}
+			bucketSeries = append(bucketSeries, v)
+		case NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("expected a histogram, got type %v", val.Type())
+		}
+	}
+	if len(bucketSeries) > 0 {
+		histograms, err := HistogramsFromBucketSeries(e.RefID, bucketSeries)
+		if err != nil {
+			return newRes, err
+		}
+		for _, h := range histograms {
+			newRes.Values = append(newRes.Values, h.perPoint(e.RefID, fn))
+		}
+	}
+	return newRes, nil
+}
+
+func scalarArg(res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("expected a scalar argument")
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return math.NaN(), nil
+	}
+	return *f, nil
+}
+
+// histogramCount returns the total number of observations in the buckets.
+func histogramCount(buckets []HistogramBucket) float64 {
+	var total float64
+	for _, b := range buckets {
+		total += b.Count
+	}
+	return total
+}
+
+// histogramQuantile estimates the q-quantile of the buckets, following the behaviour of the
+// PromQL function of the same name: the observations are assumed to be linearly distributed
+// within a bucket, and a quantile falling into the +Inf bucket returns its lower bound.
+func histogramQuantile(q float64, buckets []HistogramBucket) float64 {
+	switch {
+	case math.IsNaN(q):
+		return math.NaN()
+	case q < 0:
+		return math.Inf(-1)
+	case q > 1:
+		return math.Inf(1)
+	}
+
+	total := histogramCount(buckets)
+	if total == 0 || math.IsNaN(total) {
+		return math.NaN()
+	}
+
+	rank := q * total
+	var cumulative float64
+	for _, b := range buckets {
+		cumulative += b.Count
+		if cumulative < rank {
+			continue
+		}
+		switch {
+		case math.IsInf(b.Upper, 1):
+			return b.Lower
+		case math.IsInf(b.Lower, -1) || b.Count == 0:
+			return b.Upper
+		}
+		rankInBucket := rank - (cumulative - b.Count)
+		return b.Lower + (b.Upper-b.Lower)*(rankInBucket/b.Count)
+	}
+	return buckets[len(buckets)-1].Upper
+}
+
+// histogramFraction estimates the fraction of observations between lower and upper, assuming
+// the observations are linearly distributed within a bucket. Observations in a bucket with an
+// infinite bound are assumed to be at its finite bound.
+func histogramFraction(lower, upper float64, buckets []HistogramBucket) float64 {
+	total := histogramCount(buckets)
+	if total == 0 || math.IsNaN(total) || math.IsNaN(lower) || math.IsNaN(upper) {
+		return math.NaN()
+	}
+	if lower >= upper {
+		return 0
+	}
+
+	var inRange float64
+	for _, b := range buckets {
+		switch {
+		case b.Count == 0:
+			continue
+		case b.Lower >= lower && b.Upper <= upper:
+			inRange += b.Count
+		case math.IsInf(b.Upper, 1):
+			if b.Lower >= lower && b.Lower <= upper {
+				inRange += b.Count
+			}
+		case math.IsInf(b.Lower, -1) || b.Lower == b.Upper:
+			if b.Upper >= lower && b.Upper <= upper {
+				inRange += b.Count
+			}
+		default:
+			overlap := math.Min(b.Upper, upper) - math.Max(b.Lower, lower)
+			if overlap > 0 {
+				inRange += b.Count * overlap / (b.Upper - b.Lower)
+			}
+		}
+	}
+	return inRange / total
+}
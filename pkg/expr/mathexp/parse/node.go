@@ -179,6 +179,11 @@ func (f *FuncNode) Check(t *Tree) error {
 			if !(argType == TypeNumberSet || argType == TypeSeriesSet || argType == TypeScalar) {
 				return fmt.Errorf("parse: expected %v or %v for argument %v, got %v", TypeNumberSet, TypeSeriesSet, i, argType)
 			}
+		} else if funcType == TypeHistogramSet {
+			// The type of a variable is only known at execution time, so any variable is accepted here.
+			if !(argType == TypeHistogramSet || arg.Type() == NodeVar) {
+				return fmt.Errorf("parse: expected %v for argument %v, got %v", TypeHistogramSet, i, argType)
+			}
 		} else if funcType != argType {
 			return fmt.Errorf("parse: expected %v, got %v for argument %v (%v)", funcType, argType, i, arg.String())
 		}
@@ -405,6 +410,8 @@ const (
 	TypeVariantSet
 	// TypeNoData is a no data response without a known data type.
 	TypeNoData
+	// TypeHistogramSet is a collection of labelled time series of histograms.
+	TypeHistogramSet
 )
 
 // String returns a string representation of the ReturnType.
@@ -422,6 +429,8 @@ func (f ReturnType) String() string {
 		return "variant"
 	case TypeNoData:
 		return "noData"
+	case TypeHistogramSet:
+		return "histogramSet"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,376 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+const (
+	// FrameTypeHeatmapCells is the frame type used by the Prometheus data source for native histograms.
+	FrameTypeHeatmapCells data.FrameType = "heatmap-cells"
+	// FrameTypeHeatmapRows is the frame type of a heatmap with one numeric field per bucket.
+	FrameTypeHeatmapRows data.FrameType = "heatmap-rows"
+
+	// bucketLabel is the label holding the upper bound of a classic Prometheus histogram bucket.
+	bucketLabel = "le"
+)
+
+// Field indices of the Histogram type's frame, which uses the heatmap-cells layout.
+const (
+	histogramTypeTimeIdx = iota
+	histogramTypeMinIdx
+	histogramTypeMaxIdx
+	histogramTypeCountIdx
+)
+
+// Histogram holds a labelled time series of histograms. Each row of the underlying
+// frame is one bucket at one point in time, with the lower and upper bound of the
+// bucket and the (non-cumulative) number of observations that fell into it.
+// Rows are ordered by time and then by upper bound.
+type Histogram struct{ Frame *data.Frame }
+
+// HistogramBucket is a single bucket of a histogram at one point in time.
+type HistogramBucket struct {
+	Lower, Upper float64
+	Count        float64
+}
+
+// histogramPoint is a histogram at one point in time.
+type histogramPoint struct {
+	Time    time.Time
+	Buckets []HistogramBucket
+}
+
+// Type returns the Value type and allows it to fulfill the Value interface.
+func (h Histogram) Type() parse.ReturnType { return parse.TypeHistogramSet }
+
+// Value returns the actual value allows it to fulfill the Value interface.
+func (h Histogram) Value() any { return &h }
+
+func (h Histogram) GetLabels() data.Labels { return h.Frame.Fields[histogramTypeMinIdx].Labels }
+
+func (h Histogram) SetLabels(ls data.Labels) { h.Frame.Fields[histogramTypeMinIdx].Labels = ls }
+
+// AsDataFrame returns the underlying *data.Frame.
+func (h Histogram) AsDataFrame() *data.Frame { return h.Frame }
+
+func (h Histogram) GetMeta() any {
+	return h.Frame.Meta.Custom
+}
+
+func (h Histogram) SetMeta(v any) {
+	m := h.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		h.Frame.SetMeta(m)
+	}
+	m.Custom = v
+}
+
+func (h Histogram) AddNotice(notice data.Notice) {
+	m := h.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		h.Frame.SetMeta(m)
+	}
+	m.Notices = append(m.Notices, notice)
+}
+
+// NewHistogram returns an empty Histogram with the given labels.
+func NewHistogram(refID string, labels data.Labels) Histogram {
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{}),
+		data.NewField("yMin", labels, []float64{}),
+		data.NewField("yMax", nil, []float64{}),
+		data.NewField("count", nil, []float64{}),
+	).SetMeta(&data.FrameMeta{Type: FrameTypeHeatmapCells})
+	frame.RefID = refID
+	return Histogram{frame}
+}
+
+// AppendBucket adds a bucket to the histogram. Buckets must be appended in time and upper bound order.
+func (h Histogram) AppendBucket(t time.Time, b HistogramBucket) {
+	h.Frame.Fields[histogramTypeTimeIdx].Append(t)
+	h.Frame.Fields[histogramTypeMinIdx].Append(b.Lower)
+	h.Frame.Fields[histogramTypeMaxIdx].Append(b.Upper)
+	h.Frame.Fields[histogramTypeCountIdx].Append(b.Count)
+}
+
+// Len returns the number of buckets over all points in time.
+func (h Histogram) Len() int {
+	return h.Frame.Fields[histogramTypeTimeIdx].Len()
+}
+
+// points groups the buckets of the histogram by time.
+func (h Histogram) points() []histogramPoint {
+	var points []histogramPoint
+	for i := 0; i < h.Len(); i++ {
+		t := h.Frame.Fields[histogramTypeTimeIdx].At(i).(time.Time)
+		b := HistogramBucket{
+			Lower: h.Frame.Fields[histogramTypeMinIdx].At(i).(float64),
+			Upper: h.Frame.Fields[histogramTypeMaxIdx].At(i).(float64),
+			Count: h.Frame.Fields[histogramTypeCountIdx].At(i).(float64),
+		}
+		if len(points) == 0 || !points[len(points)-1].Time.Equal(t) {
+			points = append(points, histogramPoint{Time: t})
+		}
+		points[len(points)-1].Buckets = append(points[len(points)-1].Buckets, b)
+	}
+	return points
+}
+
+// perPoint builds a Series with the histogram's labels by calling fn for each point in time.
+func (h Histogram) perPoint(refID string, fn func(buckets []HistogramBucket) float64) Series {
+	var l data.Labels
+	if h.GetLabels() != nil {
+		l = h.GetLabels().Copy()
+	}
+	points := h.points()
+	s := NewSeries(refID, l, len(points))
+	for i, p := range points {
+		f := fn(p.Buckets)
+		s.SetPoint(i, p.Time, &f)
+	}
+	return s
+}
+
+// Reduce turns the Histogram into a Number by reducing the series of its total observation
+// count (see histogram_count) with the given reduction function.
+func (h Histogram) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error) {
+	return h.perPoint(refID, histogramCount).Reduce(refID, rFunc, mapper)
+}
+
+// HistogramFromHeatmapCells converts a frame in the heatmap-cells format, as returned by
+// the Prometheus data source for native histograms, into a Histogram.
+func HistogramFromHeatmapCells(frame *data.Frame) (Histogram, error) {
+	var timeField, minField, maxField, countField *data.Field
+	for _, f := range frame.Fields {
+		switch f.Name {
+		case "xMax", "x":
+			timeField = f
+		case "yMin":
+			minField = f
+		case "yMax":
+			maxField = f
+		case "count":
+			countField = f
+		}
+	}
+	if timeField == nil || minField == nil || maxField == nil || countField == nil {
+		return Histogram{}, fmt.Errorf("heatmap-cells frame %q must have xMax, yMin, yMax and count fields", frame.Name)
+	}
+
+	type cell struct {
+		t time.Time
+		b HistogramBucket
+	}
+	cells := make([]cell, 0, timeField.Len())
+	for i := 0; i < timeField.Len(); i++ {
+		t, ok := timeField.ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		tt, ok := t.(time.Time)
+		if !ok {
+			return Histogram{}, fmt.Errorf("unexpected type %T for histogram time in frame %q", t, frame.Name)
+		}
+		lower, err := minField.FloatAt(i)
+		if err != nil {
+			return Histogram{}, err
+		}
+		upper, err := maxField.FloatAt(i)
+		if err != nil {
+			return Histogram{}, err
+		}
+		count, err := countField.FloatAt(i)
+		if err != nil {
+			return Histogram{}, err
+		}
+		cells = append(cells, cell{t: tt, b: HistogramBucket{Lower: lower, Upper: upper, Count: count}})
+	}
+	sort.SliceStable(cells, func(i, j int) bool {
+		if !cells[i].t.Equal(cells[j].t) {
+			return cells[i].t.Before(cells[j].t)
+		}
+		return cells[i].b.Upper < cells[j].b.Upper
+	})
+
+	labels := minField.Labels
+	if labels == nil {
+		labels = countField.Labels
+	}
+	h := NewHistogram(frame.RefID, labels)
+	for _, c := range cells {
+		h.AppendBucket(c.t, c.b)
+	}
+	return h, nil
+}
+
+// HistogramFromHeatmapRows converts a frame in the heatmap-rows format into a Histogram.
+// The upper bound of each bucket is read from the "le" label of the field, or from the field
+// name if there is no such label. Bucket values are expected to be non-cumulative.
+func HistogramFromHeatmapRows(frame *data.Frame) (Histogram, error) {
+	timeIdx := -1
+	type bucketField struct {
+		upper float64
+		field *data.Field
+	}
+	var buckets []bucketField
+	var labels data.Labels
+	for i, f := range frame.Fields {
+		if f.Type().Time() {
+			timeIdx = i
+			continue
+		}
+		if !f.Type().Numeric() {
+			continue
+		}
+		upper, err := parseBucketBound(f)
+		if err != nil {
+			return Histogram{}, err
+		}
+		if labels == nil && f.Labels != nil {
+			labels = withoutBucketLabel(f.Labels)
+		}
+		buckets = append(buckets, bucketField{upper: upper, field: f})
+	}
+	if timeIdx == -1 {
+		return Histogram{}, fmt.Errorf("no time column found in heatmap-rows frame %q", frame.Name)
+	}
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].upper < buckets[j].upper })
+
+	h := NewHistogram(frame.RefID, labels)
+	timeField := frame.Fields[timeIdx]
+	for row := 0; row < timeField.Len(); row++ {
+		t, ok := timeField.ConcreteAt(row)
+		if !ok {
+			continue
+		}
+		lower := 0.0
+		for i, b := range buckets {
+			count, err := b.field.NullableFloatAt(row)
+			if err != nil {
+				return Histogram{}, err
+			}
+			c := 0.0
+			if count != nil {
+				c = *count
+			}
+			if i > 0 {
+				lower = buckets[i-1].upper
+			} else if b.upper <= 0 {
+				lower = b.upper
+			}
+			h.AppendBucket(t.(time.Time), HistogramBucket{Lower: lower, Upper: b.upper, Count: c})
+		}
+	}
+	return h, nil
+}
+
+// HistogramsFromBucketSeries groups series of classic Prometheus histogram buckets (series that
+// differ only by their "le" label and hold cumulative counts) into Histograms.
+func HistogramsFromBucketSeries(refID string, series []Series) ([]Histogram, error) {
+	type group struct {
+		labels  data.Labels
+		points  map[time.Time]map[float64]float64
+		times   []time.Time
+		buckets map[float64]struct{}
+	}
+	groups := map[string]*group{}
+	var order []string
+
+	for _, s := range series {
+		le, ok := s.GetLabels()[bucketLabel]
+		if !ok {
+			return nil, fmt.Errorf("series %s is not a histogram bucket: missing %q label", s.GetLabels(), bucketLabel)
+		}
+		upper, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid histogram bucket bound %q in series %s: %w", le, s.GetLabels(), err)
+		}
+		labels := withoutBucketLabel(s.GetLabels())
+		key := labels.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: labels, points: map[time.Time]map[float64]float64{}, buckets: map[float64]struct{}{}}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.buckets[upper] = struct{}{}
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				continue
+			}
+			if _, ok := g.points[t]; !ok {
+				g.points[t] = map[float64]float64{}
+				g.times = append(g.times, t)
+			}
+			g.points[t][upper] = *f
+		}
+	}
+
+	histograms := make([]Histogram, 0, len(groups))
+	for _, key := range order {
+		g := groups[key]
+		bounds := make([]float64, 0, len(g.buckets))
+		for b := range g.buckets {
+			bounds = append(bounds, b)
+		}
+		sort.Float64s(bounds)
+		sort.Slice(g.times, func(i, j int) bool { return g.times[i].Before(g.times[j]) })
+
+		h := NewHistogram(refID, g.labels)
+		for _, t := range g.times {
+			var prevCumulative float64
+			for i, upper := range bounds {
+				cumulative, ok := g.points[t][upper]
+				if !ok {
+					// A missing bucket holds no more observations than the previous one.
+					cumulative = prevCumulative
+				}
+				lower := 0.0
+				if i > 0 {
+					lower = bounds[i-1]
+				} else if upper <= 0 {
+					lower = upper
+				}
+				// Counters might not be scraped atomically, so treat decreases as zero.
+				h.AppendBucket(t, HistogramBucket{Lower: lower, Upper: upper, Count: math.Max(cumulative-prevCumulative, 0)})
+				if cumulative > prevCumulative {
+					prevCumulative = cumulative
+				}
+			}
+		}
+		histograms = append(histograms, h)
+	}
+	return histograms, nil
+}
+
+func parseBucketBound(f *data.Field) (float64, error) {
+	bound, ok := f.Labels[bucketLabel]
+	if !ok {
+		bound = f.Name
+	}
+	upper, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not read histogram bucket bound from field %q: %w", f.Name, err)
+	}
+	return upper, nil
+}
+
+func withoutBucketLabel(l data.Labels) data.Labels {
+	if l == nil {
+		return nil
+	}
+	c := l.Copy()
+	delete(c, bucketLabel)
+	return c
+}
@@ -0,0 +1,195 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// makeHistogram returns a histogram with the same non-cumulative bucket counts at each of the given times.
+func makeHistogram(labels data.Labels, bounds []float64, counts []float64, times ...time.Time) Histogram {
+	h := NewHistogram("", labels)
+	for _, t := range times {
+		for i, upper := range bounds {
+			lower := 0.0
+			if i > 0 {
+				lower = bounds[i-1]
+			}
+			h.AppendBucket(t, HistogramBucket{Lower: lower, Upper: upper, Count: counts[i]})
+		}
+	}
+	return h
+}
+
+func TestHistogramFuncs(t *testing.T) {
+	bounds := []float64{1, 2, 4, math.Inf(1)}
+	counts := []float64{10, 20, 10, 0}
+	hist := makeHistogram(data.Labels{"job": "api"}, bounds, counts, time.Unix(5, 0), time.Unix(10, 0))
+
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name: "histogram_count",
+			expr: "histogram_count($A)",
+			vars: Vars{"A": resultValuesNoErr(hist)},
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"job": "api"},
+					tp{time.Unix(5, 0), float64Pointer(40)},
+					tp{time.Unix(10, 0), float64Pointer(40)}),
+			),
+		},
+		{
+			name: "histogram_quantile interpolates within the bucket",
+			expr: "histogram_quantile(0.5, $A)",
+			vars: Vars{"A": resultValuesNoErr(hist)},
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"job": "api"},
+					tp{time.Unix(5, 0), float64Pointer(1.5)},
+					tp{time.Unix(10, 0), float64Pointer(1.5)}),
+			),
+		},
+		{
+			name: "histogram_fraction",
+			expr: "histogram_fraction(0, 2, $A)",
+			vars: Vars{"A": resultValuesNoErr(hist)},
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"job": "api"},
+					tp{time.Unix(5, 0), float64Pointer(0.75)},
+					tp{time.Unix(10, 0), float64Pointer(0.75)}),
+			),
+		},
+		{
+			name: "histogram_quantile on classic bucket series",
+			expr: "histogram_quantile(0.9, $A)",
+			vars: Vars{"A": resultValuesNoErr(
+				makeSeries("", data.Labels{"job": "api", "le": "1"}, tp{time.Unix(5, 0), float64Pointer(10)}),
+				makeSeries("", data.Labels{"job": "api", "le": "2"}, tp{time.Unix(5, 0), float64Pointer(30)}),
+				makeSeries("", data.Labels{"job": "api", "le": "4"}, tp{time.Unix(5, 0), float64Pointer(40)}),
+				makeSeries("", data.Labels{"job": "api", "le": "+Inf"}, tp{time.Unix(5, 0), float64Pointer(40)}),
+			)},
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"job": "api"}, tp{time.Unix(5, 0), float64Pointer(3.2)}),
+			),
+		},
+		{
+			name:    "histogram functions pass through no data",
+			expr:    "histogram_count($A)",
+			vars:    Vars{"A": resultValuesNoErr(NewNoData())},
+			results: resultValuesNoErr(NewNoData()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Len(t, res.Values, len(tt.results.Values))
+			for i := range tt.results.Values {
+				expected, ok := tt.results.Values[i].(Series)
+				if !ok {
+					require.Equal(t, tt.results.Values[i].Type(), res.Values[i].Type())
+					continue
+				}
+				actual := res.Values[i].(Series)
+				require.Equal(t, expected.GetLabels(), actual.GetLabels())
+				require.Equal(t, expected.Len(), actual.Len())
+				for p := 0; p < expected.Len(); p++ {
+					require.Equal(t, expected.GetTime(p), actual.GetTime(p))
+					require.InDelta(t, *expected.GetValue(p), *actual.GetValue(p), 1e-9)
+				}
+			}
+		})
+	}
+
+	t.Run("histogram functions reject series without a le label", func(t *testing.T) {
+		e, err := New("histogram_count($A)")
+		require.NoError(t, err)
+		_, err = e.Execute("", Vars{"A": resultValuesNoErr(makeSeries("", nil, tp{time.Unix(5, 0), float64Pointer(1)}))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+
+	t.Run("histogram functions reject non histogram arguments at parse time", func(t *testing.T) {
+		_, err := New("histogram_count(1)")
+		require.Error(t, err)
+	})
+
+	t.Run("histogram functions can be used in binary operations", func(t *testing.T) {
+		e, err := New("histogram_quantile(0.5, $A) > 1")
+		require.NoError(t, err)
+		res, err := e.Execute("", Vars{"A": resultValuesNoErr(hist)}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, float64(1), *res.Values[0].(Series).GetValue(0))
+	})
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := []HistogramBucket{
+		{Lower: 0, Upper: 1, Count: 10},
+		{Lower: 1, Upper: 2, Count: 20},
+		{Lower: 2, Upper: math.Inf(1), Count: 10},
+	}
+	require.Equal(t, math.Inf(-1), histogramQuantile(-1, buckets))
+	require.Equal(t, math.Inf(1), histogramQuantile(2, buckets))
+	require.InDelta(t, 0.5, histogramQuantile(0.125, buckets), 1e-9)
+	// quantiles in the +Inf bucket return its lower bound
+	require.Equal(t, 2.0, histogramQuantile(0.99, buckets))
+	require.True(t, math.IsNaN(histogramQuantile(0.5, []HistogramBucket{{Lower: 0, Upper: 1}})))
+}
+
+func TestHistogramReduce(t *testing.T) {
+	h := makeHistogram(nil, []float64{1, 2}, []float64{1, 2}, time.Unix(5, 0), time.Unix(10, 0))
+	n, err := h.Reduce("B", "sum", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(6), *n.GetFloat64Value())
+}
+
+func TestHistogramFromHeatmapCells(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{time.Unix(10, 0), time.Unix(5, 0), time.Unix(5, 0)}),
+		data.NewField("yMin", data.Labels{"job": "api"}, []float64{0, 1, 0}),
+		data.NewField("yMax", nil, []float64{1, 2, 1}),
+		data.NewField("count", nil, []float64{3, 2, 1}),
+		data.NewField("yLayout", nil, []int8{0, 0, 0}),
+	).SetMeta(&data.FrameMeta{Type: FrameTypeHeatmapCells})
+
+	h, err := HistogramFromHeatmapCells(frame)
+	require.NoError(t, err)
+	require.Equal(t, data.Labels{"job": "api"}, h.GetLabels())
+
+	points := h.points()
+	require.Len(t, points, 2)
+	require.Equal(t, time.Unix(5, 0), points[0].Time)
+	require.Equal(t, []HistogramBucket{{Lower: 0, Upper: 1, Count: 1}, {Lower: 1, Upper: 2, Count: 2}}, points[0].Buckets)
+	require.Equal(t, []HistogramBucket{{Lower: 0, Upper: 1, Count: 3}}, points[1].Buckets)
+}
+
+func TestHistogramFromHeatmapRows(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{time.Unix(5, 0)}),
+		data.NewField("2", nil, []float64{4}),
+		data.NewField("value", data.Labels{"le": "1", "job": "api"}, []*float64{float64Pointer(3)}),
+		data.NewField("+Inf", nil, []float64{1}),
+	).SetMeta(&data.FrameMeta{Type: FrameTypeHeatmapRows})
+
+	h, err := HistogramFromHeatmapRows(frame)
+	require.NoError(t, err)
+	require.Equal(t, data.Labels{"job": "api"}, h.GetLabels())
+	points := h.points()
+	require.Len(t, points, 1)
+	require.Equal(t, []HistogramBucket{
+		{Lower: 0, Upper: 1, Count: 3},
+		{Lower: 1, Upper: 2, Count: 4},
+		{Lower: 2, Upper: math.Inf(1), Count: 1},
+	}, points[0].Buckets)
+}
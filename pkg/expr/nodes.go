@@ -149,6 +149,8 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+	// asHistogram is set by queries that want their heatmap frames to be read as histograms.
+	asHistogram bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -201,6 +203,12 @@ func (s *Service) buildDSNode(dp *simple.DirectedGraph, rn *rawNode, req *Reques
 		dsNode.maxDP = int64(floatMaxDP)
 	}
 
+	if rawAsHistogram, ok := rn.Query["asHistogram"]; ok {
+		if dsNode.asHistogram, ok = rawAsHistogram.(bool); !ok {
+			return nil, fmt.Errorf("expected asHistogram to be a bool, got type %T for refId %v", rawAsHistogram, rn.RefID)
+		}
+	}
+
 	return dsNode, nil
 }
 
@@ -290,7 +298,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 				}
 
 				var result mathexp.Results
-				responseType, result, err := convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
+				responseType, result, err := dn.convertFrames(ctx, dataFrames, s, logger)
 				if err != nil {
 					result.Error = makeConversionError(dn.RefID(), err)
 				}
@@ -358,13 +366,26 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	}
 
 	var result mathexp.Results
-	responseType, result, err = convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
+	responseType, result, err = dn.convertFrames(ctx, dataFrames, s, logger)
 	if err != nil {
 		err = makeConversionError(dn.refID, err)
 	}
 	return result, err
 }
 
+// convertFrames converts the frames of the response to the query of the node. Heatmap frames are read as
+// histograms only if the query sets asHistogram, otherwise they are converted like any other frames.
+func (dn *DSNode) convertFrames(ctx context.Context, frames data.Frames, s *Service, logger log.Logger) (string, mathexp.Results, error) {
+	if dn.asHistogram && len(frames) > 0 && isHistogramFrames(frames) {
+		vals, err := framesToHistograms(frames)
+		if err != nil {
+			return "", mathexp.Results{}, fmt.Errorf("failed to read frames as histograms: %w", err)
+		}
+		return "histogram", mathexp.Results{Values: vals}, nil
+	}
+	return convertDataFramesToResults(ctx, frames, dn.datasource.Type, s, logger)
+}
+
 func getResponseFrame(resp *backend.QueryDataResponse, refID string) (data.Frames, error) {
 	response, ok := resp.Responses[refID]
 	if !ok {
@@ -470,6 +491,38 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 	}, nil
 }
 
+// isHistogramFrames returns true if all frames are heatmap frames, such as the heatmap-cells
+// frames the Prometheus data source returns for native histograms.
+func isHistogramFrames(frames data.Frames) bool {
+	for _, frame := range frames {
+		if frame == nil || frame.Meta == nil {
+			return false
+		}
+		if frame.Meta.Type != mathexp.FrameTypeHeatmapCells && frame.Meta.Type != mathexp.FrameTypeHeatmapRows {
+			return false
+		}
+	}
+	return true
+}
+
+func framesToHistograms(frames data.Frames) ([]mathexp.Value, error) {
+	vals := make([]mathexp.Value, 0, len(frames))
+	for _, frame := range frames {
+		var h mathexp.Histogram
+		var err error
+		if frame.Meta.Type == mathexp.FrameTypeHeatmapCells {
+			h, err = mathexp.HistogramFromHeatmapCells(frame)
+		} else {
+			h, err = mathexp.HistogramFromHeatmapRows(frame)
+		}
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, h)
+	}
+	return vals, nil
+}
+
 func isAllFrameVectors(datasourceType string, frames data.Frames) bool {
 	if datasourceType != datasources.DS_PROMETHEUS {
 		return false
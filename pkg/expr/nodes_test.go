@@ -271,4 +271,67 @@ func TestConvertDataFramesToResults(t *testing.T) {
 			}
 		})
 	})
+
+}
+
+func TestDSNodeConvertFrames(t *testing.T) {
+	s := &Service{
+		cfg:      setting.NewCfg(),
+		features: &featuremgmt.FeatureManager{},
+		tracer:   tracing.InitializeTracerForTest(),
+		metrics:  newMetrics(nil),
+	}
+	heatmapRows := func() data.Frames {
+		return data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+				data.NewField("1", data.Labels{"job": "api"}, []float64{3, 4}),
+				data.NewField("2", data.Labels{"job": "api"}, []float64{1, 2}),
+			).SetMeta(&data.FrameMeta{Type: mathexp.FrameTypeHeatmapRows}),
+		}
+	}
+
+	t.Run("should convert heatmap-cells frames to histograms if the query asks for it", func(t *testing.T) {
+		frames := []*data.Frame{
+			data.NewFrame("",
+				data.NewField("xMax", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0)}),
+				data.NewField("yMin", data.Labels{"job": "api"}, []float64{0, 1}),
+				data.NewField("yMax", nil, []float64{1, 2}),
+				data.NewField("count", nil, []float64{3, 1}),
+				data.NewField("yLayout", nil, []int8{0, 0}),
+			).SetMeta(&data.FrameMeta{Type: mathexp.FrameTypeHeatmapCells}),
+		}
+		dn := &DSNode{datasource: &datasources.DataSource{Type: datasources.DS_PROMETHEUS}, asHistogram: true}
+
+		resultType, res, err := dn.convertFrames(context.Background(), frames, s, &logtest.Fake{})
+		require.NoError(t, err)
+		assert.Equal(t, "histogram", resultType)
+		require.Len(t, res.Values, 1)
+		require.IsType(t, mathexp.Histogram{}, res.Values[0])
+		require.Equal(t, data.Labels{"job": "api"}, res.Values[0].GetLabels())
+	})
+
+	t.Run("should not change math expressions over heatmap-rows frames if the query does not ask for histograms", func(t *testing.T) {
+		dn := &DSNode{baseNode: baseNode{refID: "A"}, datasource: &datasources.DataSource{Type: datasources.DS_PROMETHEUS}}
+
+		resultType, res, err := dn.convertFrames(context.Background(), heatmapRows(), s, &logtest.Fake{})
+		require.NoError(t, err)
+		expectedType, expected, err := convertDataFramesToResults(context.Background(), heatmapRows(), datasources.DS_PROMETHEUS, s, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, expectedType, resultType)
+		require.Equal(t, expected, res)
+
+		cmd, err := NewMathCommand("B", "$A * 2")
+		require.NoError(t, err)
+		out, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": res}, s.tracer)
+		require.NoError(t, err)
+		require.Len(t, out.Values, 2)
+		firstPoints := make([]float64, 0, len(out.Values))
+		for _, v := range out.Values {
+			require.IsType(t, mathexp.Series{}, v)
+			_, value := v.(mathexp.Series).GetPoint(0)
+			firstPoints = append(firstPoints, *value)
+		}
+		require.ElementsMatch(t, []float64{6, 2}, firstPoints)
+	})
 }
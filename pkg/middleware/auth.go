@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/authn"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginaccesscontrol"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
@@ -27,6 +28,9 @@ type AuthOptions struct {
 	ReqGrafanaAdmin bool
 	ReqNoAnonynmous bool
 	ReqSignedIn     bool
+	// ReqNoExchangedToken rejects identities authenticated by an exchanged token. Their permissions are limited to
+	// the permissions of the token, which are only checked by the routes that authorize them.
+	ReqNoExchangedToken bool
 }
 
 func accessForbidden(c *contextmodel.ReqContext) {
@@ -195,6 +199,11 @@ func Auth(options *AuthOptions) web.Handler {
 			accessForbidden(c)
 			return
 		}
+
+		if options.ReqNoExchangedToken && c.SignedInUser.GetAuthenticatedBy() == login.TokenExchangeModule {
+			accessForbidden(c)
+			return
+		}
 	}
 }
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,8 +14,10 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authnimpl/sync"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -155,6 +158,50 @@ func TestAuth_Middleware(t *testing.T) {
 	}
 }
 
+func TestAuth_Middleware_LimitedPermissions(t *testing.T) {
+	// identities of exchanged tokens, whose permissions are limited to the permissions of the token
+	newIdentity := func(t *testing.T, limitPermissions map[string][]string) *authn.Identity {
+		isGrafanaAdmin := true
+		identity := &authn.Identity{
+			ID:             "user:1",
+			OrgID:          1,
+			OrgRoles:       map[int64]org.RoleType{1: org.RoleAdmin},
+			IsGrafanaAdmin: &isGrafanaAdmin,
+			ClientParams:   authn.ClientParams{SyncPermissions: true, LimitPermissions: limitPermissions},
+		}
+		permissionsSync := sync.ProvidePermissionsSync(actest.FakeService{
+			ExpectedPermissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}},
+		})
+		require.NoError(t, permissionsSync.SyncPermissionsHook(context.Background(), identity, &authn.Request{}))
+		return identity
+	}
+
+	for desc, authMiddleware := range map[string]web.Handler{"ReqGrafanaAdmin": ReqGrafanaAdmin, "ReqOrgAdmin": ReqOrgAdmin} {
+		t.Run(desc+" should return 403 for identities with limited permissions", func(t *testing.T) {
+			for _, tt := range []struct {
+				limitPermissions map[string][]string
+				expectedCode     int
+			}{
+				{limitPermissions: nil, expectedCode: http.StatusOK},
+				{limitPermissions: map[string][]string{"dashboards:read": {"dashboards:uid:1"}}, expectedCode: http.StatusForbidden},
+			} {
+				server := web.New()
+				server.Use(setupAuthMiddlewareTest(t, newIdentity(t, tt.limitPermissions), nil).Middleware)
+				server.Use(authMiddleware)
+				server.Get("/api/secure", func(c *contextmodel.ReqContext) {
+					c.Resp.WriteHeader(http.StatusOK)
+				})
+
+				req, err := http.NewRequest(http.MethodGet, "/api/secure", nil)
+				require.NoError(t, err)
+				recorder := httptest.NewRecorder()
+				server.ServeHTTP(recorder, req)
+				assert.Equal(t, tt.expectedCode, recorder.Code)
+			}
+		})
+	}
+}
+
 func TestRoleAppPluginAuth(t *testing.T) {
 	t.Run("Verify user's role when requesting app route which requires role", func(t *testing.T) {
 		appSubURL := setting.AppSubUrl
@@ -16,8 +16,9 @@ var (
 		ReqSignedIn:     true,
 		ReqGrafanaAdmin: true,
 	})
-	ReqSignedIn            = Auth(&AuthOptions{ReqSignedIn: true})
-	ReqSignedInNoAnonymous = Auth(&AuthOptions{ReqSignedIn: true, ReqNoAnonynmous: true})
+	ReqSignedIn = Auth(&AuthOptions{ReqSignedIn: true})
+	// ReqSignedInNoAnonymous guards the routes of the signed in user, which don't check permissions
+	ReqSignedInNoAnonymous = Auth(&AuthOptions{ReqSignedIn: true, ReqNoAnonynmous: true, ReqNoExchangedToken: true})
 	ReqEditorRole          = RoleAuth(org.RoleEditor, org.RoleAdmin)
 	ReqOrgAdmin            = RoleAuth(org.RoleAdmin)
 )
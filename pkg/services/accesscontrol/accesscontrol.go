@@ -167,6 +167,20 @@ func GroupScopesByAction(permissions []Permission) map[string][]string {
 	return m
 }
 
+// UngroupScopesByAction is the inverse of GroupScopesByAction
+func UngroupScopesByAction(m map[string][]string) []Permission {
+	permissions := make([]Permission, 0, len(m))
+	for action, scopes := range m {
+		if len(scopes) == 0 {
+			permissions = append(permissions, Permission{Action: action})
+		}
+		for _, scope := range scopes {
+			permissions = append(permissions, Permission{Action: action, Scope: scope})
+		}
+	}
+	return permissions
+}
+
 // Reduce will reduce a list of permissions to its minimal form, grouping scopes by action
 func Reduce(ps []Permission) map[string][]string {
 	reduced := make(map[string][]string)
@@ -364,6 +378,10 @@ func ManagedBuiltInRoleName(builtInRole string) string {
 func GetOrgRoles(user identity.Requester) []string {
 	roles := []string{string(user.GetOrgRole())}
 
+	if namespace, _ := user.GetNamespacedID(); namespace == identity.NamespaceAnonymous {
+		roles = append(roles, RoleAnonymous)
+	}
+
 	if user.GetIsGrafanaAdmin() {
 		if user.GetOrgID() == GlobalOrgID {
 			// A server admin is the admin of the global organization
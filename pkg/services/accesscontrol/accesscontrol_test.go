@@ -6,6 +6,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+
 	// this import is needed for github.com/grafana/grafana/pkg/web hack_wrap to work
 	_ "github.com/grafana/grafana/pkg/api/response"
 )
@@ -333,3 +336,20 @@ func Test_intersectScopes(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOrgRoles(t *testing.T) {
+	t.Run("should return org role for users", func(t *testing.T) {
+		roles := GetOrgRoles(&user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+		require.Equal(t, []string{string(org.RoleViewer)}, roles)
+	})
+
+	t.Run("should include Grafana Admin for server admins", func(t *testing.T) {
+		roles := GetOrgRoles(&user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer, IsGrafanaAdmin: true})
+		require.Equal(t, []string{string(org.RoleViewer), RoleGrafanaAdmin}, roles)
+	})
+
+	t.Run("should include Anonymous for anonymous visitors", func(t *testing.T) {
+		roles := GetOrgRoles(&user.SignedInUser{OrgID: 1, OrgRole: org.RoleNone, IsAnonymous: true})
+		require.Equal(t, []string{string(org.RoleNone), RoleAnonymous}, roles)
+	})
+}
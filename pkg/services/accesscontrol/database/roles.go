@@ -0,0 +1,227 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// GetCustomRoles returns the custom roles of an organization, including global custom roles, with their permissions.
+func (s *AccessControlStore) GetCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		roles, err = findRoles(sess, "(org_id = ? OR org_id = ?) AND name LIKE ?", orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%")
+		return err
+	})
+	return roles, err
+}
+
+// GetCustomRole returns a custom role, visible in the organization, by uid.
+func (s *AccessControlStore) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	var role *accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		roles, err := findRoles(sess, "(org_id = ? OR org_id = ?) AND name LIKE ? AND uid = ?", orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%", uid)
+		if err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return accesscontrol.ErrRoleNotFound
+		}
+		role = roles[0]
+		return nil
+	})
+	return role, err
+}
+
+// CustomRoleExists checks if a role uses the uid, in any organization, or the name in the organization.
+func (s *AccessControlStore) CustomRoleExists(ctx context.Context, orgID int64, uid, name string) (bool, error) {
+	var exists bool
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		if uid != "" {
+			if exists, err = sess.Where("uid = ?", uid).Exist(&accesscontrol.Role{}); err != nil || exists {
+				return err
+			}
+		}
+		if name != "" {
+			exists, err = sess.Where("org_id = ? AND name = ?", orgID, name).Exist(&accesscontrol.Role{})
+		}
+		return err
+	})
+	return exists, err
+}
+
+// SaveCustomRole creates or updates a role, matched by uid, and replaces its permissions.
+func (s *AccessControlStore) SaveCustomRole(ctx context.Context, role accesscontrol.RoleDTO) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		now := time.Now()
+		r := role.Role()
+		r.Created = now
+		r.Updated = now
+
+		stored, err := s.saveRole(ctx, sess, &r)
+		if err != nil {
+			return err
+		}
+
+		permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			p.Kind, p.Attribute, p.Identifier = p.SplitScope()
+			permissions = append(permissions, p)
+		}
+		if err := s.savePermissions(ctx, sess, stored.ID, permissions); err != nil {
+			return err
+		}
+
+		roles, err := findRoles(sess, "id = ?", stored.ID)
+		if err != nil {
+			return err
+		}
+		result = roles[0]
+		return nil
+	})
+	return result, err
+}
+
+// DeleteCustomRole removes a role, its permissions and all its assignments.
+func (s *AccessControlStore) DeleteCustomRole(ctx context.Context, roleID int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, table := range []string{"user_role", "team_role", "builtin_role", "permission"} {
+			if _, err := sess.Exec("DELETE FROM "+table+" WHERE role_id = ?", roleID); err != nil {
+				return err
+			}
+		}
+		_, err := sess.Exec("DELETE FROM role WHERE id = ?", roleID)
+		return err
+	})
+}
+
+// CountRoleAssignments returns the number of users and teams a role is assigned to.
+func (s *AccessControlStore) CountRoleAssignments(ctx context.Context, roleID int64) (int64, error) {
+	var total int64
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		users, err := sess.Where("role_id = ?", roleID).Count(&accesscontrol.UserRole{})
+		if err != nil {
+			return err
+		}
+		teams, err := sess.Where("role_id = ?", roleID).Count(&accesscontrol.TeamRole{})
+		if err != nil {
+			return err
+		}
+		total = users + teams
+		return nil
+	})
+	return total, err
+}
+
+// GetUserCustomRoles returns the custom roles assigned to a user in an organization.
+func (s *AccessControlStore) GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		roles, err = findRoles(sess, "name LIKE ? AND id IN (SELECT role_id FROM user_role WHERE user_id = ? AND (org_id = ? OR org_id = ?))",
+			accesscontrol.CustomRolePrefix+"%", userID, orgID, accesscontrol.GlobalOrgID)
+		return err
+	})
+	return roles, err
+}
+
+// AddUserRole assigns a role to a user in an organization. Assigning a role twice is a no-op.
+func (s *AccessControlStore) AddUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, roleID).Exist(&accesscontrol.UserRole{})
+		if err != nil || has {
+			return err
+		}
+		_, err = sess.Insert(&accesscontrol.UserRole{OrgID: orgID, UserID: userID, RoleID: roleID, Created: time.Now()})
+		return err
+	})
+}
+
+// RemoveUserRole removes a role assignment from a user in an organization.
+func (s *AccessControlStore) RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_role WHERE org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, roleID)
+		return err
+	})
+}
+
+// GetTeamCustomRoles returns the custom roles assigned to a team.
+func (s *AccessControlStore) GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		roles, err = findRoles(sess, "name LIKE ? AND id IN (SELECT role_id FROM team_role WHERE team_id = ? AND org_id = ?)",
+			accesscontrol.CustomRolePrefix+"%", teamID, orgID)
+		return err
+	})
+	return roles, err
+}
+
+// AddTeamRole assigns a role to a team. Assigning a role twice is a no-op.
+func (s *AccessControlStore) AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, roleID).Exist(&accesscontrol.TeamRole{})
+		if err != nil || has {
+			return err
+		}
+		_, err = sess.Insert(&accesscontrol.TeamRole{OrgID: orgID, TeamID: teamID, RoleID: roleID, Created: time.Now()})
+		return err
+	})
+}
+
+// RemoveTeamRole removes a role assignment from a team.
+func (s *AccessControlStore) RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM team_role WHERE org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, roleID)
+		return err
+	})
+}
+
+// findRoles returns the roles matching the condition with their permissions.
+func findRoles(sess *db.Session, cond string, args ...any) ([]*accesscontrol.RoleDTO, error) {
+	var stored []accesscontrol.Role
+	if err := sess.Table("role").Where(cond, args...).Asc("id").Find(&stored); err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return []*accesscontrol.RoleDTO{}, nil
+	}
+
+	ids := make([]int64, 0, len(stored))
+	for i := range stored {
+		ids = append(ids, stored[i].ID)
+	}
+
+	var permissions []accesscontrol.Permission
+	if err := sess.In("role_id", ids).Asc("id").Find(&permissions); err != nil {
+		return nil, err
+	}
+	byRole := make(map[int64][]accesscontrol.Permission, len(stored))
+	for _, p := range permissions {
+		byRole[p.RoleID] = append(byRole[p.RoleID], p)
+	}
+
+	roles := make([]*accesscontrol.RoleDTO, 0, len(stored))
+	for _, r := range stored {
+		roles = append(roles, &accesscontrol.RoleDTO{
+			ID:          r.ID,
+			OrgID:       r.OrgID,
+			Version:     r.Version,
+			UID:         r.UID,
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			Description: r.Description,
+			Group:       r.Group,
+			Hidden:      r.Hidden,
+			Permissions: byRole[r.ID],
+			Created:     r.Created,
+			Updated:     r.Updated,
+		})
+	}
+	return roles, nil
+}
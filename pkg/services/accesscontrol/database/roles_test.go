@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestIntegrationAccessControlStore_CustomRoles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	store, _, userService, teamService, _ := setupTestEnv(t)
+	usr, tm := createUserAndTeam(t, userService, teamService, 1)
+
+	role, err := store.SaveCustomRole(ctx, accesscontrol.RoleDTO{
+		OrgID:   1,
+		UID:     "alert_editor",
+		Name:    "custom:alerting:editor",
+		Version: 1,
+		Permissions: []accesscontrol.Permission{
+			{Action: accesscontrol.ActionAlertingRuleRead, Scope: "folders:uid:sre"},
+			{Action: accesscontrol.ActionAlertingRuleUpdate, Scope: "folders:uid:sre"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, role.Permissions, 2)
+
+	global, err := store.SaveCustomRole(ctx, accesscontrol.RoleDTO{
+		OrgID:       accesscontrol.GlobalOrgID,
+		UID:         "global_reader",
+		Name:        "custom:global:reader",
+		Version:     1,
+		Permissions: []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll}},
+	})
+	require.NoError(t, err)
+
+	t.Run("should list org and global custom roles", func(t *testing.T) {
+		roles, err := store.GetCustomRoles(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, roles, 2)
+
+		roles, err = store.GetCustomRoles(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		assert.Equal(t, global.UID, roles[0].UID)
+
+		_, err = store.GetCustomRole(ctx, 2, role.UID)
+		assert.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+
+	t.Run("should check uid and name usage", func(t *testing.T) {
+		exists, err := store.CustomRoleExists(ctx, 2, role.UID, "")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = store.CustomRoleExists(ctx, 2, "", role.Name)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("should replace permissions on update", func(t *testing.T) {
+		updated, err := store.SaveCustomRole(ctx, accesscontrol.RoleDTO{
+			ID:          role.ID,
+			OrgID:       1,
+			UID:         role.UID,
+			Name:        role.Name,
+			Version:     2,
+			Permissions: []accesscontrol.Permission{{Action: accesscontrol.ActionAlertingRuleRead, Scope: "folders:uid:sre"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+		require.Len(t, updated.Permissions, 1)
+		assert.Equal(t, "folders", updated.Permissions[0].Kind)
+	})
+
+	t.Run("should resolve permissions of assigned custom roles", func(t *testing.T) {
+		require.NoError(t, store.AddUserRole(ctx, 1, usr.ID, global.ID))
+		require.NoError(t, store.AddUserRole(ctx, 1, usr.ID, global.ID))
+		require.NoError(t, store.AddTeamRole(ctx, 1, tm.ID, role.ID))
+
+		userRoles, err := store.GetUserCustomRoles(ctx, 1, usr.ID)
+		require.NoError(t, err)
+		assert.Len(t, userRoles, 1)
+
+		permissions, err := store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
+			OrgID:        1,
+			UserID:       usr.ID,
+			TeamIDs:      []int64{tm.ID},
+			RolePrefixes: []string{accesscontrol.CustomRolePrefix},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+			{Action: accesscontrol.ActionAlertingRuleRead, Scope: "folders:uid:sre"},
+		}, permissions)
+
+		count, err := store.CountRoleAssignments(ctx, role.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should remove assignments", func(t *testing.T) {
+		require.NoError(t, store.RemoveUserRole(ctx, 1, usr.ID, global.ID))
+
+		userRoles, err := store.GetUserCustomRoles(ctx, 1, usr.ID)
+		require.NoError(t, err)
+		assert.Empty(t, userRoles)
+	})
+
+	t.Run("should delete role with its assignments", func(t *testing.T) {
+		require.NoError(t, store.DeleteCustomRole(ctx, role.ID))
+
+		teamRoles, err := store.GetTeamCustomRoles(ctx, 1, tm.ID)
+		require.NoError(t, err)
+		assert.Empty(t, teamRoles)
+
+		_, err = store.GetCustomRole(ctx, 1, role.UID)
+		assert.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+}
@@ -132,6 +132,10 @@ func (r *RoleDTO) IsBasic() bool {
 	return strings.HasPrefix(r.Name, BasicRolePrefix) || strings.HasPrefix(r.UID, BasicRoleUIDPrefix)
 }
 
+func (r *RoleDTO) IsCustom() bool {
+	return strings.HasPrefix(r.Name, CustomRolePrefix)
+}
+
 func (r *RoleDTO) IsExternalService() bool {
 	return strings.HasPrefix(r.Name, ExternalServiceRolePrefix) || strings.HasPrefix(r.UID, ExternalServiceRoleUIDPrefix)
 }
@@ -324,6 +328,9 @@ const (
 	GlobalOrgID      = 0
 	GeneralFolderUID = "general"
 	RoleGrafanaAdmin = "Grafana Admin"
+	// RoleAnonymous is the built-in role held by anonymous visitors, it can be granted access to
+	// individual resources through resource permissions.
+	RoleAnonymous = "Anonymous"
 
 	// Permission actions
 
@@ -379,6 +386,19 @@ const (
 	// Server actions
 	ActionServerStatsRead = "server.stats:read"
 
+	// Roles actions
+	ActionRolesRead   = "roles:read"
+	ActionRolesWrite  = "roles:write"
+	ActionRolesDelete = "roles:delete"
+
+	ActionUsersRolesRead   = "users.roles:read"
+	ActionUsersRolesAdd    = "users.roles:add"
+	ActionUsersRolesRemove = "users.roles:remove"
+
+	ActionTeamsRolesRead   = "teams.roles:read"
+	ActionTeamsRolesAdd    = "teams.roles:add"
+	ActionTeamsRolesRemove = "teams.roles:remove"
+
 	// Settings actions
 	ActionSettingsRead  = "settings:read"
 	ActionSettingsWrite = "settings:write"
@@ -396,6 +416,10 @@ const (
 	ScopeUsersAll    = "users:*"
 	ScopeUsersPrefix = "users:id:"
 
+	// Roles scope
+	ScopeRolesAll    = "roles:*"
+	ScopeRolesPrefix = "roles:uid:"
+
 	// Settings scope
 	ScopeSettingsAll  = "settings:*"
 	ScopeSettingsSAML = "settings:auth.saml:*"
@@ -172,15 +172,17 @@ func ProvideDashboardPermissions(
 			Teams:           true,
 			BuiltInRoles:    true,
 			ServiceAccounts: true,
+			Anonymous:       true,
 		},
 		PermissionsToActions: map[string][]string{
 			"View":  DashboardViewActions,
 			"Edit":  DashboardEditActions,
 			"Admin": DashboardAdminActions,
 		},
-		ReaderRoleName: "Dashboard permission reader",
-		WriterRoleName: "Dashboard permission writer",
-		RoleGroup:      "Dashboards",
+		AnonymousPermissions: []string{"View"},
+		ReaderRoleName:       "Dashboard permission reader",
+		WriterRoleName:       "Dashboard permission writer",
+		RoleGroup:            "Dashboards",
 	}
 
 	srv, err := resourcepermissions.New(options, features, router, license, ac, service, sql, teamService, userService)
@@ -237,15 +239,17 @@ func ProvideFolderPermissions(
 			Teams:           true,
 			BuiltInRoles:    true,
 			ServiceAccounts: true,
+			Anonymous:       true,
 		},
 		PermissionsToActions: map[string][]string{
 			"View":  append(DashboardViewActions, FolderViewActions...),
 			"Edit":  append(DashboardEditActions, FolderEditActions...),
 			"Admin": append(DashboardAdminActions, FolderAdminActions...),
 		},
-		ReaderRoleName: "Folder permission reader",
-		WriterRoleName: "Folder permission writer",
-		RoleGroup:      "Folders",
+		AnonymousPermissions: []string{"View"},
+		ReaderRoleName:       "Folder permission reader",
+		WriterRoleName:       "Folder permission writer",
+		RoleGroup:            "Folders",
 	}
 	srv, err := resourcepermissions.New(options, features, router, license, accesscontrol, service, sql, teamService, userService)
 	if err != nil {
@@ -0,0 +1,333 @@
+package ossaccesscontrol
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/database"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const maxRoleNameLength = 190
+
+var (
+	ErrRoleNotFound      = errutil.NotFound("accesscontrol.role-not-found")
+	ErrRoleInvalid       = errutil.BadRequest("accesscontrol.role-invalid")
+	ErrRoleAlreadyExists = errutil.Conflict("accesscontrol.role-already-exists")
+	ErrRoleVersion       = errutil.Conflict("accesscontrol.role-version")
+	ErrRoleAssigned      = errutil.BadRequest("accesscontrol.role-assigned")
+	ErrRoleAssignee      = errutil.NotFound("accesscontrol.role-assignee-not-found")
+)
+
+type roleStore interface {
+	GetCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error)
+	GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error)
+	CustomRoleExists(ctx context.Context, orgID int64, uid, name string) (bool, error)
+	SaveCustomRole(ctx context.Context, role accesscontrol.RoleDTO) (*accesscontrol.RoleDTO, error)
+	DeleteCustomRole(ctx context.Context, roleID int64) error
+	CountRoleAssignments(ctx context.Context, roleID int64) (int64, error)
+	GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error)
+	AddUserRole(ctx context.Context, orgID, userID, roleID int64) error
+	RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error
+	GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error)
+	AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error
+	RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error
+}
+
+// CreateRoleCommand is the payload to create a custom role.
+type CreateRoleCommand struct {
+	UID         string                     `json:"uid"`
+	Name        string                     `json:"name"`
+	DisplayName string                     `json:"displayName"`
+	Description string                     `json:"description"`
+	Group       string                     `json:"group"`
+	Global      bool                       `json:"global"`
+	Version     int64                      `json:"version"`
+	Permissions []accesscontrol.Permission `json:"permissions"`
+}
+
+// UpdateRoleCommand is the payload to update a custom role. When set, Version has
+// to be greater than the stored version, otherwise the stored version is incremented.
+type UpdateRoleCommand struct {
+	Name        string                     `json:"name"`
+	DisplayName string                     `json:"displayName"`
+	Description string                     `json:"description"`
+	Group       string                     `json:"group"`
+	Version     int64                      `json:"version"`
+	Permissions []accesscontrol.Permission `json:"permissions"`
+}
+
+// RoleService manages custom roles and their assignments to users, service accounts and teams.
+// Permissions of assigned custom roles are resolved by accesscontrol.Service together with
+// basic and managed role permissions.
+type RoleService struct {
+	ac          accesscontrol.AccessControl
+	acService   accesscontrol.Service
+	store       roleStore
+	userService user.Service
+	orgService  org.Service
+	teamService team.Service
+	log         log.Logger
+}
+
+func ProvideRoleService(sql db.DB, router routing.RouteRegister, ac accesscontrol.AccessControl, acService accesscontrol.Service,
+	userService user.Service, orgService org.Service, teamService team.Service) *RoleService {
+	s := &RoleService{
+		ac:          ac,
+		acService:   acService,
+		store:       database.ProvideService(sql),
+		userService: userService,
+		orgService:  orgService,
+		teamService: teamService,
+		log:         log.New("accesscontrol.roles"),
+	}
+	s.registerAPIEndpoints(router)
+	return s
+}
+
+// GetRoles returns the custom roles of the organization, including global custom roles.
+func (s *RoleService) GetRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.store.GetCustomRoles(ctx, orgID)
+}
+
+// GetRole returns a custom role of the organization, or a global custom role, by uid.
+func (s *RoleService) GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	role, err := s.store.GetCustomRole(ctx, orgID, uid)
+	if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+		return nil, ErrRoleNotFound.Errorf("role %s not found", uid)
+	}
+	return role, err
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, orgID int64, cmd CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	permissions, err := validateRole(cmd.Name, cmd.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(cmd.UID) {
+		return nil, ErrRoleInvalid.Errorf("invalid role uid %q", cmd.UID)
+	}
+	if cmd.Global {
+		orgID = accesscontrol.GlobalOrgID
+	}
+	if cmd.Version <= 0 {
+		cmd.Version = 1
+	}
+
+	exists, err := s.store.CustomRoleExists(ctx, orgID, cmd.UID, cmd.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrRoleAlreadyExists.Errorf("a role with uid %q or name %q already exists", cmd.UID, cmd.Name)
+	}
+
+	return s.store.SaveCustomRole(ctx, accesscontrol.RoleDTO{
+		OrgID:       orgID,
+		UID:         cmd.UID,
+		Version:     cmd.Version,
+		Name:        cmd.Name,
+		DisplayName: cmd.DisplayName,
+		Description: cmd.Description,
+		Group:       cmd.Group,
+		Permissions: permissions,
+	})
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, orgID int64, uid string, cmd UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	stored, err := s.GetRole(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := validateRole(cmd.Name, cmd.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Version == 0 {
+		cmd.Version = stored.Version + 1
+	} else if cmd.Version <= stored.Version {
+		return nil, ErrRoleVersion.Errorf("role version %d must be greater than the current version %d", cmd.Version, stored.Version)
+	}
+
+	if cmd.Name != stored.Name {
+		exists, err := s.store.CustomRoleExists(ctx, stored.OrgID, "", cmd.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrRoleAlreadyExists.Errorf("a role with name %q already exists", cmd.Name)
+		}
+	}
+
+	return s.store.SaveCustomRole(ctx, accesscontrol.RoleDTO{
+		ID:          stored.ID,
+		OrgID:       stored.OrgID,
+		UID:         stored.UID,
+		Version:     cmd.Version,
+		Name:        cmd.Name,
+		DisplayName: cmd.DisplayName,
+		Description: cmd.Description,
+		Group:       cmd.Group,
+		Permissions: permissions,
+	})
+}
+
+// DeleteRole removes a custom role. Roles that are still assigned are only removed, together with
+// their assignments, when force is set.
+func (s *RoleService) DeleteRole(ctx context.Context, orgID int64, uid string, force bool) error {
+	stored, err := s.GetRole(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		count, err := s.store.CountRoleAssignments(ctx, stored.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRoleAssigned.Errorf("role %s is assigned to %d users or teams", uid, count)
+		}
+	}
+
+	return s.store.DeleteCustomRole(ctx, stored.ID)
+}
+
+func (s *RoleService) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.store.GetUserCustomRoles(ctx, orgID, userID)
+}
+
+// AddUserRole assigns a custom role to a user or service account of the organization.
+func (s *RoleService) AddUserRole(ctx context.Context, orgID, userID int64, uid string) error {
+	role, err := s.GetRole(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return ErrRoleAssignee.Errorf("user %d not found", userID)
+		}
+		return err
+	}
+	member, err := s.isOrgMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrRoleAssignee.Errorf("user %d is not a member of organization %d", userID, orgID)
+	}
+
+	if err := s.store.AddUserRole(ctx, orgID, userID, role.ID); err != nil {
+		return err
+	}
+	s.clearUserPermissionCache(orgID, usr)
+	return nil
+}
+
+func (s *RoleService) RemoveUserRole(ctx context.Context, orgID, userID int64, uid string) error {
+	role, err := s.GetRole(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+	if err := s.store.RemoveUserRole(ctx, orgID, userID, role.ID); err != nil {
+		return err
+	}
+	if usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID}); err == nil {
+		s.clearUserPermissionCache(orgID, usr)
+	}
+	return nil
+}
+
+func (s *RoleService) isOrgMember(ctx context.Context, orgID, userID int64) (bool, error) {
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		if o.OrgID == orgID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clearUserPermissionCache clears the cached permissions of the user in the organization, so that a change of its
+// roles applies to its next request.
+func (s *RoleService) clearUserPermissionCache(orgID int64, usr *user.User) {
+	s.acService.ClearUserPermissionCache(&user.SignedInUser{
+		UserID:           usr.ID,
+		OrgID:            orgID,
+		IsServiceAccount: usr.IsServiceAccount,
+	})
+}
+
+func (s *RoleService) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.store.GetTeamCustomRoles(ctx, orgID, teamID)
+}
+
+// AddTeamRole assigns a custom role to a team of the organization.
+func (s *RoleService) AddTeamRole(ctx context.Context, orgID, teamID int64, uid string) error {
+	role, err := s.GetRole(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+	if _, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: orgID, ID: teamID}); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return ErrRoleAssignee.Errorf("team %d not found", teamID)
+		}
+		return err
+	}
+	return s.store.AddTeamRole(ctx, orgID, teamID, role.ID)
+}
+
+func (s *RoleService) RemoveTeamRole(ctx context.Context, orgID, teamID int64, uid string) error {
+	role, err := s.GetRole(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+	return s.store.RemoveTeamRole(ctx, orgID, teamID, role.ID)
+}
+
+// validateRole checks the name and permissions of a custom role and returns the deduplicated permissions.
+func validateRole(name string, permissions []accesscontrol.Permission) ([]accesscontrol.Permission, error) {
+	if !strings.HasPrefix(name, accesscontrol.CustomRolePrefix) || len(name) == len(accesscontrol.CustomRolePrefix) {
+		return nil, ErrRoleInvalid.Errorf("role name %q must be prefixed with %q", name, accesscontrol.CustomRolePrefix)
+	}
+	if len(name) > maxRoleNameLength {
+		return nil, ErrRoleInvalid.Errorf("role name must be at most %d characters", maxRoleNameLength)
+	}
+
+	type key struct{ action, scope string }
+	seen := make(map[key]bool, len(permissions))
+	result := make([]accesscontrol.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		if p.Action == "" {
+			return nil, ErrRoleInvalid.Errorf("role %s has a permission without action", name)
+		}
+		if p.Scope != "" && !accesscontrol.ValidateScope(p.Scope) {
+			return nil, ErrRoleInvalid.Errorf("role %s has an invalid scope %q", name, p.Scope)
+		}
+		k := key{p.Action, p.Scope}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, accesscontrol.Permission{Action: p.Action, Scope: p.Scope})
+	}
+	return result, nil
+}
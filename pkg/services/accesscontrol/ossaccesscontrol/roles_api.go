@@ -0,0 +1,454 @@
+package ossaccesscontrol
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/middleware/requestmeta"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *RoleService) registerAPIEndpoints(router routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.ac)
+	roleUIDScope := accesscontrol.Scope("roles", "uid", accesscontrol.Parameter(":roleUID"))
+	userIDScope := accesscontrol.Scope("users", "id", accesscontrol.Parameter(":userId"))
+	teamIDScope := accesscontrol.Scope("teams", "id", accesscontrol.Parameter(":teamId"))
+
+	router.Group("/api/access-control", func(rr routing.RouteRegister) {
+		rr.Get("/roles", authorize(accesscontrol.EvalPermission(accesscontrol.ActionRolesRead)), routing.Wrap(s.listRoles))
+		rr.Get("/roles/:roleUID", authorize(accesscontrol.EvalPermission(accesscontrol.ActionRolesRead, roleUIDScope)), routing.Wrap(s.getRole))
+		rr.Post("/roles", authorize(accesscontrol.EvalPermission(accesscontrol.ActionRolesWrite)), routing.Wrap(s.createRole))
+		rr.Put("/roles/:roleUID", authorize(accesscontrol.EvalPermission(accesscontrol.ActionRolesWrite, roleUIDScope)), routing.Wrap(s.updateRole))
+		rr.Delete("/roles/:roleUID", authorize(accesscontrol.EvalPermission(accesscontrol.ActionRolesDelete, roleUIDScope)), routing.Wrap(s.deleteRole))
+
+		rr.Get("/users/:userId/roles", authorize(accesscontrol.EvalPermission(accesscontrol.ActionUsersRolesRead, userIDScope)), routing.Wrap(s.listUserRoles))
+		rr.Post("/users/:userId/roles", authorize(accesscontrol.EvalPermission(accesscontrol.ActionUsersRolesAdd, userIDScope)), routing.Wrap(s.addUserRole))
+		rr.Delete("/users/:userId/roles/:roleUID", authorize(accesscontrol.EvalPermission(accesscontrol.ActionUsersRolesRemove, userIDScope)), routing.Wrap(s.removeUserRole))
+
+		rr.Get("/teams/:teamId/roles", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRolesRead, teamIDScope)), routing.Wrap(s.listTeamRoles))
+		rr.Post("/teams/:teamId/roles", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRolesAdd, teamIDScope)), routing.Wrap(s.addTeamRole))
+		rr.Delete("/teams/:teamId/roles/:roleUID", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRolesRemove, teamIDScope)), routing.Wrap(s.removeTeamRole))
+	}, middleware.ReqSignedIn, requestmeta.SetOwner(requestmeta.TeamAuth))
+}
+
+// AddRoleAssignmentCommand is the payload to assign a custom role to a user or a team.
+type AddRoleAssignmentCommand struct {
+	RoleUID string `json:"roleUid"`
+}
+
+// swagger:route GET /access-control/roles access_control listCustomRoles
+//
+// Lists the custom roles of the organization, including global custom roles.
+//
+// You need to have a permission with action `roles:read`.
+//
+// Responses:
+// 200: listCustomRolesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *RoleService) listRoles(c *contextmodel.ReqContext) response.Response {
+	roles, err := s.GetRoles(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list roles", err)
+	}
+	return response.JSON(http.StatusOK, roles)
+}
+
+// swagger:route GET /access-control/roles/{roleUID} access_control getCustomRole
+//
+// Returns a custom role with its permissions.
+//
+// You need to have a permission with action `roles:read` and scope `roles:uid:<uid>`.
+//
+// Responses:
+// 200: customRoleResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *RoleService) getRole(c *contextmodel.ReqContext) response.Response {
+	role, err := s.GetRole(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":roleUID"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+	return response.JSON(http.StatusOK, role)
+}
+
+// swagger:route POST /access-control/roles access_control createCustomRole
+//
+// Creates a custom role. Custom role names must be prefixed with `custom:`.
+//
+// You need to have a permission with action `roles:write`, and to have all the permissions granted by the role.
+// Only Grafana server administrators can create global roles.
+//
+// Responses:
+// 201: customRoleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *RoleService) createRole(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	if cmd.Global && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return response.Error(http.StatusForbidden, "Only server administrators can create global roles", nil)
+	}
+	if resp := s.checkDelegation(c, cmd.Permissions); resp != nil {
+		return resp
+	}
+
+	role, err := s.CreateRole(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create role", err)
+	}
+	return response.JSON(http.StatusCreated, role)
+}
+
+// swagger:route PUT /access-control/roles/{roleUID} access_control updateCustomRole
+//
+// Updates a custom role and replaces its permissions.
+//
+// The version must be greater than the current version of the role. If omitted, the version is incremented.
+// You need to have a permission with action `roles:write` and scope `roles:uid:<uid>`, and to have all the permissions granted by the role.
+//
+// Responses:
+// 200: customRoleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *RoleService) updateRole(c *contextmodel.ReqContext) response.Response {
+	cmd := UpdateRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	uid := web.Params(c.Req)[":roleUID"]
+	stored, err := s.GetRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+	if stored.Global() && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return response.Error(http.StatusForbidden, "Only server administrators can update global roles", nil)
+	}
+	if resp := s.checkDelegation(c, cmd.Permissions); resp != nil {
+		return resp
+	}
+
+	role, err := s.UpdateRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid, cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update role", err)
+	}
+	return response.JSON(http.StatusOK, role)
+}
+
+// swagger:route DELETE /access-control/roles/{roleUID} access_control deleteCustomRole
+//
+// Deletes a custom role. A role that is assigned to users or teams is only deleted, with its assignments, when `force` is set.
+//
+// You need to have a permission with action `roles:delete` and scope `roles:uid:<uid>`.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *RoleService) deleteRole(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":roleUID"]
+	stored, err := s.GetRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+	if stored.Global() && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return response.Error(http.StatusForbidden, "Only server administrators can delete global roles", nil)
+	}
+
+	if err := s.DeleteRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid, c.QueryBool("force")); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete role", err)
+	}
+	return response.Success("Role deleted")
+}
+
+// swagger:route GET /access-control/users/{userId}/roles access_control listUserCustomRoles
+//
+// Lists the custom roles assigned to a user or service account in the organization.
+//
+// You need to have a permission with action `users.roles:read` and scope `users:id:<user ID>`.
+//
+// Responses:
+// 200: listCustomRolesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *RoleService) listUserRoles(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	roles, err := s.GetUserRoles(c.Req.Context(), c.SignedInUser.GetOrgID(), userID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list user roles", err)
+	}
+	return response.JSON(http.StatusOK, roles)
+}
+
+// swagger:route POST /access-control/users/{userId}/roles access_control addUserCustomRole
+//
+// Assigns a custom role to a user or service account in the organization.
+//
+// You need to have a permission with action `users.roles:add` and scope `users:id:<user ID>`, and to have all the permissions granted by the role.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *RoleService) addUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+	cmd := AddRoleAssignmentCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	if resp := s.checkRoleDelegation(c, cmd.RoleUID); resp != nil {
+		return resp
+	}
+	if err := s.AddUserRole(c.Req.Context(), c.SignedInUser.GetOrgID(), userID, cmd.RoleUID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to assign role", err)
+	}
+	return response.Success("Role added to the user")
+}
+
+// swagger:route DELETE /access-control/users/{userId}/roles/{roleUID} access_control removeUserCustomRole
+//
+// Removes a custom role assignment from a user or service account in the organization.
+//
+// You need to have a permission with action `users.roles:remove` and scope `users:id:<user ID>`, and to have all the permissions granted by the role.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *RoleService) removeUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	uid := web.Params(c.Req)[":roleUID"]
+	if resp := s.checkRoleDelegation(c, uid); resp != nil {
+		return resp
+	}
+	if err := s.RemoveUserRole(c.Req.Context(), c.SignedInUser.GetOrgID(), userID, uid); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove role", err)
+	}
+	return response.Success("Role removed from the user")
+}
+
+// swagger:route GET /access-control/teams/{teamId}/roles access_control listTeamCustomRoles
+//
+// Lists the custom roles assigned to a team.
+//
+// You need to have a permission with action `teams.roles:read` and scope `teams:id:<team ID>`.
+//
+// Responses:
+// 200: listCustomRolesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *RoleService) listTeamRoles(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	roles, err := s.GetTeamRoles(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list team roles", err)
+	}
+	return response.JSON(http.StatusOK, roles)
+}
+
+// swagger:route POST /access-control/teams/{teamId}/roles access_control addTeamCustomRole
+//
+// Assigns a custom role to a team.
+//
+// You need to have a permission with action `teams.roles:add` and scope `teams:id:<team ID>`, and to have all the permissions granted by the role.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *RoleService) addTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+	cmd := AddRoleAssignmentCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	if resp := s.checkRoleDelegation(c, cmd.RoleUID); resp != nil {
+		return resp
+	}
+	if err := s.AddTeamRole(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, cmd.RoleUID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to assign role", err)
+	}
+	return response.Success("Role added to the team")
+}
+
+// swagger:route DELETE /access-control/teams/{teamId}/roles/{roleUID} access_control removeTeamCustomRole
+//
+// Removes a custom role assignment from a team.
+//
+// You need to have a permission with action `teams.roles:remove` and scope `teams:id:<team ID>`, and to have all the permissions granted by the role.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *RoleService) removeTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	uid := web.Params(c.Req)[":roleUID"]
+	if resp := s.checkRoleDelegation(c, uid); resp != nil {
+		return resp
+	}
+	if err := s.RemoveTeamRole(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, uid); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove role", err)
+	}
+	return response.Success("Role removed from the team")
+}
+
+// checkRoleDelegation ensures the signed in user has all the permissions granted by the role.
+func (s *RoleService) checkRoleDelegation(c *contextmodel.ReqContext, uid string) response.Response {
+	role, err := s.GetRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+	return s.checkDelegation(c, role.Permissions)
+}
+
+// checkDelegation prevents privilege escalation: users can only create, update or assign roles
+// with permissions they have themselves.
+func (s *RoleService) checkDelegation(c *contextmodel.ReqContext, permissions []accesscontrol.Permission) response.Response {
+	for _, p := range permissions {
+		var evaluator accesscontrol.Evaluator
+		if p.Scope == "" {
+			evaluator = accesscontrol.EvalPermission(p.Action)
+		} else {
+			evaluator = accesscontrol.EvalPermission(p.Action, p.Scope)
+		}
+
+		ok, err := s.ac.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+		}
+		if !ok {
+			return response.Error(http.StatusForbidden, "Cannot delegate permissions the user does not have: "+evaluator.String(), nil)
+		}
+	}
+	return nil
+}
+
+// swagger:parameters getCustomRole updateCustomRole deleteCustomRole
+type CustomRoleParams struct {
+	// in:path
+	// required:true
+	RoleUID string `json:"roleUID"`
+}
+
+// swagger:parameters deleteCustomRole
+type DeleteCustomRoleParams struct {
+	// in:query
+	Force bool `json:"force"`
+}
+
+// swagger:parameters createCustomRole
+type CreateCustomRoleParams struct {
+	// in:body
+	// required:true
+	Body CreateRoleCommand `json:"body"`
+}
+
+// swagger:parameters updateCustomRole
+type UpdateCustomRoleParams struct {
+	// in:body
+	// required:true
+	Body UpdateRoleCommand `json:"body"`
+}
+
+// swagger:parameters listUserCustomRoles addUserCustomRole removeUserCustomRole
+type UserCustomRoleParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"userId"`
+}
+
+// swagger:parameters listTeamCustomRoles addTeamCustomRole removeTeamCustomRole
+type TeamCustomRoleParams struct {
+	// in:path
+	// required:true
+	TeamID int64 `json:"teamId"`
+}
+
+// swagger:parameters removeUserCustomRole removeTeamCustomRole
+type RemoveCustomRoleAssignmentParams struct {
+	// in:path
+	// required:true
+	RoleUID string `json:"roleUID"`
+}
+
+// swagger:parameters addUserCustomRole addTeamCustomRole
+type AddCustomRoleAssignmentParams struct {
+	// in:body
+	// required:true
+	Body AddRoleAssignmentCommand `json:"body"`
+}
+
+// swagger:response listCustomRolesResponse
+type ListCustomRolesResponse struct {
+	// in: body
+	Body []accesscontrol.RoleDTOStatic `json:"body"`
+}
+
+// swagger:response customRoleResponse
+type CustomRoleResponse struct {
+	// in: body
+	Body accesscontrol.RoleDTOStatic `json:"body"`
+}
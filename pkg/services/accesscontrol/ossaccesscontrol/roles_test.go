@@ -0,0 +1,177 @@
+package ossaccesscontrol
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+var alertRuleEditor = []accesscontrol.Permission{
+	{Action: accesscontrol.ActionAlertingRuleRead, Scope: "folders:uid:sre"},
+	{Action: accesscontrol.ActionAlertingRuleUpdate, Scope: "folders:uid:sre"},
+}
+
+func TestValidateRole(t *testing.T) {
+	t.Run("should require the custom prefix", func(t *testing.T) {
+		_, err := validateRole("fixed:alerting:editor", alertRuleEditor)
+		assert.ErrorIs(t, err, ErrRoleInvalid)
+
+		_, err = validateRole("custom:", alertRuleEditor)
+		assert.ErrorIs(t, err, ErrRoleInvalid)
+	})
+
+	t.Run("should reject invalid permissions", func(t *testing.T) {
+		_, err := validateRole("custom:editor", []accesscontrol.Permission{{Scope: "folders:*"}})
+		assert.ErrorIs(t, err, ErrRoleInvalid)
+
+		_, err = validateRole("custom:editor", []accesscontrol.Permission{{Action: "folders:read", Scope: "folders:uid*"}})
+		assert.ErrorIs(t, err, ErrRoleInvalid)
+	})
+
+	t.Run("should deduplicate permissions", func(t *testing.T) {
+		permissions, err := validateRole("custom:editor", append(alertRuleEditor, alertRuleEditor...))
+		require.NoError(t, err)
+		assert.Len(t, permissions, 2)
+	})
+}
+
+func TestIntegrationRoleService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	userService := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 2}}
+	orgService := &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1}}}
+	teamService := &teamtest.FakeService{ExpectedTeamDTO: &team.TeamDTO{ID: 3}}
+	acService := acmock.New()
+	s := ProvideRoleService(db.InitTestDB(t), routing.NewRouteRegister(), acimpl.ProvideAccessControl(setting.NewCfg()), acService, userService, orgService, teamService)
+
+	role, err := s.CreateRole(ctx, 1, CreateRoleCommand{Name: "custom:alerting:editor", Permissions: alertRuleEditor})
+	require.NoError(t, err)
+	assert.NotEmpty(t, role.UID)
+	assert.Equal(t, int64(1), role.Version)
+
+	t.Run("should not create a role with a used name", func(t *testing.T) {
+		_, err := s.CreateRole(ctx, 1, CreateRoleCommand{Name: role.Name, Permissions: alertRuleEditor})
+		assert.ErrorIs(t, err, ErrRoleAlreadyExists)
+	})
+
+	t.Run("should only update to a greater version", func(t *testing.T) {
+		_, err := s.UpdateRole(ctx, 1, role.UID, UpdateRoleCommand{Name: role.Name, Version: 1, Permissions: alertRuleEditor[:1]})
+		assert.ErrorIs(t, err, ErrRoleVersion)
+
+		updated, err := s.UpdateRole(ctx, 1, role.UID, UpdateRoleCommand{Name: role.Name, Permissions: alertRuleEditor[:1]})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Len(t, updated.Permissions, 1)
+	})
+
+	t.Run("should not find roles of another organization", func(t *testing.T) {
+		_, err := s.GetRole(ctx, 2, role.UID)
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+
+	t.Run("should assign roles to users and teams", func(t *testing.T) {
+		require.NoError(t, s.AddUserRole(ctx, 1, 2, role.UID))
+		require.NoError(t, s.AddTeamRole(ctx, 1, 3, role.UID))
+
+		require.Len(t, acService.Calls.ClearUserPermissionCache, 1, "the permissions of the user are reloaded")
+		cleared := acService.Calls.ClearUserPermissionCache[0].([]any)[0].(identity.Requester)
+		assert.Equal(t, "1-user-2", cleared.GetCacheKey())
+
+		roles, err := s.GetUserRoles(ctx, 1, 2)
+		require.NoError(t, err)
+		assert.Len(t, roles, 1)
+
+		roles, err = s.GetTeamRoles(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Len(t, roles, 1)
+	})
+
+	t.Run("should not assign roles to unknown users", func(t *testing.T) {
+		userService.ExpectedError = user.ErrUserNotFound
+		defer func() { userService.ExpectedError = nil }()
+
+		err := s.AddUserRole(ctx, 1, 4, role.UID)
+		assert.ErrorIs(t, err, ErrRoleAssignee)
+	})
+
+	t.Run("should not assign roles to users of other organizations", func(t *testing.T) {
+		orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 2}}
+		defer func() { orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1}} }()
+
+		err := s.AddUserRole(ctx, 1, 2, role.UID)
+		assert.ErrorIs(t, err, ErrRoleAssignee)
+	})
+
+	t.Run("should only delete assigned roles when forced", func(t *testing.T) {
+		err := s.DeleteRole(ctx, 1, role.UID, false)
+		assert.ErrorIs(t, err, ErrRoleAssigned)
+
+		require.NoError(t, s.DeleteRole(ctx, 1, role.UID, true))
+
+		roles, err := s.GetUserRoles(ctx, 1, 2)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+	})
+}
+
+func TestIntegrationRoleAPI_Delegation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	router := routing.NewRouteRegister()
+	ProvideRoleService(db.InitTestDB(t), router, acimpl.ProvideAccessControl(setting.NewCfg()), actest.FakeService{}, &usertest.FakeUserService{}, &orgtest.FakeOrgService{}, &teamtest.FakeService{})
+	server := webtest.NewServer(t, router)
+
+	body := `{"name": "custom:alerting:editor", "permissions": [{"action": "alert.rules:read", "scope": "folders:uid:sre"}]}`
+	create := func(permissions map[string][]string) int {
+		req := server.NewPostRequest("/api/access-control/roles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			UserID:      1,
+			OrgID:       1,
+			OrgRole:     org.RoleAdmin,
+			Permissions: map[int64]map[string][]string{1: permissions},
+		})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res.StatusCode
+	}
+
+	t.Run("should not create a role with permissions the user does not have", func(t *testing.T) {
+		code := create(map[string][]string{accesscontrol.ActionRolesWrite: {accesscontrol.ScopeRolesAll}})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("should create a role with permissions the user has", func(t *testing.T) {
+		code := create(map[string][]string{
+			accesscontrol.ActionRolesWrite:       {accesscontrol.ScopeRolesAll},
+			accesscontrol.ActionAlertingRuleRead: {"folders:*"},
+		})
+		assert.Equal(t, http.StatusCreated, code)
+	})
+}
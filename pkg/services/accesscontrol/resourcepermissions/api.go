@@ -61,6 +61,7 @@ type Assignments struct {
 	ServiceAccounts bool `json:"serviceAccounts"`
 	Teams           bool `json:"teams"`
 	BuiltInRoles    bool `json:"builtInRoles"`
+	Anonymous       bool `json:"anonymous"`
 }
 
 // swagger:response resourcePermissionsDescription
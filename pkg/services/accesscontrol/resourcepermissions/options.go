@@ -26,6 +26,9 @@ type Options struct {
 	// PermissionsToAction is a map of friendly named permissions and what access control actions they should generate.
 	// E.g. Edit permissions should generate dashboards:read, dashboards:write and dashboards:delete
 	PermissionsToActions map[string][]string
+	// AnonymousPermissions restricts the friendly named permissions that can be granted to anonymous visitors,
+	// if empty any permission can be granted when Assignments.Anonymous is set
+	AnonymousPermissions []string
 	// ReaderRoleName is the display name for the generated fixed reader role
 	ReaderRoleName string
 	// WriterRoleName is the display name for the generated fixed writer role
@@ -3,6 +3,7 @@ package resourcepermissions
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -190,7 +191,7 @@ func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, bui
 		return nil, err
 	}
 
-	if err := s.validateBuiltinRole(ctx, builtInRole); err != nil {
+	if err := s.validateBuiltinRole(ctx, builtInRole, permission); err != nil {
 		return nil, err
 	}
 
@@ -226,7 +227,7 @@ func (s *Service) SetPermissions(
 				return nil, err
 			}
 		} else {
-			if err := s.validateBuiltinRole(ctx, cmd.BuiltinRole); err != nil {
+			if err := s.validateBuiltinRole(ctx, cmd.BuiltinRole, cmd.Permission); err != nil {
 				return nil, err
 			}
 		}
@@ -314,7 +315,18 @@ func (s *Service) validateTeam(ctx context.Context, orgID, teamID int64) error {
 	return nil
 }
 
-func (s *Service) validateBuiltinRole(ctx context.Context, builtinRole string) error {
+func (s *Service) validateBuiltinRole(ctx context.Context, builtinRole, permission string) error {
+	if builtinRole == accesscontrol.RoleAnonymous {
+		if !s.options.Assignments.Anonymous {
+			return ErrInvalidAssignment
+		}
+		// removing the permission of anonymous visitors is always allowed
+		if permission != "" && len(s.options.AnonymousPermissions) > 0 && !slices.Contains(s.options.AnonymousPermissions, permission) {
+			return ErrInvalidPermission
+		}
+		return nil
+	}
+
 	if !s.options.Assignments.BuiltInRoles {
 		return ErrInvalidAssignment
 	}
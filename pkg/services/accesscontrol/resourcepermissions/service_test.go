@@ -205,6 +205,51 @@ func TestService_SetPermissions(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			desc: "should set permissions for anonymous",
+			options: Options{
+				Resource:    "dashboards",
+				Assignments: Assignments{Anonymous: true},
+				PermissionsToActions: map[string][]string{
+					"View": {"dashboards:read"},
+					"Edit": {"dashboards:read", "dashboards:write"},
+				},
+				AnonymousPermissions: []string{"View"},
+			},
+			commands: []accesscontrol.SetResourcePermissionCommand{
+				{BuiltinRole: accesscontrol.RoleAnonymous, Permission: "View"},
+			},
+		},
+		{
+			desc: "should return error for permission not allowed for anonymous",
+			options: Options{
+				Resource:    "dashboards",
+				Assignments: Assignments{Anonymous: true},
+				PermissionsToActions: map[string][]string{
+					"View": {"dashboards:read"},
+					"Edit": {"dashboards:read", "dashboards:write"},
+				},
+				AnonymousPermissions: []string{"View"},
+			},
+			commands: []accesscontrol.SetResourcePermissionCommand{
+				{BuiltinRole: accesscontrol.RoleAnonymous, Permission: "Edit"},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "should return error when anonymous assignments are disabled",
+			options: Options{
+				Resource:    "dashboards",
+				Assignments: Assignments{BuiltInRoles: true},
+				PermissionsToActions: map[string][]string{
+					"View": {"dashboards:read"},
+				},
+			},
+			commands: []accesscontrol.SetResourcePermissionCommand{
+				{BuiltinRole: accesscontrol.RoleAnonymous, Permission: "View"},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -16,6 +16,8 @@ const (
 	BasicRolePrefix    = "basic:"
 	BasicRoleUIDPrefix = "basic_"
 
+	CustomRolePrefix = "custom:"
+
 	ExternalServiceRolePrefix    = "extsvc:"
 	ExternalServiceRoleUIDPrefix = "extsvc_"
 
@@ -194,6 +196,60 @@ var (
 		}),
 	}
 
+	rolesReaderRole = RoleDTO{
+		Name:        "fixed:roles:reader",
+		DisplayName: "Role reader",
+		Description: "Read custom roles and the custom roles assigned to users and teams.",
+		Group:       "Roles",
+		Permissions: []Permission{
+			{
+				Action: ActionRolesRead,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionUsersRolesRead,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionTeamsRolesRead,
+				Scope:  ScopeTeamsAll,
+			},
+		},
+	}
+
+	rolesWriterRole = RoleDTO{
+		Name:        "fixed:roles:writer",
+		DisplayName: "Role writer",
+		Description: "Create, update and delete custom roles and assign them to users and teams.",
+		Group:       "Roles",
+		Permissions: ConcatPermissions(rolesReaderRole.Permissions, []Permission{
+			{
+				Action: ActionRolesWrite,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesDelete,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionUsersRolesAdd,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionUsersRolesRemove,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionTeamsRolesAdd,
+				Scope:  ScopeTeamsAll,
+			},
+			{
+				Action: ActionTeamsRolesRemove,
+				Scope:  ScopeTeamsAll,
+			},
+		}),
+	}
+
 	authenticationConfigWriterRole = RoleDTO{
 		Name:        "fixed:authentication.config:writer",
 		DisplayName: "Authentication config writer",
@@ -294,6 +350,14 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 		Role:   usersWriterRole,
 		Grants: []string{RoleGrafanaAdmin},
 	}
+	rolesReader := RoleRegistration{
+		Role:   rolesReaderRole,
+		Grants: []string{RoleGrafanaAdmin, string(org.RoleAdmin)},
+	}
+	rolesWriter := RoleRegistration{
+		Role:   rolesWriterRole,
+		Grants: []string{RoleGrafanaAdmin, string(org.RoleAdmin)},
+	}
 
 	// TODO: Move to own service when implemented
 	authenticationConfigWriter := RoleRegistration{
@@ -306,7 +370,7 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 	}
 
 	return service.DeclareFixedRoles(ldapReader, ldapWriter, orgUsersReader, orgUsersWriter,
-		settingsReader, statsReader, usersReader, usersWriter, authenticationConfigWriter)
+		settingsReader, statsReader, usersReader, usersWriter, rolesReader, rolesWriter, authenticationConfigWriter)
 }
 
 func ConcatPermissions(permissions ...[]Permission) []Permission {
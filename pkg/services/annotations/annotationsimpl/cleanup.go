@@ -23,6 +23,10 @@ const (
 	alertAnnotationType     = "alert_id <> 0"
 	dashboardAnnotationType = "dashboard_id <> 0 AND alert_id = 0"
 	apiAnnotationType       = "alert_id = 0 AND dashboard_id = 0"
+
+	// The state history of Grafana-managed alert rules is written with the ID of the rule as alert ID.
+	alertStateAnnotationType  = "alert_id IN (SELECT id FROM alert_rule WHERE alert_rule.org_id = annotation.org_id)"
+	legacyAlertAnnotationType = "alert_id <> 0 AND alert_id NOT IN (SELECT id FROM alert_rule WHERE alert_rule.org_id = annotation.org_id)"
 )
 
 // Run deletes old annotations created by alert rules, API
@@ -30,11 +34,31 @@ const (
 // from the annotation_tag table. Cleanup actions are performed in batches
 // so that no query takes too long to complete.
 //
+// The state history annotations of Grafana-managed alert rules are cleaned
+// up with their own settings when these are configured, where the maximum
+// count applies per rule. Otherwise, the alerting settings apply to them.
+//
 // Returns the number of annotation and annotation_tag rows deleted. If an
 // error occurs, it returns the number of rows affected so far.
 func (cs *CleanupServiceImpl) Run(ctx context.Context, cfg *setting.Cfg) (int64, int64, error) {
 	var totalCleanedAnnotations int64
-	affected, err := cs.store.CleanAnnotations(ctx, cfg.AlertingAnnotationCleanupSetting, alertAnnotationType)
+	alertType := alertAnnotationType
+	if stateHistory := cfg.StateHistoryAnnotationCleanupSettings; stateHistory.MaxAge > 0 || stateHistory.MaxCount > 0 {
+		affected, err := cs.store.CleanAnnotations(ctx, setting.AnnotationCleanupSettings{MaxAge: stateHistory.MaxAge}, alertStateAnnotationType)
+		totalCleanedAnnotations += affected
+		if err != nil {
+			return totalCleanedAnnotations, 0, err
+		}
+
+		affected, err = cs.store.CleanAnnotationsPerAlert(ctx, stateHistory.MaxCount, alertStateAnnotationType)
+		totalCleanedAnnotations += affected
+		if err != nil {
+			return totalCleanedAnnotations, 0, err
+		}
+		alertType = legacyAlertAnnotationType
+	}
+
+	affected, err := cs.store.CleanAnnotations(ctx, cfg.AlertingAnnotationCleanupSetting, alertType)
 	totalCleanedAnnotations += affected
 	if err != nil {
 		return totalCleanedAnnotations, 0, err
@@ -2,6 +2,7 @@ package annotationsimpl
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -164,6 +165,97 @@ func TestOldAnnotationsAreDeletedFirst(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStateHistoryAnnotationCleanUp(t *testing.T) {
+	fakeSQL := db.InitTestDB(t)
+	ctx := context.Background()
+
+	// Rules 100 and 200 are Grafana-managed alert rules, alert 300 is a legacy alert.
+	err := fakeSQL.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, id := range []int64{100, 200} {
+			_, err := sess.Exec("INSERT INTO alert_rule (id, org_id, uid, title, condition, data, updated, interval_seconds, version, namespace_uid, rule_group, no_data_state, exec_err_state) VALUES (?, 1, ?, ?, 'A', '[]', ?, 60, 1, 'folder', 'group', 'NoData', 'Error')",
+				id, fmt.Sprintf("rule-%d", id), fmt.Sprintf("rule %d", id), time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		for _, alertID := range []int64{100, 200, 300} {
+			for i := 0; i < 5; i++ {
+				created := now
+				// the first two annotations of every alert are old
+				if i < 2 {
+					created = now.AddDate(0, 0, -10)
+				}
+				a := &annotations.Item{
+					OrgID:   1,
+					AlertID: alertID,
+					Created: created.UnixNano() / int64(time.Millisecond),
+				}
+				if _, err := sess.Insert(a); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	cfg := setting.NewCfg()
+	cfg.AnnotationCleanupJobBatchSize = 1
+	cleaner := ProvideCleanupService(fakeSQL, cfg)
+
+	t.Run("the alerting settings apply to state history annotations if their settings are not configured", func(t *testing.T) {
+		affected, _, err := cleaner.Run(ctx, &setting.Cfg{
+			AlertingAnnotationCleanupSetting: settingsFn(0, 14),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), affected)
+		assertAnnotationCount(t, fakeSQL, "alert_id = 100", 4)
+	})
+
+	t.Run("state history annotations are cleaned up per rule with their own settings", func(t *testing.T) {
+		affected, _, err := cleaner.Run(ctx, &setting.Cfg{
+			AlertingAnnotationCleanupSetting:      settingsFn(0, 4),
+			StateHistoryAnnotationCleanupSettings: settingsFn(time.Hour*48, 2),
+		})
+		require.NoError(t, err)
+		// 100 loses its remaining old and one new annotation, 200 two old and one new annotation, and 300
+		// one old annotation to the alerting settings.
+		require.Equal(t, int64(6), affected)
+
+		assertAnnotationCount(t, fakeSQL, "alert_id = 100", 2)
+		assertAnnotationCount(t, fakeSQL, "alert_id = 200", 2)
+		assertAnnotationCount(t, fakeSQL, "alert_id = 300", 4)
+	})
+}
+
+func TestCleanAnnotationsPerAlert(t *testing.T) {
+	fakeSQL := db.InitTestDB(t)
+	ctx := context.Background()
+
+	// the oldest annotations of the alert are of the cleaned up type, the newest of another type
+	err := fakeSQL.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, dashboardID := range []int64{0, 0, 0, 1, 1} {
+			if _, err := sess.Insert(&annotations.Item{OrgID: 1, AlertID: 100, DashboardID: dashboardID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	cfg := setting.NewCfg()
+	cfg.AnnotationCleanupJobBatchSize = 1
+	store := NewXormStore(cfg, log.New("annotation.test"), fakeSQL, nil)
+
+	affected, err := store.CleanAnnotationsPerAlert(ctx, 2, "dashboard_id = 0")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected, "annotations of other types should not count towards the maximum")
+	assertAnnotationCount(t, fakeSQL, "dashboard_id = 0", 2)
+	assertAnnotationCount(t, fakeSQL, "dashboard_id = 1", 2)
+}
+
 func assertAnnotationCount(t *testing.T, fakeSQL db.DB, sql string, expectedCount int64) {
 	t.Helper()
 
@@ -25,5 +25,6 @@ type writeStore interface {
 	Update(ctx context.Context, item *annotations.Item) error
 	Delete(ctx context.Context, params *annotations.DeleteParams) error
 	CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error)
+	CleanAnnotationsPerAlert(ctx context.Context, maxCount int64, annotationType string) (int64, error)
 	CleanOrphanedAnnotationTags(ctx context.Context) (int64, error)
 }
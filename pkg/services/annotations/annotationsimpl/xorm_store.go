@@ -540,6 +540,37 @@ func (r *xormRepositoryImpl) CleanAnnotations(ctx context.Context, cfg setting.A
 	return totalAffected, nil
 }
 
+// CleanAnnotationsPerAlert deletes the oldest annotations of the given type of every alert that has more than maxCount of them.
+func (r *xormRepositoryImpl) CleanAnnotationsPerAlert(ctx context.Context, maxCount int64, annotationType string) (int64, error) {
+	if maxCount <= 0 {
+		return 0, nil
+	}
+
+	var alerts []struct {
+		OrgID   int64 `xorm:"org_id"`
+		AlertID int64 `xorm:"alert_id"`
+	}
+	err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := fmt.Sprintf(`SELECT org_id, alert_id FROM annotation WHERE %s GROUP BY org_id, alert_id HAVING COUNT(*) > ?`, annotationType)
+		return sess.SQL(sql, maxCount).Find(&alerts)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var totalAffected int64
+	for _, alert := range alerts {
+		deleteQuery := `DELETE FROM annotation WHERE id IN (SELECT id FROM (SELECT id FROM annotation WHERE %s AND org_id = %d AND alert_id = %d ORDER BY id DESC %s) a)`
+		sql := fmt.Sprintf(deleteQuery, annotationType, alert.OrgID, alert.AlertID, r.db.GetDialect().LimitOffset(r.cfg.AnnotationCleanupJobBatchSize, maxCount))
+		affected, err := r.executeUntilDoneOrCancelled(ctx, sql)
+		totalAffected += affected
+		if err != nil {
+			return totalAffected, err
+		}
+	}
+	return totalAffected, nil
+}
+
 func (r *xormRepositoryImpl) CleanOrphanedAnnotationTags(ctx context.Context) (int64, error) {
 	deleteQuery := `DELETE FROM annotation_tag WHERE id IN ( SELECT id FROM (SELECT id FROM annotation_tag WHERE NOT EXISTS (SELECT 1 FROM annotation a WHERE annotation_id = a.id) %s) a)`
 	sql := fmt.Sprintf(deleteQuery, r.db.GetDialect().Limit(r.cfg.AnnotationCleanupJobBatchSize))
@@ -76,5 +76,11 @@ func (a *Anonymous) UsageStatFn(ctx context.Context) (map[string]any, error) {
 		m["stats.anonymous.customized_role.count"] = 1
 	}
 
+	// Anonymous visitors without a role only get access through resource permissions
+	m["stats.anonymous.scoped_access.count"] = 0
+	if org.RoleType(a.cfg.AnonymousOrgRole) == org.RoleNone {
+		m["stats.anonymous.scoped_access.count"] = 1
+	}
+
 	return m, nil
 }
@@ -33,6 +33,14 @@ func TestAnonymous_Authenticate(t *testing.T) {
 				AnonymousOrgRole: "Viewer",
 			},
 		},
+		{
+			desc: "should success without a role when access is scoped by resource permissions",
+			org:  &org.Org{ID: 1, Name: "some org"},
+			cfg: &setting.Cfg{
+				AnonymousOrgName: "some org",
+				AnonymousOrgRole: "None",
+			},
+		},
 		{
 			desc: "should return error if any error occurs during org lookup",
 			err:  fmt.Errorf("some error"),
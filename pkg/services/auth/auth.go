@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models/usertoken"
 	"github.com/grafana/grafana/pkg/registry"
@@ -22,6 +23,7 @@ const (
 var (
 	ErrUserTokenNotFound   = errors.New("user token not found")
 	ErrInvalidSessionToken = usertoken.ErrInvalidSessionToken
+	ErrNoRevokeCriteria    = errors.New("at least one of userId, authModule or issuedBefore is required")
 )
 
 type (
@@ -58,6 +60,49 @@ type RevokeAuthTokenCmd struct {
 	AuthTokenId int64 `json:"authTokenId"`
 }
 
+// SearchSessionsQuery filters the active sessions of all users.
+type SearchSessionsQuery struct {
+	UserID int64
+	// ClientIP is the IP address the sessions were last used from.
+	ClientIP string
+	// UserAgent matches the sessions whose user agent contains it.
+	UserAgent  string
+	SeenAfter  time.Time
+	SeenBefore time.Time
+	Page       int
+	Limit      int
+}
+
+type SearchSessionsResult struct {
+	TotalCount int64      `json:"totalCount"`
+	Sessions   []*Session `json:"sessions"`
+	Page       int        `json:"page"`
+	PerPage    int        `json:"perPage"`
+}
+
+// Session is an active session, with the user it belongs to.
+type Session struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"userId"`
+	Login     string    `json:"login"`
+	Email     string    `json:"email"`
+	ClientIP  string    `json:"clientIp"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+	SeenAt    time.Time `json:"seenAt"`
+}
+
+// RevokeSessionsCommand selects the sessions to revoke. Criteria are combined, and at least one is required.
+type RevokeSessionsCommand struct {
+	UserID int64 `json:"userId"`
+	// AuthModule revokes the sessions of the users who logged in with the auth module, e.g. oauth_generic_oauth.
+	AuthModule string `json:"authModule"`
+	// IssuedBefore revokes the sessions created before the time.
+	IssuedBefore time.Time `json:"issuedBefore"`
+	// ExceptTokenID keeps a session, e.g. the session of the user changing their password.
+	ExceptTokenID int64 `json:"-"`
+}
+
 type RotateCommand struct {
 	// token is the un-hashed token
 	UnHashedToken string
@@ -78,6 +123,10 @@ type UserTokenService interface {
 	GetUserTokens(ctx context.Context, userID int64) ([]*UserToken, error)
 	ActiveTokenCount(ctx context.Context, userID *int64) (int64, error)
 	GetUserRevokedTokens(ctx context.Context, userID int64) ([]*UserToken, error)
+	// SearchSessions returns the active sessions of all users.
+	SearchSessions(ctx context.Context, query *SearchSessionsQuery) (*SearchSessionsResult, error)
+	// RevokeSessions revokes sessions in bulk, and returns how many were revoked.
+	RevokeSessions(ctx context.Context, cmd *RevokeSessionsCommand) (int64, error)
 }
 
 type UserTokenBackgroundService interface {
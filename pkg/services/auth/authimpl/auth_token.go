@@ -102,6 +102,10 @@ func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User,
 
 	userAuthToken.UnhashedToken = token
 
+	if err := s.revokeSessionsOverLimit(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
 	ctxLogger := s.log.FromContext(ctx)
 	ctxLogger.Debug("User auth token created", "tokenID", userAuthToken.Id, "userID", userAuthToken.UserId, "clientIP", userAuthToken.ClientIp, "userAgent", userAuthToken.UserAgent, "authToken", userAuthToken.AuthToken)
 
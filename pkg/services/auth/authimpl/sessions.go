@@ -0,0 +1,167 @@
+package authimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/auth"
+)
+
+// lastSeenAt is the last time a session was used, or its creation time if it was never used.
+const lastSeenAt = "CASE WHEN t.seen_at > 0 THEN t.seen_at ELSE t.created_at END"
+
+type sessionRow struct {
+	Id         int64
+	UserId     int64
+	Login      string
+	Email      string
+	ClientIp   string
+	UserAgent  string
+	CreatedAt  int64
+	LastSeenAt int64
+}
+
+// SearchSessions returns the active sessions of all users, the most recently used first.
+func (s *UserAuthTokenService) SearchSessions(ctx context.Context, query *auth.SearchSessionsQuery) (*auth.SearchSessionsResult, error) {
+	result := &auth.SearchSessionsResult{
+		Sessions: make([]*auth.Session, 0),
+		Page:     query.Page,
+		PerPage:  query.Limit,
+	}
+
+	whereConditions := []string{"t.created_at > ?", "t.rotated_at > ?", "t.revoked_at = 0"}
+	whereParams := []any{s.createdAfterParam(), s.rotatedAfterParam()}
+	if query.UserID != 0 {
+		whereConditions = append(whereConditions, "t.user_id = ?")
+		whereParams = append(whereParams, query.UserID)
+	}
+	if query.ClientIP != "" {
+		whereConditions = append(whereConditions, "t.client_ip = ?")
+		whereParams = append(whereParams, query.ClientIP)
+	}
+	if query.UserAgent != "" {
+		whereConditions = append(whereConditions, "t.user_agent "+s.sqlStore.GetDialect().LikeStr()+" ?")
+		whereParams = append(whereParams, "%"+query.UserAgent+"%")
+	}
+	if !query.SeenAfter.IsZero() {
+		whereConditions = append(whereConditions, lastSeenAt+" >= ?")
+		whereParams = append(whereParams, query.SeenAfter.Unix())
+	}
+	if !query.SeenBefore.IsZero() {
+		whereConditions = append(whereConditions, lastSeenAt+" <= ?")
+		whereParams = append(whereParams, query.SeenBefore.Unix())
+	}
+	where := strings.Join(whereConditions, " AND ")
+
+	err := s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		count, err := dbSession.Table("user_auth_token").Alias("t").Where(where, whereParams...).Count()
+		if err != nil {
+			return err
+		}
+		result.TotalCount = count
+
+		sess := dbSession.Table("user_auth_token").Alias("t").
+			Join("INNER", []string{s.sqlStore.GetDialect().Quote("user"), "u"}, "t.user_id = u.id").
+			Select("t.id, t.user_id, u.login, u.email, t.client_ip, t.user_agent, t.created_at, "+lastSeenAt+" AS last_seen_at").
+			Where(where, whereParams...).
+			OrderBy(lastSeenAt + " DESC, t.id DESC")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit, query.Limit*(query.Page-1))
+		}
+
+		var rows []sessionRow
+		if err := sess.Find(&rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			result.Sessions = append(result.Sessions, &auth.Session{
+				ID:        row.Id,
+				UserID:    row.UserId,
+				Login:     row.Login,
+				Email:     row.Email,
+				ClientIP:  row.ClientIp,
+				UserAgent: row.UserAgent,
+				CreatedAt: time.Unix(row.CreatedAt, 0),
+				SeenAt:    time.Unix(row.LastSeenAt, 0),
+			})
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// RevokeSessions deletes the sessions matching all the criteria of the command.
+func (s *UserAuthTokenService) RevokeSessions(ctx context.Context, cmd *auth.RevokeSessionsCommand) (int64, error) {
+	whereConditions := make([]string, 0)
+	whereParams := make([]any, 0)
+	if cmd.UserID != 0 {
+		whereConditions = append(whereConditions, "user_id = ?")
+		whereParams = append(whereParams, cmd.UserID)
+	}
+	if cmd.AuthModule != "" {
+		whereConditions = append(whereConditions, "user_id IN (SELECT user_id FROM user_auth WHERE auth_module = ?)")
+		whereParams = append(whereParams, cmd.AuthModule)
+	}
+	if !cmd.IssuedBefore.IsZero() {
+		whereConditions = append(whereConditions, "created_at < ?")
+		whereParams = append(whereParams, cmd.IssuedBefore.Unix())
+	}
+	if len(whereConditions) == 0 {
+		return 0, auth.ErrNoRevokeCriteria
+	}
+	if cmd.ExceptTokenID != 0 {
+		whereConditions = append(whereConditions, "id <> ?")
+		whereParams = append(whereParams, cmd.ExceptTokenID)
+	}
+
+	var affected int64
+	err := s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sql := "DELETE FROM user_auth_token WHERE " + strings.Join(whereConditions, " AND ")
+		res, err := dbSession.Exec(append([]any{sql}, whereParams...)...)
+		if err != nil {
+			return err
+		}
+
+		affected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.log.FromContext(ctx).Info("Sessions revoked", "userID", cmd.UserID, "authModule", cmd.AuthModule, "issuedBefore", cmd.IssuedBefore, "count", affected)
+	return affected, nil
+}
+
+// revokeSessionsOverLimit revokes the oldest active sessions of a user beyond the maximum number of concurrent sessions.
+func (s *UserAuthTokenService) revokeSessionsOverLimit(ctx context.Context, userID int64) error {
+	limit := s.cfg.LoginMaxConcurrentSessions
+	if limit <= 0 {
+		return nil
+	}
+
+	return s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		var ids []int64
+		err := dbSession.Table("user_auth_token").Cols("id").
+			Where("user_id = ? AND created_at > ? AND rotated_at > ? AND revoked_at = 0", userID, s.createdAfterParam(), s.rotatedAfterParam()).
+			Desc("created_at", "id").
+			Find(&ids)
+		if err != nil {
+			return err
+		}
+		if len(ids) <= limit {
+			return nil
+		}
+
+		revoked, err := dbSession.In("id", ids[limit:]).Delete(&userAuthToken{})
+		if err != nil {
+			return err
+		}
+
+		s.log.FromContext(ctx).Info("Revoked oldest sessions over the concurrent session limit", "userID", userID, "limit", limit, "count", revoked)
+		return nil
+	})
+}
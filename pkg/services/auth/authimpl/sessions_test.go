@@ -0,0 +1,142 @@
+package authimpl
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationSessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	createUser := func(t *testing.T, ctx *testContext, login string) *user.User {
+		t.Helper()
+		usr := &user.User{Login: login, Email: login + "@example.org", Created: time.Now(), Updated: time.Now()}
+		err := ctx.sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Insert(usr)
+			return err
+		})
+		require.NoError(t, err)
+		return usr
+	}
+
+	t.Run("should search the active sessions of all users", func(t *testing.T) {
+		ctx := createTestContext(t)
+		alice, bob := createUser(t, ctx, "alice"), createUser(t, ctx, "bob")
+
+		_, err := ctx.tokenService.CreateToken(context.Background(), alice, net.ParseIP("192.168.10.11"), "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0")
+		require.NoError(t, err)
+		_, err = ctx.tokenService.CreateToken(context.Background(), bob, net.ParseIP("192.168.10.12"), "curl/8.4.0")
+		require.NoError(t, err)
+		revoked, err := ctx.tokenService.CreateToken(context.Background(), bob, net.ParseIP("192.168.10.12"), "curl/8.4.0")
+		require.NoError(t, err)
+		require.NoError(t, ctx.tokenService.RevokeToken(context.Background(), revoked, true))
+
+		result, err := ctx.tokenService.SearchSessions(context.Background(), &auth.SearchSessionsQuery{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, result.TotalCount)
+		require.Len(t, result.Sessions, 2)
+
+		result, err = ctx.tokenService.SearchSessions(context.Background(), &auth.SearchSessionsQuery{UserAgent: "firefox", Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Sessions, 1)
+		assert.Equal(t, "alice", result.Sessions[0].Login)
+		assert.Equal(t, "alice@example.org", result.Sessions[0].Email)
+
+		result, err = ctx.tokenService.SearchSessions(context.Background(), &auth.SearchSessionsQuery{UserID: bob.ID, ClientIP: "192.168.10.12", Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Sessions, 1)
+		assert.Equal(t, "bob", result.Sessions[0].Login)
+
+		result, err = ctx.tokenService.SearchSessions(context.Background(), &auth.SearchSessionsQuery{SeenBefore: time.Now().Add(-time.Hour), Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, result.Sessions)
+	})
+
+	t.Run("should revoke sessions in bulk", func(t *testing.T) {
+		ctx := createTestContext(t)
+		alice, bob := createUser(t, ctx, "alice"), createUser(t, ctx, "bob")
+
+		err := ctx.sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("INSERT INTO user_auth (user_id, auth_module, auth_id, created) VALUES (?, ?, ?, ?)", bob.ID, "oauth_generic_oauth", "bob", time.Now())
+			return err
+		})
+		require.NoError(t, err)
+
+		aliceToken, err := ctx.tokenService.CreateToken(context.Background(), alice, nil, "")
+		require.NoError(t, err)
+		_, err = ctx.tokenService.CreateToken(context.Background(), alice, nil, "")
+		require.NoError(t, err)
+		_, err = ctx.tokenService.CreateToken(context.Background(), bob, nil, "")
+		require.NoError(t, err)
+
+		_, err = ctx.tokenService.RevokeSessions(context.Background(), &auth.RevokeSessionsCommand{})
+		require.ErrorIs(t, err, auth.ErrNoRevokeCriteria)
+
+		count, err := ctx.tokenService.RevokeSessions(context.Background(), &auth.RevokeSessionsCommand{AuthModule: "oauth_generic_oauth"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		count, err = ctx.tokenService.RevokeSessions(context.Background(), &auth.RevokeSessionsCommand{UserID: alice.ID, ExceptTokenID: aliceToken.Id})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		count, err = ctx.tokenService.RevokeSessions(context.Background(), &auth.RevokeSessionsCommand{IssuedBefore: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		count, err = ctx.tokenService.RevokeSessions(context.Background(), &auth.RevokeSessionsCommand{IssuedBefore: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+	})
+
+	t.Run("should revoke the oldest sessions over the concurrent session limit", func(t *testing.T) {
+		ctx := createTestContext(t)
+		ctx.tokenService.cfg.LoginMaxConcurrentSessions = 2
+		alice := createUser(t, ctx, "alice")
+
+		var tokens []*auth.UserToken
+		for i := 0; i < 3; i++ {
+			token, err := ctx.tokenService.CreateToken(context.Background(), alice, nil, "")
+			require.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+
+		active, err := ctx.tokenService.GetUserTokens(context.Background(), alice.ID)
+		require.NoError(t, err)
+		require.Len(t, active, 2)
+		for _, token := range active {
+			assert.NotEqual(t, tokens[0].Id, token.Id)
+		}
+	})
+
+	t.Run("should delete the sessions of disabled users", func(t *testing.T) {
+		ctx := createTestContext(t)
+		alice, bob := createUser(t, ctx, "alice"), createUser(t, ctx, "bob")
+
+		_, err := ctx.tokenService.CreateToken(context.Background(), alice, nil, "")
+		require.NoError(t, err)
+		_, err = ctx.tokenService.CreateToken(context.Background(), bob, nil, "")
+		require.NoError(t, err)
+
+		err = ctx.sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE "+ctx.sqlstore.GetDialect().Quote("user")+" SET is_disabled = ? WHERE id = ?", ctx.sqlstore.GetDialect().BooleanStr(true), bob.ID)
+			return err
+		})
+		require.NoError(t, err)
+
+		affected, err := ctx.tokenService.deleteDisabledUsersTokens(context.Background())
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affected)
+	})
+}
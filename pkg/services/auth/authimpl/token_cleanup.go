@@ -16,6 +16,9 @@ func (s *UserAuthTokenService) Run(ctx context.Context) error {
 		if _, err := s.deleteExpiredTokens(ctx, maxInactiveLifetime, maxLifetime); err != nil {
 			s.log.Error("An error occurred while deleting expired tokens", "err", err)
 		}
+		if _, err := s.deleteDisabledUsersTokens(ctx); err != nil {
+			s.log.Error("An error occurred while deleting tokens of disabled users", "err", err)
+		}
 	})
 	if err != nil {
 		s.log.Error("Failed to lock and execute cleanup of expired auth token", "error", err)
@@ -28,6 +31,9 @@ func (s *UserAuthTokenService) Run(ctx context.Context) error {
 				if _, err := s.deleteExpiredTokens(ctx, maxInactiveLifetime, maxLifetime); err != nil {
 					s.log.Error("An error occurred while deleting expired tokens", "err", err)
 				}
+				if _, err := s.deleteDisabledUsersTokens(ctx); err != nil {
+					s.log.Error("An error occurred while deleting tokens of disabled users", "err", err)
+				}
 			})
 			if err != nil {
 				s.log.Error("Failed to lock and execute cleanup of expired auth token", "error", err)
@@ -66,3 +72,26 @@ func (s *UserAuthTokenService) deleteExpiredTokens(ctx context.Context, maxInact
 
 	return affected, err
 }
+
+// deleteDisabledUsersTokens deletes the tokens of disabled users, so that they aren't logged in again when enabled.
+func (s *UserAuthTokenService) deleteDisabledUsersTokens(ctx context.Context) (int64, error) {
+	var affected int64
+	err := s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		dialect := s.sqlStore.GetDialect()
+		sql := `DELETE FROM user_auth_token WHERE user_id IN (SELECT id FROM ` + dialect.Quote("user") + ` WHERE is_disabled = ?)`
+		res, err := dbSession.Exec(sql, dialect.BooleanStr(true))
+		if err != nil {
+			return err
+		}
+
+		affected, err = res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		s.log.Debug("Cleanup of disabled users tokens done", "count", affected)
+		return nil
+	})
+
+	return affected, err
+}
@@ -26,6 +26,8 @@ type FakeUserAuthTokenService struct {
 	GetUserTokensProvider        func(ctx context.Context, userID int64) ([]*auth.UserToken, error)
 	GetUserRevokedTokensProvider func(ctx context.Context, userID int64) ([]*auth.UserToken, error)
 	BatchRevokedTokenProvider    func(ctx context.Context, userIDs []int64) error
+	SearchSessionsProvider       func(ctx context.Context, query *auth.SearchSessionsQuery) (*auth.SearchSessionsResult, error)
+	RevokeSessionsProvider       func(ctx context.Context, cmd *auth.RevokeSessionsCommand) (int64, error)
 }
 
 func NewFakeUserAuthTokenService() *FakeUserAuthTokenService {
@@ -113,6 +115,20 @@ func (s *FakeUserAuthTokenService) GetUserRevokedTokens(ctx context.Context, use
 	return s.GetUserRevokedTokensProvider(context.Background(), userId)
 }
 
+func (s *FakeUserAuthTokenService) SearchSessions(ctx context.Context, query *auth.SearchSessionsQuery) (*auth.SearchSessionsResult, error) {
+	if s.SearchSessionsProvider == nil {
+		return &auth.SearchSessionsResult{Sessions: []*auth.Session{}}, nil
+	}
+	return s.SearchSessionsProvider(ctx, query)
+}
+
+func (s *FakeUserAuthTokenService) RevokeSessions(ctx context.Context, cmd *auth.RevokeSessionsCommand) (int64, error) {
+	if s.RevokeSessionsProvider == nil {
+		return 0, nil
+	}
+	return s.RevokeSessionsProvider(ctx, cmd)
+}
+
 func (s *FakeUserAuthTokenService) BatchRevokeAllUserTokens(ctx context.Context, userIds []int64) error {
 	return s.BatchRevokedTokenProvider(ctx, userIds)
 }
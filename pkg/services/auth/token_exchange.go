@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+const (
+	// GrantTypeTokenExchange is the grant type of token exchange requests, as defined in RFC 8693.
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// TokenTypeJWT is the type of the tokens issued by token exchange.
+	TokenTypeJWT = "urn:ietf:params:oauth:token-type:jwt"
+	// TokenExchangeKeyPrefix is the prefix of the signing keys of exchanged tokens.
+	TokenExchangeKeyPrefix = "token-exchange"
+)
+
+// ExchangedTokenClaims are the claims of a token issued by token exchange. The subject is the namespaced ID of the
+// identity the token was issued for, and the audience its organization.
+type ExchangedTokenClaims struct {
+	jwt.Claims
+	// Permissions are the actions and scopes the token is limited to.
+	Permissions map[string][]string `json:"permissions"`
+}
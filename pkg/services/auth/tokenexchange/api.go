@@ -0,0 +1,53 @@
+package tokenexchange
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Post("/api/auth/token-exchange", middleware.ReqSignedIn, routing.Wrap(s.handleExchange))
+}
+
+// swagger:route POST /auth/token-exchange auth exchangeToken
+//
+// Exchange the signed in identity for a short-lived token limited to a subset of its permissions.
+//
+// Responses:
+// 200: exchangeTokenResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleExchange(c *contextmodel.ReqContext) response.Response {
+	cmd := ExchangeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	token, err := s.Exchange(c.Req.Context(), c.SignedInUser, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to exchange token", err)
+	}
+
+	// tokens must not be cached, as required by RFC 6749
+	return response.JSON(http.StatusOK, token).SetHeader("Cache-Control", "no-store")
+}
+
+// swagger:parameters exchangeToken
+type ExchangeTokenParams struct {
+	// in:body
+	// required:true
+	Body ExchangeCommand `json:"body"`
+}
+
+// swagger:response exchangeTokenResponse
+type ExchangeTokenResponse struct {
+	// in:body
+	Body TokenResponse `json:"body"`
+}
@@ -0,0 +1,179 @@
+package tokenexchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/google/uuid"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/signingkeys"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrInvalidRequest = errutil.BadRequest("tokenexchange.invalid-request")
+	ErrInvalidSubject = errutil.Forbidden("tokenexchange.invalid-subject",
+		errutil.WithPublicMessage("Only users and service accounts can exchange their identity for a token"))
+	ErrPermissionDenied = errutil.Forbidden("tokenexchange.permission-denied")
+)
+
+// Service exchanges the identity of a signed in user or service account for a short-lived token, limited to a
+// subset of its permissions. It implements the token exchange grant of RFC 8693, with the signed in identity as the
+// subject token.
+type Service struct {
+	cfg         *setting.Cfg
+	signingKeys signingkeys.Service
+	log         log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, signingKeys signingkeys.Service, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:         cfg,
+		signingKeys: signingKeys,
+		log:         log.New("tokenexchange"),
+	}
+
+	if cfg.TokenExchangeEnabled {
+		s.registerAPIEndpoints(routeRegister)
+	}
+
+	return s
+}
+
+type Permission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope"`
+}
+
+type ExchangeCommand struct {
+	// GrantType must be urn:ietf:params:oauth:grant-type:token-exchange
+	GrantType string `json:"grant_type"`
+	// RequestedTokenType is optional, only urn:ietf:params:oauth:token-type:jwt is supported
+	RequestedTokenType string `json:"requested_token_type"`
+	// Permissions are the actions and scopes to limit the token to. A wildcard scope is narrowed down to the scopes
+	// of the identity.
+	Permissions []Permission `json:"permissions"`
+	// ExpiresIn is the lifetime of the token in seconds
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	// Permissions are the actions and scopes the token is limited to
+	Permissions map[string][]string `json:"permissions"`
+}
+
+// Exchange issues a token for the identity, limited to the requested permissions. All the requested actions must be
+// granted to the identity.
+func (s *Service) Exchange(ctx context.Context, id identity.Requester, cmd *ExchangeCommand) (*TokenResponse, error) {
+	if cmd.GrantType != auth.GrantTypeTokenExchange {
+		return nil, ErrInvalidRequest.Errorf("unsupported grant type %q", cmd.GrantType)
+	}
+	if cmd.RequestedTokenType != "" && cmd.RequestedTokenType != auth.TokenTypeJWT {
+		return nil, ErrInvalidRequest.Errorf("unsupported requested token type %q", cmd.RequestedTokenType)
+	}
+	if len(cmd.Permissions) == 0 {
+		return nil, ErrInvalidRequest.Errorf("at least one permission is required")
+	}
+
+	namespace, identifier := id.GetNamespacedID()
+	if !identity.IsNamespace(namespace, identity.NamespaceUser, identity.NamespaceServiceAccount) {
+		return nil, ErrInvalidSubject.Errorf("identity %s:%s can't exchange its identity", namespace, identifier)
+	}
+	// otherwise a token could be exchanged over and over to extend its lifetime
+	if id.GetAuthenticatedBy() == login.TokenExchangeModule {
+		return nil, ErrInvalidSubject.Errorf("identity %s:%s is authenticated by an exchanged token", namespace, identifier)
+	}
+
+	requested := make([]accesscontrol.Permission, 0, len(cmd.Permissions))
+	for _, p := range cmd.Permissions {
+		if p.Action == "" {
+			return nil, ErrInvalidRequest.Errorf("permissions require an action")
+		}
+		if p.Scope != "" && !accesscontrol.ValidateScope(p.Scope) {
+			return nil, ErrInvalidRequest.Errorf("invalid scope %q", p.Scope)
+		}
+		requested = append(requested, accesscontrol.Permission{Action: p.Action, Scope: p.Scope})
+	}
+
+	granted := accesscontrol.Intersect(accesscontrol.UngroupScopesByAction(id.GetPermissions()), requested)
+	for _, p := range requested {
+		scopes, ok := granted[p.Action]
+		if !ok || (p.Scope != "" && len(scopes) == 0) {
+			return nil, ErrPermissionDenied.Errorf("permission %s is not granted to %s:%s", p.Action, namespace, identifier)
+		}
+	}
+
+	ttl := s.cfg.TokenExchangeDefaultTTL
+	if cmd.ExpiresIn > 0 {
+		ttl = time.Duration(cmd.ExpiresIn) * time.Second
+	}
+	if ttl > s.cfg.TokenExchangeMaxTTL {
+		ttl = s.cfg.TokenExchangeMaxTTL
+	}
+
+	now := time.Now()
+	token, err := s.sign(ctx, &auth.ExchangedTokenClaims{
+		Claims: jwt.Claims{
+			ID:       uuid.NewString(),
+			Issuer:   s.cfg.AppURL,
+			Subject:  fmt.Sprintf("%s:%s", namespace, identifier),
+			Audience: jwt.Audience{fmt.Sprintf("org:%d", id.GetOrgID())},
+			Expiry:   jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt: jwt.NewNumericDate(now),
+		},
+		Permissions: granted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.FromContext(ctx).Info("Token exchanged", "namespace", namespace, "id", identifier, "orgID", id.GetOrgID(),
+		"actions", strings.Join(actions(granted), ","), "ttl", ttl)
+
+	return &TokenResponse{
+		AccessToken:     token,
+		IssuedTokenType: auth.TokenTypeJWT,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(ttl.Seconds()),
+		Permissions:     granted,
+	}, nil
+}
+
+func (s *Service) sign(ctx context.Context, claims *auth.ExchangedTokenClaims) (string, error) {
+	keyID, key, err := s.signingKeys.GetOrCreatePrivateKey(ctx, auth.TokenExchangeKeyPrefix, jose.ES256)
+	if err != nil {
+		return "", err
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, &jose.SignerOptions{
+		ExtraHeaders: map[jose.HeaderKey]any{"kid": keyID, "typ": "JWT"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+func actions(permissions map[string][]string) []string {
+	result := make([]string, 0, len(permissions))
+	for action := range permissions {
+		result = append(result, action)
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package tokenexchange
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/signingkeys/signingkeystest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Exchange(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	s := &Service{
+		cfg: &setting.Cfg{
+			AppURL:                  "http://localhost:3000/",
+			TokenExchangeEnabled:    true,
+			TokenExchangeDefaultTTL: 5 * time.Minute,
+			TokenExchangeMaxTTL:     time.Hour,
+		},
+		signingKeys: &signingkeystest.FakeSigningKeysService{ExpectedKeyID: "token-exchange-2024-01-es256", ExpectedSinger: key},
+		log:         log.NewNopLogger(),
+	}
+
+	signedInUser := func() *user.SignedInUser {
+		return &user.SignedInUser{UserID: 2, OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+			"dashboards:read":  {"dashboards:uid:a", "dashboards:uid:b"},
+			"dashboards:write": {"dashboards:uid:a"},
+			"users:read":       {""},
+		}}}
+	}
+	exchange := func(permissions ...Permission) *ExchangeCommand {
+		return &ExchangeCommand{GrantType: auth.GrantTypeTokenExchange, Permissions: permissions}
+	}
+
+	t.Run("should issue a token limited to the requested permissions", func(t *testing.T) {
+		result, err := s.Exchange(context.Background(), signedInUser(), exchange(
+			Permission{Action: "dashboards:read", Scope: "dashboards:*"},
+			Permission{Action: "users:read"},
+		))
+		require.NoError(t, err)
+
+		assert.Equal(t, auth.TokenTypeJWT, result.IssuedTokenType)
+		assert.Equal(t, "Bearer", result.TokenType)
+		assert.EqualValues(t, 300, result.ExpiresIn)
+
+		parsed, err := jwt.ParseSigned(result.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "token-exchange-2024-01-es256", parsed.Headers[0].KeyID)
+
+		var claims auth.ExchangedTokenClaims
+		require.NoError(t, parsed.Claims(key.Public(), &claims))
+		assert.Equal(t, "user:2", claims.Subject)
+		assert.Equal(t, jwt.Audience{"org:1"}, claims.Audience)
+		assert.Equal(t, "http://localhost:3000/", claims.Issuer)
+		assert.NotEmpty(t, claims.ID)
+		assert.ElementsMatch(t, []string{"dashboards:uid:a", "dashboards:uid:b"}, claims.Permissions["dashboards:read"])
+		assert.Contains(t, claims.Permissions, "users:read")
+		assert.NotContains(t, claims.Permissions, "dashboards:write")
+	})
+
+	t.Run("should cap the lifetime of the token", func(t *testing.T) {
+		cmd := exchange(Permission{Action: "users:read"})
+		cmd.ExpiresIn = int64((2 * time.Hour).Seconds())
+
+		result, err := s.Exchange(context.Background(), signedInUser(), cmd)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3600, result.ExpiresIn)
+	})
+
+	t.Run("should reject permissions not granted to the identity", func(t *testing.T) {
+		_, err := s.Exchange(context.Background(), signedInUser(), exchange(Permission{Action: "dashboards:write", Scope: "dashboards:uid:b"}))
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+
+		_, err = s.Exchange(context.Background(), signedInUser(), exchange(Permission{Action: "users:write"}))
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		_, err := s.Exchange(context.Background(), signedInUser(), &ExchangeCommand{GrantType: "client_credentials", Permissions: []Permission{{Action: "users:read"}}})
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+
+		_, err = s.Exchange(context.Background(), signedInUser(), exchange())
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+
+		_, err = s.Exchange(context.Background(), signedInUser(), exchange(Permission{Action: "dashboards:read", Scope: "dashboards*"}))
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+	})
+
+	t.Run("should reject identities that can't exchange their identity", func(t *testing.T) {
+		_, err := s.Exchange(context.Background(), &user.SignedInUser{OrgID: 1, IsAnonymous: true}, exchange(Permission{Action: "users:read"}))
+		assert.ErrorIs(t, err, ErrInvalidSubject)
+
+		exchanged := signedInUser()
+		exchanged.AuthenticatedBy = login.TokenExchangeModule
+		_, err = s.Exchange(context.Background(), exchanged, exchange(Permission{Action: "users:read"}))
+		assert.ErrorIs(t, err, ErrInvalidSubject)
+	})
+}
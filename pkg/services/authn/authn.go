@@ -18,22 +18,27 @@ import (
 )
 
 const (
-	ClientAPIKey      = "auth.client.api-key" // #nosec G101
-	ClientAnonymous   = "auth.client.anonymous"
-	ClientBasic       = "auth.client.basic"
-	ClientJWT         = "auth.client.jwt"
-	ClientExtendedJWT = "auth.client.extended-jwt"
-	ClientRender      = "auth.client.render"
-	ClientSession     = "auth.client.session"
-	ClientForm        = "auth.client.form"
-	ClientProxy       = "auth.client.proxy"
-	ClientSAML        = "auth.client.saml"
+	ClientAPIKey        = "auth.client.api-key" // #nosec G101
+	ClientAnonymous     = "auth.client.anonymous"
+	ClientBasic         = "auth.client.basic"
+	ClientJWT           = "auth.client.jwt"
+	ClientMTLS          = "auth.client.mtls"
+	ClientExtendedJWT   = "auth.client.extended-jwt"
+	ClientTokenExchange = "auth.client.token-exchange"
+	ClientRender        = "auth.client.render"
+	ClientSession       = "auth.client.session"
+	ClientForm          = "auth.client.form"
+	ClientProxy         = "auth.client.proxy"
+	ClientSAML          = "auth.client.saml"
 )
 
 const (
 	MetaKeyUsername   = "username"
 	MetaKeyAuthModule = "authModule"
 	MetaKeyIsLogin    = "isLogin"
+	MetaKeyTOTPCode   = "totpCode"
+	// MetaKeyIPAddress is the IP address that login attempts of the request are counted for.
+	MetaKeyIPAddress = "ipAddress"
 )
 
 // ClientParams are hints to the auth service about how to handle the identity management
@@ -57,6 +62,9 @@ type ClientParams struct {
 	LookUpParams login.UserLookupParams
 	// SyncPermissions ensure that permissions are loaded from DB and added to the identity
 	SyncPermissions bool
+	// LimitPermissions restricts the permissions loaded by SyncPermissions to these actions and scopes,
+	// and removes the org role and Grafana admin flag of the identity
+	LimitPermissions map[string][]string
 }
 
 type PostAuthHookFn func(ctx context.Context, identity *Identity, r *Request) error
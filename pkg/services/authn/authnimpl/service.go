@@ -112,7 +112,7 @@ func ProvideService(
 
 	// if we have password clients configure check if basic auth or form auth is enabled
 	if len(passwordClients) > 0 {
-		passwordClient := clients.ProvidePassword(loginAttempts, passwordClients...)
+		passwordClient := clients.ProvidePassword(cfg, loginAttempts, passwordClients...)
 		if s.cfg.BasicAuthEnabled {
 			s.RegisterClient(clients.ProvideBasic(passwordClient))
 		}
@@ -135,6 +135,14 @@ func ProvideService(
 		s.RegisterClient(clients.ProvideJWT(jwtService, cfg))
 	}
 
+	if s.cfg.MTLSAuthEnabled {
+		s.RegisterClient(clients.ProvideMTLS(cfg, userService, authInfoService))
+	}
+
+	if s.cfg.TokenExchangeEnabled {
+		s.RegisterClient(clients.ProvideTokenExchange(cfg, userService, signingKeysService))
+	}
+
 	if s.cfg.ExtendedJWTAuthEnabled && features.IsEnabledGlobally(featuremgmt.FlagExternalServiceAuth) {
 		s.RegisterClient(clients.ProvideExtendedJWT(userService, cfg, signingKeysService, oauthServer))
 	}
@@ -237,6 +245,12 @@ func (s *Service) authenticate(ctx context.Context, c authn.Client, r *authn.Req
 	}
 
 	if identity.IsDisabled {
+		// the user was disabled without revoking their sessions, e.g. by a directory sync
+		if identity.SessionToken != nil {
+			if err := s.sessionService.RevokeAllUserTokens(ctx, identity.SessionToken.UserId); err != nil {
+				s.log.FromContext(ctx).Warn("Failed to revoke sessions of disabled user", "id", identity.ID, "error", err)
+			}
+		}
 		return nil, errDisabledIdentity.Errorf("identity is disabled")
 	}
 
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -39,6 +40,14 @@ func (s *PermissionsSync) SyncPermissionsHook(ctx context.Context, identity *aut
 	if identity.Permissions == nil {
 		identity.Permissions = make(map[int64]map[string][]string)
 	}
+	if identity.ClientParams.LimitPermissions != nil {
+		identity.Permissions[identity.OrgID] = accesscontrol.Intersect(permissions, accesscontrol.UngroupScopesByAction(identity.ClientParams.LimitPermissions))
+		// Routes that check roles instead of permissions would otherwise grant the full access of the identity
+		isGrafanaAdmin := false
+		identity.OrgRoles = map[int64]org.RoleType{identity.OrgID: org.RoleNone}
+		identity.IsGrafanaAdmin = &isGrafanaAdmin
+		return nil
+	}
 	identity.Permissions[identity.OrgID] = accesscontrol.GroupScopesByAction(permissions)
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,6 +50,41 @@ func TestPermissionsSync_SyncPermission(t *testing.T) {
 	}
 }
 
+func TestPermissionsSync_LimitPermissions(t *testing.T) {
+	s := &PermissionsSync{
+		ac: &acmock.Mock{
+			GetUserPermissionsFunc: func(ctx context.Context, siu identity.Requester, o accesscontrol.Options) ([]accesscontrol.Permission, error) {
+				return []accesscontrol.Permission{
+					{Action: accesscontrol.ActionUsersRead},
+					{Action: "dashboards:read", Scope: "dashboards:*"},
+					{Action: "dashboards:write", Scope: "dashboards:uid:1"},
+				}, nil
+			},
+		},
+		log: log.NewNopLogger(),
+	}
+
+	isGrafanaAdmin := true
+	identity := &authn.Identity{ID: "user:2", OrgID: 1, OrgRoles: map[int64]org.RoleType{1: org.RoleAdmin}, IsGrafanaAdmin: &isGrafanaAdmin, ClientParams: authn.ClientParams{
+		SyncPermissions: true,
+		LimitPermissions: map[string][]string{
+			"dashboards:read":  {"dashboards:uid:1", "dashboards:uid:2"},
+			"dashboards:write": {"dashboards:uid:2"},
+			"folders:read":     {"folders:*"},
+		},
+	}}
+
+	err := s.SyncPermissionsHook(context.Background(), identity, &authn.Request{})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"dashboards:uid:1", "dashboards:uid:2"}, identity.Permissions[1]["dashboards:read"])
+	assert.Empty(t, identity.Permissions[1]["dashboards:write"])
+	assert.NotContains(t, identity.Permissions[1], accesscontrol.ActionUsersRead)
+	assert.NotContains(t, identity.Permissions[1], "folders:read")
+	assert.Equal(t, org.RoleNone, identity.GetOrgRole())
+	assert.False(t, identity.GetIsGrafanaAdmin())
+}
+
 func setupTestEnv() *PermissionsSync {
 	acMock := &acmock.Mock{
 		GetUserPermissionsFunc: func(ctx context.Context, siu identity.Requester, o accesscontrol.Options) ([]accesscontrol.Permission, error) {
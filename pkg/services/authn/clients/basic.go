@@ -8,6 +8,9 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
+// headerTOTPCode is the header users who have enabled a second factor send their code in.
+const headerTOTPCode = "X-Grafana-TOTP-Code"
+
 var (
 	errDecodingBasicAuthHeader = errutil.BadRequest("basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
 )
@@ -35,6 +38,9 @@ func (c *Basic) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 	if !ok {
 		return nil, errDecodingBasicAuthHeader.Errorf("failed to decode basic auth header")
 	}
+	if code := r.HTTPRequest.Header.Get(headerTOTPCode); code != "" {
+		r.SetMeta(authn.MetaKeyTOTPCode, code)
+	}
 
 	return c.client.AuthenticatePassword(ctx, r, username, password)
 }
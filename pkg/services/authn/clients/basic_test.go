@@ -18,6 +18,7 @@ func TestBasic_Authenticate(t *testing.T) {
 		client           authn.PasswordClient
 		expectedErr      error
 		expectedIdentity *authn.Identity
+		expectedTOTPCode string
 	}
 
 	tests := []TestCase{
@@ -27,6 +28,16 @@ func TestBasic_Authenticate(t *testing.T) {
 			client:           authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}},
 			expectedIdentity: &authn.Identity{ID: "user:1"},
 		},
+		{
+			desc: "should pass the second factor code of the request",
+			req: &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{
+				authorizationHeaderName:                 {encodeBasicAuth("user", "password")},
+				http.CanonicalHeaderKey(headerTOTPCode): {"123456"},
+			}}},
+			client:           authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}},
+			expectedIdentity: &authn.Identity{ID: "user:1"},
+			expectedTOTPCode: "123456",
+		},
 		{
 			desc:        "should fail when basic auth header could not be decoded",
 			req:         &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{authorizationHeaderName: {}}}},
@@ -46,6 +57,7 @@ func TestBasic_Authenticate(t *testing.T) {
 				assert.NoError(t, err)
 				assert.EqualValues(t, *tt.expectedIdentity, *identity)
 			}
+			assert.Equal(t, tt.expectedTOTPCode, tt.req.GetMeta(authn.MetaKeyTOTPCode))
 		})
 	}
 }
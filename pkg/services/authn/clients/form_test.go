@@ -15,9 +15,10 @@ import (
 
 func TestForm_Authenticate(t *testing.T) {
 	type testCase struct {
-		desc        string
-		req         *authn.Request
-		expectedErr error
+		desc             string
+		req              *authn.Request
+		expectedErr      error
+		expectedTOTPCode string
 	}
 
 	tests := []testCase{
@@ -28,6 +29,14 @@ func TestForm_Authenticate(t *testing.T) {
 				Body:   io.NopCloser(strings.NewReader(`{"user": "test", "password": "test"}`)),
 			}},
 		},
+		{
+			desc: "should pass the second factor code of the request",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				Header: map[string][]string{"Content-Type": {"application/json"}},
+				Body:   io.NopCloser(strings.NewReader(`{"user": "test", "password": "test", "totpCode": "123456"}`)),
+			}},
+			expectedTOTPCode: "123456",
+		},
 		{
 			desc: "should return error for bad request",
 			req: &authn.Request{HTTPRequest: &http.Request{
@@ -43,6 +52,7 @@ func TestForm_Authenticate(t *testing.T) {
 			c := ProvideForm(&authntest.FakePasswordClient{})
 			_, err := c.Authenticate(context.Background(), tt.req)
 			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedTOTPCode, tt.req.GetMeta(authn.MetaKeyTOTPCode))
 		})
 	}
 }
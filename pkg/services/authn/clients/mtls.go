@@ -0,0 +1,387 @@
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var _ authn.ContextAwareClient = new(MTLS)
+
+var (
+	errMTLSRevoked = errutil.Unauthorized(
+		"mtls.revoked", errutil.WithPublicMessage("Client certificate has been revoked"))
+	errMTLSMissingAttribute = errutil.Unauthorized(
+		"mtls.missing_attribute", errutil.WithPublicMessage("Missing mandatory attribute in client certificate"))
+	errMTLSInvalidServiceAccount = errutil.Unauthorized(
+		"mtls.invalid_service_account", errutil.WithPublicMessage("Client certificate does not match an enabled service account"))
+	errMTLSOrgMismatch = errutil.Unauthorized(
+		"mtls.organization-mismatch", errutil.WithPublicMessage("Service account does not belong to the requested organization"))
+	errMTLSInvalidRole = errutil.Forbidden(
+		"mtls.invalid_role", errutil.WithPublicMessage("Invalid role in client certificate"))
+	errMTLSUserConflict = errutil.Unauthorized(
+		"mtls.user_conflict", errutil.WithPublicMessage("Client certificate matches a user that does not sign in with client certificates"))
+)
+
+func ProvideMTLS(cfg *setting.Cfg, userService user.Service, authInfoService login.AuthInfoService) *MTLS {
+	return &MTLS{
+		cfg:             cfg,
+		log:             log.New(authn.ClientMTLS),
+		userService:     userService,
+		authInfoService: authInfoService,
+		roleMapping:     parseCertRoleMapping(cfg.MTLSAuthRoleMapping),
+		denyList:        &certDenyList{path: cfg.MTLSAuthDenyListFile},
+	}
+}
+
+// MTLS authenticates requests with the client certificate verified during the TLS handshake.
+// The certificate is mapped to a user, or to a service account, with the configured certificate attributes.
+type MTLS struct {
+	cfg             *setting.Cfg
+	log             log.Logger
+	userService     user.Service
+	authInfoService login.AuthInfoService
+	roleMapping     []certRoleMapping
+	denyList        *certDenyList
+}
+
+func (c *MTLS) Name() string {
+	return authn.ClientMTLS
+}
+
+func (c *MTLS) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	cert := r.HTTPRequest.TLS.VerifiedChains[0][0]
+
+	denied, err := c.denyList.contains(cert)
+	if err != nil {
+		c.log.FromContext(ctx).Error("Failed to read client certificate deny list", "path", c.denyList.path, "error", err)
+		return nil, errMTLSRevoked.Errorf("failed to read deny list: %w", err)
+	}
+	if denied {
+		return nil, errMTLSRevoked.Errorf("client certificate with serial %s is in the deny list", cert.SerialNumber.Text(16))
+	}
+
+	certLogin := firstCertAttribute(cert, c.cfg.MTLSAuthLoginAttribute)
+	email := firstCertAttribute(cert, c.cfg.MTLSAuthEmailAttribute)
+
+	if c.cfg.MTLSAuthAllowServiceAccounts && strings.HasPrefix(certLogin, serviceaccounts.ServiceAccountPrefix) {
+		return c.authenticateServiceAccount(ctx, r, certLogin)
+	}
+
+	if certLogin == "" && email == "" {
+		c.log.FromContext(ctx).Debug("Failed to get login or email from client certificate", "subject", cert.Subject.String())
+		return nil, errMTLSMissingAttribute.Errorf("missing login and email attribute in client certificate")
+	}
+
+	id := &authn.Identity{
+		Login:           certLogin,
+		Email:           email,
+		Name:            firstCertAttribute(cert, c.cfg.MTLSAuthNameAttribute),
+		AuthenticatedBy: login.MTLSAuthModule,
+		AuthID:          cert.Subject.String(),
+		OrgRoles:        map[int64]org.RoleType{},
+		ClientParams: authn.ClientParams{
+			SyncUser:        true,
+			FetchSyncedUser: true,
+			SyncPermissions: true,
+			SyncOrgRoles:    !c.cfg.MTLSAuthSkipOrgRoleSync,
+			AllowSignUp:     c.cfg.MTLSAuthAutoSignUp,
+		},
+	}
+	if err := c.linkExistingUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	orgRoles, isGrafanaAdmin, err := getRoles(c.cfg, func() (org.RoleType, *bool, error) {
+		if c.cfg.MTLSAuthSkipOrgRoleSync {
+			return "", nil, nil
+		}
+
+		role, grafanaAdmin := c.extractRoleAndAdmin(cert)
+		if c.cfg.MTLSAuthRoleAttributeStrict && !role.IsValid() {
+			return "", nil, errMTLSInvalidRole.Errorf("invalid role in client certificate: %s", role)
+		}
+
+		if !c.cfg.MTLSAuthAllowAssignGrafanaAdmin {
+			return role, nil, nil
+		}
+
+		return role, &grafanaAdmin, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id.OrgRoles = orgRoles
+	id.IsGrafanaAdmin = isGrafanaAdmin
+
+	return id, nil
+}
+
+// linkExistingUser sets the user the identity is synced to when the certificate subject is not linked to a user yet.
+// Users are found by their certificate subject, so a certificate is only linked to an existing user with the same
+// login or email if that user already signs in with client certificates. Otherwise a certificate with the login of
+// a local user, such as admin, would sign in as that user.
+func (c *MTLS) linkExistingUser(ctx context.Context, id *authn.Identity) error {
+	_, err := c.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{AuthModule: login.MTLSAuthModule, AuthId: id.AuthID})
+	if err == nil || !errors.Is(err, user.ErrUserNotFound) {
+		return err
+	}
+
+	usr, err := c.getUserByLoginOrEmail(ctx, id.Login, id.Email)
+	if err != nil || usr == nil {
+		return err
+	}
+
+	if _, err := c.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{UserId: usr.ID, AuthModule: login.MTLSAuthModule}); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return errMTLSUserConflict.Errorf("user %d matches the client certificate but does not sign in with client certificates", usr.ID)
+		}
+		return err
+	}
+
+	id.ClientParams.LookUpParams.UserID = &usr.ID
+	return nil
+}
+
+// getUserByLoginOrEmail returns the user with the email, or else with the login, or nil if there is none.
+func (c *MTLS) getUserByLoginOrEmail(ctx context.Context, certLogin, email string) (*user.User, error) {
+	if email != "" {
+		usr, err := c.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: email})
+		if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		if err == nil && usr != nil {
+			return usr, nil
+		}
+	}
+
+	if certLogin != "" {
+		usr, err := c.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: certLogin})
+		if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		if err == nil && usr != nil {
+			return usr, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *MTLS) authenticateServiceAccount(ctx context.Context, r *authn.Request, saLogin string) (*authn.Identity, error) {
+	sa, err := c.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: saLogin})
+	if err != nil {
+		return nil, errMTLSInvalidServiceAccount.Errorf("failed to get service account %s: %w", saLogin, err)
+	}
+	if !sa.IsServiceAccount || sa.IsDisabled {
+		return nil, errMTLSInvalidServiceAccount.Errorf("%s is not an enabled service account", saLogin)
+	}
+
+	if r.OrgID == 0 {
+		r.OrgID = sa.OrgID
+	} else if r.OrgID != sa.OrgID {
+		return nil, errMTLSOrgMismatch.Errorf("service account %s does not belong to organization %d", saLogin, r.OrgID)
+	}
+
+	usr, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{
+		UserID: sa.ID,
+		OrgID:  sa.OrgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr, authn.ClientParams{SyncPermissions: true}, login.MTLSAuthModule), nil
+}
+
+func (c *MTLS) Test(ctx context.Context, r *authn.Request) bool {
+	if !c.cfg.MTLSAuthEnabled || r.HTTPRequest == nil || r.HTTPRequest.TLS == nil {
+		return false
+	}
+
+	chains := r.HTTPRequest.TLS.VerifiedChains
+	return len(chains) > 0 && len(chains[0]) > 0
+}
+
+func (c *MTLS) Priority() uint {
+	return 25
+}
+
+// extractRoleAndAdmin returns the role of the first configured role mapping that matches a value of the role attribute.
+// Without role mappings, the value of the role attribute is used as the role.
+func (c *MTLS) extractRoleAndAdmin(cert *x509.Certificate) (org.RoleType, bool) {
+	values := certAttribute(cert, c.cfg.MTLSAuthRoleAttribute)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	role := ""
+	if len(c.roleMapping) == 0 {
+		role = values[0]
+	} else {
+	mappings:
+		for _, m := range c.roleMapping {
+			for _, v := range values {
+				if v == m.value {
+					role = m.role
+					break mappings
+				}
+			}
+		}
+	}
+
+	if role == roleGrafanaAdmin {
+		return org.RoleAdmin, true
+	}
+	return org.RoleType(role), false
+}
+
+type certRoleMapping struct {
+	value string
+	role  string
+}
+
+// parseCertRoleMapping parses mappings of certificate attribute values to roles, such as "platform:Admin,dev:Editor".
+func parseCertRoleMapping(mapping string) []certRoleMapping {
+	var mappings []certRoleMapping
+	for _, entry := range strings.Split(mapping, ",") {
+		value, role, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		mappings = append(mappings, certRoleMapping{value: strings.TrimSpace(value), role: strings.TrimSpace(role)})
+	}
+	return mappings
+}
+
+func firstCertAttribute(cert *x509.Certificate, attribute string) string {
+	values := certAttribute(cert, attribute)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// certAttribute returns the values of a certificate attribute: subject.CN, subject.O, subject.OU, san.email,
+// san.dns or san.uri.
+func certAttribute(cert *x509.Certificate, attribute string) []string {
+	switch strings.ToLower(attribute) {
+	case "subject.cn":
+		if cert.Subject.CommonName == "" {
+			return nil
+		}
+		return []string{cert.Subject.CommonName}
+	case "subject.o":
+		return cert.Subject.Organization
+	case "subject.ou":
+		return cert.Subject.OrganizationalUnit
+	case "san.email":
+		return cert.EmailAddresses
+	case "san.dns":
+		return cert.DNSNames
+	case "san.uri":
+		uris := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+		}
+		return uris
+	}
+	return nil
+}
+
+// certDenyList is a file of revoked certificates, with one serial number or SHA-256 fingerprint in hex per line.
+// Colons in the hex values are ignored, and lines starting with # are comments. The file is read again when it changes.
+type certDenyList struct {
+	path string
+
+	mu      sync.RWMutex
+	modTime time.Time
+	entries map[string]struct{}
+}
+
+func (d *certDenyList) contains(cert *x509.Certificate) (bool, error) {
+	if d.path == "" {
+		return false, nil
+	}
+	if err := d.reload(); err != nil {
+		return false, err
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, serialDenied := d.entries[cert.SerialNumber.Text(16)]
+	_, fingerprintDenied := d.entries[hex.EncodeToString(fingerprint[:])]
+	return serialDenied || fingerprintDenied, nil
+}
+
+func (d *certDenyList) reload() error {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	current := info.ModTime().Equal(d.modTime)
+	d.mu.RUnlock()
+	if current {
+		return nil
+	}
+
+	// nolint:gosec
+	content, err := os.ReadFile(d.path)
+	if err != nil {
+		return err
+	}
+
+	entries := map[string]struct{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries[normalizeCertHex(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", d.path, err)
+	}
+
+	d.mu.Lock()
+	d.entries = entries
+	d.modTime = info.ModTime()
+	d.mu.Unlock()
+	return nil
+}
+
+// normalizeCertHex lower-cases hex values and strips colons and leading zeros of serial numbers,
+// to match the format of big.Int.Text(16).
+func normalizeCertHex(value string) string {
+	value = strings.ToLower(strings.ReplaceAll(value, ":", ""))
+	if len(value) == sha256.Size*2 {
+		return value
+	}
+	if trimmed := strings.TrimLeft(value, "0"); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/login/authinfotest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func newMTLSRequest(cert *x509.Certificate) *authn.Request {
+	req := &http.Request{Header: map[string][]string{}}
+	if cert != nil {
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+	return &authn.Request{HTTPRequest: req}
+}
+
+func testCertificate() *x509.Certificate {
+	return &x509.Certificate{
+		Raw:          []byte("raw certificate"),
+		SerialNumber: big.NewInt(0x0a1b2c),
+		Subject: pkix.Name{
+			CommonName:         "eai-doe",
+			Organization:       []string{"Cor Po"},
+			OrganizationalUnit: []string{"developers", "platform"},
+		},
+		EmailAddresses: []string{"eai.doe@cor.po"},
+	}
+}
+
+// newMTLSAuthInfoService returns an auth info service without users linked to certificates.
+func newMTLSAuthInfoService() *authinfotest.FakeService {
+	return &authinfotest.FakeService{ExpectedError: user.ErrUserNotFound}
+}
+
+func TestMTLS_Test(t *testing.T) {
+	c := ProvideMTLS(&setting.Cfg{MTLSAuthEnabled: true}, usertest.NewUserServiceFake(), newMTLSAuthInfoService())
+
+	assert.True(t, c.Test(context.Background(), newMTLSRequest(testCertificate())))
+	assert.False(t, c.Test(context.Background(), newMTLSRequest(nil)), "requests without a verified certificate are ignored")
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{TLS: &tls.ConnectionState{}}}))
+}
+
+func TestMTLS_Authenticate(t *testing.T) {
+	defaultCfg := func() *setting.Cfg {
+		return &setting.Cfg{
+			MTLSAuthEnabled:        true,
+			MTLSAuthLoginAttribute: "subject.CN",
+			MTLSAuthEmailAttribute: "san.email",
+			MTLSAuthNameAttribute:  "subject.CN",
+			MTLSAuthAutoSignUp:     true,
+			MTLSAuthRoleAttribute:  "subject.OU",
+		}
+	}
+
+	t.Run("maps the certificate to a user", func(t *testing.T) {
+		cfg := defaultCfg()
+		cfg.MTLSAuthRoleMapping = "platform:Admin, developers:Editor"
+		c := ProvideMTLS(cfg, usertest.NewUserServiceFake(), newMTLSAuthInfoService())
+
+		id, err := c.Authenticate(context.Background(), newMTLSRequest(testCertificate()))
+		require.NoError(t, err)
+		assert.Equal(t, "eai-doe", id.Login)
+		assert.Equal(t, "eai.doe@cor.po", id.Email)
+		assert.Equal(t, login.MTLSAuthModule, id.AuthenticatedBy)
+		assert.Equal(t, "CN=eai-doe,OU=developers+OU=platform,O=Cor Po", id.AuthID)
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleAdmin}, id.OrgRoles, "the first matching role mapping is used")
+		assert.Nil(t, id.IsGrafanaAdmin)
+		assert.True(t, id.ClientParams.AllowSignUp)
+		assert.True(t, id.ClientParams.SyncOrgRoles)
+		assert.Nil(t, id.ClientParams.LookUpParams.Login, "users are only looked up by their certificate subject")
+		assert.Nil(t, id.ClientParams.LookUpParams.Email)
+		assert.Nil(t, id.ClientParams.LookUpParams.UserID)
+	})
+
+	t.Run("uses the attribute as the role without role mappings", func(t *testing.T) {
+		cfg := defaultCfg()
+		cfg.MTLSAuthRoleAttribute = "subject.O"
+		cfg.MTLSAuthRoleAttributeStrict = true
+		c := ProvideMTLS(cfg, usertest.NewUserServiceFake(), newMTLSAuthInfoService())
+
+		_, err := c.Authenticate(context.Background(), newMTLSRequest(testCertificate()))
+		assert.ErrorIs(t, err, errMTLSInvalidRole)
+
+		cert := testCertificate()
+		cert.Subject.Organization = []string{roleGrafanaAdmin}
+		cfg.MTLSAuthAllowAssignGrafanaAdmin = true
+		id, err := c.Authenticate(context.Background(), newMTLSRequest(cert))
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleAdmin}, id.OrgRoles)
+		assert.True(t, *id.IsGrafanaAdmin)
+	})
+
+	t.Run("requires a login or an email", func(t *testing.T) {
+		cert := testCertificate()
+		cert.Subject.CommonName = ""
+		cert.EmailAddresses = nil
+		c := ProvideMTLS(defaultCfg(), usertest.NewUserServiceFake(), newMTLSAuthInfoService())
+
+		_, err := c.Authenticate(context.Background(), newMTLSRequest(cert))
+		assert.ErrorIs(t, err, errMTLSMissingAttribute)
+	})
+
+	t.Run("maps the certificate to a service account", func(t *testing.T) {
+		cfg := defaultCfg()
+		cfg.MTLSAuthAllowServiceAccounts = true
+		userService := &usertest.FakeUserService{
+			ExpectedUser:         &user.User{ID: 2, OrgID: 1, Login: "sa-ci", IsServiceAccount: true},
+			ExpectedSignedInUser: &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleViewer, IsServiceAccount: true},
+		}
+		c := ProvideMTLS(cfg, userService, newMTLSAuthInfoService())
+
+		cert := testCertificate()
+		cert.Subject.CommonName = "sa-ci"
+		id, err := c.Authenticate(context.Background(), newMTLSRequest(cert))
+		require.NoError(t, err)
+		assert.Equal(t, "service-account:2", id.ID)
+		assert.Equal(t, login.MTLSAuthModule, id.AuthenticatedBy)
+
+		_, err = c.Authenticate(context.Background(), &authn.Request{OrgID: 2, HTTPRequest: newMTLSRequest(cert).HTTPRequest})
+		assert.ErrorIs(t, err, errMTLSOrgMismatch)
+
+		userService.ExpectedUser.IsDisabled = true
+		_, err = c.Authenticate(context.Background(), newMTLSRequest(cert))
+		assert.ErrorIs(t, err, errMTLSInvalidServiceAccount)
+	})
+
+	t.Run("does not sign in as existing users that do not sign in with client certificates", func(t *testing.T) {
+		cert := testCertificate()
+		cert.Subject.CommonName = "admin"
+		cert.EmailAddresses = nil
+		userService := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1, Login: "admin", IsAdmin: true}}
+		c := ProvideMTLS(defaultCfg(), userService, newMTLSAuthInfoService())
+
+		_, err := c.Authenticate(context.Background(), newMTLSRequest(cert))
+		assert.ErrorIs(t, err, errMTLSUserConflict)
+	})
+
+	t.Run("links existing users that sign in with client certificates", func(t *testing.T) {
+		userService := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 3, Login: "eai-doe"}}
+		authInfoService := &authinfotest.FakeService{ExpectedUserAuth: &login.UserAuth{UserId: 3, AuthModule: login.MTLSAuthModule}}
+		c := ProvideMTLS(defaultCfg(), userService, authInfoService)
+
+		id, err := c.Authenticate(context.Background(), newMTLSRequest(testCertificate()))
+		require.NoError(t, err)
+		assert.Nil(t, id.ClientParams.LookUpParams.UserID, "the user is found by the certificate subject")
+	})
+
+	t.Run("rejects certificates in the deny list", func(t *testing.T) {
+		cert := testCertificate()
+		fingerprint := sha256.Sum256(cert.Raw)
+		path := filepath.Join(t.TempDir(), "deny-list")
+
+		cfg := defaultCfg()
+		cfg.MTLSAuthDenyListFile = path
+		c := ProvideMTLS(cfg, usertest.NewUserServiceFake(), newMTLSAuthInfoService())
+
+		require.NoError(t, os.WriteFile(path, []byte("# revoked certificates\n"), 0600))
+		_, err := c.Authenticate(context.Background(), newMTLSRequest(cert))
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte("00:0A:1B:2C\n"), 0600))
+		require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+		_, err = c.Authenticate(context.Background(), newMTLSRequest(cert))
+		assert.ErrorIs(t, err, errMTLSRevoked, "the deny list is read again when it changes")
+
+		require.NoError(t, os.WriteFile(path, []byte(hex.EncodeToString(fingerprint[:])+"\n"), 0600))
+		require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+		_, err = c.Authenticate(context.Background(), newMTLSRequest(cert))
+		assert.ErrorIs(t, err, errMTLSRevoked)
+	})
+}
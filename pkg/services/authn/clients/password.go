@@ -3,10 +3,13 @@ package clients
 import (
 	"context"
 	"errors"
+	"net"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -18,14 +21,20 @@ var (
 
 var _ authn.PasswordClient = new(Password)
 
-func ProvidePassword(loginAttempts loginattempt.Service, clients ...authn.PasswordClient) *Password {
-	return &Password{loginAttempts, clients, log.New("authn.password")}
+func ProvidePassword(cfg *setting.Cfg, loginAttempts loginattempt.Service, clients ...authn.PasswordClient) *Password {
+	logger := log.New("authn.password")
+	trustedProxies, err := parseAcceptList(cfg.LoginProtectionTrustedProxies)
+	if err != nil {
+		logger.Error("Failed to parse trusted proxies of login protection, forwarded client addresses are ignored", "error", err)
+	}
+	return &Password{loginAttempts, clients, trustedProxies, logger}
 }
 
 type Password struct {
-	loginAttempts loginattempt.Service
-	clients       []authn.PasswordClient
-	log           log.Logger
+	loginAttempts  loginattempt.Service
+	clients        []authn.PasswordClient
+	trustedProxies []*net.IPNet
+	log            log.Logger
 }
 
 func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, username, password string) (*authn.Identity, error) {
@@ -39,6 +48,20 @@ func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, u
 		return nil, errPasswordAuthFailed.Errorf("too many consecutive incorrect login attempts for user - login for user temporarily blocked")
 	}
 
+	var ipAddress string
+	if r.HTTPRequest != nil {
+		ipAddress = c.clientIPAddress(r.HTTPRequest)
+		r.SetMeta(authn.MetaKeyIPAddress, ipAddress)
+	}
+
+	ok, err = c.loginAttempts.ValidateIPAddress(ctx, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errPasswordAuthFailed.Errorf("too many incorrect login attempts from ip address or subnet - login temporarily blocked")
+	}
+
 	if len(password) == 0 {
 		return nil, errPasswordAuthFailed.Errorf("no password provided")
 	}
@@ -57,8 +80,26 @@ func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, u
 	}
 
 	if errors.Is(clientErrs, errInvalidPassword) {
-		_ = c.loginAttempts.Add(ctx, username, web.RemoteAddr(r.HTTPRequest))
+		_ = c.loginAttempts.Add(ctx, username, ipAddress)
 	}
 
 	return nil, errPasswordAuthFailed.Errorf("failed to authenticate identity: %w", clientErrs)
 }
+
+// clientIPAddress returns the IP address that login attempts are counted for. Clients can set the X-Real-IP and
+// X-Forwarded-For headers to any address, so they are only used for requests from trusted proxies.
+func (c *Password) clientIPAddress(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, proxy := range c.trustedProxies {
+			if proxy.Contains(ip) {
+				return web.RemoteAddr(req)
+			}
+		}
+	}
+	return host
+}
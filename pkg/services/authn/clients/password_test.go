@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
 	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestPassword_AuthenticatePassword(t *testing.T) {
@@ -67,7 +69,7 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvidePassword(loginattempttest.FakeLoginAttemptService{ExpectedValid: !tt.blockLogin}, tt.clients...)
+			c := ProvidePassword(setting.NewCfg(), loginattempttest.FakeLoginAttemptService{ExpectedValid: !tt.blockLogin}, tt.clients...)
 
 			identity, err := c.AuthenticatePassword(context.Background(), tt.req, tt.username, tt.password)
 			if tt.expectedErr != nil {
@@ -80,3 +82,24 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestPassword_ClientIPAddress(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.LoginProtectionTrustedProxies = "10.0.0.1, 192.168.0.0/24"
+
+	authenticate := func(remoteAddr string, header http.Header) string {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: true}
+		c := ProvidePassword(cfg, loginAttempts, authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}})
+		r := &authn.Request{HTTPRequest: &http.Request{RemoteAddr: remoteAddr, Header: header}}
+		_, err := c.AuthenticatePassword(context.Background(), r, "test", "test")
+		assert.NoError(t, err)
+		return loginAttempts.IPAddress
+	}
+
+	assert.Equal(t, "203.0.113.7", authenticate("203.0.113.7:4321", http.Header{}))
+	assert.Equal(t, "203.0.113.7", authenticate("203.0.113.7:4321", http.Header{"X-Forwarded-For": {"198.51.100.1"}}),
+		"forwarded addresses of untrusted clients are ignored")
+	assert.Equal(t, "203.0.113.7", authenticate("203.0.113.7:4321", http.Header{"X-Real-Ip": {"198.51.100.1"}}))
+	assert.Equal(t, "198.51.100.1", authenticate("10.0.0.1:4321", http.Header{"X-Forwarded-For": {"198.51.100.1, 10.0.0.1"}}))
+	assert.Equal(t, "198.51.100.1", authenticate("192.168.0.20:4321", http.Header{"X-Real-Ip": {"198.51.100.1"}}))
+}
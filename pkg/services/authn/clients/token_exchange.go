@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/signingkeys"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var _ authn.ContextAwareClient = new(TokenExchange)
+
+var (
+	errExchangedTokenInvalid = errutil.Unauthorized(
+		"token-exchange.invalid", errutil.WithPublicMessage("Failed to verify token"))
+	errExchangedTokenOrgMismatch = errutil.Unauthorized(
+		"token-exchange.organization-mismatch", errutil.WithPublicMessage("Token was not issued for the requested organization"))
+)
+
+func ProvideTokenExchange(cfg *setting.Cfg, userService user.Service, signingKeys signingkeys.Service) *TokenExchange {
+	return &TokenExchange{
+		cfg:         cfg,
+		log:         log.New(authn.ClientTokenExchange),
+		userService: userService,
+		signingKeys: signingKeys,
+	}
+}
+
+// TokenExchange authenticates requests with the tokens issued by token exchange. The permissions of the identity are
+// limited to the permissions of the token.
+type TokenExchange struct {
+	cfg         *setting.Cfg
+	log         log.Logger
+	userService user.Service
+	signingKeys signingkeys.Service
+}
+
+func (c *TokenExchange) Name() string {
+	return authn.ClientTokenExchange
+}
+
+func (c *TokenExchange) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	claims, err := c.verify(ctx, getBearerToken(r))
+	if err != nil {
+		c.log.FromContext(ctx).Debug("Failed to verify exchanged token", "error", err)
+		return nil, errExchangedTokenInvalid.Errorf("failed to verify exchanged token: %w", err)
+	}
+
+	namespace, identifier, _ := strings.Cut(claims.Subject, ":")
+	if !identity.IsNamespace(namespace, identity.NamespaceUser, identity.NamespaceServiceAccount) {
+		return nil, errExchangedTokenInvalid.Errorf("unsupported subject %q", claims.Subject)
+	}
+	userID, err := identity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		return nil, errExchangedTokenInvalid.Errorf("invalid subject %q: %w", claims.Subject, err)
+	}
+
+	if len(claims.Audience) != 1 || !strings.HasPrefix(claims.Audience[0], "org:") {
+		return nil, errExchangedTokenInvalid.Errorf("invalid audience %v", claims.Audience)
+	}
+	orgID, err := strconv.ParseInt(strings.TrimPrefix(claims.Audience[0], "org:"), 10, 64)
+	if err != nil {
+		return nil, errExchangedTokenInvalid.Errorf("invalid audience %v: %w", claims.Audience, err)
+	}
+	if r.OrgID != 0 && r.OrgID != orgID {
+		return nil, errExchangedTokenOrgMismatch.Errorf("token was issued for organization %d, not %d", orgID, r.OrgID)
+	}
+
+	usr, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{UserID: userID, OrgID: orgID})
+	if err != nil {
+		return nil, errExchangedTokenInvalid.Errorf("failed to get %s: %w", claims.Subject, err)
+	}
+	if usr.IsServiceAccount != (namespace == identity.NamespaceServiceAccount) {
+		return nil, errExchangedTokenInvalid.Errorf("subject %q doesn't match the identity", claims.Subject)
+	}
+
+	permissions := claims.Permissions
+	if permissions == nil {
+		permissions = map[string][]string{}
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(namespace, usr.UserID), usr,
+		authn.ClientParams{SyncPermissions: true, LimitPermissions: permissions}, login.TokenExchangeModule), nil
+}
+
+func (c *TokenExchange) Test(ctx context.Context, r *authn.Request) bool {
+	if !c.cfg.TokenExchangeEnabled {
+		return false
+	}
+
+	token := getBearerToken(r)
+	if token == "" {
+		return false
+	}
+
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil || len(parsed.Headers) != 1 {
+		return false
+	}
+
+	return strings.HasPrefix(parsed.Headers[0].KeyID, auth.TokenExchangeKeyPrefix+"-")
+}
+
+func (c *TokenExchange) Priority() uint {
+	// before the JWT client, exchanged tokens are signed by Grafana
+	return 16
+}
+
+func (c *TokenExchange) verify(ctx context.Context, token string) (*auth.ExchangedTokenClaims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, fmt.Errorf("only one header supported, got %d", len(parsed.Headers))
+	}
+	if parsed.Headers[0].Algorithm != string(jose.ES256) {
+		return nil, fmt.Errorf("invalid algorithm: %s", parsed.Headers[0].Algorithm)
+	}
+
+	key, err := c.publicKey(ctx, parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims auth.ExchangedTokenClaims
+	if err := parsed.Claims(key, &claims); err != nil {
+		return nil, fmt.Errorf("failed to verify the signature: %w", err)
+	}
+
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("missing 'exp' claim")
+	}
+	if err := claims.ValidateWithLeeway(jwt.Expected{Issuer: c.cfg.AppURL, Time: time.Now()}, 0); err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	return &claims, nil
+}
+
+// publicKey returns the key tokens signed with keyID are verified with. Only keys used for token exchange are
+// accepted, so that other tokens signed by Grafana, such as ID tokens, are rejected.
+func (c *TokenExchange) publicKey(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	if !strings.HasPrefix(keyID, auth.TokenExchangeKeyPrefix+"-") {
+		return nil, fmt.Errorf("invalid key id: %s", keyID)
+	}
+
+	currentKeyID, key, err := c.signingKeys.GetOrCreatePrivateKey(ctx, auth.TokenExchangeKeyPrefix, jose.ES256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing key: %w", err)
+	}
+	if keyID == currentKeyID {
+		return key.Public(), nil
+	}
+
+	// tokens issued before the rotation of the key
+	jwks, err := c.signingKeys.GetJWKS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+	keys := jwks.Key(keyID)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("unknown key id: %s", keyID)
+	}
+	return keys[0].Key, nil
+}
+
+func getBearerToken(r *authn.Request) string {
+	if r.HTTPRequest == nil {
+		return ""
+	}
+	header := r.HTTPRequest.Header.Get(authorizationHeaderName)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, bearerPrefix)
+}
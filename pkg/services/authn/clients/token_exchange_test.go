@@ -0,0 +1,155 @@
+package clients
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/signingkeys/signingkeystest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testExchangeKeyID = "token-exchange-2024-01-es256"
+
+func signExchangedToken(t *testing.T, key *ecdsa.PrivateKey, keyID string, claims *auth.ExchangedTokenClaims) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, &jose.SignerOptions{
+		ExtraHeaders: map[jose.HeaderKey]any{"kid": keyID},
+	})
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func exchangedTokenClaims(subject string, expiry time.Time) *auth.ExchangedTokenClaims {
+	return &auth.ExchangedTokenClaims{
+		Claims: jwt.Claims{
+			ID:       "1234",
+			Issuer:   "http://localhost:3000/",
+			Subject:  subject,
+			Audience: jwt.Audience{"org:1"},
+			Expiry:   jwt.NewNumericDate(expiry),
+			IssuedAt: jwt.NewNumericDate(expiry.Add(-time.Minute)),
+		},
+		Permissions: map[string][]string{"dashboards:read": {"dashboards:uid:a"}},
+	}
+}
+
+func newBearerRequest(orgID int64, token string) *authn.Request {
+	return &authn.Request{OrgID: orgID, HTTPRequest: &http.Request{Header: http.Header{"Authorization": {"Bearer " + token}}}}
+}
+
+func TestTokenExchange_Test(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	claims := exchangedTokenClaims("user:2", time.Now().Add(time.Minute))
+
+	c := ProvideTokenExchange(&setting.Cfg{TokenExchangeEnabled: true}, usertest.NewUserServiceFake(), &signingkeystest.FakeSigningKeysService{})
+	assert.True(t, c.Test(context.Background(), newBearerRequest(1, signExchangedToken(t, key, testExchangeKeyID, claims))))
+	assert.False(t, c.Test(context.Background(), newBearerRequest(1, signExchangedToken(t, key, "id-2024-01-es256", claims))), "tokens signed with other keys are ignored")
+	assert.False(t, c.Test(context.Background(), newBearerRequest(1, "glsa_token")))
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{Header: http.Header{}}}))
+
+	disabled := ProvideTokenExchange(&setting.Cfg{}, usertest.NewUserServiceFake(), &signingkeystest.FakeSigningKeysService{})
+	assert.False(t, disabled.Test(context.Background(), newBearerRequest(1, signExchangedToken(t, key, testExchangeKeyID, claims))))
+}
+
+func TestTokenExchange_Authenticate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cfg := &setting.Cfg{AppURL: "http://localhost:3000/", TokenExchangeEnabled: true}
+	signingKeys := &signingkeystest.FakeSigningKeysService{ExpectedKeyID: testExchangeKeyID, ExpectedSinger: key}
+	now := time.Now()
+
+	type testCase struct {
+		desc          string
+		token         string
+		orgID         int64
+		signedInUser  *user.SignedInUser
+		expectedID    string
+		expectedError error
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should authenticate the user of the token with the permissions of the token",
+			token:        signExchangedToken(t, key, testExchangeKeyID, exchangedTokenClaims("user:2", now.Add(time.Minute))),
+			orgID:        1,
+			signedInUser: &user.SignedInUser{UserID: 2, OrgID: 1, Login: "alice"},
+			expectedID:   "user:2",
+		},
+		{
+			desc:         "should authenticate service accounts",
+			token:        signExchangedToken(t, key, testExchangeKeyID, exchangedTokenClaims("service-account:3", now.Add(time.Minute))),
+			signedInUser: &user.SignedInUser{UserID: 3, OrgID: 1, Login: "sa-scripts", IsServiceAccount: true},
+			expectedID:   "service-account:3",
+		},
+		{
+			desc:          "should reject expired tokens",
+			token:         signExchangedToken(t, key, testExchangeKeyID, exchangedTokenClaims("user:2", now.Add(-time.Minute))),
+			signedInUser:  &user.SignedInUser{UserID: 2, OrgID: 1},
+			expectedError: errExchangedTokenInvalid,
+		},
+		{
+			desc:          "should reject tokens with an invalid signature",
+			token:         signExchangedToken(t, otherKey, testExchangeKeyID, exchangedTokenClaims("user:2", now.Add(time.Minute))),
+			signedInUser:  &user.SignedInUser{UserID: 2, OrgID: 1},
+			expectedError: errExchangedTokenInvalid,
+		},
+		{
+			desc:          "should reject tokens signed with a key not used for token exchange",
+			token:         signExchangedToken(t, key, "id-2024-01-es256", exchangedTokenClaims("user:2", now.Add(time.Minute))),
+			signedInUser:  &user.SignedInUser{UserID: 2, OrgID: 1},
+			expectedError: errExchangedTokenInvalid,
+		},
+		{
+			desc:          "should reject tokens issued for another organization",
+			token:         signExchangedToken(t, key, testExchangeKeyID, exchangedTokenClaims("user:2", now.Add(time.Minute))),
+			orgID:         2,
+			signedInUser:  &user.SignedInUser{UserID: 2, OrgID: 1},
+			expectedError: errExchangedTokenOrgMismatch,
+		},
+		{
+			desc:          "should reject tokens whose subject doesn't match the identity",
+			token:         signExchangedToken(t, key, testExchangeKeyID, exchangedTokenClaims("user:3", now.Add(time.Minute))),
+			signedInUser:  &user.SignedInUser{UserID: 3, OrgID: 1, IsServiceAccount: true},
+			expectedError: errExchangedTokenInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := ProvideTokenExchange(cfg, &usertest.FakeUserService{ExpectedSignedInUser: tt.signedInUser}, signingKeys)
+
+			identity, err := c.Authenticate(context.Background(), newBearerRequest(tt.orgID, tt.token))
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedID, identity.ID)
+			assert.Equal(t, login.TokenExchangeModule, identity.AuthenticatedBy)
+			assert.True(t, identity.ClientParams.SyncPermissions)
+			assert.Equal(t, map[string][]string{"dashboards:read": {"dashboards:uid:a"}}, identity.ClientParams.LimitPermissions)
+		})
+	}
+}
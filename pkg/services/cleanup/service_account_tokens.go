@@ -0,0 +1,135 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+const (
+	tokenExpiryWarningNamespace = "serviceaccounts"
+	tokenExpiryWarningLastRun   = "token_expiry_warning_last_run"
+	tokenExpiryWarningTemplate  = "service_account_token_expiring"
+)
+
+type expiringTokenData struct {
+	Name               string
+	ServiceAccountName string
+	Expires            string
+	URL                string
+}
+
+// warnAboutExpiringServiceAccountTokens emails the admins of each organization about the service account tokens
+// that expire within the warning period. The end of the window of the last run is stored, so that each token is
+// only warned about once, whichever instance runs the job.
+func (srv *CleanUpService) warnAboutExpiringServiceAccountTokens(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.SATokenExpiryWarningPeriod <= 0 {
+		return
+	}
+
+	err := srv.ServerLockService.LockExecuteAndRelease(ctx, "warn about expiring service account tokens", 10*time.Minute, func(ctx context.Context) {
+		now := time.Now()
+		after, before, err := srv.expiryWarningWindow(ctx, now)
+		if err != nil {
+			logger.Error("Failed to read last run of service account token expiry warnings", "error", err)
+			return
+		}
+
+		tokens, err := srv.serviceAccountsService.ListExpiringTokens(ctx, &serviceaccounts.GetExpiringSATokensQuery{
+			ExpiresAfter:  after,
+			ExpiresBefore: before,
+		})
+		if err != nil {
+			logger.Error("Failed to list expiring service account tokens", "error", err)
+			return
+		}
+
+		byOrg := make(map[int64][]*serviceaccounts.ExpiringToken)
+		for _, token := range tokens {
+			byOrg[token.OrgID] = append(byOrg[token.OrgID], token)
+		}
+		for orgID, orgTokens := range byOrg {
+			if err := srv.sendTokenExpiryWarning(ctx, orgID, orgTokens); err != nil {
+				logger.Error("Failed to warn about expiring service account tokens", "orgId", orgID, "error", err)
+			}
+		}
+
+		if err := srv.kvStore.Set(ctx, 0, tokenExpiryWarningNamespace, tokenExpiryWarningLastRun, now.Format(time.RFC3339)); err != nil {
+			logger.Error("Failed to store last run of service account token expiry warnings", "error", err)
+			return
+		}
+		logger.Debug("Warned about expiring service account tokens", "tokens", len(tokens), "orgs", len(byOrg))
+	})
+	if err != nil {
+		logger.Debug("Did not warn about expiring service account tokens", "error", err)
+	}
+}
+
+// expiryWarningWindow returns the range of expiry times of the tokens to warn about. Tokens that entered the warning
+// period by the last run have already been warned about.
+func (srv *CleanUpService) expiryWarningWindow(ctx context.Context, now time.Time) (time.Time, time.Time, error) {
+	period := srv.Cfg.SATokenExpiryWarningPeriod
+	after, before := now, now.Add(period)
+
+	value, ok, err := srv.kvStore.Get(ctx, 0, tokenExpiryWarningNamespace, tokenExpiryWarningLastRun)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !ok {
+		return after, before, nil
+	}
+
+	lastRun, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if warnedUntil := lastRun.Add(period); warnedUntil.After(after) {
+		after = warnedUntil
+	}
+	return after, before, nil
+}
+
+func (srv *CleanUpService) sendTokenExpiryWarning(ctx context.Context, orgID int64, tokens []*serviceaccounts.ExpiringToken) error {
+	o, err := srv.orgService.GetByID(ctx, &org.GetOrgByIDQuery{ID: orgID})
+	if err != nil {
+		return err
+	}
+
+	users, err := srv.orgService.GetOrgUsers(ctx, &org.GetOrgUsersQuery{OrgID: orgID, DontEnforceAccessControl: true})
+	if err != nil {
+		return err
+	}
+	var to []string
+	for _, u := range users {
+		if u.Role == string(org.RoleAdmin) && !u.IsDisabled && u.Email != "" {
+			to = append(to, u.Email)
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	data := make([]expiringTokenData, 0, len(tokens))
+	for _, token := range tokens {
+		data = append(data, expiringTokenData{
+			Name:               token.Name,
+			ServiceAccountName: token.ServiceAccountName,
+			Expires:            time.Unix(token.Expires, 0).UTC().Format(time.RFC1123),
+			URL:                fmt.Sprintf("%sorg/serviceaccounts/%d", srv.Cfg.AppURL, token.ServiceAccountID),
+		})
+	}
+
+	return srv.notificationService.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+		To:       to,
+		Template: tokenExpiryWarningTemplate,
+		Data: map[string]any{
+			"OrgName": o.Name,
+			"Tokens":  data,
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package cleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestExpiryWarningWindow(t *testing.T) {
+	ctx := context.Background()
+	cfg := setting.NewCfg()
+	cfg.SATokenExpiryWarningPeriod = 24 * time.Hour
+	store := kvstore.NewFakeKVStore()
+	service := CleanUpService{Cfg: cfg, kvStore: store}
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("first run warns about tokens that expire within the warning period", func(t *testing.T) {
+		after, before, err := service.expiryWarningWindow(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, now, after)
+		assert.Equal(t, now.Add(24*time.Hour), before)
+	})
+
+	t.Run("later runs skip tokens that have already been warned about", func(t *testing.T) {
+		require.NoError(t, store.Set(ctx, 0, tokenExpiryWarningNamespace, tokenExpiryWarningLastRun, now.Add(-time.Hour).Format(time.RFC3339)))
+		after, before, err := service.expiryWarningWindow(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(23*time.Hour), after)
+		assert.Equal(t, now.Add(24*time.Hour), before)
+	})
+
+	t.Run("runs after a long pause start at the current time", func(t *testing.T) {
+		require.NoError(t, store.Set(ctx, 0, tokenExpiryWarningNamespace, tokenExpiryWarningLastRun, now.Add(-48*time.Hour).Format(time.RFC3339)))
+		after, _, err := service.expiryWarningWindow(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, now, after)
+	})
+}
+
+func TestSendTokenExpiryWarning(t *testing.T) {
+	ctx := context.Background()
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	tokens := []*serviceaccounts.ExpiringToken{
+		{ID: 1, Name: "deploy", OrgID: 1, ServiceAccountID: 2, ServiceAccountName: "ci", Expires: 1696161600},
+	}
+
+	t.Run("emails the admins of the organization", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 1, Name: "Main Org."}
+		orgService.ExpectedOrgUsers = []*org.OrgUserDTO{
+			{Email: "admin@example.com", Role: string(org.RoleAdmin)},
+			{Email: "disabled@example.com", Role: string(org.RoleAdmin), IsDisabled: true},
+			{Email: "editor@example.com", Role: string(org.RoleEditor)},
+		}
+		mailer := notifications.MockNotificationService()
+		service := CleanUpService{Cfg: cfg, log: log.NewNopLogger(), orgService: orgService, notificationService: mailer}
+
+		require.NoError(t, service.sendTokenExpiryWarning(ctx, 1, tokens))
+		assert.Equal(t, []string{"admin@example.com"}, mailer.Email.To)
+		assert.Equal(t, tokenExpiryWarningTemplate, mailer.Email.Template)
+		assert.Equal(t, "Main Org.", mailer.Email.Data["OrgName"])
+		assert.Equal(t, []expiringTokenData{{
+			Name:               "deploy",
+			ServiceAccountName: "ci",
+			Expires:            "Sun, 01 Oct 2023 12:00:00 UTC",
+			URL:                "http://localhost:3000/org/serviceaccounts/2",
+		}}, mailer.Email.Data["Tokens"])
+	})
+
+	t.Run("does not send an email without admins to send it to", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 1, Name: "Main Org."}
+		mailer := notifications.MockNotificationService()
+		mailer.ShouldError = assert.AnError
+		service := CleanUpService{Cfg: cfg, log: log.NewNopLogger(), orgService: orgService, notificationService: mailer}
+
+		require.NoError(t, service.sendTokenExpiryWarning(ctx, 1, tokens))
+		assert.Empty(t, mailer.Email.To)
+	})
+}
@@ -8,6 +8,7 @@ import (
 
 	"xorm.io/xorm"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
@@ -417,10 +418,11 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 	}
 
 	parentVersion := dash.Version
+	isNew := dash.ID == 0
 	var affectedRows int64
 	var err error
 
-	if dash.ID == 0 {
+	if isNew {
 		dash.SetVersion(1)
 		dash.Created = time.Now()
 		dash.CreatedBy = userId
@@ -489,6 +491,19 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 			return dash, err
 		}
 	}
+
+	sess.PublishAfterCommit(&events.DashboardSaved{
+		Timestamp: dash.Updated,
+		Title:     dash.Title,
+		ID:        dash.ID,
+		UID:       dash.UID,
+		OrgID:     dash.OrgID,
+		FolderUID: dash.FolderUID,
+		IsFolder:  dash.IsFolder,
+		Version:   dash.Version,
+		Created:   isNew,
+		UserID:    cmd.UserID,
+	})
 	return dash, nil
 }
 
@@ -708,6 +723,16 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 			return err
 		}
 	}
+
+	sess.PublishAfterCommit(&events.DashboardDeleted{
+		Timestamp: time.Now(),
+		Title:     dashboard.Title,
+		ID:        dashboard.ID,
+		UID:       dashboard.UID,
+		OrgID:     dashboard.OrgID,
+		FolderUID: dashboard.FolderUID,
+		IsFolder:  dashboard.IsFolder,
+	})
 	return nil
 }
 
@@ -392,6 +392,16 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 			logDeprecatedInvalidDsUid(ss.logger, cmd.UID, cmd.Name)
 		}
 
+		if err == nil {
+			sess.PublishAfterCommit(&events.DataSourceUpdated{
+				Timestamp: ds.Updated,
+				Name:      ds.Name,
+				ID:        ds.ID,
+				UID:       ds.UID,
+				OrgID:     ds.OrgID,
+			})
+		}
+
 		return err
 	})
 }
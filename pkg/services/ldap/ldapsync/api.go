@@ -0,0 +1,123 @@
+package ldapsync
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(router routing.RouteRegister, accessControl ac.AccessControl) {
+	authorize := ac.Middleware(accessControl)
+
+	router.Group("/api/admin/ldap-sync", func(syncRoute routing.RouteRegister) {
+		syncRoute.Get("/status", authorize(ac.EvalPermission(ac.ActionLDAPStatusRead)), routing.Wrap(s.GetLDAPSyncStatus))
+		syncRoute.Post("/", authorize(ac.EvalPermission(ac.ActionLDAPUsersSync)), routing.Wrap(s.PostLDAPSync))
+	}, middleware.ReqSignedIn)
+}
+
+// StatusDTO is the status of the background LDAP sync.
+type StatusDTO struct {
+	Enabled  bool   `json:"enabled"`
+	Schedule string `json:"schedule"`
+	DryRun   bool   `json:"dryRun"`
+	// LastResult is the result of the last sync, null if LDAP users were never synced.
+	LastResult *Result `json:"lastResult"`
+}
+
+// swagger:route GET /admin/ldap-sync/status admin_ldap getLDAPSyncStatus
+//
+// Returns the configuration of the background LDAP sync, and the result of the last sync.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `ldap.status:read`.
+//
+// Responses:
+// 200: getLDAPSyncStatusResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) GetLDAPSyncStatus(c *contextmodel.ReqContext) response.Response {
+	if !s.cfg.LDAPAuthEnabled {
+		return response.Error(http.StatusBadRequest, "LDAP is not enabled", nil)
+	}
+
+	lastResult, err := s.LastResult(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the result of the last LDAP sync", err)
+	}
+
+	return response.JSON(http.StatusOK, StatusDTO{
+		Enabled:    s.cfg.LDAPActiveSyncEnabled,
+		Schedule:   s.cfg.LDAPSyncCron,
+		DryRun:     s.cfg.LDAPSyncDryRun,
+		LastResult: lastResult,
+	})
+}
+
+// SyncCommand are the options of a sync started through the API.
+type SyncCommand struct {
+	// DryRun only reports the changes the sync would make.
+	DryRun bool `json:"dryRun"`
+}
+
+// swagger:route POST /admin/ldap-sync admin_ldap postLDAPSync
+//
+// Syncs all the users who logged in with LDAP, and returns the changes made. In dry-run mode, returns the changes the sync would make.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `ldap.user:sync`.
+//
+// Responses:
+// 200: postLDAPSyncResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) PostLDAPSync(c *contextmodel.ReqContext) response.Response {
+	if !s.cfg.LDAPAuthEnabled {
+		return response.Error(http.StatusBadRequest, "LDAP is not enabled", nil)
+	}
+
+	cmd := SyncCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := s.SyncNow(c.Req.Context(), cmd.DryRun)
+	if err != nil {
+		if errors.Is(err, ErrSyncInProgress) {
+			return response.Error(http.StatusConflict, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to sync LDAP users", err)
+	}
+	if result.Error != "" {
+		return response.Error(http.StatusInternalServerError, "LDAP sync failed: "+result.Error, nil)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters postLDAPSync
+type PostLDAPSyncParams struct {
+	// in:body
+	// required:true
+	Body SyncCommand `json:"body"`
+}
+
+// swagger:response getLDAPSyncStatusResponse
+type GetLDAPSyncStatusResponse struct {
+	// in:body
+	Body StatusDTO `json:"body"`
+}
+
+// swagger:response postLDAPSyncResponse
+type PostLDAPSyncResponse struct {
+	// in:body
+	Body Result `json:"body"`
+}
@@ -0,0 +1,56 @@
+package ldapsync
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "grafana"
+	metricsSubSystem = "ldap_sync"
+)
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		runsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "runs_total",
+			Help:      "Number of LDAP syncs, by status",
+		}, []string{"status"}),
+		usersCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "users_total",
+			Help:      "Number of users changed by LDAP syncs, by action",
+		}, []string{"action"}),
+		durationHistogram: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "duration_seconds",
+			Help:      "Histogram of LDAP sync duration",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800},
+		}),
+		lastSuccessGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Time of the last successful LDAP sync",
+		}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(m.runsCounter)
+		reg.MustRegister(m.usersCounter)
+		reg.MustRegister(m.durationHistogram)
+		reg.MustRegister(m.lastSuccessGauge)
+	}
+
+	return m
+}
+
+type metrics struct {
+	runsCounter       *prometheus.CounterVec
+	usersCounter      *prometheus.CounterVec
+	durationHistogram prometheus.Histogram
+	lastSuccessGauge  prometheus.Gauge
+}
@@ -0,0 +1,143 @@
+package ldapsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	kvNamespace   = "ldap.sync"
+	lastResultKey = "last-result"
+	lockName      = "ldap sync"
+	// lockTimeout is the time after which the lock of a sync that didn't complete is released
+	lockTimeout = time.Hour
+)
+
+var ErrSyncInProgress = errors.New("an LDAP sync is already in progress")
+
+// Service syncs the users who logged in with LDAP on a schedule, so that changes in LDAP, such as the removal of a
+// user from a group, are applied without waiting for the user to log in again.
+type Service struct {
+	cfg                  *setting.Cfg
+	store                store
+	serverLock           *serverlock.ServerLockService
+	kv                   *kvstore.NamespacedKVStore
+	ldapService          service.LDAP
+	userService          user.Service
+	orgService           org.Service
+	identitySynchronizer authn.IdentitySynchronizer
+	sessionService       auth.UserTokenService
+	metrics              *metrics
+	log                  log.Logger
+}
+
+func ProvideService(
+	cfg *setting.Cfg, sqlStore db.DB, serverLock *serverlock.ServerLockService, kv kvstore.KVStore,
+	routeRegister routing.RouteRegister, accessControl ac.AccessControl, ldapService service.LDAP,
+	userService user.Service, orgService org.Service, identitySynchronizer authn.IdentitySynchronizer,
+	sessionService auth.UserTokenService, reg prometheus.Registerer,
+) *Service {
+	s := &Service{
+		cfg:                  cfg,
+		store:                &xormStore{db: sqlStore},
+		serverLock:           serverLock,
+		kv:                   kvstore.WithNamespace(kv, 0, kvNamespace),
+		ldapService:          ldapService,
+		userService:          userService,
+		orgService:           orgService,
+		identitySynchronizer: identitySynchronizer,
+		sessionService:       sessionService,
+		metrics:              newMetrics(reg),
+		log:                  log.New("ldap.sync"),
+	}
+
+	s.registerAPIEndpoints(routeRegister, accessControl)
+
+	return s
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	if !s.cfg.LDAPAuthEnabled || !s.cfg.LDAPActiveSyncEnabled {
+		return nil
+	}
+
+	schedule, err := cron.ParseStandard(s.cfg.LDAPSyncCron)
+	if err != nil {
+		s.log.Error("Invalid LDAP sync schedule, the background sync is disabled", "sync_cron", s.cfg.LDAPSyncCron, "error", err)
+		return nil
+	}
+
+	for {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+
+		// instances share the schedule, only the first one to take the lock runs the sync
+		if _, err := s.SyncNow(ctx, s.cfg.LDAPSyncDryRun); err != nil && !errors.Is(err, ErrSyncInProgress) {
+			s.log.Error("Failed to run LDAP sync", "error", err)
+		}
+	}
+}
+
+// SyncNow runs a sync, unless one is already running on any instance.
+func (s *Service) SyncNow(ctx context.Context, dryRun bool) (*Result, error) {
+	var result *Result
+	err := s.serverLock.LockExecuteAndRelease(ctx, lockName, lockTimeout, func(ctx context.Context) {
+		result = s.Sync(ctx, dryRun)
+	})
+	if err != nil {
+		var lockErr *serverlock.ServerLockExistsError
+		if errors.As(err, &lockErr) {
+			return nil, ErrSyncInProgress
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// LastResult returns the result of the last sync, or nil if LDAP users were never synced.
+func (s *Service) LastResult(ctx context.Context) (*Result, error) {
+	value, ok, err := s.kv.Get(ctx, lastResultKey)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var result Result
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *Service) saveResult(ctx context.Context, result *Result) {
+	value, err := json.Marshal(result)
+	if err == nil {
+		err = s.kv.Set(ctx, lastResultKey, string(value))
+	}
+	if err != nil {
+		s.log.FromContext(ctx).Error("Failed to save the result of the LDAP sync", "error", err)
+	}
+}
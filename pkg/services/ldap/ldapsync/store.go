@@ -0,0 +1,39 @@
+package ldapsync
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/login"
+)
+
+// ldapUser is a Grafana user who logged in with LDAP.
+type ldapUser struct {
+	ID         int64 `xorm:"id"`
+	Login      string
+	Email      string
+	Name       string
+	IsAdmin    bool
+	IsDisabled bool
+}
+
+type store interface {
+	// listUsers returns the users who logged in with LDAP, ordered by ID, after the user with ID afterID.
+	listUsers(ctx context.Context, afterID int64, limit int) ([]ldapUser, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) listUsers(ctx context.Context, afterID int64, limit int) ([]ldapUser, error) {
+	users := make([]ldapUser, 0, limit)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		dialect := s.db.GetDialect()
+		rawSQL := "SELECT id, login, email, name, is_admin, is_disabled FROM " + dialect.Quote("user") +
+			" WHERE id > ? AND id IN (SELECT user_id FROM user_auth WHERE auth_module = ?) ORDER BY id " +
+			dialect.Limit(int64(limit))
+		return sess.SQL(rawSQL, afterID, login.LDAPAuthModule).Find(&users)
+	})
+	return users, err
+}
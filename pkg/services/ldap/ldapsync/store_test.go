@@ -0,0 +1,66 @@
+package ldapsync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationStore_ListUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	s := &xormStore{db: sqlStore}
+
+	authModules := []string{login.LDAPAuthModule, login.GenericOAuthModule, login.LDAPAuthModule, "", login.LDAPAuthModule}
+	err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for i, authModule := range authModules {
+			u := &user.User{
+				Login:      fmt.Sprintf("user%d", i),
+				Email:      fmt.Sprintf("user%d@example.org", i),
+				IsDisabled: i == 4,
+				OrgID:      1,
+				Created:    time.Now(),
+				Updated:    time.Now(),
+			}
+			if _, err := sess.Insert(u); err != nil {
+				return err
+			}
+			if authModule == "" {
+				continue
+			}
+			if _, err := sess.Insert(&login.UserAuth{UserId: u.ID, AuthModule: authModule, AuthId: u.Login, Created: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	page, err := s.listUsers(ctx, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "user0", page[0].Login)
+	assert.Equal(t, "user2", page[1].Login)
+
+	page, err = s.listUsers(ctx, page[1].ID, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "user4", page[0].Login)
+	assert.True(t, page[0].IsDisabled)
+
+	page, err = s.listUsers(ctx, page[0].ID, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
@@ -0,0 +1,365 @@
+package ldapsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	ActionUpdate  = "update"
+	ActionEnable  = "enable"
+	ActionDisable = "disable"
+
+	// maxChanges is the maximum number of changes kept in the result of a sync.
+	maxChanges = 1000
+)
+
+// pageSize is the number of users synced at once.
+var pageSize = ldap.UsersMaxRequest
+
+// Change is a change made to a user by a sync, or that a sync would make in dry-run mode.
+type Change struct {
+	UserID  int64    `json:"userId"`
+	Login   string   `json:"login"`
+	Action  string   `json:"action"`
+	Details []string `json:"details,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Result is the outcome of a sync.
+type Result struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	DryRun     bool      `json:"dryRun"`
+	// Error is set when the sync was aborted.
+	Error    string `json:"error,omitempty"`
+	Users    int    `json:"users"`
+	Updated  int    `json:"updated"`
+	Enabled  int    `json:"enabled"`
+	Disabled int    `json:"disabled"`
+	Failed   int    `json:"failed"`
+	// Changes are the changes made to users, up to 1000.
+	Changes          []Change `json:"changes"`
+	ChangesTruncated bool     `json:"changesTruncated"`
+}
+
+func (r *Result) addChange(change Change) {
+	switch {
+	case change.Error != "":
+		r.Failed++
+	case change.Action == ActionUpdate:
+		r.Updated++
+	case change.Action == ActionEnable:
+		r.Enabled++
+	case change.Action == ActionDisable:
+		r.Disabled++
+	}
+
+	if len(r.Changes) >= maxChanges {
+		r.ChangesTruncated = true
+		return
+	}
+	r.Changes = append(r.Changes, change)
+}
+
+// Sync syncs the users who logged in with LDAP: their org roles, server admin flag and teams are updated from their
+// LDAP groups, and the users not found in LDAP are disabled. In dry-run mode, the changes are only reported.
+func (s *Service) Sync(ctx context.Context, dryRun bool) *Result {
+	logger := s.log.FromContext(ctx)
+	result := &Result{StartedAt: time.Now(), DryRun: dryRun, Changes: []Change{}}
+	logger.Info("Starting LDAP sync", "dryRun", dryRun)
+
+	err := s.sync(ctx, result)
+	result.FinishedAt = time.Now()
+
+	status := "success"
+	if err != nil {
+		status = "failure"
+		result.Error = err.Error()
+		logger.Error("LDAP sync failed", "error", err, "users", result.Users)
+	} else {
+		logger.Info("LDAP sync finished", "dryRun", dryRun, "users", result.Users, "updated", result.Updated,
+			"enabled", result.Enabled, "disabled", result.Disabled, "failed", result.Failed, "duration", result.FinishedAt.Sub(result.StartedAt))
+	}
+
+	s.metrics.runsCounter.WithLabelValues(status).Inc()
+	s.metrics.durationHistogram.Observe(result.FinishedAt.Sub(result.StartedAt).Seconds())
+	if !dryRun {
+		s.metrics.usersCounter.WithLabelValues(ActionUpdate).Add(float64(result.Updated))
+		s.metrics.usersCounter.WithLabelValues(ActionEnable).Add(float64(result.Enabled))
+		s.metrics.usersCounter.WithLabelValues(ActionDisable).Add(float64(result.Disabled))
+		s.metrics.usersCounter.WithLabelValues("failed").Add(float64(result.Failed))
+		if err == nil {
+			s.metrics.lastSuccessGauge.SetToCurrentTime()
+		}
+	}
+
+	s.saveResult(ctx, result)
+	return result
+}
+
+func (s *Service) sync(ctx context.Context, result *Result) error {
+	if !s.cfg.LDAPAuthEnabled {
+		return fmt.Errorf("LDAP is not enabled")
+	}
+	client := s.ldapService.Client()
+	if client == nil {
+		return fmt.Errorf("failed to find the LDAP server")
+	}
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		users, err := s.store.listUsers(ctx, afterID, pageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list LDAP users: %w", err)
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		afterID = users[len(users)-1].ID
+
+		logins := make([]string, 0, len(users))
+		for _, u := range users {
+			logins = append(logins, u.Login)
+		}
+
+		// abort rather than disabling users when LDAP can't be searched
+		infos, err := client.Users(logins)
+		if err != nil {
+			return fmt.Errorf("failed to search users in LDAP: %w", err)
+		}
+		infosByLogin := make(map[string]*login.ExternalUserInfo, len(infos))
+		for _, info := range infos {
+			infosByLogin[strings.ToLower(info.Login)] = info
+		}
+
+		for _, u := range users {
+			result.Users++
+			if change := s.syncUser(ctx, u, infosByLogin[strings.ToLower(u.Login)], result.DryRun); change != nil {
+				result.addChange(*change)
+			}
+		}
+	}
+}
+
+// syncUser syncs a user with its LDAP info, nil when the user wasn't found in LDAP. It returns the change made to the
+// user, or nil if the user is up to date.
+func (s *Service) syncUser(ctx context.Context, u ldapUser, info *login.ExternalUserInfo, dryRun bool) *Change {
+	logger := s.log.FromContext(ctx)
+	change := &Change{UserID: u.ID, Login: u.Login}
+
+	// users are disabled when they aren't found, or match no group mapping
+	if info == nil || info.IsDisabled {
+		if u.IsDisabled {
+			return nil
+		}
+		if u.Login == s.cfg.AdminUser {
+			logger.Warn("Refusing to disable the Grafana server admin not found in LDAP", "login", u.Login)
+			return nil
+		}
+
+		change.Action = ActionDisable
+		if info == nil {
+			change.Details = []string{"not found in LDAP"}
+		} else {
+			change.Details = []string{"not a member of any mapped LDAP group"}
+		}
+		if dryRun {
+			return change
+		}
+
+		if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: u.ID, IsDisabled: true}); err != nil {
+			change.Error = err.Error()
+			return change
+		}
+		if err := s.sessionService.RevokeAllUserTokens(ctx, u.ID); err != nil {
+			change.Error = err.Error()
+		}
+		logger.Info("Disabled user not found in LDAP", "userID", u.ID, "login", u.Login)
+		return change
+	}
+
+	details, err := s.diff(ctx, u, info)
+	if err != nil {
+		change.Action = ActionUpdate
+		change.Error = err.Error()
+		return change
+	}
+	if len(details) == 0 {
+		return nil
+	}
+
+	change.Action = ActionUpdate
+	if u.IsDisabled {
+		change.Action = ActionEnable
+	}
+	change.Details = details
+	if dryRun {
+		return change
+	}
+
+	info.UserId = u.ID
+	if err := s.identitySynchronizer.SyncIdentity(ctx, s.identityFromLDAPUser(info)); err != nil {
+		change.Error = err.Error()
+		return change
+	}
+	if err := s.saveGroups(ctx, u.ID, info.Groups); err != nil {
+		change.Error = err.Error()
+		return change
+	}
+	logger.Info("Synced user with LDAP", "userID", u.ID, "login", u.Login, "changes", details)
+	return change
+}
+
+// diff returns the changes a sync makes to a user.
+func (s *Service) diff(ctx context.Context, u ldapUser, info *login.ExternalUserInfo) ([]string, error) {
+	details := []string{}
+	if u.IsDisabled {
+		details = append(details, "enabled")
+	}
+	if info.Email != "" && info.Email != u.Email {
+		details = append(details, fmt.Sprintf("email: %s -> %s", u.Email, info.Email))
+	}
+	if info.Name != "" && info.Name != u.Name {
+		details = append(details, fmt.Sprintf("name: %s -> %s", u.Name, info.Name))
+	}
+	if info.IsGrafanaAdmin != nil && *info.IsGrafanaAdmin != u.IsAdmin {
+		details = append(details, fmt.Sprintf("server admin: %t -> %t", u.IsAdmin, *info.IsGrafanaAdmin))
+	}
+
+	groupDetails, err := s.diffGroups(ctx, u.ID, info.Groups)
+	if err != nil {
+		return nil, err
+	}
+	details = append(details, groupDetails...)
+
+	// org roles are only synced when the user is mapped to at least one org
+	if s.cfg.LDAPSkipOrgRoleSync || len(info.OrgRoles) == 0 {
+		return details, nil
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: u.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the organizations of the user: %w", err)
+	}
+
+	roles := make(map[int64]org.RoleType, len(orgs))
+	for _, o := range orgs {
+		roles[o.OrgID] = o.Role
+		if _, ok := info.OrgRoles[o.OrgID]; !ok {
+			details = append(details, fmt.Sprintf("org %d: removed", o.OrgID))
+		}
+	}
+
+	orgIDs := make([]int64, 0, len(info.OrgRoles))
+	for orgID := range info.OrgRoles {
+		orgIDs = append(orgIDs, orgID)
+	}
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+	for _, orgID := range orgIDs {
+		role, ok := roles[orgID]
+		if !ok {
+			details = append(details, fmt.Sprintf("org %d: added as %s", orgID, info.OrgRoles[orgID]))
+		} else if role != info.OrgRoles[orgID] {
+			details = append(details, fmt.Sprintf("org %d: %s -> %s", orgID, role, info.OrgRoles[orgID]))
+		}
+	}
+
+	return details, nil
+}
+
+// groupsKey is the key of the LDAP groups of a user at the last sync of the user.
+func groupsKey(userID int64) string {
+	return fmt.Sprintf("groups-%d", userID)
+}
+
+// diffGroups returns the LDAP groups a user was added to and removed from since the last sync of the user. Teams are
+// synced from the groups, so a change of groups changes the teams of the user even when nothing else changes.
+func (s *Service) diffGroups(ctx context.Context, userID int64, groups []string) ([]string, error) {
+	previous := []string{}
+	value, ok, err := s.kv.Get(ctx, groupsKey(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the LDAP groups of the last sync: %w", err)
+	}
+	if ok {
+		if err := json.Unmarshal([]byte(value), &previous); err != nil {
+			return nil, fmt.Errorf("failed to parse the LDAP groups of the last sync: %w", err)
+		}
+	}
+
+	current := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		current[g] = true
+	}
+	before := make(map[string]bool, len(previous))
+	for _, g := range previous {
+		before[g] = true
+	}
+
+	details := []string{}
+	for _, g := range sortedGroups(groups) {
+		if !before[g] {
+			details = append(details, fmt.Sprintf("group added: %s", g))
+		}
+	}
+	for _, g := range sortedGroups(previous) {
+		if !current[g] {
+			details = append(details, fmt.Sprintf("group removed: %s", g))
+		}
+	}
+	return details, nil
+}
+
+func (s *Service) saveGroups(ctx context.Context, userID int64, groups []string) error {
+	value, err := json.Marshal(sortedGroups(groups))
+	if err != nil {
+		return err
+	}
+	if err := s.kv.Set(ctx, groupsKey(userID), string(value)); err != nil {
+		return fmt.Errorf("failed to save the LDAP groups of the user: %w", err)
+	}
+	return nil
+}
+
+func sortedGroups(groups []string) []string {
+	sorted := make([]string, len(groups))
+	copy(sorted, groups)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func (s *Service) identityFromLDAPUser(info *login.ExternalUserInfo) *authn.Identity {
+	return &authn.Identity{
+		OrgRoles:        info.OrgRoles,
+		Login:           info.Login,
+		Name:            info.Name,
+		Email:           info.Email,
+		IsGrafanaAdmin:  info.IsGrafanaAdmin,
+		AuthenticatedBy: info.AuthModule,
+		AuthID:          info.AuthId,
+		Groups:          info.Groups,
+		ClientParams: authn.ClientParams{
+			SyncUser:     true,
+			SyncTeams:    true,
+			EnableUser:   true,
+			SyncOrgRoles: !s.cfg.LDAPSkipOrgRoleSync,
+			LookUpParams: login.UserLookupParams{
+				UserID: &info.UserId,
+			},
+		},
+	}
+}
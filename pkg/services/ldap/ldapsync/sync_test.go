@@ -0,0 +1,199 @@
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/ldap/multildap"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeStore struct {
+	users []ldapUser
+}
+
+func (f *fakeStore) listUsers(_ context.Context, afterID int64, limit int) ([]ldapUser, error) {
+	result := []ldapUser{}
+	for _, u := range f.users {
+		if u.ID > afterID && len(result) < limit {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+type fakeLDAPClient struct {
+	multildap.MultiLDAP
+	users       []*login.ExternalUserInfo
+	expectedErr error
+}
+
+func (f *fakeLDAPClient) Users(logins []string) ([]*login.ExternalUserInfo, error) {
+	result := []*login.ExternalUserInfo{}
+	for _, l := range logins {
+		for _, u := range f.users {
+			if u.Login == l {
+				result = append(result, u)
+			}
+		}
+	}
+	return result, f.expectedErr
+}
+
+type fakeIdentitySynchronizer struct {
+	synced []*authn.Identity
+}
+
+func (f *fakeIdentitySynchronizer) SyncIdentity(_ context.Context, identity *authn.Identity) error {
+	f.synced = append(f.synced, identity)
+	return nil
+}
+
+type testEnv struct {
+	service  *Service
+	disabled []int64
+	revoked  []int64
+	synced   *fakeIdentitySynchronizer
+}
+
+func setupTestEnv(t *testing.T, users []ldapUser, ldapUsers []*login.ExternalUserInfo, ldapErr error) *testEnv {
+	t.Helper()
+	env := &testEnv{synced: &fakeIdentitySynchronizer{}}
+
+	userService := usertest.NewUserServiceFake()
+	userService.DisableFn = func(_ context.Context, cmd *user.DisableUserCommand) error {
+		env.disabled = append(env.disabled, cmd.UserID)
+		return nil
+	}
+	sessionService := authtest.NewFakeUserAuthTokenService()
+	sessionService.RevokeAllUserTokensProvider = func(_ context.Context, userID int64) error {
+		env.revoked = append(env.revoked, userID)
+		return nil
+	}
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleAdmin}}
+
+	env.service = &Service{
+		cfg:                  &setting.Cfg{LDAPAuthEnabled: true, AdminUser: "admin"},
+		store:                &fakeStore{users: users},
+		kv:                   kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, kvNamespace),
+		ldapService:          &service.LDAPFakeService{ExpectedClient: &fakeLDAPClient{users: ldapUsers, expectedErr: ldapErr}},
+		userService:          userService,
+		orgService:           orgService,
+		identitySynchronizer: env.synced,
+		sessionService:       sessionService,
+		metrics:              newMetrics(nil),
+		log:                  log.NewNopLogger(),
+	}
+	return env
+}
+
+func TestService_Sync(t *testing.T) {
+	pageSize = 2
+	t.Cleanup(func() { pageSize = 500 })
+
+	isAdmin := false
+	users := []ldapUser{
+		{ID: 1, Login: "admin", Email: "admin@localhost"},
+		{ID: 2, Login: "alice", Email: "alice@example.org", Name: "Alice"},
+		{ID: 3, Login: "bob", Email: "bob@example.org", Name: "Bob"},
+		{ID: 4, Login: "carol", Email: "carol@example.org", Name: "Carol", IsDisabled: true},
+		{ID: 5, Login: "dave", Email: "dave@example.org", Name: "Dave"},
+		{ID: 6, Login: "erin", Email: "erin@example.org", Name: "Erin", IsDisabled: true},
+	}
+	ldapUsers := []*login.ExternalUserInfo{
+		// unchanged
+		{Login: "alice", Email: "alice@example.org", Name: "Alice", IsGrafanaAdmin: &isAdmin, OrgRoles: map[int64]org.RoleType{1: org.RoleAdmin}},
+		// demoted
+		{Login: "bob", Email: "bob@example.org", Name: "Bob", IsGrafanaAdmin: &isAdmin, OrgRoles: map[int64]org.RoleType{1: org.RoleViewer, 2: org.RoleEditor}},
+		// enabled again
+		{Login: "carol", Email: "carol@example.org", Name: "Carol", IsGrafanaAdmin: &isAdmin, OrgRoles: map[int64]org.RoleType{1: org.RoleAdmin}},
+		// removed from all the mapped groups
+		{Login: "dave", Email: "dave@example.org", Name: "Dave", IsDisabled: true},
+	}
+
+	t.Run("should sync users with LDAP", func(t *testing.T) {
+		env := setupTestEnv(t, users, ldapUsers, nil)
+
+		result := env.service.Sync(context.Background(), false)
+		require.Empty(t, result.Error)
+
+		assert.Equal(t, 6, result.Users)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, 1, result.Enabled)
+		assert.Equal(t, 1, result.Disabled)
+		assert.Equal(t, 0, result.Failed)
+		require.Len(t, result.Changes, 3)
+		assert.Equal(t, Change{UserID: 3, Login: "bob", Action: ActionUpdate, Details: []string{"org 1: Admin -> Viewer", "org 2: added as Editor"}}, result.Changes[0])
+		assert.Equal(t, Change{UserID: 4, Login: "carol", Action: ActionEnable, Details: []string{"enabled"}}, result.Changes[1])
+		assert.Equal(t, Change{UserID: 5, Login: "dave", Action: ActionDisable, Details: []string{"not a member of any mapped LDAP group"}}, result.Changes[2])
+
+		// the server admin is never disabled, and disabled users stay disabled
+		assert.Equal(t, []int64{5}, env.disabled)
+		assert.Equal(t, []int64{5}, env.revoked)
+		require.Len(t, env.synced.synced, 2)
+		assert.Equal(t, int64(3), *env.synced.synced[0].ClientParams.LookUpParams.UserID)
+		assert.Equal(t, int64(4), *env.synced.synced[1].ClientParams.LookUpParams.UserID)
+
+		lastResult, err := env.service.LastResult(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, result.Changes, lastResult.Changes)
+	})
+
+	t.Run("should only report changes in dry-run mode", func(t *testing.T) {
+		env := setupTestEnv(t, users, ldapUsers, nil)
+
+		result := env.service.Sync(context.Background(), true)
+		require.Empty(t, result.Error)
+
+		assert.True(t, result.DryRun)
+		assert.Len(t, result.Changes, 3)
+		assert.Empty(t, env.disabled)
+		assert.Empty(t, env.revoked)
+		assert.Empty(t, env.synced.synced)
+	})
+
+	t.Run("should sync users whose LDAP groups changed", func(t *testing.T) {
+		users := []ldapUser{{ID: 2, Login: "alice", Email: "alice@example.org", Name: "Alice"}}
+		ldapUsers := []*login.ExternalUserInfo{
+			{Login: "alice", Email: "alice@example.org", Name: "Alice", Groups: []string{"cn=ops", "cn=dev"}},
+		}
+		env := setupTestEnv(t, users, ldapUsers, nil)
+		require.NoError(t, env.service.kv.Set(context.Background(), groupsKey(2), `["cn=admins","cn=dev"]`))
+
+		result := env.service.Sync(context.Background(), false)
+		require.Empty(t, result.Error)
+		require.Len(t, result.Changes, 1)
+		assert.Equal(t, Change{UserID: 2, Login: "alice", Action: ActionUpdate, Details: []string{"group added: cn=ops", "group removed: cn=admins"}}, result.Changes[0])
+		require.Len(t, env.synced.synced, 1)
+		assert.Equal(t, []string{"cn=ops", "cn=dev"}, env.synced.synced[0].Groups)
+		assert.True(t, env.synced.synced[0].ClientParams.SyncTeams)
+
+		result = env.service.Sync(context.Background(), false)
+		require.Empty(t, result.Error)
+		assert.Empty(t, result.Changes, "the groups of the last sync are saved")
+	})
+
+	t.Run("should abort without disabling users when LDAP can't be searched", func(t *testing.T) {
+		env := setupTestEnv(t, users, nil, errors.New("connection refused"))
+
+		result := env.service.Sync(context.Background(), false)
+		assert.Contains(t, result.Error, "connection refused")
+		assert.Empty(t, env.disabled)
+		assert.Empty(t, env.synced.synced)
+	})
+}
@@ -32,6 +32,8 @@ const (
 	AuthProxyAuthModule = "authproxy"
 	JWTModule           = "jwt"
 	ExtendedJWTModule   = "extendedjwt"
+	MTLSAuthModule      = "mtls"
+	TokenExchangeModule = "tokenexchange"
 	RenderModule        = "render"
 	// OAuth provider modules
 	AzureADAuthModule    = "oauth_azuread"
@@ -47,6 +49,7 @@ const (
 	SAMLLabel = "SAML"
 	LDAPLabel = "LDAP"
 	JWTLabel  = "JWT"
+	MTLSLabel = "mTLS"
 	// OAuth provider labels
 	AuthProxyLabel    = "Auth Proxy"
 	AzureADLabel      = "AzureAD"
@@ -69,7 +72,7 @@ func IsExternallySynced(cfg *setting.Cfg, authModule string, oauthInfo *social.O
 	if !IsProviderEnabled(cfg, authModule, oauthInfo) {
 		return false
 	}
-	// first check SAML, LDAP and JWT
+	// first check SAML, LDAP, JWT and mTLS
 	switch authModule {
 	case SAMLAuthModule:
 		return !cfg.SAMLSkipOrgRoleSync
@@ -77,6 +80,8 @@ func IsExternallySynced(cfg *setting.Cfg, authModule string, oauthInfo *social.O
 		return !cfg.LDAPSkipOrgRoleSync
 	case JWTModule:
 		return !cfg.JWTAuthSkipOrgRoleSync
+	case MTLSAuthModule:
+		return !cfg.MTLSAuthSkipOrgRoleSync
 	}
 	// then check the rest of the oauth providers
 	// FIXME: remove this once we remove the setting
@@ -105,6 +110,8 @@ func IsGrafanaAdminExternallySynced(cfg *setting.Cfg, oauthInfo *social.OAuthInf
 	switch authModule {
 	case JWTModule:
 		return cfg.JWTAuthAllowAssignGrafanaAdmin
+	case MTLSAuthModule:
+		return cfg.MTLSAuthAllowAssignGrafanaAdmin
 	case SAMLAuthModule:
 		return cfg.SAMLRoleValuesGrafanaAdmin != ""
 	case LDAPAuthModule:
@@ -122,6 +129,8 @@ func IsProviderEnabled(cfg *setting.Cfg, authModule string, oauthInfo *social.OA
 		return cfg.LDAPAuthEnabled
 	case JWTModule:
 		return cfg.JWTAuthEnabled
+	case MTLSAuthModule:
+		return cfg.MTLSAuthEnabled
 	case GoogleAuthModule, OktaAuthModule, AzureADAuthModule, GitLabAuthModule, GithubAuthModule, GrafanaComAuthModule, GenericOAuthModule:
 		if oauthInfo == nil {
 			return false
@@ -152,6 +161,8 @@ func GetAuthProviderLabel(authModule string) string {
 		return LDAPLabel
 	case JWTModule:
 		return JWTLabel
+	case MTLSAuthModule:
+		return MTLSLabel
 	case AuthProxyAuthModule:
 		return AuthProxyLabel
 	case GenericOAuthModule:
@@ -10,6 +10,9 @@ type Service interface {
 	// Validate checks if username has to many login attempts inside a window.
 	// Will return true if provided username do not have too many attempts.
 	Validate(ctx context.Context, username string) (bool, error)
+	// ValidateIPAddress checks if the IP address, or its subnet, has too many login attempts inside a window.
+	// Will return true if neither the IP address nor its subnet have too many attempts.
+	ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error)
 	// Reset resets all login attempts attached to username
 	Reset(ctx context.Context, username string) error
 }
@@ -18,5 +21,20 @@ type LoginAttempt struct {
 	Id        int64
 	Username  string
 	IpAddress string
+	IpSubnet  string
 	Created   int64
 }
+
+// Blocked is a username, IP address or subnet that is currently blocked because of too many login attempts.
+type Blocked struct {
+	Value       string `json:"value"`
+	Attempts    int64  `json:"attempts"`
+	LastAttempt int64  `json:"lastAttempt"`
+}
+
+// BlockedDTO lists the usernames, IP addresses and subnets that are currently blocked.
+type BlockedDTO struct {
+	Usernames   []Blocked `json:"usernames"`
+	IPAddresses []Blocked `json:"ipAddresses"`
+	Subnets     []Blocked `json:"subnets"`
+}
@@ -0,0 +1,53 @@
+package loginattemptimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/admin/login-attempts", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/blocked", authorize(ac.EvalPermission(ac.ActionUsersRead, ac.ScopeGlobalUsersAll)), routing.Wrap(s.handleGetBlocked))
+		subrouter.Delete("/", authorize(ac.EvalPermission(ac.ActionUsersWrite, ac.ScopeGlobalUsersAll)), routing.Wrap(s.handleReset))
+	}, middleware.ReqSignedIn)
+}
+
+func (s *Service) handleGetBlocked(c *contextmodel.ReqContext) response.Response {
+	blocked, err := s.Blocked(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get blocked login attempts", err)
+	}
+	return response.JSON(http.StatusOK, blocked)
+}
+
+// handleReset resets the login attempts of the username, IP address or subnet given as query parameter,
+// which unblocks it.
+func (s *Service) handleReset(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	query := c.Req.URL.Query()
+
+	var err error
+	switch {
+	case query.Get("username") != "":
+		err = s.Reset(ctx, query.Get("username"))
+	case query.Get("ip") != "":
+		err = s.ResetIPAddress(ctx, query.Get("ip"))
+	case query.Get("subnet") != "":
+		err = s.ResetSubnet(ctx, query.Get("subnet"))
+	default:
+		return response.Error(http.StatusBadRequest, "One of username, ip or subnet is required", nil)
+	}
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reset login attempts", err)
+	}
+
+	s.logger.FromContext(ctx).Info("Login attempts reset by admin", "query", query.Encode(), "adminLogin", c.SignedInUser.GetLogin())
+	return response.Success("Login attempts reset")
+}
@@ -2,11 +2,15 @@ package loginattemptimpl
 
 import (
 	"context"
+	"net"
 	"time"
 
+	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -15,20 +19,29 @@ const (
 	loginAttemptsWindow           = time.Minute * 5
 )
 
-func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService) *Service {
-	return &Service{
-		&xormStore{db: db, now: time.Now},
-		cfg,
-		lock,
-		log.New("login_attempt"),
+func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService,
+	routeRegister routing.RouteRegister, accessControl ac.AccessControl) *Service {
+	s := &Service{
+		store:         &xormStore{db: db, now: time.Now},
+		cfg:           cfg,
+		lock:          lock,
+		accessControl: accessControl,
+		logger:        log.New("login_attempt"),
 	}
+
+	if !cfg.DisableBruteForceLoginProtection {
+		s.registerAPIEndpoints(routeRegister)
+	}
+
+	return s
 }
 
 type Service struct {
-	store  store
-	cfg    *setting.Cfg
-	lock   *serverlock.ServerLockService
-	logger log.Logger
+	store         store
+	cfg           *setting.Cfg
+	lock          *serverlock.ServerLockService
+	accessControl ac.AccessControl
+	logger        log.Logger
 }
 
 func (s *Service) Run(ctx context.Context) error {
@@ -56,12 +69,13 @@ func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
 	_, err := s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
 		Username:  username,
 		IpAddress: IPAddress,
+		IpSubnet:  s.subnet(IPAddress),
 	})
 	return err
 }
 
 func (s *Service) Reset(ctx context.Context, username string) error {
-	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{username})
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{Username: username})
 }
 
 func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
@@ -86,10 +100,129 @@ func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
 	return true, nil
 }
 
+func (s *Service) ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error) {
+	if s.cfg.DisableBruteForceLoginProtection {
+		return true, nil
+	}
+
+	if s.cfg.IPLoginProtectionMaxAttempts > 0 && IPAddress != "" {
+		count, err := s.store.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{
+			IpAddress: IPAddress,
+			Since:     time.Now().Add(-s.cfg.IPLoginProtectionWindow),
+		})
+		if err != nil {
+			return false, err
+		}
+		if count >= s.cfg.IPLoginProtectionMaxAttempts {
+			return false, nil
+		}
+	}
+
+	if subnet := s.subnet(IPAddress); s.cfg.SubnetLoginProtectionMaxAttempts > 0 && subnet != "" {
+		count, err := s.store.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{
+			IpSubnet: subnet,
+			Since:    time.Now().Add(-s.cfg.SubnetLoginProtectionWindow),
+		})
+		if err != nil {
+			return false, err
+		}
+		if count >= s.cfg.SubnetLoginProtectionMaxAttempts {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Blocked returns the usernames, IP addresses and subnets that currently have too many login attempts.
+func (s *Service) Blocked(ctx context.Context) (*loginattempt.BlockedDTO, error) {
+	now := time.Now()
+	dto := &loginattempt.BlockedDTO{
+		Usernames:   []loginattempt.Blocked{},
+		IPAddresses: []loginattempt.Blocked{},
+		Subnets:     []loginattempt.Blocked{},
+	}
+
+	var err error
+	if dto.Usernames, err = s.store.GetBlocked(ctx, GetBlockedQuery{
+		Column:      "username",
+		Since:       now.Add(-loginAttemptsWindow),
+		MinAttempts: maxInvalidLoginAttempts,
+	}); err != nil {
+		return nil, err
+	}
+
+	if s.cfg.IPLoginProtectionMaxAttempts > 0 {
+		if dto.IPAddresses, err = s.store.GetBlocked(ctx, GetBlockedQuery{
+			Column:      "ip_address",
+			Since:       now.Add(-s.cfg.IPLoginProtectionWindow),
+			MinAttempts: s.cfg.IPLoginProtectionMaxAttempts,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cfg.SubnetLoginProtectionMaxAttempts > 0 {
+		if dto.Subnets, err = s.store.GetBlocked(ctx, GetBlockedQuery{
+			Column:      "ip_subnet",
+			Since:       now.Add(-s.cfg.SubnetLoginProtectionWindow),
+			MinAttempts: s.cfg.SubnetLoginProtectionMaxAttempts,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return dto, nil
+}
+
+// ResetIPAddress resets all login attempts of an IP address.
+func (s *Service) ResetIPAddress(ctx context.Context, IPAddress string) error {
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{IpAddress: IPAddress})
+}
+
+// ResetSubnet resets all login attempts of a subnet.
+func (s *Service) ResetSubnet(ctx context.Context, subnet string) error {
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{IpSubnet: subnet})
+}
+
+// subnet returns the subnet of the IP address with the configured prefix length, e.g. 192.168.1.0/24,
+// or an empty string if it isn't a valid IP address.
+func (s *Service) subnet(IPAddress string) string {
+	ip := net.ParseIP(IPAddress)
+	if ip == nil {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(s.cfg.SubnetLoginProtectionIPv4Prefix, 8*net.IPv4len)
+		if mask == nil {
+			return ""
+		}
+		return (&net.IPNet{IP: ip4.Mask(mask), Mask: mask}).String()
+	}
+
+	mask := net.CIDRMask(s.cfg.SubnetLoginProtectionIPv6Prefix, 8*net.IPv6len)
+	if mask == nil {
+		return ""
+	}
+	return (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+}
+
+// retention returns how long login attempts are kept for: 10 minutes, or the longest window if it's longer.
+func (s *Service) retention() time.Duration {
+	retention := time.Minute * 10
+	for _, window := range []time.Duration{s.cfg.IPLoginProtectionWindow, s.cfg.SubnetLoginProtectionWindow} {
+		if window > retention {
+			retention = window
+		}
+	}
+	return retention
+}
+
 func (s *Service) cleanup(ctx context.Context) {
 	err := s.lock.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func(context.Context) {
 		cmd := DeleteOldLoginAttemptsCommand{
-			OlderThan: time.Now().Add(time.Minute * -10),
+			OlderThan: time.Now().Add(-s.retention()),
 		}
 		if deletedLogs, err := s.store.DeleteOldLoginAttempts(ctx, cmd); err != nil {
 			s.logger.Error("Problem deleting expired login attempts", "error", err.Error())
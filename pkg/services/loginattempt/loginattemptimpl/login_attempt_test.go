@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/setting"
@@ -79,11 +80,62 @@ func TestService_Validate(t *testing.T) {
 	}
 }
 
+func TestService_ValidateIPAddress(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ipCount     int64
+		subnetCount int64
+		disabled    bool
+		expected    bool
+	}{
+		{name: "When IP address and subnet login attempt counts are less than max", ipCount: 49, subnetCount: 199, expected: true},
+		{name: "When IP address login attempt count equals max", ipCount: 50, subnetCount: 50, expected: false},
+		{name: "When subnet login attempt count equals max", ipCount: 1, subnetCount: 200, expected: false},
+		{name: "When brute force protection disabled", ipCount: 50, subnetCount: 200, disabled: true, expected: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.DisableBruteForceLoginProtection = tt.disabled
+			cfg.IPLoginProtectionMaxAttempts = 50
+			cfg.SubnetLoginProtectionMaxAttempts = 200
+			cfg.SubnetLoginProtectionIPv4Prefix = 24
+			service := &Service{
+				store: fakeStore{ExpectedIPCount: tt.ipCount, ExpectedSubnetCount: tt.subnetCount},
+				cfg:   cfg,
+			}
+
+			ok, err := service.ValidateIPAddress(context.Background(), "192.168.0.1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestService_subnet(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.SubnetLoginProtectionIPv4Prefix = 24
+	cfg.SubnetLoginProtectionIPv6Prefix = 64
+	service := &Service{cfg: cfg}
+
+	assert.Equal(t, "192.168.1.0/24", service.subnet("192.168.1.42"))
+	assert.Equal(t, "2001:db8:1:2::/64", service.subnet("2001:db8:1:2:3:4:5:6"))
+	assert.Equal(t, "192.168.1.0/24", service.subnet("::ffff:192.168.1.42"))
+	assert.Equal(t, "", service.subnet("not an ip"))
+
+	cfg.SubnetLoginProtectionIPv4Prefix = 33
+	assert.Equal(t, "", service.subnet("192.168.1.42"), "invalid prefix lengths disable subnets")
+}
+
 var _ store = new(fakeStore)
 
 type fakeStore struct {
 	ExpectedErr         error
 	ExpectedCount       int64
+	ExpectedIPCount     int64
+	ExpectedSubnetCount int64
+	ExpectedBlocked     []loginattempt.Blocked
 	ExpectedDeletedRows int64
 }
 
@@ -91,6 +143,17 @@ func (f fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLo
 	return f.ExpectedCount, f.ExpectedErr
 }
 
+func (f fakeStore) GetIPLoginAttemptCount(ctx context.Context, query GetIPLoginAttemptCountQuery) (int64, error) {
+	if query.IpSubnet != "" {
+		return f.ExpectedSubnetCount, f.ExpectedErr
+	}
+	return f.ExpectedIPCount, f.ExpectedErr
+}
+
+func (f fakeStore) GetBlocked(ctx context.Context, query GetBlockedQuery) ([]loginattempt.Blocked, error) {
+	return f.ExpectedBlocked, f.ExpectedErr
+}
+
 func (f fakeStore) CreateLoginAttempt(ctx context.Context, command CreateLoginAttemptCommand) (loginattempt.LoginAttempt, error) {
 	return loginattempt.LoginAttempt{}, f.ExpectedErr
 }
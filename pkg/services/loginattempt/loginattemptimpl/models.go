@@ -7,6 +7,7 @@ import (
 type CreateLoginAttemptCommand struct {
 	Username  string
 	IpAddress string
+	IpSubnet  string
 }
 
 type GetUserLoginAttemptCountQuery struct {
@@ -18,6 +19,23 @@ type DeleteOldLoginAttemptsCommand struct {
 	OlderThan time.Time
 }
 
+// GetIPLoginAttemptCountQuery counts the login attempts of an IP address or, if IpSubnet is set, of a subnet.
+type GetIPLoginAttemptCountQuery struct {
+	IpAddress string
+	IpSubnet  string
+	Since     time.Time
+}
+
+// GetBlockedQuery lists the values of a column with at least MinAttempts login attempts since a time.
+type GetBlockedQuery struct {
+	Column      string
+	Since       time.Time
+	MinAttempts int64
+}
+
+// DeleteLoginAttemptsCommand deletes the login attempts of a username, an IP address or a subnet.
 type DeleteLoginAttemptsCommand struct {
-	Username string
+	Username  string
+	IpAddress string
+	IpSubnet  string
 }
@@ -18,6 +18,8 @@ type store interface {
 	DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLoginAttemptsCommand) (int64, error)
 	DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error
 	GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error)
+	GetIPLoginAttemptCount(ctx context.Context, query GetIPLoginAttemptCountQuery) (int64, error)
+	GetBlocked(ctx context.Context, query GetBlockedQuery) ([]loginattempt.Blocked, error)
 }
 
 func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) (result loginattempt.LoginAttempt, err error) {
@@ -25,6 +27,7 @@ func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAtte
 		loginAttempt := loginattempt.LoginAttempt{
 			Username:  cmd.Username,
 			IpAddress: cmd.IpAddress,
+			IpSubnet:  cmd.IpSubnet,
 			Created:   xs.now().Unix(),
 		}
 
@@ -58,7 +61,15 @@ func (xs *xormStore) DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLo
 
 func (xs *xormStore) DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error {
 	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
-		_, err := sess.Exec("DELETE FROM login_attempt WHERE username = ?", cmd.Username)
+		var err error
+		switch {
+		case cmd.IpAddress != "":
+			_, err = sess.Exec("DELETE FROM login_attempt WHERE ip_address = ?", cmd.IpAddress)
+		case cmd.IpSubnet != "":
+			_, err = sess.Exec("DELETE FROM login_attempt WHERE ip_subnet = ?", cmd.IpSubnet)
+		default:
+			_, err = sess.Exec("DELETE FROM login_attempt WHERE username = ?", cmd.Username)
+		}
 		return err
 	})
 }
@@ -82,3 +93,37 @@ func (xs *xormStore) GetUserLoginAttemptCount(ctx context.Context, query GetUser
 
 	return total, err
 }
+
+func (xs *xormStore) GetIPLoginAttemptCount(ctx context.Context, query GetIPLoginAttemptCountQuery) (int64, error) {
+	var total int64
+	err := xs.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sess := dbSession.Where("created >= ?", query.Since.Unix())
+		if query.IpSubnet != "" {
+			sess = sess.And("ip_subnet = ?", query.IpSubnet)
+		} else {
+			sess = sess.And("ip_address = ?", query.IpAddress)
+		}
+
+		var queryErr error
+		total, queryErr = sess.Count(new(loginattempt.LoginAttempt))
+		return queryErr
+	})
+
+	return total, err
+}
+
+func (xs *xormStore) GetBlocked(ctx context.Context, query GetBlockedQuery) ([]loginattempt.Blocked, error) {
+	blocked := make([]loginattempt.Blocked, 0)
+	err := xs.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		dialect := xs.db.GetDialect()
+		column := dialect.Quote(query.Column)
+		return dbSession.SQL(
+			"SELECT "+column+" AS "+dialect.Quote("value")+", COUNT(*) AS attempts, MAX(created) AS last_attempt FROM login_attempt"+
+				" WHERE created >= ? AND "+column+" IS NOT NULL AND "+column+" != ''"+
+				" GROUP BY "+column+" HAVING COUNT(*) >= ? ORDER BY attempts DESC",
+			query.Since.Unix(), query.MinAttempts,
+		).Find(&blocked)
+	})
+
+	return blocked, err
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 )
 
 func TestIntegrationLoginAttemptsQuery(t *testing.T) {
@@ -143,3 +144,55 @@ func TestIntegrationLoginAttemptsDelete(t *testing.T) {
 		require.Equal(t, test.DeletedRows, deletedRows, test.Name)
 	}
 }
+
+func TestIntegrationLoginAttemptsByIPAddress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	now := time.Date(2017, 10, 22, 8, 0, 0, 0, time.Local)
+	s := &xormStore{
+		db:  db.InitTestDB(t),
+		now: func() time.Time { return now },
+	}
+
+	for _, attempt := range []CreateLoginAttemptCommand{
+		{Username: "alice", IpAddress: "192.168.0.1", IpSubnet: "192.168.0.0/24"},
+		{Username: "bob", IpAddress: "192.168.0.1", IpSubnet: "192.168.0.0/24"},
+		{Username: "carol", IpAddress: "192.168.0.2", IpSubnet: "192.168.0.0/24"},
+		{Username: "alice", IpAddress: "10.0.0.1", IpSubnet: "10.0.0.0/24"},
+	} {
+		_, err := s.CreateLoginAttempt(ctx, attempt)
+		require.NoError(t, err)
+	}
+
+	count, err := s.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{IpAddress: "192.168.0.1", Since: now})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = s.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{IpSubnet: "192.168.0.0/24", Since: now})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	count, err = s.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{IpAddress: "192.168.0.1", Since: now.Add(time.Second)})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	blocked, err := s.GetBlocked(ctx, GetBlockedQuery{Column: "ip_subnet", Since: now, MinAttempts: 2})
+	require.NoError(t, err)
+	require.Equal(t, []loginattempt.Blocked{{Value: "192.168.0.0/24", Attempts: 3, LastAttempt: now.Unix()}}, blocked)
+
+	blocked, err = s.GetBlocked(ctx, GetBlockedQuery{Column: "username", Since: now, MinAttempts: 2})
+	require.NoError(t, err)
+	require.Equal(t, []loginattempt.Blocked{{Value: "alice", Attempts: 2, LastAttempt: now.Unix()}}, blocked)
+
+	require.NoError(t, s.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{IpSubnet: "192.168.0.0/24"}))
+	count, err = s.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{IpAddress: "192.168.0.2", Since: now})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	require.NoError(t, s.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{IpAddress: "10.0.0.1"}))
+	count, err = s.GetUserLoginAttemptCount(ctx, GetUserLoginAttemptCountQuery{Username: "alice", Since: now})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
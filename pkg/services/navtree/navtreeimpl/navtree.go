@@ -262,6 +262,13 @@ func (s *ServiceImpl) getProfileNode(c *contextmodel.ReqContext) *navtree.NavLin
 			Text: "Change password", Id: "profile/password", Url: s.cfg.AppSubURL + "/profile/password",
 			Icon: "lock",
 		})
+
+		if s.cfg.SectionWithEnvOverrides("auth.totp").Key("enabled").MustBool(false) {
+			children = append(children, &navtree.NavLink{
+				Text: "Two-factor authentication", Id: "profile/totp", Url: s.cfg.AppSubURL + "/profile/totp",
+				Icon: "shield",
+			})
+		}
 	}
 
 	if !setting.DisableSignoutMenu {
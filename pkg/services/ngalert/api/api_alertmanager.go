@@ -77,6 +77,13 @@ func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postab
 		return response.Err(authz.NewAuthorizationErrorWithPermissions(fmt.Sprintf("%s silences", errAction), evaluator))
 	}
 
+	if err := srv.silenceProvenanceGuard(c.Req.Context(), am, c.SignedInUser.GetOrgID(), postableSilence.ID, postableSilence.CreatedBy); err != nil {
+		if errors.Is(err, errProvisionedSilence) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to check silence provenance")
+	}
+
 	silenceID, err := am.CreateSilence(c.Req.Context(), &postableSilence)
 	if err != nil {
 		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
@@ -114,6 +121,13 @@ func (srv AlertmanagerSrv) RouteDeleteSilence(c *contextmodel.ReqContext, silenc
 		return errResp
 	}
 
+	if err := srv.silenceProvenanceGuard(c.Req.Context(), am, c.SignedInUser.GetOrgID(), silenceID, nil); err != nil {
+		if errors.Is(err, errProvisionedSilence) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to check silence provenance")
+	}
+
 	if err := am.DeleteSilence(c.Req.Context(), silenceID); err != nil {
 		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
 			return ErrResp(http.StatusNotFound, err, "")
@@ -336,6 +350,9 @@ func (srv AlertmanagerSrv) RoutePostTestReceivers(c *contextmodel.ReqContext, bo
 		if errors.Is(err, alertingNotify.ErrNoReceivers) {
 			return response.Error(http.StatusBadRequest, "", err)
 		}
+		if errors.Is(err, notifier.ErrNotSupported) {
+			return response.Error(http.StatusNotImplemented, "", err)
+		}
 		return response.Error(http.StatusInternalServerError, "", err)
 	}
 
@@ -350,12 +367,46 @@ func (srv AlertmanagerSrv) RoutePostTestTemplates(c *contextmodel.ReqContext, bo
 
 	res, err := am.TestTemplate(c.Req.Context(), body)
 	if err != nil {
+		if errors.Is(err, notifier.ErrNotSupported) {
+			return response.Error(http.StatusNotImplemented, "", err)
+		}
 		return response.Error(http.StatusInternalServerError, "", err)
 	}
 
 	return response.JSON(http.StatusOK, newTestTemplateResult(res))
 }
 
+// RoutePostTestRoutes returns the notification policies that alerts with the given labels match. The routing tree of
+// the configuration in the request is used if set, otherwise the one of the current configuration.
+func (srv AlertmanagerSrv) RoutePostTestRoutes(c *contextmodel.ReqContext, body apimodels.TestRoutesConfigBodyParams) response.Response {
+	if len(body.Alerts) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("at least one alert is required"), "")
+	}
+
+	var cfg apimodels.Config
+	if body.AlertmanagerConfig != nil {
+		cfg = body.AlertmanagerConfig.Config
+	} else {
+		current, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		cfg = current.AlertmanagerConfig.Config
+	}
+
+	res, err := notifier.TestRoutes(cfg, body.Alerts, time.Now())
+	if err != nil {
+		if errors.Is(err, notifier.ErrNoRoutingTree) || errors.Is(err, notifier.ErrInvalidRoutingTree) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to test routes")
+	}
+	return response.JSON(http.StatusOK, res)
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
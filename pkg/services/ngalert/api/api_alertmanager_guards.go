@@ -1,17 +1,21 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	alertingNotify "github.com/grafana/alerting/notify"
 	amConfig "github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/util/cmputil"
 )
 
@@ -28,6 +32,9 @@ func (srv AlertmanagerSrv) provenanceGuard(currentConfig apimodels.GettableUserC
 	if err := checkMuteTimes(currentConfig, newConfig); err != nil {
 		return err
 	}
+	if err := checkInhibitRules(currentConfig, newConfig); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -145,3 +152,64 @@ func checkMuteTimes(currentConfig apimodels.GettableUserConfig, newConfig apimod
 	}
 	return nil
 }
+
+func checkInhibitRules(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
+	if ngmodels.Provenance(currentConfig.AlertmanagerConfig.InhibitRulesProvenance) == ngmodels.ProvenanceNone {
+		return nil
+	}
+	reporter := cmputil.DiffReporter{}
+	options := []cmp.Option{
+		cmp.Reporter(&reporter),
+		cmpopts.EquateEmpty(),
+		cmpopts.IgnoreUnexported(labels.Matcher{}),
+		cmp.Comparer(func(a, b amConfig.Regexp) bool {
+			ra, _ := a.MarshalYAML()
+			rb, _ := b.MarshalYAML()
+			return ra == rb
+		}),
+	}
+	rulesEqual := cmp.Equal(currentConfig.AlertmanagerConfig.InhibitRules, newConfig.AlertmanagerConfig.InhibitRules, options...)
+	if !rulesEqual {
+		return fmt.Errorf("cannot save provisioned inhibition rules")
+	}
+	return nil
+}
+
+var errProvisionedSilence = errors.New("silence is provisioned")
+
+// silenceProvenanceGuard returns an error wrapping errProvisionedSilence if the silence with the given ID was
+// materialized from a provisioned silence definition, or if the new author claims that it was.
+func (srv AlertmanagerSrv) silenceProvenanceGuard(ctx context.Context, am notifier.Alertmanager, orgID int64, silenceID string, newCreatedBy *string) error {
+	if newCreatedBy != nil {
+		if _, ok := notifier.ProvisionedSilenceUID(*newCreatedBy); ok {
+			return fmt.Errorf("%w: the author %q is reserved for silences of provisioned silence definitions", errProvisionedSilence, *newCreatedBy)
+		}
+	}
+	if silenceID == "" {
+		return nil
+	}
+
+	current, err := am.GetSilence(ctx, silenceID)
+	if err != nil {
+		// Unknown silences are reported by the Alertmanager itself.
+		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
+			return nil
+		}
+		return err
+	}
+	if current.CreatedBy == nil {
+		return nil
+	}
+	uid, ok := notifier.ProvisionedSilenceUID(*current.CreatedBy)
+	if !ok {
+		return nil
+	}
+	provenance, err := srv.mam.ProvStore.GetProvenance(ctx, &apimodels.SilenceDefinition{UID: uid}, orgID)
+	if err != nil {
+		return err
+	}
+	if provenance != ngmodels.ProvenanceNone {
+		return fmt.Errorf("%w: cannot change silence %s of provisioned silence definition %q", errProvisionedSilence, silenceID, uid)
+	}
+	return nil
+}
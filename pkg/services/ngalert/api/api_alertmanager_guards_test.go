@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"testing"
 
 	amConfig "github.com/prometheus/alertmanager/config"
@@ -537,6 +538,95 @@ func TestCheckMuteTimes(t *testing.T) {
 	}
 }
 
+func TestCheckInhibitRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		shouldErr     bool
+		currentConfig definitions.GettableUserConfig
+		newConfig     definitions.PostableUserConfig
+	}{
+		{
+			name:          "equal configs should not error",
+			shouldErr:     false,
+			currentConfig: gettableInhibitRules(t, defaultInhibitRules(t), models.ProvenanceAPI),
+			newConfig:     postableInhibitRules(t, defaultInhibitRules(t)),
+		},
+		{
+			name:          "removing non provisioned rules should not fail",
+			shouldErr:     false,
+			currentConfig: gettableInhibitRules(t, defaultInhibitRules(t), models.ProvenanceNone),
+			newConfig:     postableInhibitRules(t, nil),
+		},
+		{
+			name:          "removing provisioned rules should fail",
+			shouldErr:     true,
+			currentConfig: gettableInhibitRules(t, defaultInhibitRules(t), models.ProvenanceFile),
+			newConfig:     postableInhibitRules(t, nil),
+		},
+		{
+			name:          "editing provisioned rules should fail",
+			shouldErr:     true,
+			currentConfig: gettableInhibitRules(t, defaultInhibitRules(t), models.ProvenanceAPI),
+			newConfig: func() definitions.PostableUserConfig {
+				rules := defaultInhibitRules(t)
+				rules[0].Equal = model.LabelNames{"instance"}
+				return postableInhibitRules(t, rules)
+			}(),
+		},
+		{
+			name:          "adding to provisioned rules should fail",
+			shouldErr:     true,
+			currentConfig: gettableInhibitRules(t, defaultInhibitRules(t), models.ProvenanceAPI),
+			newConfig:     postableInhibitRules(t, append(defaultInhibitRules(t), defaultInhibitRules(t)...)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkInhibitRules(test.currentConfig, test.newConfig)
+			if test.shouldErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func gettableInhibitRules(t *testing.T, rules []amConfig.InhibitRule, provenance models.Provenance) definitions.GettableUserConfig {
+	t.Helper()
+	return definitions.GettableUserConfig{
+		AlertmanagerConfig: definitions.GettableApiAlertingConfig{
+			InhibitRulesProvenance: definitions.Provenance(provenance),
+			Config: definitions.Config{
+				InhibitRules: rules,
+			},
+		},
+	}
+}
+
+func postableInhibitRules(t *testing.T, rules []amConfig.InhibitRule) definitions.PostableUserConfig {
+	t.Helper()
+	return definitions.PostableUserConfig{
+		AlertmanagerConfig: definitions.PostableApiAlertingConfig{
+			Config: definitions.Config{
+				InhibitRules: rules,
+			},
+		},
+	}
+}
+
+func defaultInhibitRules(t *testing.T) []amConfig.InhibitRule {
+	t.Helper()
+	var rules []amConfig.InhibitRule
+	err := json.Unmarshal([]byte(`[{
+		"source_matchers": ["alertname=ClusterDown"],
+		"target_match_re": {"severity": "warning|info"},
+		"equal": ["cluster"]
+	}]`), &rules)
+	require.NoError(t, err)
+	return rules
+}
+
 func gettableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeInterval, provenances map[string]definitions.Provenance) definitions.GettableUserConfig {
 	return definitions.GettableUserConfig{
 		AlertmanagerConfig: definitions.GettableApiAlertingConfig{
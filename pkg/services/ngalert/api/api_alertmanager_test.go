@@ -13,6 +13,7 @@ import (
 	alertingNotify "github.com/grafana/alerting/notify"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -495,6 +496,57 @@ func TestRoutePostTestTemplates(t *testing.T) {
 	})
 }
 
+func TestRoutePostTestRoutes(t *testing.T) {
+	sut := createSut(t)
+	alerts := []model.LabelSet{{"alertname": "test"}}
+
+	t.Run("assert 400 when no alerts", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), apimodels.TestRoutesConfigBodyParams{})
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("assert 404 when no configuration found", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(10), apimodels.TestRoutesConfigBodyParams{Alerts: alerts})
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert 200 with the current configuration", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), apimodels.TestRoutesConfigBodyParams{Alerts: alerts})
+		require.Equal(t, 200, response.Status(), string(response.Body()))
+
+		res := apimodels.TestRoutesResults{}
+		require.NoError(t, json.Unmarshal(response.Body(), &res))
+		require.Len(t, res.Results, 1)
+		require.Len(t, res.Results[0].Routes, 1)
+		require.Equal(t, "grafana-default-email", res.Results[0].Routes[0].Receiver)
+	})
+
+	t.Run("assert 200 with a candidate configuration", func(t *testing.T) {
+		candidate := createAmConfigRequest(t, validConfig)
+		candidate.AlertmanagerConfig.Route.Receiver = "candidate"
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), apimodels.TestRoutesConfigBodyParams{
+			Alerts:             alerts,
+			AlertmanagerConfig: &candidate.AlertmanagerConfig,
+		})
+		require.Equal(t, 200, response.Status())
+
+		res := apimodels.TestRoutesResults{}
+		require.NoError(t, json.Unmarshal(response.Body(), &res))
+		require.Equal(t, "candidate", res.Results[0].Routes[0].Receiver)
+	})
+
+	t.Run("assert 400 when a candidate policy uses an unknown mute time interval", func(t *testing.T) {
+		candidate := createAmConfigRequest(t, validConfig)
+		candidate.AlertmanagerConfig.Route.MuteTimeIntervals = []string{"unknown"}
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), apimodels.TestRoutesConfigBodyParams{
+			Alerts:             alerts,
+			AlertmanagerConfig: &candidate.AlertmanagerConfig,
+		})
+		require.Equal(t, 400, response.Status())
+		require.Contains(t, string(response.Body()), "mute time interval 'unknown' does not exist")
+	})
+}
+
 func TestSilenceCreate(t *testing.T) {
 	makeSilence := func(comment string, createdBy string,
 		startsAt, endsAt strfmt.DateTime, matchers amv2.Matchers) amv2.Silence {
@@ -630,6 +682,80 @@ func TestRouteCreateSilence(t *testing.T) {
 	}
 }
 
+func TestRouteSilenceProvenance(t *testing.T) {
+	createRequestCtx := func() *contextmodel.ReqContext {
+		return &contextmodel.ReqContext{
+			Context: &web.Context{
+				Req: &http.Request{},
+			},
+			SignedInUser: &user.SignedInUser{
+				OrgID: 1,
+				Permissions: map[int64]map[string][]string{
+					1: {
+						accesscontrol.ActionAlertingInstanceCreate: {},
+						accesscontrol.ActionAlertingInstanceUpdate: {},
+					},
+				},
+			},
+		}
+	}
+	createProvisionedSilence := func(t *testing.T, sut AlertmanagerSrv, provenance ngmodels.Provenance) apimodels.PostableSilence {
+		t.Helper()
+		am, err := sut.mam.AlertmanagerFor(1)
+		require.NoError(t, err)
+		silence := silenceGen(withEmptyID)()
+		createdBy := notifier.ProvisionedSilenceCreatedByPrefix + "maintenance"
+		silence.CreatedBy = &createdBy
+		silence.ID, err = am.CreateSilence(context.Background(), &silence)
+		require.NoError(t, err)
+		if provenance != ngmodels.ProvenanceNone {
+			err = sut.mam.ProvStore.SetProvenance(context.Background(), &apimodels.SilenceDefinition{UID: "maintenance"}, 1, provenance)
+			require.NoError(t, err)
+		}
+		return silence
+	}
+
+	t.Run("deleting provisioned silence fails", func(t *testing.T) {
+		sut := createSut(t)
+		silence := createProvisionedSilence(t, sut, ngmodels.ProvenanceFile)
+
+		response := sut.RouteDeleteSilence(createRequestCtx(), silence.ID)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("updating provisioned silence fails", func(t *testing.T) {
+		sut := createSut(t)
+		silence := createProvisionedSilence(t, sut, ngmodels.ProvenanceFile)
+		comment := "changed"
+		silence.Comment = &comment
+
+		response := sut.RouteCreateSilence(createRequestCtx(), silence)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("creating silence with reserved author fails", func(t *testing.T) {
+		sut := createSut(t)
+		silence := silenceGen(withEmptyID)()
+		createdBy := notifier.ProvisionedSilenceCreatedByPrefix + "maintenance"
+		silence.CreatedBy = &createdBy
+
+		response := sut.RouteCreateSilence(createRequestCtx(), silence)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("deleting silence of definition without provenance succeeds", func(t *testing.T) {
+		sut := createSut(t)
+		silence := createProvisionedSilence(t, sut, ngmodels.ProvenanceNone)
+
+		response := sut.RouteDeleteSilence(createRequestCtx(), silence.ID)
+
+		require.Equal(t, http.StatusOK, response.Status())
+	})
+}
+
 func createSut(t *testing.T) AlertmanagerSrv {
 	t.Helper()
 
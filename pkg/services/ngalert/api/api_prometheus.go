@@ -134,6 +134,8 @@ func getStatesFromRequest(r *http.Request) ([]eval.State, error) {
 			states = append(states, eval.Pending)
 		case "nodata":
 			states = append(states, eval.NoData)
+		case "suppressed":
+			states = append(states, eval.Suppressed)
 		// nolint:goconst
 		case "error":
 			states = append(states, eval.Error)
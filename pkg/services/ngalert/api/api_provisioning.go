@@ -28,6 +28,7 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	inhibitRules        InhibitRuleService
 }
 
 type ContactPointService interface {
@@ -49,6 +50,12 @@ type NotificationPolicyService interface {
 	ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
 }
 
+type InhibitRuleService interface {
+	GetInhibitRules(ctx context.Context, orgID int64) (definitions.InhibitRules, error)
+	UpdateInhibitRules(ctx context.Context, orgID int64, rules definitions.InhibitRules, p alerting_models.Provenance) error
+	ResetInhibitRules(ctx context.Context, orgID int64) error
+}
+
 type MuteTimingService interface {
 	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
 	CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
@@ -122,6 +129,54 @@ func (srv *ProvisioningSrv) RouteResetPolicyTree(c *contextmodel.ReqContext) res
 	return response.JSON(http.StatusAccepted, tree)
 }
 
+func (srv *ProvisioningSrv) RouteGetInhibitRules(c *contextmodel.ReqContext) response.Response {
+	rules, err := srv.inhibitRules.GetInhibitRules(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	return response.JSON(http.StatusOK, rules)
+}
+
+func (srv *ProvisioningSrv) RouteGetInhibitRulesExport(c *contextmodel.ReqContext) response.Response {
+	rules, err := srv.inhibitRules.GetInhibitRules(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	return exportResponse(c, AlertingFileExportFromInhibitRules(c.SignedInUser.GetOrgID(), rules))
+}
+
+func (srv *ProvisioningSrv) RoutePutInhibitRules(c *contextmodel.ReqContext, rules definitions.InhibitRules) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.inhibitRules.UpdateInhibitRules(c.Req.Context(), c.SignedInUser.GetOrgID(), rules, alerting_models.Provenance(provenance))
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "inhibition rules updated"})
+}
+
+func (srv *ProvisioningSrv) RouteResetInhibitRules(c *contextmodel.ReqContext) response.Response {
+	err := srv.inhibitRules.ResetInhibitRules(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "inhibition rules reset"})
+}
+
 func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) response.Response {
 	q := provisioning.ContactPointQuery{
 		Name:  c.Query("name"),
@@ -574,7 +629,7 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 }
 
 func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
-	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings))
+	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings)+len(body.InhibitRules))
 	convertToResources := func() error {
 		for idx, group := range body.Groups {
 			gr := group
@@ -616,6 +671,18 @@ func exportHcl(download bool, body definitions.AlertingFileExport) response.Resp
 				Body: mthcl,
 			})
 		}
+
+		for idx, ir := range body.InhibitRules {
+			irhcl, err := InhibitRulesExportToInhibitRulesHclExport(ir)
+			if err != nil {
+				return fmt.Errorf("failed to convert inhibition rules to HCL:%w", err)
+			}
+			resources = append(resources, hcl.Resource{
+				Type: "grafana_inhibit_rules",
+				Name: fmt.Sprintf("inhibit_rules_%d", idx+1),
+				Body: &irhcl,
+			})
+		}
 		return nil
 	}
 	if err := convertToResources(); err != nil {
@@ -237,6 +237,51 @@ func TestProvisioningApi(t *testing.T) {
 		})
 	})
 
+	t.Run("inhibition rules", func(t *testing.T) {
+		t.Run("successful GET returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetInhibitRules(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), "ClusterDown")
+		})
+
+		t.Run("successful PUT returns 202", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			env.configs.(*provisioning.MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutInhibitRules(&rc, definitions.InhibitRules{})
+
+			require.Equal(t, 202, response.Status())
+		})
+
+		t.Run("successful DELETE returns 202", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			env.configs.(*provisioning.MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteResetInhibitRules(&rc)
+
+			require.Equal(t, 202, response.Status())
+		})
+
+		t.Run("are invalid, PUT returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rules := definitions.InhibitRules{Rules: []prometheus.InhibitRule{{Equal: model.LabelNames{"cluster"}}}}
+
+			response := sut.RoutePutInhibitRules(&rc, rules)
+
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "source matchers must not be empty")
+		})
+	})
+
 	t.Run("alert rules", func(t *testing.T) {
 		t.Run("are invalid", func(t *testing.T) {
 			t.Run("POST returns 400 on wrong body params", func(t *testing.T) {
@@ -1277,6 +1322,35 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, string(expectedResponse), string(response.Body()))
 			})
 		})
+
+		t.Run("inhibition rules", func(t *testing.T) {
+			t.Run("json body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				response := sut.RouteGetInhibitRulesExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				expectedResponse := `{"apiVersion":1,"inhibitRules":[{"orgId":1,"rules":[` +
+					`{"source_matchers":["alertname=\"ClusterDown\""],"target_matchers":["alertname!=\"ClusterDown\""],"equal":["cluster"]},` +
+					`{"source_match":{"severity":"critical"},"target_match_re":{"severity":"warning|info"},"equal":["instance"]}]}]}`
+				require.JSONEq(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				expectedResponse, err := testData.ReadFile(path.Join("test-data", "alertmanager_default_inhibitrules-export.hcl"))
+				require.NoError(t, err)
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Add("format", "hcl")
+
+				response := sut.RouteGetInhibitRulesExport(&rc)
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, string(expectedResponse), string(response.Body()))
+			})
+		})
 	})
 }
 
@@ -1620,6 +1694,7 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, env.log, env.ac),
 		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
+		inhibitRules:        provisioning.NewInhibitRuleService(env.configs, env.prov, env.xact, env.log),
 		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
 	}
 }
@@ -1923,7 +1998,16 @@ var testConfig = `
                         "location": "America/New_York"
                     }
                 ]
-            }]
+            }],
+		"inhibit_rules": [{
+			"source_matchers": ["alertname=ClusterDown"],
+			"target_matchers": ["alertname!=ClusterDown"],
+			"equal": ["cluster"]
+		}, {
+			"source_match": {"severity": "critical"},
+			"target_match_re": {"severity": "warning|info"},
+			"equal": ["instance"]
+		}]
 	}
 }
 `
@@ -2,7 +2,11 @@ package api
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
@@ -10,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -20,10 +25,17 @@ type Historian interface {
 type HistorySrv struct {
 	logger log.Logger
 	hist   Historian
+	store  RuleStore
+	authz  RuleAccessControlService
 }
 
 const labelQueryPrefix = "labels_"
 
+const (
+	historyExportFormatCSV    = "csv"
+	historyExportFormatNDJSON = "ndjson"
+)
+
 func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) response.Response {
 	from := c.QueryInt64("from")
 	to := c.QueryInt64("to")
@@ -56,3 +68,265 @@ func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) respon
 	}
 	return response.JSON(http.StatusOK, frame)
 }
+
+// RouteExportStateHistory streams the state history of the rules of a folder, or of a set of rules, as CSV or
+// newline-delimited JSON. Rules that the user cannot access are skipped.
+func (srv *HistorySrv) RouteExportStateHistory(c *contextmodel.ReqContext) response.Response {
+	format := c.QueryStrings("format")
+	exportFormat := historyExportFormatCSV
+	if len(format) > 0 {
+		exportFormat = format[0]
+	}
+	if exportFormat != historyExportFormatCSV && exportFormat != historyExportFormatNDJSON {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unsupported format %q, must be %s or %s", exportFormat, historyExportFormatCSV, historyExportFormatNDJSON), "")
+	}
+
+	folderUID := c.Query("folderUID")
+	ruleUIDs := c.QueryStrings("ruleUID")
+	if folderUID == "" && len(ruleUIDs) == 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("either folderUID or ruleUID must be specified"), "")
+	}
+
+	to := time.Now()
+	if c.Query("to") != "" {
+		to = time.Unix(c.QueryInt64("to"), 0)
+	}
+	from := time.Unix(c.QueryInt64("from"), 0)
+	if from.After(to) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("from must be before to"), "")
+	}
+
+	rules, err := srv.getExportedRules(c, folderUID, ruleUIDs)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rules")
+	}
+
+	return &historyExportResponse{
+		format: exportFormat,
+		rules:  rules,
+		query: func(ctx context.Context, rule *models.AlertRule) (*data.Frame, error) {
+			return srv.hist.Query(ctx, models.HistoryQuery{
+				RuleUID:      rule.UID,
+				OrgID:        rule.OrgID,
+				SignedInUser: c.SignedInUser,
+				From:         from,
+				To:           to,
+			})
+		},
+		logger: srv.logger,
+	}
+}
+
+// getExportedRules returns the rules of the folder, or the rules with the given UIDs, that the user has access to.
+func (srv *HistorySrv) getExportedRules(c *contextmodel.ReqContext, folderUID string, ruleUIDs []string) ([]*models.AlertRule, error) {
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespaces visible to the user: %w", err)
+	}
+	if folderUID != "" {
+		if _, ok := namespaceMap[folderUID]; !ok {
+			return nil, nil
+		}
+		namespaceMap = map[string]*folder.Folder{folderUID: namespaceMap[folderUID]}
+	}
+	if len(namespaceMap) == 0 {
+		return nil, nil
+	}
+
+	namespaceUIDs := make([]string, 0, len(namespaceMap))
+	for uid := range namespaceMap {
+		namespaceUIDs = append(namespaceUIDs, uid)
+	}
+	ruleList, err := srv.store.ListAlertRules(c.Req.Context(), &models.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		NamespaceUIDs: namespaceUIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]struct{}, len(ruleUIDs))
+	for _, uid := range ruleUIDs {
+		selected[uid] = struct{}{}
+	}
+	result := make([]*models.AlertRule, 0, len(ruleList))
+	for _, rule := range ruleList {
+		if _, ok := selected[rule.UID]; len(selected) > 0 && !ok {
+			continue
+		}
+		ok, err := srv.authz.HasAccessToRuleGroup(c.Req.Context(), c.SignedInUser, models.RulesGroup{rule})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, rule)
+		}
+	}
+	models.AlertRulesBy(models.AlertRulesByGroupKeyAndIndex).Sort(result)
+	return result, nil
+}
+
+// historyExportResponse writes the state history of the rules to the response one rule at a time, so that
+// the history of all rules is never kept in memory. Every row carries the UID and title of its rule followed
+// by the fields of the frame returned by the historian.
+type historyExportResponse struct {
+	format string
+	rules  []*models.AlertRule
+	query  func(ctx context.Context, rule *models.AlertRule) (*data.Frame, error)
+	logger log.Logger
+}
+
+func (r *historyExportResponse) Status() int {
+	return http.StatusOK
+}
+
+func (r *historyExportResponse) Body() []byte {
+	return nil
+}
+
+func (r *historyExportResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	var w historyRowWriter
+	switch r.format {
+	case historyExportFormatNDJSON:
+		ctx.Resp.Header().Set("Content-Type", "application/x-ndjson")
+		w = &ndjsonHistoryRowWriter{enc: json.NewEncoder(ctx.Resp)}
+	default:
+		ctx.Resp.Header().Set("Content-Type", "text/csv")
+		w = &csvHistoryRowWriter{w: csv.NewWriter(ctx.Resp)}
+	}
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment;filename="state-history.%s"`, r.format))
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	for _, rule := range r.rules {
+		frame, err := r.query(ctx.Req.Context(), rule)
+		if err != nil {
+			// The response has already started, so the export can only be stopped.
+			r.logger.Error("Failed to query state history, stopping the export", "rule_uid", rule.UID, "error", err)
+			return
+		}
+		if err := writeHistoryFrame(w, rule, frame); err != nil {
+			r.logger.Error("Failed to write state history", "rule_uid", rule.UID, "error", err)
+			return
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("Failed to write state history", "error", err)
+	}
+}
+
+func writeHistoryFrame(w historyRowWriter, rule *models.AlertRule, frame *data.Frame) error {
+	if frame == nil {
+		return nil
+	}
+	rows, err := frame.RowLen()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		names = append(names, field.Name)
+	}
+	for i := 0; i < rows; i++ {
+		values := make([]any, 0, len(frame.Fields))
+		for _, field := range frame.Fields {
+			values = append(values, historyExportValue(field.At(i)))
+		}
+		if err := w.WriteRow(rule, names, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historyExportValue dereferences the values of nullable fields and formats times as RFC 3339.
+func historyExportValue(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		v = rv.Elem().Interface()
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+type historyRowWriter interface {
+	WriteRow(rule *models.AlertRule, names []string, values []any) error
+	Flush() error
+}
+
+type ndjsonHistoryRowWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonHistoryRowWriter) WriteRow(rule *models.AlertRule, names []string, values []any) error {
+	row := make(map[string]any, len(names)+2)
+	for i, name := range names {
+		row[name] = values[i]
+	}
+	row["rule_uid"] = rule.UID
+	row["rule_title"] = rule.Title
+	return w.enc.Encode(row)
+}
+
+func (w *ndjsonHistoryRowWriter) Flush() error {
+	return nil
+}
+
+// csvHistoryRowWriter writes the header from the fields of the first row. The fields of later rows are matched
+// by name, and fields that are not in the header are dropped.
+type csvHistoryRowWriter struct {
+	w       *csv.Writer
+	columns map[string]int
+}
+
+func (w *csvHistoryRowWriter) WriteRow(rule *models.AlertRule, names []string, values []any) error {
+	if w.columns == nil {
+		w.columns = make(map[string]int, len(names))
+		header := []string{"rule_uid", "rule_title"}
+		for _, name := range names {
+			w.columns[name] = len(header)
+			header = append(header, name)
+		}
+		if err := w.w.Write(header); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(w.columns)+2)
+	record[0] = rule.UID
+	record[1] = rule.Title
+	for i, name := range names {
+		idx, ok := w.columns[name]
+		if !ok {
+			continue
+		}
+		record[idx] = csvHistoryValue(values[i])
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvHistoryRowWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func csvHistoryValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.RawMessage:
+		return string(val)
+	case fmt.Stringer:
+		return val.String()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
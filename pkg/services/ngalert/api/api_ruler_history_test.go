@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeHistorian struct {
+	queries []models.HistoryQuery
+}
+
+func (f *fakeHistorian) Query(_ context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	f.queries = append(f.queries, query)
+	frame := data.NewFrame("states")
+	frame.Fields = append(frame.Fields, data.NewField("time", nil, []time.Time{time.Unix(10, 0), time.Unix(20, 0)}))
+	frame.Fields = append(frame.Fields, data.NewField("next", nil, []string{"Alerting", "Normal"}))
+	frame.Fields = append(frame.Fields, data.NewField("data", nil, []json.RawMessage{json.RawMessage(`{"a":1}`), json.RawMessage(`{}`)}))
+	return frame, nil
+}
+
+func TestRouteExportStateHistory(t *testing.T) {
+	orgID := int64(1)
+	folder := randFolder()
+	otherFolder := randFolder()
+
+	setup := func(t *testing.T) (*HistorySrv, *fakeHistorian, []*models.AlertRule, []*models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		rules := models.GenerateAlertRules(2, models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("group")))
+		otherRules := models.GenerateAlertRules(1, models.AlertRuleGen(withOrgID(orgID), withNamespace(otherFolder), withGroup("group")))
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder, otherFolder)
+		ruleStore.PutRule(context.Background(), rules...)
+		ruleStore.PutRule(context.Background(), otherRules...)
+
+		hist := &fakeHistorian{}
+		return &HistorySrv{
+			logger: log.NewNopLogger(),
+			hist:   hist,
+			store:  ruleStore,
+			authz:  accesscontrol.NewRuleService(acimpl.ProvideAccessControl(setting.NewCfg())),
+		}, hist, rules, otherRules
+	}
+
+	export := func(t *testing.T, srv *HistorySrv, permissions map[int64]map[string][]string, query url.Values) (int, *httptest.ResponseRecorder) {
+		t.Helper()
+		c := createRequestContextWithPerms(orgID, permissions, nil)
+		c.Req.Form = query
+		recorder := httptest.NewRecorder()
+		c.Resp = web.NewResponseWriter(http.MethodGet, recorder)
+		resp := srv.RouteExportStateHistory(c)
+		if resp.Status() == http.StatusOK {
+			resp.WriteTo(c)
+		}
+		return resp.Status(), recorder
+	}
+
+	t.Run("should export the history of the rules of the folder as CSV", func(t *testing.T) {
+		srv, hist, rules, otherRules := setup(t)
+		permissions := createPermissionsForRules(append(rules, otherRules...), orgID)
+
+		status, recorder := export(t, srv, permissions, url.Values{"folderUID": {folder.UID}, "from": {"5"}, "to": {"30"}})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+		require.Equal(t, []string{"rule_uid", "rule_title", "time", "next", "data"}, records[0])
+		require.Contains(t, []string{rules[0].UID, rules[1].UID}, records[1][0])
+		require.Equal(t, []string{"1970-01-01T00:00:10Z", "Alerting", `{"a":1}`}, records[1][2:])
+
+		require.Len(t, hist.queries, 2)
+		for _, q := range hist.queries {
+			require.Equal(t, time.Unix(5, 0), q.From)
+			require.Equal(t, time.Unix(30, 0), q.To)
+		}
+	})
+
+	t.Run("should export the history of the selected rules as NDJSON", func(t *testing.T) {
+		srv, _, rules, otherRules := setup(t)
+		permissions := createPermissionsForRules(append(rules, otherRules...), orgID)
+
+		status, recorder := export(t, srv, permissions, url.Values{"ruleUID": {rules[0].UID, otherRules[0].UID}, "format": {"ndjson"}})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+
+		uids := map[string]int{}
+		scanner := bufio.NewScanner(recorder.Body)
+		for scanner.Scan() {
+			var row map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			uids[row["rule_uid"].(string)]++
+		}
+		require.Equal(t, map[string]int{rules[0].UID: 2, otherRules[0].UID: 2}, uids)
+	})
+
+	t.Run("should skip rules the user cannot access", func(t *testing.T) {
+		srv, hist, rules, _ := setup(t)
+		permissions := createPermissionsForRules(rules[:1], orgID)
+
+		status, recorder := export(t, srv, permissions, url.Values{"folderUID": {folder.UID}, "format": {"ndjson"}})
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, hist.queries, 1)
+		require.Equal(t, rules[0].UID, hist.queries[0].RuleUID)
+		require.Equal(t, 2, strings.Count(recorder.Body.String(), "\n"))
+	})
+
+	t.Run("should return 400 on invalid requests", func(t *testing.T) {
+		srv, _, _, _ := setup(t)
+		for _, query := range []url.Values{
+			{},
+			{"folderUID": {folder.UID}, "format": {"xml"}},
+			{"folderUID": {folder.UID}, "from": {"20"}, "to": {"10"}},
+		} {
+			status, _ := export(t, srv, nil, query)
+			require.Equal(t, http.StatusBadRequest, status)
+		}
+	})
+}
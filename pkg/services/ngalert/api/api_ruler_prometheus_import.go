@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+)
+
+// errImportedRuleConflict is returned when an imported rule has the title of a rule of the folder that was not imported,
+// or when an imported group has the name of a group with rules that were not imported.
+var errImportedRuleConflict = errors.New("alert rule title conflict")
+
+// RoutePostPrometheusRulesImport converts Prometheus rule groups to Grafana-managed rule groups and saves them in a folder.
+// The groups of the folder with the same name are replaced, other groups are not changed. Rules imported before are
+// matched by title, so that importing the same groups again updates the rules instead of re-creating them. Other rules
+// of the folder with the title of an imported rule are never replaced, the import fails with a conflict instead. So do
+// groups with the name of an imported group that have rules that were not imported, as replacing the group would
+// delete them.
+// The imported rules are marked with the provenance ProvenanceConvertedPrometheus, and can only be changed by another import.
+func (srv RulerSrv) RoutePostPrometheusRulesImport(c *contextmodel.ReqContext, body apimodels.PrometheusRuleGroups) response.Response {
+	folderUID, datasourceUID, dryRun := c.Query("folder_uid"), c.Query("datasource_uid"), c.QueryBool("dry_run")
+	if folderUID == "" || datasourceUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("folder_uid and datasource_uid query parameters are required"), "")
+	}
+
+	orgID := c.SignedInUser.GetOrgID()
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), folderUID, orgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	groups, skipped, err := srv.convertPrometheusRuleGroups(orgID, namespace.UID, datasourceUID, body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	result := apimodels.PrometheusRulesImportResult{
+		DryRun:                dryRun,
+		Groups:                make([]apimodels.PrometheusRuleGroupImportResult, 0, len(groups)),
+		SkippedRecordingRules: skipped,
+	}
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		existing, err := srv.store.ListAlertRules(tranCtx, &ngmodels.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespace.UID},
+		})
+		if err != nil {
+			return err
+		}
+		existingProvenances, err := srv.provenanceStore.GetProvenances(tranCtx, orgID, (&ngmodels.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		uidsByTitle := make(map[string]string, len(existing))
+		otherTitles := make(map[string]struct{})
+		otherGroups := make(map[string]struct{})
+		for _, rule := range existing {
+			if existingProvenances[rule.UID] == ngmodels.ProvenanceConvertedPrometheus {
+				uidsByTitle[rule.Title] = rule.UID
+			} else {
+				otherTitles[rule.Title] = struct{}{}
+				otherGroups[rule.RuleGroup] = struct{}{}
+			}
+		}
+
+		provenances := make(map[string]ngmodels.Provenance)
+		for _, group := range groups {
+			if _, ok := otherGroups[group.Title]; ok {
+				return fmt.Errorf("%w: the folder has a group named %q with rules that were not imported from Prometheus rules", errImportedRuleConflict, group.Title)
+			}
+			rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group.Rules))
+			for i := range group.Rules {
+				if _, ok := otherTitles[group.Rules[i].Title]; ok {
+					return fmt.Errorf("%w: the folder has a rule titled %q that was not imported from Prometheus rules", errImportedRuleConflict, group.Rules[i].Title)
+				}
+				group.Rules[i].UID = uidsByTitle[group.Rules[i].Title]
+				// the pause state of rules is not part of Prometheus rules, the current state is kept
+				rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: group.Rules[i]})
+			}
+			groupKey := ngmodels.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespace.UID, RuleGroup: group.Title}
+			changes, err := srv.applyRuleGroupChanges(c, tranCtx, groupKey, rules, ngmodels.ProvenanceConvertedPrometheus, dryRun)
+			if err != nil {
+				return err
+			}
+
+			groupResult := apimodels.PrometheusRuleGroupImportResult{
+				Name:    group.Title,
+				Created: make([]string, 0, len(changes.New)),
+				Updated: make([]string, 0, len(changes.Update)),
+				Deleted: make([]string, 0, len(changes.Delete)),
+				Rules:   make([]apimodels.GettableExtendedRuleNode, 0, len(group.Rules)),
+			}
+			for _, r := range changes.New {
+				groupResult.Created = append(groupResult.Created, r.Title)
+			}
+			for _, r := range changes.Update {
+				if len(r.Diff) > 0 {
+					groupResult.Updated = append(groupResult.Updated, r.Existing.Title)
+				}
+			}
+			for _, r := range changes.Delete {
+				groupResult.Deleted = append(groupResult.Deleted, r.Title)
+			}
+			for _, r := range rules {
+				provenances[r.UID] = ngmodels.ProvenanceConvertedPrometheus
+				groupResult.Rules = append(groupResult.Rules, toGettableExtendedRuleNode(r.AlertRule, provenances))
+			}
+			result.Groups = append(result.Groups, groupResult)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errImportedRuleConflict) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ruleGroupChangesErrorToResponse(err)
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+// convertPrometheusRuleGroups converts and validates the Prometheus rule groups, and returns the names of the skipped recording rules.
+func (srv RulerSrv) convertPrometheusRuleGroups(orgID int64, folderUID, datasourceUID string, body apimodels.PrometheusRuleGroups) ([]*ngmodels.AlertRuleGroup, []string, error) {
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:   datasourceUID,
+		DefaultInterval: srv.cfg.DefaultRuleEvaluationInterval,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	skipped := make([]string, 0)
+	groups := make([]*ngmodels.AlertRuleGroup, 0, len(body.Groups))
+	groupNames := make(map[string]struct{}, len(body.Groups))
+	titles := make(map[string]struct{})
+	for _, promGroup := range body.Groups {
+		if _, ok := groupNames[promGroup.Name]; ok {
+			return nil, nil, fmt.Errorf("%w: group %q is defined more than once", prom.ErrInvalidRuleGroup, promGroup.Name)
+		}
+		groupNames[promGroup.Name] = struct{}{}
+		for _, rule := range promGroup.Rules {
+			if rule.Record != "" && rule.Alert == "" {
+				skipped = append(skipped, rule.Record)
+			}
+		}
+
+		group, err := converter.PrometheusRulesToGrafana(orgID, folderUID, promGroup)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ngmodels.ValidateRuleGroupInterval(group.Interval, int64(srv.cfg.BaseInterval.Seconds())); err != nil {
+			return nil, nil, err
+		}
+		for i := range group.Rules {
+			rule := &group.Rules[i]
+			// titles of alert rules are unique in a folder
+			if _, ok := titles[rule.Title]; ok {
+				return nil, nil, fmt.Errorf("%w: alert %q is defined more than once, alert names must be unique in a folder", prom.ErrInvalidRuleGroup, rule.Title)
+			}
+			titles[rule.Title] = struct{}{}
+			if err := rule.ValidateAlertRule(*srv.cfg); err != nil {
+				return nil, nil, err
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, skipped, nil
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+)
+
+func TestRoutePostPrometheusRulesImport(t *testing.T) {
+	body := apimodels.PrometheusRuleGroups{
+		Groups: []apimodels.PrometheusRuleGroup{
+			{
+				Name:     "node",
+				Interval: prommodel.Duration(30 * time.Second),
+				Rules: []apimodels.PrometheusRule{
+					{Record: "instance:node_cpu:rate5m", Expr: "rate(node_cpu_seconds_total[5m])"},
+					{Alert: "HighCPU", Expr: "instance:node_cpu:rate5m > 0.9", For: prommodel.Duration(5 * time.Minute)},
+					{Alert: "InstanceDown", Expr: "up == 0", Labels: map[string]string{"severity": "critical"}},
+				},
+			},
+		},
+	}
+
+	setup := func(t *testing.T) (*RulerSrv, *fakes.RuleStore, *folder.Folder, int64) {
+		orgID := rand.Int63()
+		ruleStore := fakes.NewRuleStore(t)
+		f := randFolder()
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		svc.cfg.DefaultRuleEvaluationInterval = time.Minute
+		svc.QuotaService = quotatest.New(false, nil)
+		return svc, ruleStore, f, orgID
+	}
+
+	createRequest := func(orgID int64, folderUID string, dryRun bool) *contextmodel.ReqContext {
+		permissions := map[int64]map[string][]string{orgID: {
+			datasources.ActionQuery:     {datasources.ScopeAll},
+			ac.ActionAlertingRuleRead:   {"folders:*"},
+			ac.ActionAlertingRuleCreate: {"folders:*"},
+			ac.ActionAlertingRuleUpdate: {"folders:*"},
+			ac.ActionAlertingRuleDelete: {"folders:*"},
+		}}
+		c := createRequestContextWithPerms(orgID, permissions, nil)
+		c.Req.Form.Set("folder_uid", folderUID)
+		c.Req.Form.Set("datasource_uid", "mimir")
+		if dryRun {
+			c.Req.Form.Set("dry_run", "true")
+		}
+		return c
+	}
+
+	insertedRules := func(ruleStore *fakes.RuleStore) []models.AlertRule {
+		var result []models.AlertRule
+		for _, op := range ruleStore.RecordedOps {
+			if inserts, ok := op.([]models.AlertRule); ok {
+				result = append(result, inserts...)
+			}
+		}
+		return result
+	}
+
+	t.Run("should import alerting rules and skip recording rules", func(t *testing.T) {
+		svc, ruleStore, f, orgID := setup(t)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), body)
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		result := apimodels.PrometheusRulesImportResult{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.False(t, result.DryRun)
+		assert.Equal(t, []string{"instance:node_cpu:rate5m"}, result.SkippedRecordingRules)
+		require.Len(t, result.Groups, 1)
+		assert.Equal(t, "node", result.Groups[0].Name)
+		assert.ElementsMatch(t, []string{"HighCPU", "InstanceDown"}, result.Groups[0].Created)
+		require.Len(t, result.Groups[0].Rules, 2)
+		assert.Equal(t, apimodels.Provenance(models.ProvenanceConvertedPrometheus), result.Groups[0].Rules[0].GrafanaManagedAlert.Provenance)
+
+		inserted := insertedRules(ruleStore)
+		require.Len(t, inserted, 2)
+		for _, rule := range inserted {
+			assert.Equal(t, f.UID, rule.NamespaceUID)
+			assert.Equal(t, "node", rule.RuleGroup)
+			assert.Equal(t, int64(30), rule.IntervalSeconds)
+			assert.Equal(t, "mimir", rule.Data[0].DatasourceUID)
+		}
+	})
+
+	t.Run("should not save rules in a dry run", func(t *testing.T) {
+		svc, ruleStore, f, orgID := setup(t)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, true), body)
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		result := apimodels.PrometheusRulesImportResult{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		assert.ElementsMatch(t, []string{"HighCPU", "InstanceDown"}, result.Groups[0].Created)
+		assert.Empty(t, insertedRules(ruleStore))
+	})
+
+	t.Run("should update rules with the same title", func(t *testing.T) {
+		svc, ruleStore, f, orgID := setup(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(f), withGroup("node"), func(rule *models.AlertRule) {
+			rule.Title = "InstanceDown"
+		})()
+		ruleStore.PutRule(context.Background(), existing)
+		require.NoError(t, svc.provenanceStore.SetProvenance(context.Background(), existing, orgID, models.ProvenanceConvertedPrometheus))
+
+		response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), body)
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		result := apimodels.PrometheusRulesImportResult{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Groups, 1)
+		assert.Equal(t, []string{"HighCPU"}, result.Groups[0].Created)
+		assert.Equal(t, []string{"InstanceDown"}, result.Groups[0].Updated)
+	})
+
+	t.Run("should not update rules with the same title that were not imported", func(t *testing.T) {
+		for _, provenance := range []models.Provenance{models.ProvenanceNone, models.ProvenanceAPI} {
+			svc, ruleStore, f, orgID := setup(t)
+			existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(f), withGroup("other"), func(rule *models.AlertRule) {
+				rule.Title = "InstanceDown"
+			})()
+			ruleStore.PutRule(context.Background(), existing)
+			require.NoError(t, svc.provenanceStore.SetProvenance(context.Background(), existing, orgID, provenance))
+
+			response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), body)
+			require.Equal(t, http.StatusConflict, response.Status())
+			assert.Empty(t, insertedRules(ruleStore))
+		}
+	})
+
+	t.Run("should not replace rules provisioned by other means", func(t *testing.T) {
+		svc, ruleStore, f, orgID := setup(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(f), withGroup("node"))()
+		ruleStore.PutRule(context.Background(), existing)
+		require.NoError(t, svc.provenanceStore.SetProvenance(context.Background(), existing, orgID, models.ProvenanceFile))
+
+		response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), body)
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("should not replace groups with rules that were not imported", func(t *testing.T) {
+		svc, ruleStore, f, orgID := setup(t)
+		imported := models.AlertRuleGen(withOrgID(orgID), withNamespace(f), withGroup("node"), func(rule *models.AlertRule) {
+			rule.Title = "InstanceDown"
+		})()
+		manual := models.AlertRuleGen(withOrgID(orgID), withNamespace(f), withGroup("node"), func(rule *models.AlertRule) {
+			rule.Title = "DiskFull"
+		})()
+		ruleStore.PutRule(context.Background(), imported, manual)
+		require.NoError(t, svc.provenanceStore.SetProvenance(context.Background(), imported, orgID, models.ProvenanceConvertedPrometheus))
+
+		response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), body)
+		require.Equal(t, http.StatusConflict, response.Status())
+		assert.Contains(t, string(response.Body()), `group named \"node\"`)
+		assert.Empty(t, insertedRules(ruleStore))
+
+		rules, err := ruleStore.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID, NamespaceUIDs: []string{f.UID}})
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		svc, _, f, orgID := setup(t)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequest(orgID, "", false), body)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+
+		duplicated := apimodels.PrometheusRuleGroups{Groups: []apimodels.PrometheusRuleGroup{
+			{Name: "first", Rules: []apimodels.PrometheusRule{{Alert: "InstanceDown", Expr: "up == 0"}}},
+			{Name: "second", Rules: []apimodels.PrometheusRule{{Alert: "InstanceDown", Expr: "up == 0"}}},
+		}}
+		response = svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), duplicated)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+
+		invalidInterval := apimodels.PrometheusRuleGroups{Groups: []apimodels.PrometheusRuleGroup{
+			{Name: "first", Interval: prommodel.Duration(15 * time.Second), Rules: []apimodels.PrometheusRule{{Alert: "InstanceDown", Expr: "up == 0"}}},
+		}}
+		response = svc.RoutePostPrometheusRulesImport(createRequest(orgID, f.UID, false), invalidInterval)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
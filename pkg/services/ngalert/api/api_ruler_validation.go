@@ -95,6 +95,13 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Dependencies:    RuleDependenciesFromApiRuleDependencies(ruleNode.GrafanaManagedAlert.Dependencies),
+	}
+
+	for _, d := range newAlertRule.Dependencies {
+		if err := d.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
+		}
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -216,13 +223,14 @@ func validateRuleGroup(
 			uids[rule.UID] = idx
 		}
 
-		var hasPause, isPaused bool
+		var hasPause, isPaused, hasDependencies bool
 		original := ruleGroupConfig.Rules[idx]
 		if alert := original.GrafanaManagedAlert; alert != nil {
 			if alert.IsPaused != nil {
 				isPaused = *alert.IsPaused
 				hasPause = true
 			}
+			hasDependencies = alert.Dependencies != nil
 		}
 
 		ruleWithOptionals := ngmodels.AlertRuleWithOptionals{}
@@ -230,6 +238,7 @@ func validateRuleGroup(
 		rule.RuleGroupIndex = idx + 1
 		ruleWithOptionals.AlertRule = *rule
 		ruleWithOptionals.HasPause = hasPause
+		ruleWithOptionals.HasDependencies = hasDependencies
 
 		result = append(result, &ruleWithOptionals)
 	}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ruleVersionFields are the fields of a rule that are reported by the diff of two versions, in the order they are reported.
+var ruleVersionFields = []struct {
+	name  string
+	json  string
+	value func(r ngmodels.AlertRule) any
+}{
+	{name: "Title", json: "title", value: func(r ngmodels.AlertRule) any { return r.Title }},
+	{name: "Condition", json: "condition", value: func(r ngmodels.AlertRule) any { return r.Condition }},
+	{name: "Data", json: "data", value: func(r ngmodels.AlertRule) any { return ApiAlertQueriesFromAlertQueries(r.Data) }},
+	{name: "IntervalSeconds", json: "interval_seconds", value: func(r ngmodels.AlertRule) any { return r.IntervalSeconds }},
+	{name: "For", json: "for", value: func(r ngmodels.AlertRule) any { return model.Duration(r.For).String() }},
+	{name: "NoDataState", json: "no_data_state", value: func(r ngmodels.AlertRule) any { return r.NoDataState }},
+	{name: "ExecErrState", json: "exec_err_state", value: func(r ngmodels.AlertRule) any { return r.ExecErrState }},
+	{name: "Labels", json: "labels", value: func(r ngmodels.AlertRule) any { return r.Labels }},
+	{name: "Annotations", json: "annotations", value: func(r ngmodels.AlertRule) any { return r.Annotations }},
+	{name: "IsPaused", json: "is_paused", value: func(r ngmodels.AlertRule) any { return r.IsPaused }},
+	{name: "Dependencies", json: "dependencies", value: func(r ngmodels.AlertRule) any { return ApiRuleDependenciesFromRuleDependencies(r.Dependencies) }},
+	{name: "NamespaceUID", json: "namespace_uid", value: func(r ngmodels.AlertRule) any { return r.NamespaceUID }},
+	{name: "RuleGroup", json: "rule_group", value: func(r ngmodels.AlertRule) any { return r.RuleGroup }},
+}
+
+// RouteGetRuleVersions returns the versions of the rule identified by UID, the most recent first.
+// The definition of the rule at each version is not included, use RouteGetRuleVersion to get it.
+func (srv RulerSrv) RouteGetRuleVersions(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	if _, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID); err != nil {
+		return ruleVersionErrorToResponse(err)
+	}
+
+	versions, err := srv.store.GetAlertRuleVersions(c.Req.Context(), &ngmodels.GetAlertRuleVersionsQuery{
+		OrgID:   c.SignedInUser.GetOrgID(),
+		RuleUID: ruleUID,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+	}
+
+	result := make([]apimodels.GettableRuleVersion, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, toGettableRuleVersion(*v))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersion returns the definition of the rule identified by UID at the given version.
+func (srv RulerSrv) RouteGetRuleVersion(c *contextmodel.ReqContext, ruleUID string, version string) response.Response {
+	v, err := parseRuleVersion(version)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	ruleVersion, err := srv.getAuthorizedRuleVersion(c, ruleUID, v)
+	if err != nil {
+		return ruleVersionErrorToResponse(err)
+	}
+
+	provenanceRecords, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.GetOrgID(), (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule version")
+	}
+
+	result := toGettableRuleVersion(*ruleVersion)
+	node := toGettableExtendedRuleNode(ruleVersion.ToAlertRule(), provenanceRecords)
+	result.Rule = &node
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersionsDiff compares two versions of the rule identified by UID.
+// The version to compare from is required, the version to compare to defaults to the current version of the rule.
+func (srv RulerSrv) RouteGetRuleVersionsDiff(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	fromParam, toParam := c.Query("from"), c.Query("to")
+	if fromParam == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("the version to compare from must be specified"), "")
+	}
+	fromVersion, err := parseRuleVersion(fromParam)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	current, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID)
+	if err != nil {
+		return ruleVersionErrorToResponse(err)
+	}
+
+	from, err := srv.getAuthorizedRuleVersion(c, ruleUID, fromVersion)
+	if err != nil {
+		return ruleVersionErrorToResponse(err)
+	}
+	fromRule := from.ToAlertRule()
+
+	toRule := current
+	if toParam != "" {
+		toVersion, err := parseRuleVersion(toParam)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		to, err := srv.getAuthorizedRuleVersion(c, ruleUID, toVersion)
+		if err != nil {
+			return ruleVersionErrorToResponse(err)
+		}
+		toRule = to.ToAlertRule()
+	}
+
+	return response.JSON(http.StatusOK, apimodels.RuleVersionsDiff{
+		From:    fromRule.Version,
+		To:      toRule.Version,
+		Changes: diffRuleVersions(fromRule, toRule),
+	})
+}
+
+// RouteRestoreRuleVersion restores the rule identified by UID to the definition it had at the given version.
+// The restored definition is saved as a new version of the rule, and is subject to the same checks as any other update of the group.
+func (srv RulerSrv) RouteRestoreRuleVersion(c *contextmodel.ReqContext, ruleUID string, version string) response.Response {
+	v, err := parseRuleVersion(version)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	ruleVersion, err := srv.getAuthorizedRuleVersion(c, ruleUID, v)
+	if err != nil {
+		return ruleVersionErrorToResponse(err)
+	}
+	restored := ruleVersion.ToAlertRule()
+
+	group, err := srv.store.GetAlertRulesGroupByRuleUID(c.Req.Context(), &ngmodels.GetAlertRulesGroupByRuleUIDQuery{
+		UID:   ruleUID,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return ruleVersionErrorToResponse(err)
+	}
+
+	var groupKey ngmodels.AlertRuleGroupKey
+	rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group))
+	for _, r := range group {
+		rule := ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true, HasDependencies: true}
+		if r.UID == ruleUID {
+			groupKey = r.GetGroupKey()
+			// the rule stays in its current group, only its definition is restored
+			rule.Title = restored.Title
+			rule.Condition = restored.Condition
+			rule.Data = restored.Data
+			rule.NoDataState = restored.NoDataState
+			rule.ExecErrState = restored.ExecErrState
+			rule.For = restored.For
+			rule.Annotations = restored.Annotations
+			rule.Labels = restored.Labels
+			rule.IsPaused = restored.IsPaused
+			rule.Dependencies = restored.Dependencies
+			rule.DashboardUID = restored.DashboardUID
+			rule.PanelID = restored.PanelID
+		}
+		rules = append(rules, &rule)
+	}
+	if groupKey.RuleGroup == "" {
+		return ruleVersionErrorToResponse(ngmodels.ErrAlertRuleNotFound)
+	}
+
+	return srv.updateAlertRulesInGroup(c, groupKey, rules)
+}
+
+// getAuthorizedRuleVersion checks that the user is authorized to access the rule and the data sources used by the rule at
+// the given version, and returns the version.
+func (srv RulerSrv) getAuthorizedRuleVersion(c *contextmodel.ReqContext, ruleUID string, version int64) (*ngmodels.AlertRuleVersion, error) {
+	if _, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID); err != nil {
+		return nil, err
+	}
+	ruleVersion, err := srv.store.GetAlertRuleVersion(c.Req.Context(), &ngmodels.GetAlertRuleVersionQuery{
+		OrgID:   c.SignedInUser.GetOrgID(),
+		RuleUID: ruleUID,
+		Version: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rule := ruleVersion.ToAlertRule()
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &rule); err != nil {
+		return nil, err
+	}
+	return ruleVersion, nil
+}
+
+// diffRuleVersions returns the fields of the rule that differ between the two versions.
+func diffRuleVersions(from, to ngmodels.AlertRule) []apimodels.RuleFieldDiff {
+	diff := from.Diff(&to, store.AlertRuleFieldsToIgnoreInDiff[:]...)
+	changes := make([]apimodels.RuleFieldDiff, 0)
+	for _, field := range ruleVersionFields {
+		if len(diff.GetDiffsForField(field.name)) == 0 {
+			continue
+		}
+		changes = append(changes, apimodels.RuleFieldDiff{
+			Field: field.json,
+			From:  field.value(from),
+			To:    field.value(to),
+		})
+	}
+	return changes
+}
+
+func toGettableRuleVersion(v ngmodels.AlertRuleVersion) apimodels.GettableRuleVersion {
+	return apimodels.GettableRuleVersion{
+		Version:       v.Version,
+		ParentVersion: v.ParentVersion,
+		Created:       v.Created,
+		CreatedBy:     v.CreatedBy,
+	}
+}
+
+func parseRuleVersion(version string) (int64, error) {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid rule version %q", version)
+	}
+	return v, nil
+}
+
+func ruleVersionErrorToResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	return errorToResponse(err)
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRouteGetRuleVersions(t *testing.T) {
+	orgID := rand.Int63()
+	ruleStore, rule := createRuleStoreWithVersions(t, orgID)
+
+	t.Run("should return versions the most recent first", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersions(createRequestContext(orgID, nil), rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result []apimodels.GettableRuleVersion
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 2)
+		assert.Equal(t, rule.Version, result[0].Version)
+		assert.Equal(t, rule.Version-1, result[1].Version)
+		assert.Equal(t, "editor", result[1].CreatedBy)
+		assert.Nil(t, result[0].Rule)
+	})
+
+	t.Run("should return Forbidden if user cannot query the data sources of the rule", func(t *testing.T) {
+		request := createRequestContextWithPerms(orgID, map[int64]map[string][]string{}, nil)
+		response := createService(ruleStore).RouteGetRuleVersions(request, rule.UID)
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should return NotFound if rule does not exist", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersions(createRequestContext(orgID, nil), "unknown")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestRouteGetRuleVersion(t *testing.T) {
+	orgID := rand.Int63()
+	ruleStore, rule := createRuleStoreWithVersions(t, orgID)
+
+	t.Run("should return the definition of the rule at the version", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersion(createRequestContext(orgID, nil), rule.UID, "1")
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.GettableRuleVersion
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.NotNil(t, result.Rule)
+		assert.Equal(t, "previous title", result.Rule.GrafanaManagedAlert.Title)
+	})
+
+	t.Run("should return NotFound if version does not exist", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersion(createRequestContext(orgID, nil), rule.UID, "100")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return BadRequest if version is invalid", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersion(createRequestContext(orgID, nil), rule.UID, "latest")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestRouteGetRuleVersionsDiff(t *testing.T) {
+	orgID := rand.Int63()
+	ruleStore, rule := createRuleStoreWithVersions(t, orgID)
+
+	t.Run("should compare to the current version by default", func(t *testing.T) {
+		request := createRequestContext(orgID, nil)
+		request.Req.Form.Set("from", "1")
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(request, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersionsDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Equal(t, int64(1), result.From)
+		assert.Equal(t, rule.Version, result.To)
+		assert.Equal(t, []apimodels.RuleFieldDiff{{Field: "title", From: "previous title", To: rule.Title}}, result.Changes)
+	})
+
+	t.Run("should return no changes when comparing a version to itself", func(t *testing.T) {
+		request := createRequestContext(orgID, nil)
+		request.Req.Form.Set("from", "2")
+		request.Req.Form.Set("to", "2")
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(request, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersionsDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Empty(t, result.Changes)
+	})
+
+	t.Run("should return BadRequest if the version to compare from is missing", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(createRequestContext(orgID, nil), rule.UID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestRouteRestoreRuleVersion(t *testing.T) {
+	permissions := func(orgID int64) map[int64]map[string][]string {
+		return map[int64]map[string][]string{orgID: {
+			datasources.ActionQuery:      {datasources.ScopeAll},
+			ac.ActionAlertingRuleRead:    {"folders:*"},
+			ac.ActionAlertingRuleUpdate:  {"folders:*"},
+			ac.ActionAlertingRuleCreate:  {"folders:*"},
+			ac.ActionAlertingRuleDelete:  {"folders:*"},
+			dashboards.ActionFoldersRead: {dashboards.ScopeFoldersAll},
+		}}
+	}
+
+	t.Run("should update the rule with the definition of the version", func(t *testing.T) {
+		orgID := rand.Int63()
+		ruleStore, rule := createRuleStoreWithVersions(t, orgID)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+
+		request := createRequestContextWithPerms(orgID, permissions(orgID), nil)
+		request.SignedInUser.Login = "admin"
+		response := svc.RouteRestoreRuleVersion(request, rule.UID, "1")
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		var result apimodels.UpdateRuleGroupResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		assert.Equal(t, []string{rule.UID}, result.Updated)
+
+		var updates []models.UpdateRule
+		for _, op := range ruleStore.RecordedOps {
+			if u, ok := op.([]models.UpdateRule); ok {
+				updates = u
+			}
+		}
+		require.Len(t, updates, 1)
+		assert.Equal(t, "previous title", updates[0].New.Title)
+		assert.Equal(t, rule.RuleGroup, updates[0].New.RuleGroup)
+		assert.Equal(t, "admin", updates[0].New.UpdatedBy)
+	})
+
+	t.Run("should not restore a provisioned rule", func(t *testing.T) {
+		orgID := rand.Int63()
+		ruleStore, rule := createRuleStoreWithVersions(t, orgID)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		require.NoError(t, svc.provenanceStore.SetProvenance(context.Background(), rule, orgID, models.ProvenanceAPI))
+
+		response := svc.RouteRestoreRuleVersion(createRequestContextWithPerms(orgID, permissions(orgID), nil), rule.UID, "1")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return NotFound if version does not exist", func(t *testing.T) {
+		orgID := rand.Int63()
+		ruleStore, rule := createRuleStoreWithVersions(t, orgID)
+
+		response := createService(ruleStore).RouteRestoreRuleVersion(createRequestContextWithPerms(orgID, permissions(orgID), nil), rule.UID, "100")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+// createRuleStoreWithVersions creates a store with a rule at version 2, which only differs from version 1 by its title.
+func createRuleStoreWithVersions(t *testing.T, orgID int64) (*fakes.RuleStore, *models.AlertRule) {
+	t.Helper()
+	ruleStore := fakes.NewRuleStore(t)
+	rule := models.AlertRuleGen(withOrgID(orgID))()
+	rule.Version = 2
+	ruleStore.PutRule(context.Background(), rule)
+
+	toVersion := func(r models.AlertRule, parent int64) *models.AlertRuleVersion {
+		return &models.AlertRuleVersion{
+			RuleOrgID:        r.OrgID,
+			RuleUID:          r.UID,
+			RuleNamespaceUID: r.NamespaceUID,
+			RuleGroup:        r.RuleGroup,
+			RuleGroupIndex:   r.RuleGroupIndex,
+			ParentVersion:    parent,
+			Version:          r.Version,
+			Created:          r.Updated,
+			Title:            r.Title,
+			Condition:        r.Condition,
+			Data:             r.Data,
+			IntervalSeconds:  r.IntervalSeconds,
+			NoDataState:      r.NoDataState,
+			ExecErrState:     r.ExecErrState,
+			For:              r.For,
+			Annotations:      r.Annotations,
+			Labels:           r.Labels,
+			IsPaused:         r.IsPaused,
+			CreatedBy:        "editor",
+		}
+	}
+	previous := models.CopyRule(rule)
+	previous.Version = 1
+	previous.Title = "previous title"
+	ruleStore.Versions = append(ruleStore.Versions, toVersion(*previous, 0), toVersion(*rule, 1))
+	return ruleStore, rule
+}
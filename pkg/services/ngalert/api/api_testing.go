@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
@@ -41,6 +42,8 @@ type TestingApiSrv struct {
 	featureManager  featuremgmt.FeatureToggles
 	appUrl          *url.URL
 	tracer          tracing.Tracer
+	ruleStore       RuleStore
+	mam             *notifier.MultiOrgAlertmanager
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -254,3 +257,69 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// BacktestRuleGroup evaluates all rules of a rule group over a time range, and simulates the notifications that the
+// Alertmanager would have sent for the resulting alerts. The notification policies and mute timings of the configuration
+// in the request are used if set, otherwise the ones of the current configuration.
+func (srv TestingApiSrv) BacktestRuleGroup(c *contextmodel.ReqContext, cmd apimodels.BacktestRuleGroupConfig) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	if !cmd.From.Before(cmd.To) {
+		return ErrResp(http.StatusBadRequest, nil, "From must be before To")
+	}
+	if cmd.FolderUID == "" || cmd.RuleGroup == "" {
+		return ErrResp(http.StatusBadRequest, nil, "folderUid and ruleGroup are required")
+	}
+
+	orgID := c.SignedInUser.GetOrgID()
+	namespace, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), cmd.FolderUID, orgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	rules, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespace.UID},
+		RuleGroup:     cmd.RuleGroup,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule group")
+	}
+	if len(rules) == 0 {
+		return ErrResp(http.StatusNotFound, nil, "rule group does not exist")
+	}
+	if err := srv.authz.AuthorizeAccessToRuleGroup(c.Req.Context(), c.SignedInUser, rules); err != nil {
+		return errorToResponse(err)
+	}
+
+	var cfg apimodels.Config
+	if cmd.AlertmanagerConfig != nil {
+		cfg = cmd.AlertmanagerConfig.Config
+	} else {
+		current, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), orgID)
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		cfg = current.AlertmanagerConfig.Config
+	}
+
+	includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel)
+	extraLabels := make(map[string]data.Labels, len(rules))
+	for _, rule := range rules {
+		extraLabels[rule.UID] = state.GetRuleExtraLabels(rule, namespace.Title, includeFolder)
+	}
+
+	result, err := srv.backtesting.TestRuleGroup(c.Req.Context(), c.SignedInUser, rules, extraLabels, cmd.From, cmd.To, cfg)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "Failed to evaluate")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate")
+	}
+	return response.JSON(http.StatusOK, result)
+}
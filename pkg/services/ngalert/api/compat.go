@@ -2,9 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
+	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -30,6 +33,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		Annotations:  a.Annotations,
 		Labels:       a.Labels,
 		IsPaused:     a.IsPaused,
+		Dependencies: RuleDependenciesFromApiRuleDependencies(a.Dependencies),
 	}, nil
 }
 
@@ -52,6 +56,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		Labels:       rule.Labels,
 		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
 		IsPaused:     rule.IsPaused,
+		Dependencies: ApiRuleDependenciesFromRuleDependencies(rule.Dependencies),
 	}
 }
 
@@ -100,6 +105,37 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 	return result
 }
 
+// RuleDependenciesFromApiRuleDependencies converts a collection of definitions.RuleDependency to collection of models.RuleDependency.
+// A nil collection stays nil.
+func RuleDependenciesFromApiRuleDependencies(dependencies []definitions.RuleDependency) []models.RuleDependency {
+	if dependencies == nil {
+		return nil
+	}
+	result := make([]models.RuleDependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		result = append(result, models.RuleDependency{
+			RuleUID:  d.RuleUID,
+			Matchers: d.Matchers,
+		})
+	}
+	return result
+}
+
+// ApiRuleDependenciesFromRuleDependencies converts a collection of models.RuleDependency to collection of definitions.RuleDependency
+func ApiRuleDependenciesFromRuleDependencies(dependencies []models.RuleDependency) []definitions.RuleDependency {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	result := make([]definitions.RuleDependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		result = append(result, definitions.RuleDependency{
+			RuleUID:  d.RuleUID,
+			Matchers: d.Matchers,
+		})
+	}
+	return result
+}
+
 func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.AlertRuleGroup, error) {
 	ruleGroup := models.AlertRuleGroup{
 		Title:     a.Title,
@@ -373,3 +409,77 @@ func MuteTimingIntervalToMuteTimeIntervalHclExport(m definitions.MuteTimeInterva
 	err = j.Unmarshal(mdata, &result)
 	return result, err
 }
+
+// AlertingFileExportFromInhibitRules creates a definitions.AlertingFileExport DTO from definitions.InhibitRules.
+func AlertingFileExportFromInhibitRules(orgID int64, rules definitions.InhibitRules) definitions.AlertingFileExport {
+	return definitions.AlertingFileExport{
+		APIVersion: 1,
+		InhibitRules: []definitions.InhibitRulesExport{{
+			OrgID: orgID,
+			Rules: rules.Rules,
+		}},
+	}
+}
+
+// InhibitRulesExportToInhibitRulesHclExport converts definitions.InhibitRulesExport to definitions.InhibitRulesHclExport.
+// Deprecated equality and regex matchers are converted to matchers.
+func InhibitRulesExportToInhibitRulesHclExport(e definitions.InhibitRulesExport) (definitions.InhibitRulesHclExport, error) {
+	result := definitions.InhibitRulesHclExport{Rules: make([]definitions.InhibitRuleHclExport, 0, len(e.Rules))}
+	for _, rule := range e.Rules {
+		source, err := inhibitRuleMatchersToStrings(rule.SourceMatch, rule.SourceMatchRE, rule.SourceMatchers)
+		if err != nil {
+			return result, err
+		}
+		target, err := inhibitRuleMatchersToStrings(rule.TargetMatch, rule.TargetMatchRE, rule.TargetMatchers)
+		if err != nil {
+			return result, err
+		}
+		equal := make([]string, 0, len(rule.Equal))
+		for _, name := range rule.Equal {
+			equal = append(equal, string(name))
+		}
+		result.Rules = append(result.Rules, definitions.InhibitRuleHclExport{
+			SourceMatchers: source,
+			TargetMatchers: target,
+			Equal:          equal,
+		})
+	}
+	return result, nil
+}
+
+func inhibitRuleMatchersToStrings(match map[string]string, matchRE amConfig.MatchRegexps, matchers amConfig.Matchers) ([]string, error) {
+	result := make([]string, 0, len(match)+len(matchRE)+len(matchers))
+	names := make([]string, 0, len(match))
+	for name := range match {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, match[name])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m.String())
+	}
+	names = make([]string, 0, len(matchRE))
+	for name := range matchRE {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := matchRE[name].MarshalYAML()
+		if err != nil {
+			return nil, err
+		}
+		value, _ := re.(string)
+		m, err := labels.NewMatcher(labels.MatchRegexp, name, value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m.String())
+	}
+	for _, m := range matchers {
+		result = append(result, m.String())
+	}
+	return result, nil
+}
@@ -109,10 +109,30 @@ func (f *RulerApiHandler) handleRoutePostRulesGroupForExport(ctx *contextmodel.R
 	return f.GrafanaRuler.ExportFromPayload(ctx, conf, namespace)
 }
 
+func (f *RulerApiHandler) handleRoutePostPrometheusRulesImport(ctx *contextmodel.ReqContext, conf apimodels.PrometheusRuleGroups) response.Response {
+	return f.GrafanaRuler.RoutePostPrometheusRulesImport(ctx, conf)
+}
+
 func (f *RulerApiHandler) handleRouteGetRulesForExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.GrafanaRuler.ExportRules(ctx)
 }
 
+func (f *RulerApiHandler) handleRouteGetGrafanaRuleVersions(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersions(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteGetGrafanaRuleVersion(ctx *contextmodel.ReqContext, ruleUID, version string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersion(ctx, ruleUID, version)
+}
+
+func (f *RulerApiHandler) handleRouteGetGrafanaRuleVersionsDiff(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsDiff(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostGrafanaRuleVersionRestore(ctx *contextmodel.ReqContext, ruleUID, version string) response.Response {
+	return f.GrafanaRuler.RouteRestoreRuleVersion(ctx, ruleUID, version)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
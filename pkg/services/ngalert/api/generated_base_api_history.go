@@ -18,15 +18,32 @@ import (
 )
 
 type HistoryApi interface {
+	RouteExportStateHistory(*contextmodel.ReqContext) response.Response
 	RouteGetStateHistory(*contextmodel.ReqContext) response.Response
 }
 
+func (f *HistoryApiHandler) RouteExportStateHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteExportStateHistory(ctx)
+}
+
 func (f *HistoryApiHandler) RouteGetStateHistory(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStateHistory(ctx)
 }
 
 func (api *API) RegisterHistoryApiEndpoints(srv HistoryApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/v1/rules/history/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/rules/history/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rules/history/export",
+				api.Hooks.Wrap(srv.RouteExportStateHistory),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/rules/history"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
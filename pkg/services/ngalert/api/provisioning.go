@@ -135,3 +135,19 @@ func (f *ProvisioningApiHandler) handleRouteExportMuteTiming(ctx *contextmodel.R
 func (f *ProvisioningApiHandler) handleRouteExportMuteTimings(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetMuteTimingsExport(ctx)
 }
+
+func (f *ProvisioningApiHandler) handleRouteGetInhibitRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetInhibitRules(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetInhibitRulesExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetInhibitRulesExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutInhibitRules(ctx *contextmodel.ReqContext, rules apimodels.InhibitRules) response.Response {
+	return f.svc.RoutePutInhibitRules(ctx, rules)
+}
+
+func (f *ProvisioningApiHandler) handleRouteResetInhibitRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteResetInhibitRules(ctx)
+}
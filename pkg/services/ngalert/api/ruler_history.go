@@ -15,6 +15,10 @@ func NewStateHistoryApi(svc *HistorySrv) *HistoryApiHandler {
 	}
 }
 
+func (f *HistoryApiHandler) handleRouteExportStateHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteExportStateHistory(ctx)
+}
+
 func (f *HistoryApiHandler) handleRouteGetStateHistory(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteQueryStateHistory(ctx)
 }
@@ -0,0 +1,13 @@
+resource "grafana_inhibit_rules" "inhibit_rules_1" {
+
+  rule {
+    source_matchers = ["alertname=\"ClusterDown\""]
+    target_matchers = ["alertname!=\"ClusterDown\""]
+    equal           = ["cluster"]
+  }
+  rule {
+    source_matchers = ["severity=\"critical\""]
+    target_matchers = ["severity=~\"warning|info\""]
+    equal           = ["instance"]
+  }
+}
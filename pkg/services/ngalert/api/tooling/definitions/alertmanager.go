@@ -178,6 +178,19 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/routes/test alertmanager RoutePostTestGrafanaRoutes
+//
+// Test which notification policies of the routing tree alerts with the given labels match.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: TestRoutesResults
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -349,6 +362,60 @@ const (
 	ExecutionError  TemplateErrorKind = "execution_error"
 )
 
+// swagger:parameters RoutePostTestGrafanaRoutes
+type TestRoutesConfigParams struct {
+	// in:body
+	Body TestRoutesConfigBodyParams
+}
+
+type TestRoutesConfigBodyParams struct {
+	// Labels of the alerts to route.
+	Alerts []model.LabelSet `json:"alerts"`
+
+	// Configuration to test. The current configuration is used if not set.
+	AlertmanagerConfig *PostableApiAlertingConfig `json:"alertmanager_config,omitempty"`
+}
+
+// swagger:model
+type TestRoutesResults struct {
+	Results []TestRoutesResult `json:"results"`
+}
+
+type TestRoutesResult struct {
+	// Labels of the alert.
+	Labels model.LabelSet `json:"labels"`
+
+	// Routes matched by the alert. An alert matches more than one route if the routes have continue enabled.
+	Routes []TestRoutesMatchedRoute `json:"routes"`
+}
+
+type TestRoutesMatchedRoute struct {
+	// Path is the position of the route and each of its parents in the routes of their parent, from the top.
+	// The path of the default policy is empty.
+	Path []int `json:"path"`
+
+	// Matchers of the route and its parents, that the alert matches.
+	Matchers ObjectMatchers `json:"matchers,omitempty"`
+
+	// Receiver of the route, inherited from the parents if not set.
+	Receiver string `json:"receiver"`
+
+	// Labels the alerts are grouped by. Alerts are grouped by all labels if GroupByAll is set.
+	GroupBy    []string `json:"group_by"`
+	GroupByAll bool     `json:"group_by_all"`
+
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+
+	// Mute time intervals of the route, and the ones that are active now.
+	MuteTimeIntervals       []string `json:"mute_time_intervals"`
+	ActiveMuteTimeIntervals []string `json:"active_mute_time_intervals"`
+
+	// Muted is true if notifications of the route are muted now by one of its mute time intervals.
+	Muted bool `json:"muted"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -729,8 +796,9 @@ type GettableHistoricUserConfigs struct {
 }
 
 type GettableApiAlertingConfig struct {
-	Config              `yaml:",inline"`
-	MuteTimeProvenances map[string]Provenance `yaml:"muteTimeProvenances,omitempty" json:"muteTimeProvenances,omitempty"`
+	Config                 `yaml:",inline"`
+	MuteTimeProvenances    map[string]Provenance `yaml:"muteTimeProvenances,omitempty" json:"muteTimeProvenances,omitempty"`
+	InhibitRulesProvenance Provenance            `yaml:"inhibitRulesProvenance,omitempty" json:"inhibitRulesProvenance,omitempty"`
 	// Override with our superset receiver type
 	Receivers []*GettableApiReceiver `yaml:"receivers,omitempty" json:"receivers,omitempty"`
 }
@@ -1273,6 +1341,8 @@ type PostableGrafanaReceivers struct {
 
 type EncryptFn func(ctx context.Context, payload []byte) ([]byte, error)
 
+type DecryptFn func(ctx context.Context, payload []byte) ([]byte, error)
+
 // ObjectMatchers is Matchers with a different Unmarshal and Marshal methods that accept matchers as objects
 // that have already been parsed.
 type ObjectMatchers labels.Matchers
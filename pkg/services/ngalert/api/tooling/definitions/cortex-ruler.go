@@ -412,6 +412,9 @@ type PostableGrafanaRule struct {
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	// Dependencies are the upstream rules that suppress this rule while they are firing.
+	// The current dependencies of the rule are kept if the field is omitted.
+	Dependencies []RuleDependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 // swagger:model
@@ -431,6 +434,16 @@ type GettableGrafanaRule struct {
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	Dependencies    []RuleDependency    `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// RuleDependency is an upstream rule of an alert rule. While any instance of the upstream rule that matches all matchers
+// is firing, the instances of the dependent rule that would fire are suppressed instead, and are not sent to the Alertmanager.
+type RuleDependency struct {
+	// UID of the upstream rule.
+	RuleUID string `json:"ruleUid" yaml:"ruleUid"`
+	// Matchers of the labels of the upstream instances, for example region="eu".
+	Matchers []string `json:"matchers,omitempty" yaml:"matchers,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -8,9 +8,10 @@ type AlertingFileExport struct {
 	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
 	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
 	MuteTimings   []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	InhibitRules  []InhibitRulesExport       `json:"inhibitRules,omitempty" yaml:"inhibitRules,omitempty"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteExportMuteTimings RouteExportMuteTiming
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetInhibitRulesExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteExportMuteTimings RouteExportMuteTiming
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
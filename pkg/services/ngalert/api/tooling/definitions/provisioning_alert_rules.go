@@ -156,6 +156,8 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// example: [{"ruleUid": "core-network-down", "matchers": ["region=\"eu\""]}]
+	Dependencies []RuleDependency `json:"dependencies,omitempty"`
 }
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
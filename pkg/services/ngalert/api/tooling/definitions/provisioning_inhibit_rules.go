@@ -0,0 +1,113 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+// swagger:route GET /api/v1/provisioning/inhibit-rules provisioning stable RouteGetInhibitRules
+//
+// Get the inhibition rules.
+//
+//     Responses:
+//       200: InhibitRules
+//         description: The currently active inhibition rules
+
+// swagger:route PUT /api/v1/provisioning/inhibit-rules provisioning stable RoutePutInhibitRules
+//
+// Sets the inhibition rules.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/inhibit-rules provisioning stable RouteResetInhibitRules
+//
+// Clears the inhibition rules.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+
+// swagger:route GET /api/v1/provisioning/inhibit-rules/export provisioning stable RouteGetInhibitRulesExport
+//
+// Export the inhibition rules in provisioning file format.
+//
+//     Responses:
+//       200: AlertingFileExport
+//       404: NotFound
+
+// swagger:parameters RoutePutInhibitRules
+type InhibitRulesPayload struct {
+	// The new inhibition rules to use
+	// in:body
+	Body InhibitRules
+}
+
+// swagger:parameters RoutePutInhibitRules
+type InhibitRulesHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// InhibitRules are the inhibition rules of the Grafana Alertmanager. They are managed as a single resource.
+// swagger:model
+type InhibitRules struct {
+	Rules      []config.InhibitRule `json:"rules" yaml:"rules"`
+	Provenance Provenance           `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+}
+
+func (r *InhibitRules) ResourceType() string {
+	return "inhibitRules"
+}
+
+func (r *InhibitRules) ResourceID() string {
+	return ""
+}
+
+// InhibitRulesExport is the provisioned file export of alerting.InhibitRulesV1.
+type InhibitRulesExport struct {
+	OrgID int64                `json:"orgId" yaml:"orgId"`
+	Rules []config.InhibitRule `json:"rules" yaml:"rules"`
+}
+
+// InhibitRulesHclExport is the HCL export of the inhibition rules of an organization.
+type InhibitRulesHclExport struct {
+	Rules []InhibitRuleHclExport `hcl:"rule,block"`
+}
+
+// InhibitRuleHclExport is the HCL export of config.InhibitRule. Deprecated equality and regex matchers are exported as matchers.
+type InhibitRuleHclExport struct {
+	SourceMatchers []string `hcl:"source_matchers"`
+	TargetMatchers []string `hcl:"target_matchers"`
+	Equal          []string `hcl:"equal"`
+}
+
+// Validate checks that the inhibition rules are valid and that each rule has source and target matchers.
+func (r *InhibitRules) Validate() error {
+	noopUnmarshal := func(_ interface{}) error { return nil }
+	for i := range r.Rules {
+		rule := r.Rules[i]
+		if err := rule.UnmarshalYAML(noopUnmarshal); err != nil {
+			return fmt.Errorf("inhibition rule %d: %w", i, err)
+		}
+		if len(rule.SourceMatchers) == 0 && len(rule.SourceMatch) == 0 && len(rule.SourceMatchRE) == 0 {
+			return fmt.Errorf("inhibition rule %d: source matchers must not be empty", i)
+		}
+		if len(rule.TargetMatchers) == 0 && len(rule.TargetMatch) == 0 && len(rule.TargetMatchRE) == 0 {
+			return fmt.Errorf("inhibition rule %d: target matchers must not be empty", i)
+		}
+		for _, name := range rule.Equal {
+			if !name.IsValid() {
+				return fmt.Errorf("inhibition rule %d: invalid label name %q in equal", i, name)
+			}
+		}
+	}
+	return nil
+}
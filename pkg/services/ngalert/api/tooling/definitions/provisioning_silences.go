@@ -0,0 +1,82 @@
+package definitions
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SilenceDefinition is a provisioned silence identified by a stable UID. It is either a one-off silence with a fixed
+// start and end, or a recurring silence, defined by time intervals or by a cron schedule and a duration, that the
+// Alertmanager materializes into concrete silences ahead of time.
+type SilenceDefinition struct {
+	UID      string          `json:"uid" yaml:"uid"`
+	Matchers config.Matchers `json:"matchers" yaml:"matchers"`
+	Comment  string          `json:"comment" yaml:"comment"`
+
+	StartsAt *time.Time `json:"startsAt,omitempty" yaml:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty" yaml:"endsAt,omitempty"`
+
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals,omitempty" yaml:"time_intervals,omitempty"`
+
+	Schedule string         `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	Duration model.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+
+	Provenance Provenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+}
+
+func (s *SilenceDefinition) ResourceType() string {
+	return "silence"
+}
+
+func (s *SilenceDefinition) ResourceID() string {
+	return s.UID
+}
+
+// Validate checks that the silence definition has a valid UID, matchers, a comment, and exactly one of
+// a fixed time range, time intervals or a cron schedule.
+func (s *SilenceDefinition) Validate() error {
+	if err := util.ValidateUID(s.UID); err != nil {
+		return fmt.Errorf("invalid UID: %w", err)
+	}
+	if len(s.Matchers) == 0 {
+		return errors.New("matchers must not be empty")
+	}
+	if s.Comment == "" {
+		return errors.New("comment must not be empty")
+	}
+
+	kinds := 0
+	if s.StartsAt != nil || s.EndsAt != nil {
+		kinds++
+		if s.StartsAt == nil || s.EndsAt == nil {
+			return errors.New("both startsAt and endsAt must be set")
+		}
+		if !s.EndsAt.After(*s.StartsAt) {
+			return errors.New("endsAt must be after startsAt")
+		}
+	}
+	if len(s.TimeIntervals) > 0 {
+		kinds++
+	}
+	if s.Schedule != "" || s.Duration != 0 {
+		kinds++
+		if _, err := cron.ParseStandard(s.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule %q: %w", s.Schedule, err)
+		}
+		if s.Duration <= 0 {
+			return errors.New("duration must be greater than zero")
+		}
+	}
+	if kinds != 1 {
+		return errors.New("exactly one of startsAt and endsAt, time_intervals, or schedule and duration must be set")
+	}
+	return nil
+}
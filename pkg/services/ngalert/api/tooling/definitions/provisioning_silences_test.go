@@ -0,0 +1,123 @@
+package definitions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSilenceDefinitionValidate(t *testing.T) {
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "cluster", "prod")
+	require.NoError(t, err)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	valid := func() SilenceDefinition {
+		return SilenceDefinition{
+			UID:      "maintenance",
+			Matchers: config.Matchers{matcher},
+			Comment:  "Maintenance",
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(s *SilenceDefinition)
+		err    string
+	}{
+		{
+			name: "one-off silence",
+			mutate: func(s *SilenceDefinition) {
+				s.StartsAt, s.EndsAt = &start, &end
+			},
+		},
+		{
+			name: "time intervals",
+			mutate: func(s *SilenceDefinition) {
+				s.TimeIntervals = []timeinterval.TimeInterval{{}}
+			},
+		},
+		{
+			name: "schedule",
+			mutate: func(s *SilenceDefinition) {
+				s.Schedule, s.Duration = "0 2 * * SAT", model.Duration(time.Hour)
+			},
+		},
+		{
+			name: "invalid UID",
+			mutate: func(s *SilenceDefinition) {
+				s.UID = "invalid uid"
+				s.StartsAt, s.EndsAt = &start, &end
+			},
+			err: "invalid UID",
+		},
+		{
+			name: "no matchers",
+			mutate: func(s *SilenceDefinition) {
+				s.Matchers = nil
+				s.StartsAt, s.EndsAt = &start, &end
+			},
+			err: "matchers must not be empty",
+		},
+		{
+			name: "no comment",
+			mutate: func(s *SilenceDefinition) {
+				s.Comment = ""
+				s.StartsAt, s.EndsAt = &start, &end
+			},
+			err: "comment must not be empty",
+		},
+		{
+			name: "end before start",
+			mutate: func(s *SilenceDefinition) {
+				s.StartsAt, s.EndsAt = &end, &start
+			},
+			err: "endsAt must be after startsAt",
+		},
+		{
+			name: "invalid schedule",
+			mutate: func(s *SilenceDefinition) {
+				s.Schedule, s.Duration = "every saturday", model.Duration(time.Hour)
+			},
+			err: "invalid schedule",
+		},
+		{
+			name: "schedule without duration",
+			mutate: func(s *SilenceDefinition) {
+				s.Schedule = "0 2 * * SAT"
+			},
+			err: "duration must be greater than zero",
+		},
+		{
+			name:   "no time range",
+			mutate: func(s *SilenceDefinition) {},
+			err:    "exactly one of",
+		},
+		{
+			name: "several time ranges",
+			mutate: func(s *SilenceDefinition) {
+				s.StartsAt, s.EndsAt = &start, &end
+				s.TimeIntervals = []timeinterval.TimeInterval{{}}
+			},
+			err: "exactly one of",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.mutate(&s)
+			err := s.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
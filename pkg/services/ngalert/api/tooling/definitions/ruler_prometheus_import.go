@@ -0,0 +1,90 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /api/ruler/grafana/api/v1/import/prometheus ruler RoutePostPrometheusRulesImport
+//
+// Import Prometheus rule groups as Grafana-managed rule groups in a folder. Rule groups of the folder with the same name are replaced.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: PrometheusRulesImportResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: description: A rule of the folder that was not imported has the title of an imported rule.
+
+// swagger:parameters RoutePostPrometheusRulesImport
+type PrometheusRulesImportParams struct {
+	// The UID of the folder to import the rule groups into
+	// in:query
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// The UID of the Prometheus data source the imported rules query
+	// in:query
+	// required:true
+	DatasourceUID string `json:"datasource_uid"`
+	// If true, the rule groups are converted and validated but not saved
+	// in:query
+	// required:false
+	DryRun bool `json:"dry_run"`
+	// in:body
+	Body PrometheusRuleGroups
+}
+
+// PrometheusRuleGroups is a Prometheus rule file.
+// swagger:model
+type PrometheusRuleGroups struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// swagger:model
+type PrometheusRuleGroup struct {
+	Name     string           `yaml:"name" json:"name"`
+	Interval model.Duration   `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []PrometheusRule `yaml:"rules" json:"rules"`
+}
+
+// PrometheusRule is either an alerting rule or a recording rule. Recording rules are not imported.
+// swagger:model
+type PrometheusRule struct {
+	Alert       string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Record      string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Expr        string            `yaml:"expr" json:"expr"`
+	For         model.Duration    `yaml:"for,omitempty" json:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// swagger:response PrometheusRulesImportResponse
+type PrometheusRulesImportResponse struct {
+	// in:body
+	Body PrometheusRulesImportResult
+}
+
+// swagger:model
+type PrometheusRulesImportResult struct {
+	// DryRun is true when the rule groups were not saved
+	DryRun bool                              `json:"dry_run"`
+	Groups []PrometheusRuleGroupImportResult `json:"groups"`
+	// SkippedRecordingRules are the names of the recording rules that were not imported
+	SkippedRecordingRules []string `json:"skipped_recording_rules"`
+}
+
+// swagger:model
+type PrometheusRuleGroupImportResult struct {
+	Name string `json:"name"`
+	// Created, Updated and Deleted are the titles of the rules changed by the import of the group
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+	// Rules are the Grafana-managed rules converted from the Prometheus rules
+	Rules []GettableExtendedRuleNode `json:"rules"`
+}
@@ -12,6 +12,43 @@ import "github.com/grafana/grafana-plugin-sdk-go/data"
 //     Responses:
 //       200: StateHistory
 
+// swagger:route GET /api/v1/rules/history/export history RouteExportStateHistory
+//
+// Export the state history of the rules of a folder, or of a set of rules.
+//
+//     Produces:
+//     - text/csv
+//     - application/x-ndjson
+//
+//     Responses:
+//       200: Ack
+//       400: ValidationError
+
+// swagger:parameters RouteExportStateHistory
+type ExportStateHistoryParams struct {
+	// Export the history of the rules in this folder.
+	// in:query
+	// required:false
+	FolderUID string `json:"folderUID"`
+	// Export the history of these rules.
+	// in:query
+	// required:false
+	RuleUID []string `json:"ruleUID"`
+	// Unix timestamp in seconds of the start of the export.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Unix timestamp in seconds of the end of the export. Defaults to now.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Format of the export, csv or ndjson.
+	// in:query
+	// required:false
+	// default:csv
+	Format string `json:"format"`
+}
+
 // swagger:response StateHistory
 type StateHistory struct {
 	// in:body
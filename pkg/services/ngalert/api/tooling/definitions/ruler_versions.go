@@ -0,0 +1,124 @@
+package definitions
+
+import "time"
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetGrafanaRuleVersions
+//
+// List the versions of a rule, the most recent first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionsResponse
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version} ruler RouteGetGrafanaRuleVersion
+//
+// Get a version of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionResponse
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/diff ruler RouteGetGrafanaRuleVersionsDiff
+//
+// Compare two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionsDiffResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route POST /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostGrafanaRuleVersionRestore
+//
+// Restore a version of a rule as a new version
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: description: The rule was changed concurrently.
+
+// swagger:parameters RouteGetGrafanaRuleVersions RouteGetGrafanaRuleVersion RouteGetGrafanaRuleVersionsDiff RoutePostGrafanaRuleVersionRestore
+type RuleUIDParam struct {
+	// in:path
+	RuleUID string
+}
+
+// swagger:parameters RouteGetGrafanaRuleVersion RoutePostGrafanaRuleVersionRestore
+type RuleVersionParam struct {
+	// in:path
+	Version int64
+}
+
+// swagger:parameters RouteGetGrafanaRuleVersionsDiff
+type RuleVersionsDiffParams struct {
+	// The version to compare from
+	// in:query
+	// required:true
+	From int64 `json:"from"`
+	// The version to compare to, defaults to the current version of the rule
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+}
+
+// swagger:response RuleVersionsResponse
+type RuleVersionsResponse struct {
+	// in:body
+	Body []GettableRuleVersion
+}
+
+// swagger:response RuleVersionResponse
+type RuleVersionResponse struct {
+	// in:body
+	Body GettableRuleVersion
+}
+
+// swagger:model
+type GettableRuleVersion struct {
+	Version       int64     `json:"version"`
+	ParentVersion int64     `json:"parent_version"`
+	Created       time.Time `json:"created"`
+	// CreatedBy identifies who made the change, it is empty when unknown
+	CreatedBy string `json:"created_by,omitempty"`
+	// Rule is the definition of the rule at this version, it is only returned when a single version is requested
+	Rule *GettableExtendedRuleNode `json:"rule,omitempty"`
+}
+
+// swagger:response RuleVersionsDiffResponse
+type RuleVersionsDiffResponse struct {
+	// in:body
+	Body RuleVersionsDiff
+}
+
+// swagger:model
+type RuleVersionsDiff struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// Changes are the fields of the rule that differ between the two versions
+	Changes []RuleFieldDiff `json:"changes"`
+}
+
+// swagger:model
+type RuleFieldDiff struct {
+	// Field is one of title, condition, data, interval_seconds, for, no_data_state, exec_err_state,
+	// labels, annotations, is_paused, namespace_uid or rule_group
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
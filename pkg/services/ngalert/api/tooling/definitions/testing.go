@@ -68,6 +68,21 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /api/v1/rule/backtest/group testing BacktestRuleGroup
+//
+// Test all rules of a rule group over a time range, and simulate the notifications that would have been sent
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestRuleGroupResult
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -234,3 +249,45 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestRuleGroup
+type BacktestRuleGroupRequest struct {
+	// in:body
+	Body BacktestRuleGroupConfig
+}
+
+// swagger:model
+type BacktestRuleGroupConfig struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// UID of the folder of the rule group.
+	FolderUID string `json:"folderUid"`
+	// Name of the rule group.
+	RuleGroup string `json:"ruleGroup"`
+
+	// Configuration whose notification policies and mute timings are simulated. The current configuration is used if not set.
+	AlertmanagerConfig *PostableApiAlertingConfig `json:"alertmanager_config,omitempty"`
+}
+
+// BacktestRuleGroupResult is the timeline of the notifications that would have been sent for the alerts of a rule group.
+// swagger:model
+type BacktestRuleGroupResult struct {
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// BacktestNotification is a notification of a group of alerts that would have been sent to a receiver.
+type BacktestNotification struct {
+	Time        time.Time       `json:"time"`
+	Receiver    string          `json:"receiver"`
+	GroupLabels model.LabelSet  `json:"groupLabels"`
+	Alerts      []BacktestAlert `json:"alerts"`
+}
+
+// BacktestAlert is an alert of a BacktestNotification.
+type BacktestAlert struct {
+	Labels   model.LabelSet `json:"labels"`
+	Status   string         `json:"status"`
+	StartsAt time.Time      `json:"startsAt"`
+	EndsAt   *time.Time     `json:"endsAt,omitempty"`
+}
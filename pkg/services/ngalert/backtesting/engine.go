@@ -9,12 +9,14 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -126,6 +128,96 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	return result, nil
 }
 
+// TestRuleGroup evaluates all rules of a rule group over the time range, replays the alerts through the notification
+// policy tree of the Alertmanager configuration, and returns the timeline of the notifications that would have been sent.
+// extraLabels are the labels that are added to the alerts of each rule, by rule UID.
+func (e *Engine) TestRuleGroup(ctx context.Context, user identity.Requester, rules []*models.AlertRule, extraLabels map[string]data.Labels, from, to time.Time, cfg definitions.Config) (definitions.BacktestRuleGroupResult, error) {
+	logger := logger.FromContext(ctx)
+
+	if len(rules) == 0 {
+		return definitions.BacktestRuleGroupResult{}, fmt.Errorf("%w: rule group has no rules", ErrInvalidInputData)
+	}
+	if !from.Before(to) {
+		return definitions.BacktestRuleGroupResult{}, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	// All rules of a group are evaluated with the interval of the group.
+	intervalSeconds := rules[0].IntervalSeconds
+	if to.Sub(from).Seconds() < float64(intervalSeconds) {
+		return definitions.BacktestRuleGroupResult{}, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), intervalSeconds)
+	}
+	length := int(to.Sub(from).Seconds()) / int(intervalSeconds)
+	interval := time.Duration(intervalSeconds) * time.Second
+
+	simulator, err := newNotificationSimulator(cfg)
+	if err != nil {
+		return definitions.BacktestRuleGroupResult{}, errors.Join(ErrInvalidInputData, err)
+	}
+
+	logger.Info("Start testing rule group", "from", from, "to", to, "interval", intervalSeconds, "evaluations", length, "rules", len(rules))
+	start := time.Now()
+
+	type evaluation struct {
+		time    time.Time
+		results eval.Results
+	}
+	evaluations := make([][]evaluation, len(rules))
+	for i, rule := range rules {
+		ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+		evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition())
+		if err != nil {
+			return definitions.BacktestRuleGroupResult{}, errors.Join(ErrInvalidInputData, fmt.Errorf("rule %s: %w", rule.UID, err))
+		}
+		evaluations[i] = make([]evaluation, length)
+		err = evaluator.Eval(ruleCtx, from, interval, length, func(idx int, now time.Time, results eval.Results) error {
+			if idx >= length {
+				logger.Info("Unexpected evaluation. Skipping", "rule", rule.UID, "evaluationTime", now, "evaluationIndex", idx, "expectedEvaluations", length)
+				return nil
+			}
+			evaluations[i][idx] = evaluation{time: now, results: results}
+			return nil
+		})
+		if err != nil {
+			return definitions.BacktestRuleGroupResult{}, err
+		}
+	}
+
+	stateManager := e.createStateManager()
+	for idx := 0; idx < length; idx++ {
+		now := from.Add(time.Duration(idx) * interval)
+		var alerts []simulatedAlert
+		for i, rule := range rules {
+			if evaluations[i][idx].results == nil {
+				continue
+			}
+			ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+			states := stateManager.ProcessEvalResults(ruleCtx, evaluations[i][idx].time, rule, evaluations[i][idx].results, extraLabels[rule.UID])
+			for _, s := range states {
+				if !s.State.NeedsSending(0) {
+					continue
+				}
+				alert := simulatedAlert{
+					labels:   make(model.LabelSet, len(s.Labels)),
+					startsAt: s.StartsAt,
+					endsAt:   s.EndsAt,
+				}
+				for name, value := range s.Labels {
+					alert.labels[model.LabelName(name)] = model.LabelValue(value)
+				}
+				alerts = append(alerts, alert)
+			}
+		}
+		if err := simulator.put(now, alerts...); err != nil {
+			return definitions.BacktestRuleGroupResult{}, err
+		}
+	}
+	if err := simulator.advance(to); err != nil {
+		return definitions.BacktestRuleGroupResult{}, err
+	}
+
+	logger.Info("Rule group testing finished successfully", "duration", time.Since(start), "notifications", len(simulator.notifications))
+	return definitions.BacktestRuleGroupResult{Notifications: simulator.notifications}, nil
+}
+
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition) (backtestingEvaluator, error) {
 	for _, q := range condition.Data {
 		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
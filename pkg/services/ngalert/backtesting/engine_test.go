@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -378,6 +379,71 @@ func TestEvaluatorTest(t *testing.T) {
 	})
 }
 
+func TestEngineTestRuleGroup(t *testing.T) {
+	cfg := definitions.PostableApiAlertingConfig{}
+	require.NoError(t, json.Unmarshal([]byte(simulatorTestConfig), &cfg))
+
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			return eval.GenerateResults(1, eval.ResultGen()), nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	from := time.Date(2023, 10, 18, 12, 0, 0, 0, time.UTC)
+	resolvedAt := from.Add(10 * time.Minute)
+	manager := &fakeStateManager{
+		stateCallback: func(now time.Time) []state.StateTransition {
+			s := &state.State{
+				CacheID:  "state",
+				Labels:   data.Labels{"alertname": "test"},
+				State:    eval.Alerting,
+				StartsAt: from,
+				EndsAt:   now.Add(4 * time.Minute),
+			}
+			switch {
+			case now.Equal(resolvedAt):
+				s.State, s.Resolved, s.EndsAt = eval.Normal, true, now
+			case now.After(resolvedAt):
+				s.State = eval.Normal
+			}
+			return []state.StateTransition{{State: s}}
+		},
+	}
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			return manager
+		},
+	}
+	rule := models.AlertRuleGen(models.WithInterval(time.Minute))()
+
+	t.Run("should return notifications of the rule group", func(t *testing.T) {
+		result, err := engine.TestRuleGroup(context.Background(), nil, []*models.AlertRule{rule}, nil, from, from.Add(30*time.Minute), cfg.Config)
+		require.NoError(t, err)
+
+		require.Len(t, result.Notifications, 2)
+		require.Equal(t, from.Add(30*time.Second), result.Notifications[0].Time)
+		require.Equal(t, "firing", result.Notifications[0].Alerts[0].Status)
+		require.Equal(t, resolvedAt.Add(30*time.Second), result.Notifications[1].Time)
+		require.Equal(t, "resolved", result.Notifications[1].Alerts[0].Status)
+	})
+
+	t.Run("should fail if the range is less than the interval", func(t *testing.T) {
+		_, err := engine.TestRuleGroup(context.Background(), nil, []*models.AlertRule{rule}, nil, from, from.Add(time.Second), cfg.Config)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should fail without routing tree", func(t *testing.T) {
+		_, err := engine.TestRuleGroup(context.Background(), nil, []*models.AlertRule{rule}, nil, from, from.Add(30*time.Minute), definitions.Config{})
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
+
 type fakeStateManager struct {
 	stateCallback func(now time.Time) []state.StateTransition
 }
@@ -0,0 +1,224 @@
+package backtesting
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var errNoRoutingTree = errors.New("the configuration has no routing tree")
+
+// simulatedAlert is an alert as it is known to the simulated Alertmanager.
+type simulatedAlert struct {
+	labels   model.LabelSet
+	startsAt time.Time
+	endsAt   time.Time
+}
+
+func (a simulatedAlert) resolvedAt(t time.Time) bool {
+	return !a.endsAt.IsZero() && !a.endsAt.After(t)
+}
+
+// simulatedGroup is the counterpart of the aggregation group of the Alertmanager dispatcher.
+type simulatedGroup struct {
+	key        string
+	route      *dispatch.Route
+	labels     model.LabelSet
+	alerts     map[model.Fingerprint]simulatedAlert
+	next       time.Time
+	hasFlushed bool
+}
+
+// nflogEntry is the counterpart of the entry of the notification log of the Alertmanager.
+type nflogEntry struct {
+	timestamp time.Time
+	firing    map[model.Fingerprint]struct{}
+	resolved  map[model.Fingerprint]struct{}
+}
+
+// notificationSimulator replays alerts through the notification policy tree of an Alertmanager configuration,
+// and records the notifications the Alertmanager would send. It follows the dispatcher: alerts are aggregated into
+// groups per route and group labels, groups are flushed after group_wait and then every group_interval, and
+// notifications are deduplicated against the previous notification of the group unless the repeat_interval elapsed.
+// Muted groups do not notify. All receivers are assumed to send resolved notifications.
+type notificationSimulator struct {
+	root       *dispatch.Route
+	intervener *timeinterval.Intervener
+	groups     map[string]*simulatedGroup
+	nflog      map[string]nflogEntry
+
+	notifications []definitions.BacktestNotification
+}
+
+func newNotificationSimulator(cfg definitions.Config) (*notificationSimulator, error) {
+	if cfg.Route == nil {
+		return nil, errNoRoutingTree
+	}
+	muteTimes := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals))
+	for _, ti := range cfg.MuteTimeIntervals {
+		muteTimes[ti.Name] = ti.TimeIntervals
+	}
+	return &notificationSimulator{
+		root:          dispatch.NewRoute(cfg.Route.AsAMRoute(), nil),
+		intervener:    timeinterval.NewIntervener(muteTimes),
+		groups:        map[string]*simulatedGroup{},
+		nflog:         map[string]nflogEntry{},
+		notifications: []definitions.BacktestNotification{},
+	}, nil
+}
+
+// put flushes all groups that are due up to now, and then inserts the alerts received at now.
+func (s *notificationSimulator) put(now time.Time, alerts ...simulatedAlert) error {
+	if err := s.advance(now); err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		for _, route := range s.root.Match(alert.labels) {
+			groupLabels := model.LabelSet{}
+			for name, value := range alert.labels {
+				if _, ok := route.RouteOpts.GroupBy[name]; ok || route.RouteOpts.GroupByAll {
+					groupLabels[name] = value
+				}
+			}
+			key := fmt.Sprintf("%s:%s", route.Key(), groupLabels)
+			group, ok := s.groups[key]
+			if !ok {
+				group = &simulatedGroup{
+					key:    key,
+					route:  route,
+					labels: groupLabels,
+					alerts: map[model.Fingerprint]simulatedAlert{},
+					next:   now.Add(route.RouteOpts.GroupWait),
+				}
+				s.groups[key] = group
+			}
+			group.alerts[alert.labels.Fingerprint()] = alert
+			// Flush immediately if the wait duration for this alert is already over.
+			if !group.hasFlushed && alert.startsAt.Add(route.RouteOpts.GroupWait).Before(now) {
+				group.next = now
+			}
+		}
+	}
+	// Alerts that wait for no time are flushed right away.
+	return s.advance(now)
+}
+
+// advance flushes, in chronological order, all groups that are due up to and including now.
+func (s *notificationSimulator) advance(now time.Time) error {
+	for {
+		var due *simulatedGroup
+		for _, group := range s.groups {
+			if group.next.After(now) {
+				continue
+			}
+			if due == nil || group.next.Before(due.next) || (group.next.Equal(due.next) && group.key < due.key) {
+				due = group
+			}
+		}
+		if due == nil {
+			return nil
+		}
+		if err := s.flush(due); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *notificationSimulator) flush(group *simulatedGroup) error {
+	now := group.next
+	group.next = now.Add(group.route.RouteOpts.GroupInterval)
+	group.hasFlushed = true
+
+	muted, err := s.intervener.Mutes(group.route.RouteOpts.MuteTimeIntervals, now)
+	if err != nil {
+		return err
+	}
+
+	fingerprints := make([]model.Fingerprint, 0, len(group.alerts))
+	for fp := range group.alerts {
+		fingerprints = append(fingerprints, fp)
+	}
+	sort.Slice(fingerprints, func(i, j int) bool {
+		return group.alerts[fingerprints[i]].labels.String() < group.alerts[fingerprints[j]].labels.String()
+	})
+
+	firing := map[model.Fingerprint]struct{}{}
+	resolved := map[model.Fingerprint]struct{}{}
+	alerts := make([]definitions.BacktestAlert, 0, len(fingerprints))
+	for _, fp := range fingerprints {
+		alert := group.alerts[fp]
+		result := definitions.BacktestAlert{
+			Labels:   alert.labels,
+			Status:   string(model.AlertFiring),
+			StartsAt: alert.startsAt,
+		}
+		if alert.resolvedAt(now) {
+			endsAt := alert.endsAt
+			result.Status = string(model.AlertResolved)
+			result.EndsAt = &endsAt
+			resolved[fp] = struct{}{}
+		} else {
+			firing[fp] = struct{}{}
+		}
+		alerts = append(alerts, result)
+	}
+
+	if !muted {
+		entry, ok := s.nflog[group.key]
+		if needsUpdate(entry, ok, firing, resolved, group.route.RouteOpts.RepeatInterval, now) {
+			s.notifications = append(s.notifications, definitions.BacktestNotification{
+				Time:        now,
+				Receiver:    group.route.RouteOpts.Receiver,
+				GroupLabels: group.labels,
+				Alerts:      alerts,
+			})
+			s.nflog[group.key] = nflogEntry{timestamp: now, firing: firing, resolved: resolved}
+		}
+	}
+
+	// Resolved alerts are removed from the group once they were flushed, and empty groups are removed.
+	for fp := range resolved {
+		delete(group.alerts, fp)
+	}
+	if len(group.alerts) == 0 {
+		delete(s.groups, group.key)
+	}
+	return nil
+}
+
+// needsUpdate does the same as the deduplication stage of the notification pipeline of the Alertmanager.
+func needsUpdate(entry nflogEntry, exists bool, firing, resolved map[model.Fingerprint]struct{}, repeat time.Duration, now time.Time) bool {
+	// If we haven't notified about the alert group before, notify right away unless we only have resolved alerts.
+	if !exists {
+		return len(firing) > 0
+	}
+	if !isSubset(entry.firing, firing) {
+		return true
+	}
+	// Notify about all alerts being resolved, unless the alerts fired and resolved since the last notification.
+	if len(firing) == 0 {
+		return len(entry.firing) > 0
+	}
+	if !isSubset(entry.resolved, resolved) {
+		return true
+	}
+	// Nothing changed, only notify if the repeat interval has passed.
+	return entry.timestamp.Before(now.Add(-repeat))
+}
+
+// isSubset returns true if all elements of subset are in set.
+func isSubset(set, subset map[model.Fingerprint]struct{}) bool {
+	for fp := range subset {
+		if _, ok := set[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
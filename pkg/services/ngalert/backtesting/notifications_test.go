@@ -0,0 +1,132 @@
+package backtesting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const simulatorTestConfig = `{
+	"route": {
+		"receiver": "default",
+		"group_by": ["alertname"],
+		"group_wait": "30s",
+		"group_interval": "5m",
+		"repeat_interval": "1h",
+		"routes": [
+			{
+				"receiver": "muted",
+				"object_matchers": [["team", "=", "muted"]],
+				"mute_time_intervals": ["always"]
+			}
+		]
+	},
+	"mute_time_intervals": [
+		{"name": "always", "time_intervals": [{}]}
+	],
+	"receivers": [
+		{"name": "default", "grafana_managed_receivers": [{"name": "default", "type": "email", "settings": {"addresses": "a@example.com"}}]},
+		{"name": "muted", "grafana_managed_receivers": [{"name": "muted", "type": "email", "settings": {"addresses": "a@example.com"}}]}
+	]
+}`
+
+func TestNotificationSimulator(t *testing.T) {
+	cfg := definitions.PostableApiAlertingConfig{}
+	require.NoError(t, json.Unmarshal([]byte(simulatorTestConfig), &cfg))
+	from := time.Date(2023, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// simulate evaluates every minute and sends the alerts that fire at that time, like the scheduler does.
+	// Alerts are resolved at the evaluation after they stopped firing.
+	type firing struct {
+		labels   model.LabelSet
+		from, to time.Duration
+	}
+	simulate := func(t *testing.T, length time.Duration, alerts ...firing) []definitions.BacktestNotification {
+		t.Helper()
+		simulator, err := newNotificationSimulator(cfg.Config)
+		require.NoError(t, err)
+		for offset := time.Duration(0); offset < length; offset += time.Minute {
+			now := from.Add(offset)
+			var batch []simulatedAlert
+			for _, a := range alerts {
+				switch {
+				case offset >= a.from && offset < a.to:
+					batch = append(batch, simulatedAlert{labels: a.labels, startsAt: from.Add(a.from), endsAt: now.Add(4 * time.Minute)})
+				case offset == a.to:
+					batch = append(batch, simulatedAlert{labels: a.labels, startsAt: from.Add(a.from), endsAt: now})
+				}
+			}
+			require.NoError(t, simulator.put(now, batch...))
+		}
+		require.NoError(t, simulator.advance(from.Add(length)))
+		return simulator.notifications
+	}
+
+	t.Run("should notify after group wait and when resolved", func(t *testing.T) {
+		notifications := simulate(t, 30*time.Minute, firing{labels: model.LabelSet{"alertname": "a"}, from: 0, to: 10 * time.Minute})
+
+		require.Len(t, notifications, 2)
+		require.Equal(t, from.Add(30*time.Second), notifications[0].Time)
+		require.Equal(t, "default", notifications[0].Receiver)
+		require.Equal(t, model.LabelSet{"alertname": "a"}, notifications[0].GroupLabels)
+		require.Len(t, notifications[0].Alerts, 1)
+		require.Equal(t, "firing", notifications[0].Alerts[0].Status)
+		require.Nil(t, notifications[0].Alerts[0].EndsAt)
+
+		require.Equal(t, from.Add(10*time.Minute+30*time.Second), notifications[1].Time)
+		require.Equal(t, "resolved", notifications[1].Alerts[0].Status)
+		require.Equal(t, from.Add(10*time.Minute), *notifications[1].Alerts[0].EndsAt)
+	})
+
+	t.Run("should group alerts and notify about new alerts at the group interval", func(t *testing.T) {
+		notifications := simulate(t, 20*time.Minute,
+			firing{labels: model.LabelSet{"alertname": "a", "instance": "1"}, from: 0, to: time.Hour},
+			firing{labels: model.LabelSet{"alertname": "a", "instance": "2"}, from: 0, to: time.Hour},
+			firing{labels: model.LabelSet{"alertname": "a", "instance": "3"}, from: 2 * time.Minute, to: time.Hour},
+		)
+
+		require.Len(t, notifications, 2)
+		require.Equal(t, from.Add(30*time.Second), notifications[0].Time)
+		require.Len(t, notifications[0].Alerts, 2)
+		require.Equal(t, from.Add(5*time.Minute+30*time.Second), notifications[1].Time)
+		require.Len(t, notifications[1].Alerts, 3)
+	})
+
+	t.Run("should repeat notifications after the repeat interval", func(t *testing.T) {
+		notifications := simulate(t, 2*time.Hour, firing{labels: model.LabelSet{"alertname": "a"}, from: 0, to: 3 * time.Hour})
+
+		require.Len(t, notifications, 2)
+		require.Equal(t, from.Add(30*time.Second), notifications[0].Time)
+		require.Equal(t, from.Add(time.Hour+5*time.Minute+30*time.Second), notifications[1].Time)
+	})
+
+	t.Run("should wait for group wait again once all alerts of a group resolved", func(t *testing.T) {
+		notifications := simulate(t, 30*time.Minute,
+			firing{labels: model.LabelSet{"alertname": "a", "instance": "1"}, from: 0, to: 10 * time.Minute},
+			firing{labels: model.LabelSet{"alertname": "a", "instance": "2"}, from: 12 * time.Minute, to: 17 * time.Minute},
+		)
+
+		require.Len(t, notifications, 4)
+		require.Equal(t, from.Add(10*time.Minute+30*time.Second), notifications[1].Time)
+		require.Equal(t, from.Add(12*time.Minute+30*time.Second), notifications[2].Time)
+		require.Equal(t, model.LabelSet{"alertname": "a", "instance": "2"}, notifications[2].Alerts[0].Labels)
+		require.Equal(t, from.Add(17*time.Minute+30*time.Second), notifications[3].Time)
+		require.Equal(t, "resolved", notifications[3].Alerts[0].Status)
+	})
+
+	t.Run("should not notify muted groups", func(t *testing.T) {
+		notifications := simulate(t, 30*time.Minute, firing{labels: model.LabelSet{"alertname": "a", "team": "muted"}, from: 0, to: 10 * time.Minute})
+
+		require.Empty(t, notifications)
+	})
+
+	t.Run("should fail without routing tree", func(t *testing.T) {
+		_, err := newNotificationSimulator(definitions.Config{})
+		require.ErrorIs(t, err, errNoRoutingTree)
+	})
+}
@@ -265,14 +265,20 @@ const (
 	// Error is the eval state for an alert rule condition
 	// that evaluated to Error.
 	Error
+
+	// Suppressed is the state of an alert instance that would be
+	// Alerting but an upstream rule of the AlertRule is Alerting or Suppressed.
+	// The alert is resolved when the instance is suppressed, and error and no data
+	// results keep the instance suppressed. Evaluations never result in this state.
+	Suppressed
 )
 
 func (s State) IsValid() bool {
-	return s <= Error
+	return s <= Suppressed
 }
 
 func (s State) String() string {
-	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error"}[s]
+	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Suppressed"}[s]
 }
 
 func buildDatasourceHeaders(ctx context.Context) map[string]string {
@@ -23,11 +23,23 @@ func NewAlertmanagerMetrics(r prometheus.Registerer) *Alertmanager {
 	}
 }
 
+// RegisterAlertsInhibited registers a gauge with the number of alerts that are inhibited, as returned by the given function.
+func (m *Alertmanager) RegisterAlertsInhibited(f func() float64) {
+	if m.Registerer == nil {
+		return
+	}
+	m.Registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "alertmanager_alerts_inhibited",
+		Help: "The number of alerts that are inhibited",
+	}, f))
+}
+
 type AlertmanagerConfigMetrics struct {
 	Matchers       prometheus.Gauge
 	MatchRE        prometheus.Gauge
 	Match          prometheus.Gauge
 	ObjectMatchers prometheus.Gauge
+	InhibitRules   prometheus.Gauge
 }
 
 func NewAlertmanagerConfigMetrics(r prometheus.Registerer) *AlertmanagerConfigMetrics {
@@ -48,9 +60,13 @@ func NewAlertmanagerConfigMetrics(r prometheus.Registerer) *AlertmanagerConfigMe
 			Name: "alertmanager_config_object_matchers",
 			Help: "The total number of object_matchers",
 		}),
+		InhibitRules: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alertmanager_config_inhibit_rules",
+			Help: "The total number of inhibit_rules",
+		}),
 	}
 	if r != nil {
-		r.MustRegister(m.Matchers, m.MatchRE, m.Match, m.ObjectMatchers)
+		r.MustRegister(m.Matchers, m.MatchRE, m.Match, m.ObjectMatchers, m.InhibitRules)
 	}
 	return m
 }
@@ -117,6 +117,10 @@ type AlertmanagerAggregatedMetrics struct {
 	matchRE        *prometheus.Desc
 	match          *prometheus.Desc
 	objectMatchers *prometheus.Desc
+
+	// added to measure usage of inhibition rules
+	inhibitRules    *prometheus.Desc
+	alertsInhibited *prometheus.Desc
 }
 
 func NewAlertmanagerAggregatedMetrics(registries *metrics.TenantRegistries) *AlertmanagerAggregatedMetrics {
@@ -253,6 +257,15 @@ func NewAlertmanagerAggregatedMetrics(registries *metrics.TenantRegistries) *Ale
 			fmt.Sprintf("%s_%s_alertmanager_config_object_matchers", Namespace, Subsystem),
 			"The total number of object_matchers",
 			nil, nil),
+
+		inhibitRules: prometheus.NewDesc(
+			fmt.Sprintf("%s_%s_alertmanager_config_inhibit_rules", Namespace, Subsystem),
+			"The total number of inhibit_rules",
+			nil, nil),
+		alertsInhibited: prometheus.NewDesc(
+			fmt.Sprintf("%s_%s_alertmanager_alerts_inhibited", Namespace, Subsystem),
+			"How many alerts are inhibited in Grafana's Alertmanager.",
+			[]string{"org"}, nil),
 	}
 
 	return aggregatedMetrics
@@ -296,6 +309,9 @@ func (a *AlertmanagerAggregatedMetrics) Describe(out chan<- *prometheus.Desc) {
 	out <- a.matchRE
 	out <- a.match
 	out <- a.objectMatchers
+
+	out <- a.inhibitRules
+	out <- a.alertsInhibited
 }
 
 func (a *AlertmanagerAggregatedMetrics) Collect(out chan<- prometheus.Metric) {
@@ -338,4 +354,7 @@ func (a *AlertmanagerAggregatedMetrics) Collect(out chan<- prometheus.Metric) {
 	data.SendSumOfGauges(out, a.matchRE, "alertmanager_config_match_re")
 	data.SendSumOfGauges(out, a.match, "alertmanager_config_match")
 	data.SendSumOfGauges(out, a.objectMatchers, "alertmanager_config_object_matchers")
+
+	data.SendSumOfGauges(out, a.inhibitRules, "alertmanager_config_inhibit_rules")
+	data.SendSumOfGaugesPerTenant(out, a.alertsInhibited, "alertmanager_alerts_inhibited")
 }
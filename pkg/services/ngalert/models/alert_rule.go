@@ -21,6 +21,8 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -217,6 +219,10 @@ type AlertRule struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	IsPaused    bool
+	// UpdatedBy identifies who made the last change to the rule, it is empty when unknown
+	UpdatedBy string `xorm:"updated_by"`
+	// Dependencies are the upstream rules that suppress this rule while they are firing
+	Dependencies []RuleDependency `xorm:"dependencies"`
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -228,6 +234,8 @@ type AlertRuleWithOptionals struct {
 	// This parameter is to know if an optional API field was sent and, therefore, patch it with the current field from
 	// DB in case it was not sent.
 	HasPause bool
+	// HasDependencies is to know if the dependencies were sent, the current ones are kept otherwise.
+	HasDependencies bool
 }
 
 // AlertsRulesBy is a function that defines the ordering of alert rules.
@@ -468,6 +476,15 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
 	}
+
+	for _, d := range alertRule.Dependencies {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, err.Error())
+		}
+		if d.RuleUID == alertRule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+	}
 	return nil
 }
 
@@ -504,10 +521,54 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For          time.Duration
+	Annotations  map[string]string
+	Labels       map[string]string
+	IsPaused     bool
+	CreatedBy    string           `xorm:"created_by"`
+	Dependencies []RuleDependency `xorm:"dependencies"`
+}
+
+// ToAlertRule returns the alert rule as it was at this version.
+func (v AlertRuleVersion) ToAlertRule() AlertRule {
+	rule := AlertRule{
+		OrgID:           v.RuleOrgID,
+		Title:           v.Title,
+		Condition:       v.Condition,
+		Data:            v.Data,
+		Updated:         v.Created,
+		IntervalSeconds: v.IntervalSeconds,
+		Version:         v.Version,
+		UID:             v.RuleUID,
+		NamespaceUID:    v.RuleNamespaceUID,
+		RuleGroup:       v.RuleGroup,
+		RuleGroupIndex:  v.RuleGroupIndex,
+		NoDataState:     v.NoDataState,
+		ExecErrState:    v.ExecErrState,
+		For:             v.For,
+		Annotations:     v.Annotations,
+		Labels:          v.Labels,
+		IsPaused:        v.IsPaused,
+		UpdatedBy:       v.CreatedBy,
+		Dependencies:    v.Dependencies,
+	}
+	// versions do not store the dashboard and panel, they are restored from the annotations,
+	// which were validated when the version was saved.
+	_ = rule.SetDashboardAndPanelFromAnnotations()
+	return rule
+}
+
+// GetAlertRuleVersionsQuery is the query for listing the versions of an alert rule.
+type GetAlertRuleVersionsQuery struct {
+	OrgID   int64
+	RuleUID string
+}
+
+// GetAlertRuleVersionQuery is the query for retrieving a specific version of an alert rule.
+type GetAlertRuleVersionQuery struct {
+	OrgID   int64
+	RuleUID string
+	Version int64
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -626,6 +687,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
+	if !ruleToPatch.HasDependencies {
+		ruleToPatch.Dependencies = existingRule.Dependencies
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
@@ -36,6 +36,8 @@ const (
 	InstanceStateNoData InstanceStateType = "NoData"
 	// InstanceStateError is for an erroring alert.
 	InstanceStateError InstanceStateType = "Error"
+	// InstanceStateSuppressed is for an alert that is firing while an upstream rule is firing.
+	InstanceStateSuppressed InstanceStateType = "Suppressed"
 )
 
 // IsValid checks that the value of InstanceStateType is a valid
@@ -45,7 +47,8 @@ func (i InstanceStateType) IsValid() bool {
 		i == InstanceStateNormal ||
 		i == InstanceStateNoData ||
 		i == InstanceStatePending ||
-		i == InstanceStateError
+		i == InstanceStateError ||
+		i == InstanceStateSuppressed
 }
 
 // ListAlertInstancesQuery is the query list alert Instances.
@@ -8,6 +8,8 @@ const (
 	ProvenanceNone Provenance = ""
 	ProvenanceAPI  Provenance = "api"
 	ProvenanceFile Provenance = "file"
+	// ProvenanceConvertedPrometheus is the provenance of alert rules imported from Prometheus rule files.
+	ProvenanceConvertedPrometheus Provenance = "converted_prometheus"
 )
 
 // Provisionable represents a resource that can be created through a provisioning mechanism, such as Terraform or config file.
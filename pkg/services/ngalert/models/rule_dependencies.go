@@ -0,0 +1,130 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+var (
+	// ErrAlertRuleDependencyCycle is an error for alert rules that depend on each other.
+	ErrAlertRuleDependencyCycle = errors.New("alert rule dependencies form a cycle")
+	// ErrAlertRuleUnknownDependency is an error for alert rules that depend on a rule that does not exist in their organization.
+	ErrAlertRuleUnknownDependency = errors.New("alert rule depends on an unknown rule")
+)
+
+// RuleDependency is an upstream alert rule of an alert rule. While any instance of the upstream rule that matches
+// all matchers is Alerting or Suppressed, the instances of the dependent rule that would be Alerting are Suppressed instead.
+type RuleDependency struct {
+	// RuleUID is the UID of the upstream alert rule in the same organization.
+	RuleUID string `json:"ruleUid"`
+	// Matchers restrict the upstream instances that suppress the dependent rule, for example `region="eu"` or `cluster=~"prod-.*"`.
+	Matchers []string `json:"matchers,omitempty"`
+}
+
+// Validate checks that the dependency has a rule UID and valid matchers.
+func (d RuleDependency) Validate() error {
+	if d.RuleUID == "" {
+		return errors.New("rule UID of dependency must not be empty")
+	}
+	if _, err := d.ParseMatchers(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseMatchers returns the parsed matchers of the dependency.
+func (d RuleDependency) ParseMatchers() (labels.Matchers, error) {
+	matchers := make(labels.Matchers, 0, len(d.Matchers))
+	for _, s := range d.Matchers {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q of dependency on rule %s: %w", s, d.RuleUID, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// ValidateDependenciesExist checks that the upstream rules of the saved alert rules are in rules, the alert rules of
+// their organization.
+func ValidateDependenciesExist(rules []*AlertRule, saved []AlertRule) error {
+	uids := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		uids[rule.UID] = struct{}{}
+	}
+	for _, rule := range saved {
+		for _, d := range rule.Dependencies {
+			if _, ok := uids[d.RuleUID]; !ok {
+				return fmt.Errorf("%w: %w: rule %s depends on rule %s", ErrAlertRuleFailedValidation, ErrAlertRuleUnknownDependency, rule.UID, d.RuleUID)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateRuleDependencies checks that the dependencies of the alert rules of an organization do not form a cycle.
+// Dependencies on rules that are not in the list, such as deleted rules, are ignored.
+func ValidateRuleDependencies(rules []*AlertRule) error {
+	upstreams := make(map[string][]string, len(rules))
+	for _, rule := range rules {
+		uids := make([]string, 0, len(rule.Dependencies))
+		for _, d := range rule.Dependencies {
+			uids = append(uids, d.RuleUID)
+		}
+		upstreams[rule.UID] = uids
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int, len(upstreams))
+	var path []string
+	var visit func(uid string) error
+	visit = func(uid string) error {
+		switch marks[uid] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == uid {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), uid)
+			return fmt.Errorf("%w: %w: %s", ErrAlertRuleFailedValidation, ErrAlertRuleDependencyCycle, strings.Join(cycle, " -> "))
+		}
+		marks[uid] = visiting
+		path = append(path, uid)
+		for _, upstream := range upstreams[uid] {
+			if _, ok := upstreams[upstream]; !ok {
+				continue
+			}
+			if err := visit(upstream); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[uid] = visited
+		return nil
+	}
+
+	// visit the rules in a stable order so that the same cycle is reported every time
+	uids := make([]string, 0, len(upstreams))
+	for uid := range upstreams {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	for _, uid := range uids {
+		if err := visit(uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleDependencyValidate(t *testing.T) {
+	require.NoError(t, RuleDependency{RuleUID: "upstream"}.Validate())
+	require.NoError(t, RuleDependency{RuleUID: "upstream", Matchers: []string{`region="eu"`, `cluster=~"prod-.*"`}}.Validate())
+	require.Error(t, RuleDependency{}.Validate())
+	require.Error(t, RuleDependency{RuleUID: "upstream", Matchers: []string{`region`}}.Validate())
+}
+
+func TestValidateRuleDependencies(t *testing.T) {
+	rule := func(uid string, upstreams ...string) *AlertRule {
+		r := &AlertRule{UID: uid}
+		for _, u := range upstreams {
+			r.Dependencies = append(r.Dependencies, RuleDependency{RuleUID: u})
+		}
+		return r
+	}
+
+	testCases := []struct {
+		name  string
+		rules []*AlertRule
+		cycle string
+	}{
+		{
+			name:  "rules without dependencies",
+			rules: []*AlertRule{rule("a"), rule("b")},
+		},
+		{
+			name:  "chain of dependencies",
+			rules: []*AlertRule{rule("a", "b"), rule("b", "c"), rule("c")},
+		},
+		{
+			name:  "shared upstream",
+			rules: []*AlertRule{rule("a", "c"), rule("b", "c"), rule("c"), rule("d", "a", "b")},
+		},
+		{
+			name:  "unknown upstream is ignored",
+			rules: []*AlertRule{rule("a", "unknown")},
+		},
+		{
+			name:  "self dependency",
+			rules: []*AlertRule{rule("a", "a")},
+			cycle: "a -> a",
+		},
+		{
+			name:  "cycle",
+			rules: []*AlertRule{rule("a", "b"), rule("b", "c"), rule("c", "b")},
+			cycle: "b -> c -> b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRuleDependencies(tc.rules)
+			if tc.cycle == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleDependencyCycle)
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.cycle)
+		})
+	}
+}
+
+func TestValidateDependenciesExist(t *testing.T) {
+	rules := []*AlertRule{{UID: "a"}, {UID: "b"}}
+	require.NoError(t, ValidateDependenciesExist(rules, []AlertRule{{UID: "a", Dependencies: []RuleDependency{{RuleUID: "b"}}}}))
+
+	err := ValidateDependenciesExist(rules, []AlertRule{{UID: "a", Dependencies: []RuleDependency{{RuleUID: "b"}, {RuleUID: "c"}}}})
+	require.ErrorIs(t, err, ErrAlertRuleUnknownDependency)
+	require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+	require.ErrorContains(t, err, "rule a depends on rule c")
+}
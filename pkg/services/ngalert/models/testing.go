@@ -404,6 +404,13 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	for _, d := range r.Dependencies {
+		result.Dependencies = append(result.Dependencies, RuleDependency{
+			RuleUID:  d.RuleUID,
+			Matchers: append([]string(nil), d.Matchers...),
+		})
+	}
+
 	return &result
 }
 
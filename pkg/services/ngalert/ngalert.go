@@ -2,6 +2,7 @@ package ngalert
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"time"
@@ -171,7 +172,7 @@ func (ng *AlertNG) init() error {
 	// If enabled, configure the remote Alertmanager.
 	// - If several toggles are enabled, the order of precedence is RemoteOnly, RemotePrimary, RemoteSecondary
 	// - If no toggles are enabled, we default to using only the internal Alertmanager
-	// We currently support only remote secondary mode, so in case other toggles are enabled we fall back to remote secondary.
+	// We currently support remote only and remote secondary mode, so in case remote primary is enabled we fall back to remote secondary.
 	var overrides []notifier.Option
 	moaLogger := log.New("ngalert.multiorg.alertmanager")
 	remoteOnly := ng.FeatureToggles.IsEnabled(initCtx, featuremgmt.FlagAlertmanagerRemoteOnly)
@@ -179,8 +180,24 @@ func (ng *AlertNG) init() error {
 	remoteSecondary := ng.FeatureToggles.IsEnabled(initCtx, featuremgmt.FlagAlertmanagerRemoteSecondary)
 	if ng.Cfg.UnifiedAlerting.RemoteAlertmanager.Enable {
 		switch {
-		case remoteOnly, remotePrimary:
-			ng.Log.Warn("Only remote secondary mode is supported at the moment, falling back to remote secondary")
+		case remoteOnly:
+			ng.Log.Debug("Starting Grafana with remote only mode enabled")
+			// This function will be used by the MOA to create new Alertmanagers.
+			// The internal Alertmanager is never created, all notifications are sent by the remote Alertmanager.
+			override := notifier.WithAlertmanagerOverride(func(_ notifier.OrgAlertmanagerFactory) notifier.OrgAlertmanagerFactory {
+				return func(ctx context.Context, orgID int64) (notifier.Alertmanager, error) {
+					remoteAM, err := ng.createRemoteAlertmanager(orgID, true)
+					if err != nil {
+						return nil, fmt.Errorf("failed to create remote Alertmanager: %w", err)
+					}
+					return remoteAM, nil
+				}
+			})
+
+			overrides = append(overrides, override)
+
+		case remotePrimary:
+			ng.Log.Warn("Remote primary mode is not supported at the moment, falling back to remote secondary")
 			fallthrough
 
 		case remoteSecondary:
@@ -195,7 +212,7 @@ func (ng *AlertNG) init() error {
 					}
 
 					// Create remote Alertmanager.
-					remoteAM, err := createRemoteAlertmanager(orgID, ng.Cfg.UnifiedAlerting.RemoteAlertmanager, ng.KVStore)
+					remoteAM, err := ng.createRemoteAlertmanager(orgID, false)
 					if err != nil {
 						moaLogger.Error("Failed to create remote Alertmanager, falling back to using only the internal one", "err", err)
 						return internalAM, nil
@@ -279,10 +296,14 @@ func (ng *AlertNG) init() error {
 	if err != nil {
 		return err
 	}
+	instanceStore, err := configureInstanceStore(initCtx, ng.Cfg.UnifiedAlerting.StateStore, ng.store, ng.Log)
+	if err != nil {
+		return err
+	}
 	cfg := state.ManagerCfg{
 		Metrics:                        ng.Metrics.GetStateMetrics(),
 		ExternalURL:                    appUrl,
-		InstanceStore:                  ng.store,
+		InstanceStore:                  instanceStore,
 		Images:                         ng.ImageService,
 		Clock:                          clk,
 		Historian:                      history,
@@ -308,6 +329,7 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, ng.Log, ng.accesscontrol)
 	templateService := provisioning.NewTemplateService(ng.store, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(ng.store, ng.store, ng.store, ng.Log)
+	inhibitRuleService := provisioning.NewInhibitRuleService(ng.store, ng.store, ng.store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
@@ -331,6 +353,7 @@ func (ng *AlertNG) init() error {
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
+		InhibitRules:         inhibitRuleService,
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
@@ -484,6 +507,37 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
+// configureInstanceStore returns the store that persists the state of alert rules. If the state is not stored in the
+// alert_instance table, the instances in that table are moved to the configured store.
+func configureInstanceStore(ctx context.Context, cfg setting.UnifiedAlertingStateStoreSettings, db *store.DBstore, l log.Logger) (state.InstanceStore, error) {
+	var instanceStore state.RuleInstanceStore
+	switch cfg.Backend {
+	case "", "database":
+		return db, nil
+	case "snapshot":
+		instanceStore = store.NewSnapshotInstanceStore(db.SQLStore, l.New("store", "snapshot"))
+	case "redis":
+		if cfg.RedisAddr == "" {
+			return nil, errors.New("the redis state store requires redis_address")
+		}
+		instanceStore = store.NewRedisInstanceStore(store.RedisInstanceStoreConfig{
+			Addr:     cfg.RedisAddr,
+			Username: cfg.RedisUsername,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+			Prefix:   cfg.RedisPrefix,
+		}, l.New("store", "redis"))
+	default:
+		return nil, fmt.Errorf("unrecognized state store backend: %s", cfg.Backend)
+	}
+
+	l.Info("Using state store", "backend", cfg.Backend)
+	if err := state.MigrateInstances(ctx, db, instanceStore, l); err != nil {
+		l.Error("Failed to migrate alert instances to the state store", "backend", cfg.Backend, "error", err)
+	}
+	return instanceStore, nil
+}
+
 // ApplyStateHistoryFeatureToggles edits state history configuration to comply with currently active feature toggles.
 func ApplyStateHistoryFeatureToggles(cfg *setting.UnifiedAlertingStateHistorySettings, ft featuremgmt.FeatureToggles, logger log.Logger) {
 	backend, _ := historian.ParseBackendType(cfg.Backend)
@@ -529,14 +583,17 @@ func ApplyStateHistoryFeatureToggles(cfg *setting.UnifiedAlertingStateHistorySet
 	}
 }
 
-func createRemoteAlertmanager(orgID int64, amCfg setting.RemoteAlertmanagerSettings, kvstore kvstore.KVStore) (*remote.Alertmanager, error) {
+func (ng *AlertNG) createRemoteAlertmanager(orgID int64, remoteOnly bool) (*remote.Alertmanager, error) {
+	amCfg := ng.Cfg.UnifiedAlerting.RemoteAlertmanager
 	externalAMCfg := remote.AlertmanagerConfig{
 		OrgID:             orgID,
 		URL:               amCfg.URL,
 		TenantID:          amCfg.TenantID,
 		BasicAuthPassword: amCfg.Password,
+		DefaultConfig:     ng.Cfg.UnifiedAlerting.DefaultConfiguration,
+		RemoteOnly:        remoteOnly,
 	}
 	// We won't be handling files on disk, we can pass an empty string as workingDirPath.
-	stateStore := notifier.NewFileStore(orgID, kvstore, "")
-	return remote.NewAlertmanager(externalAMCfg, stateStore)
+	stateStore := notifier.NewFileStore(orgID, ng.KVStore, "")
+	return remote.NewAlertmanager(externalAMCfg, stateStore, ng.store, ng.SecretsService.Decrypt)
 }
@@ -144,10 +144,30 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		fileStore:           fileStore,
 		logger:              l,
 	}
+	m.RegisterAlertsInhibited(am.countInhibitedAlerts)
 
 	return am, nil
 }
 
+// countInhibitedAlerts returns the number of alerts that are currently inhibited by an inhibition rule.
+func (am *alertmanager) countInhibitedAlerts() float64 {
+	if !am.Ready() {
+		return 0
+	}
+	alerts, err := am.Base.GetAlerts(false, true, true, nil, "")
+	if err != nil {
+		am.logger.Warn("Failed to get alerts to count inhibited alerts", "error", err)
+		return 0
+	}
+	count := 0
+	for _, alert := range alerts {
+		if alert.Status != nil && len(alert.Status.InhibitedBy) > 0 {
+			count++
+		}
+	}
+	return float64(count)
+}
+
 func (am *alertmanager) Ready() bool {
 	// We consider AM as ready only when the config has been
 	// applied at least once successfully. Until then, some objects
@@ -255,6 +275,7 @@ func (am *alertmanager) updateConfigMetrics(cfg *apimodels.PostableUserConfig) {
 	am.ConfigMetrics.MatchRE.Set(float64(amu.MatchRE))
 	am.ConfigMetrics.Match.Set(float64(amu.Match))
 	am.ConfigMetrics.ObjectMatchers.Set(float64(amu.ObjectMatchers))
+	am.ConfigMetrics.InhibitRules.Set(float64(len(cfg.AlertmanagerConfig.InhibitRules)))
 }
 
 func (am *alertmanager) aggregateRouteMatchers(r *apimodels.Route, amu *AggregateMatchersUsage) {
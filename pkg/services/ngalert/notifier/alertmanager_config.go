@@ -268,5 +268,11 @@ func (moa *MultiOrgAlertmanager) mergeProvenance(ctx context.Context, config def
 		config.AlertmanagerConfig.MuteTimeProvenances[key] = definitions.Provenance(provenance)
 	}
 
+	provenance, err := moa.ProvStore.GetProvenance(ctx, &definitions.InhibitRules{}, org)
+	if err != nil {
+		return definitions.GettableUserConfig{}, err
+	}
+	config.AlertmanagerConfig.InhibitRulesProvenance = definitions.Provenance(provenance)
+
 	return config, nil
 }
@@ -76,6 +76,31 @@ func EncryptReceiverConfigs(c []*definitions.PostableApiReceiver, encrypt defini
 	return nil
 }
 
+// DecryptReceiverConfigs decrypts all SecureSettings in the given receivers.
+// It is the inverse of EncryptReceiverConfigs: the secure settings hold the plain text secrets afterwards.
+func DecryptReceiverConfigs(c []*definitions.PostableApiReceiver, decrypt definitions.DecryptFn) error {
+	for _, r := range c {
+		switch r.Type() {
+		case definitions.GrafanaReceiverType:
+			for _, gr := range r.PostableGrafanaReceivers.GrafanaManagedReceivers {
+				for k, v := range gr.SecureSettings {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						return fmt.Errorf("failed to decode secure setting %q of integration %q: %w", k, gr.UID, err)
+					}
+					decrypted, err := decrypt(context.Background(), decoded)
+					if err != nil {
+						return fmt.Errorf("failed to decrypt secure setting %q of integration %q: %w", k, gr.UID, err)
+					}
+					gr.SecureSettings[k] = string(decrypted)
+				}
+			}
+		default:
+		}
+	}
+	return nil
+}
+
 // LoadSecureSettings adds the corresponding unencrypted secrets stored to the list of input receivers.
 func (c *alertmanagerCrypto) LoadSecureSettings(ctx context.Context, orgId int64, receivers []*definitions.PostableApiReceiver) error {
 	// Get the last known working configuration.
@@ -28,6 +28,7 @@ import (
 var (
 	ErrNoAlertmanagerForOrg = fmt.Errorf("Alertmanager does not exist for this organization")
 	ErrAlertmanagerNotReady = fmt.Errorf("Alertmanager is not ready yet")
+	ErrNotSupported         = fmt.Errorf("not supported by this Alertmanager")
 )
 
 //go:generate mockery --name Alertmanager --structname AlertmanagerMock --with-expecter --output alertmanager_mock --outpkg alertmanager_mock
@@ -79,6 +80,8 @@ type MultiOrgAlertmanager struct {
 	kvStore     kvstore.KVStore
 	factory     OrgAlertmanagerFactory
 
+	silenceDefinitions *SilenceDefinitionStore
+
 	decryptFn alertingNotify.GetDecryptedValueFn
 
 	metrics *metrics.MultiOrgAlertmanager
@@ -113,6 +116,8 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 		metrics:       m,
 		ns:            ns,
 		peer:          &NilPeer{},
+
+		silenceDefinitions: NewSilenceDefinitionStore(kvStore),
 	}
 
 	if err := moa.setupClustering(cfg); err != nil {
@@ -224,6 +229,9 @@ func (moa *MultiOrgAlertmanager) LoadAndSyncAlertmanagersForOrgs(ctx context.Con
 	moa.metrics.DiscoveredConfigurations.Set(float64(len(orgIDs)))
 	moa.SyncAlertmanagersForOrgs(ctx, orgIDs)
 
+	// Finally, materialize the provisioned silence definitions of each organization.
+	moa.syncSilenceDefinitions(ctx)
+
 	moa.logger.Debug("Done synchronizing Alertmanagers for orgs")
 
 	return nil
@@ -62,6 +62,16 @@ grafana_alerting_active_configurations 3
 # TYPE grafana_alerting_discovered_configurations gauge
 grafana_alerting_discovered_configurations 3
 `), "grafana_alerting_discovered_configurations", "grafana_alerting_active_configurations"))
+		require.NoError(t, testutil.GatherAndCompare(reg, bytes.NewBufferString(`
+# HELP grafana_alerting_alertmanager_alerts_inhibited How many alerts are inhibited in Grafana's Alertmanager.
+# TYPE grafana_alerting_alertmanager_alerts_inhibited gauge
+grafana_alerting_alertmanager_alerts_inhibited{org="1"} 0
+grafana_alerting_alertmanager_alerts_inhibited{org="2"} 0
+grafana_alerting_alertmanager_alerts_inhibited{org="3"} 0
+# HELP grafana_alerting_alertmanager_config_inhibit_rules The total number of inhibit_rules
+# TYPE grafana_alerting_alertmanager_config_inhibit_rules gauge
+grafana_alerting_alertmanager_config_inhibit_rules 0
+`), "grafana_alerting_alertmanager_alerts_inhibited", "grafana_alerting_alertmanager_config_inhibit_rules"))
 
 		// Configurations should be marked as successfully applied.
 		for _, org := range orgStore.orgs {
@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var (
+	ErrNoRoutingTree      = errors.New("the configuration has no routing tree")
+	ErrInvalidRoutingTree = errors.New("invalid routing tree")
+)
+
+// TestRoutes returns the routes of the routing tree of the configuration that alerts with the given labels match,
+// in the same way as the Alertmanager dispatches alerts. Mute time intervals are evaluated at the given time.
+func TestRoutes(cfg definitions.Config, alerts []model.LabelSet, now time.Time) (definitions.TestRoutesResults, error) {
+	if cfg.Route == nil {
+		return definitions.TestRoutesResults{}, ErrNoRoutingTree
+	}
+	muteTimes := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals))
+	muteTimeNames := make(map[string]struct{}, len(cfg.MuteTimeIntervals))
+	for _, ti := range cfg.MuteTimeIntervals {
+		muteTimes[ti.Name] = ti.TimeIntervals
+		muteTimeNames[ti.Name] = struct{}{}
+	}
+	if err := cfg.Route.ValidateMuteTimes(muteTimeNames); err != nil {
+		return definitions.TestRoutesResults{}, fmt.Errorf("%w: %s", ErrInvalidRoutingTree, err.Error())
+	}
+	intervener := timeinterval.NewIntervener(muteTimes)
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+
+	results := definitions.TestRoutesResults{Results: make([]definitions.TestRoutesResult, 0, len(alerts))}
+	for _, lset := range alerts {
+		matches := matchRoutes(root, nil, nil, lset)
+		result := definitions.TestRoutesResult{
+			Labels: lset,
+			Routes: make([]definitions.TestRoutesMatchedRoute, 0, len(matches)),
+		}
+		for _, m := range matches {
+			route, err := toMatchedRoute(m, intervener, now)
+			if err != nil {
+				return definitions.TestRoutesResults{}, err
+			}
+			result.Routes = append(result.Routes, route)
+		}
+		results.Results = append(results.Results, result)
+	}
+	return results, nil
+}
+
+type routeMatch struct {
+	route    *dispatch.Route
+	path     []int
+	matchers definitions.ObjectMatchers
+}
+
+// matchRoutes does the same depth-first left-to-right search as dispatch.Route.Match, and also returns the path of the matching routes.
+func matchRoutes(r *dispatch.Route, path []int, matchers definitions.ObjectMatchers, lset model.LabelSet) []routeMatch {
+	if !r.Matchers.Matches(lset) {
+		return nil
+	}
+	// copy the matchers, the slice is shared by the siblings of the route
+	matchers = append(append(make(definitions.ObjectMatchers, 0, len(matchers)+len(r.Matchers)), matchers...), r.Matchers...)
+
+	var all []routeMatch
+	for i, cr := range r.Routes {
+		childPath := append(append(make([]int, 0, len(path)+1), path...), i)
+		matches := matchRoutes(cr, childPath, matchers, lset)
+
+		all = append(all, matches...)
+
+		if matches != nil && !cr.Continue {
+			break
+		}
+	}
+
+	// If no child nodes were matches, the current node itself is a match.
+	if len(all) == 0 {
+		all = append(all, routeMatch{route: r, path: path, matchers: matchers})
+	}
+	return all
+}
+
+func toMatchedRoute(m routeMatch, intervener *timeinterval.Intervener, now time.Time) (definitions.TestRoutesMatchedRoute, error) {
+	opts := m.route.RouteOpts
+	result := definitions.TestRoutesMatchedRoute{
+		Path:                    m.path,
+		Matchers:                m.matchers,
+		Receiver:                opts.Receiver,
+		GroupBy:                 make([]string, 0, len(opts.GroupBy)),
+		GroupByAll:              opts.GroupByAll,
+		GroupWait:               model.Duration(opts.GroupWait),
+		GroupInterval:           model.Duration(opts.GroupInterval),
+		RepeatInterval:          model.Duration(opts.RepeatInterval),
+		MuteTimeIntervals:       make([]string, 0, len(opts.MuteTimeIntervals)),
+		ActiveMuteTimeIntervals: make([]string, 0),
+	}
+	if result.Path == nil {
+		result.Path = []int{}
+	}
+	for label := range opts.GroupBy {
+		result.GroupBy = append(result.GroupBy, string(label))
+	}
+	sort.Strings(result.GroupBy)
+
+	for _, name := range opts.MuteTimeIntervals {
+		result.MuteTimeIntervals = append(result.MuteTimeIntervals, name)
+		active, err := intervener.Mutes([]string{name}, now)
+		if err != nil {
+			return definitions.TestRoutesMatchedRoute{}, fmt.Errorf("failed to evaluate mute time interval %q: %w", name, err)
+		}
+		if active {
+			result.ActiveMuteTimeIntervals = append(result.ActiveMuteTimeIntervals, name)
+		}
+	}
+	result.Muted = len(result.ActiveMuteTimeIntervals) > 0
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package notifier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const routesTestConfig = `{
+	"route": {
+		"receiver": "default",
+		"group_by": ["alertname"],
+		"routes": [
+			{
+				"receiver": "team-a",
+				"object_matchers": [["team", "=", "a"]],
+				"group_wait": "1m",
+				"continue": true,
+				"routes": [
+					{
+						"receiver": "team-a-critical",
+						"object_matchers": [["severity", "=", "critical"]],
+						"group_by": ["alertname", "instance"],
+						"mute_time_intervals": ["always", "never"]
+					}
+				]
+			},
+			{
+				"object_matchers": [["team", "=~", "a|b"]],
+				"repeat_interval": "1h"
+			}
+		]
+	},
+	"mute_time_intervals": [
+		{"name": "always", "time_intervals": [{}]},
+		{"name": "never", "time_intervals": [{"years": ["1999"]}]}
+	],
+	"receivers": [
+		{"name": "default", "grafana_managed_receivers": [{"name": "default", "type": "email", "settings": {"addresses": "a@example.com"}}]},
+		{"name": "team-a", "grafana_managed_receivers": [{"name": "team-a", "type": "email", "settings": {"addresses": "a@example.com"}}]},
+		{"name": "team-a-critical", "grafana_managed_receivers": [{"name": "team-a-critical", "type": "email", "settings": {"addresses": "a@example.com"}}]}
+	]
+}`
+
+func TestTestRoutes(t *testing.T) {
+	cfg := definitions.PostableApiAlertingConfig{}
+	require.NoError(t, json.Unmarshal([]byte(routesTestConfig), &cfg))
+	now := time.Date(2023, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should match the default policy", func(t *testing.T) {
+		res, err := TestRoutes(cfg.Config, []model.LabelSet{{"team": "c"}}, now)
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Len(t, res.Results[0].Routes, 1)
+
+		route := res.Results[0].Routes[0]
+		assert.Empty(t, route.Path)
+		assert.Equal(t, "default", route.Receiver)
+		assert.Equal(t, []string{"alertname"}, route.GroupBy)
+		assert.Equal(t, model.Duration(30*time.Second), route.GroupWait)
+		assert.Equal(t, model.Duration(5*time.Minute), route.GroupInterval)
+		assert.Equal(t, model.Duration(4*time.Hour), route.RepeatInterval)
+		assert.False(t, route.Muted)
+	})
+
+	t.Run("should match nested policies and continue", func(t *testing.T) {
+		res, err := TestRoutes(cfg.Config, []model.LabelSet{{"team": "a", "severity": "critical"}}, now)
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Len(t, res.Results[0].Routes, 2)
+
+		critical := res.Results[0].Routes[0]
+		assert.Equal(t, []int{0, 0}, critical.Path)
+		assert.Len(t, critical.Matchers, 2)
+		assert.Equal(t, "team-a-critical", critical.Receiver)
+		assert.Equal(t, []string{"alertname", "instance"}, critical.GroupBy)
+		assert.Equal(t, model.Duration(time.Minute), critical.GroupWait, "group_wait should be inherited from the parent")
+		assert.Equal(t, []string{"always", "never"}, critical.MuteTimeIntervals)
+		assert.Equal(t, []string{"always"}, critical.ActiveMuteTimeIntervals)
+		assert.True(t, critical.Muted)
+
+		second := res.Results[0].Routes[1]
+		assert.Equal(t, []int{1}, second.Path)
+		assert.Equal(t, "default", second.Receiver, "receiver should be inherited from the parent")
+		assert.Equal(t, model.Duration(time.Hour), second.RepeatInterval)
+		assert.False(t, second.Muted)
+	})
+
+	t.Run("should return a result for each alert", func(t *testing.T) {
+		res, err := TestRoutes(cfg.Config, []model.LabelSet{{"team": "a"}, {"team": "b"}}, now)
+		require.NoError(t, err)
+		require.Len(t, res.Results, 2)
+		assert.Equal(t, model.LabelSet{"team": "a"}, res.Results[0].Labels)
+		assert.Equal(t, "team-a", res.Results[0].Routes[0].Receiver)
+		require.Len(t, res.Results[1].Routes, 1)
+		assert.Equal(t, []int{1}, res.Results[1].Routes[0].Path)
+	})
+
+	t.Run("should fail without routing tree", func(t *testing.T) {
+		_, err := TestRoutes(definitions.Config{}, []model.LabelSet{{"team": "a"}}, now)
+		require.ErrorIs(t, err, ErrNoRoutingTree)
+	})
+
+	t.Run("should fail if a policy uses an unknown mute time interval", func(t *testing.T) {
+		invalid := definitions.Config{Route: &definitions.Route{Receiver: "default", MuteTimeIntervals: []string{"unknown"}}}
+		_, err := TestRoutes(invalid, []model.LabelSet{{"team": "a"}}, now)
+		require.ErrorIs(t, err, ErrInvalidRoutingTree)
+		require.ErrorContains(t, err, "unknown")
+	})
+}
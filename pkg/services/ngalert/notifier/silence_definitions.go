@@ -0,0 +1,311 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	silenceDefinitionsKey = "silence_definitions"
+
+	// ProvisionedSilenceCreatedByPrefix is the prefix of the author of silences materialized from a silence definition.
+	// The rest of the author is the UID of the silence definition.
+	ProvisionedSilenceCreatedByPrefix = "provisioning/"
+
+	// silenceMaterializationHorizon is how far ahead of time silence definitions are materialized into silences.
+	silenceMaterializationHorizon = 7 * 24 * time.Hour
+
+	// maxSilenceWindows is the maximum number of silences a single silence definition is materialized into.
+	maxSilenceWindows = 100
+)
+
+// SilenceDefinitionStore persists the silence definitions of each organization in the kvstore.
+type SilenceDefinitionStore struct {
+	kv kvstore.KVStore
+}
+
+func NewSilenceDefinitionStore(kv kvstore.KVStore) *SilenceDefinitionStore {
+	return &SilenceDefinitionStore{kv: kv}
+}
+
+// GetSilenceDefinitions returns the silence definitions of an organization, sorted by UID.
+func (s *SilenceDefinitionStore) GetSilenceDefinitions(ctx context.Context, orgID int64) ([]apimodels.SilenceDefinition, error) {
+	defs, _, err := s.getSilenceDefinitions(ctx, orgID)
+	return defs, err
+}
+
+// SaveSilenceDefinitions replaces the silence definitions of an organization.
+func (s *SilenceDefinitionStore) SaveSilenceDefinitions(ctx context.Context, orgID int64, defs []apimodels.SilenceDefinition) error {
+	stored := make([]apimodels.SilenceDefinition, 0, len(defs))
+	for _, def := range defs {
+		// Provenance is kept in the provisioning store.
+		def.Provenance = ""
+		stored = append(stored, def)
+	}
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].UID < stored[j].UID
+	})
+	b, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal silence definitions: %w", err)
+	}
+	return s.kv.Set(ctx, orgID, KVNamespace, silenceDefinitionsKey, string(b))
+}
+
+// getSilenceDefinitions returns the silence definitions of an organization, and whether they were ever saved.
+func (s *SilenceDefinitionStore) getSilenceDefinitions(ctx context.Context, orgID int64) ([]apimodels.SilenceDefinition, bool, error) {
+	value, exists, err := s.kv.Get(ctx, orgID, KVNamespace, silenceDefinitionsKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read silence definitions: %w", err)
+	}
+	defs := []apimodels.SilenceDefinition{}
+	if !exists {
+		return defs, false, nil
+	}
+	if err := json.Unmarshal([]byte(value), &defs); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal silence definitions: %w", err)
+	}
+	return defs, true, nil
+}
+
+// ProvisionedSilenceUID returns the UID of the silence definition a silence was materialized from,
+// and false if the silence was not materialized from a silence definition.
+func ProvisionedSilenceUID(createdBy string) (string, bool) {
+	if !strings.HasPrefix(createdBy, ProvisionedSilenceCreatedByPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(createdBy, ProvisionedSilenceCreatedByPrefix), true
+}
+
+// syncSilenceDefinitions materializes the silence definitions of every running Alertmanager into silences.
+func (moa *MultiOrgAlertmanager) syncSilenceDefinitions(ctx context.Context) {
+	moa.alertmanagersMtx.RLock()
+	ams := make(map[int64]Alertmanager, len(moa.alertmanagers))
+	for orgID, am := range moa.alertmanagers {
+		ams[orgID] = am
+	}
+	moa.alertmanagersMtx.RUnlock()
+
+	now := time.Now()
+	for orgID, am := range ams {
+		if err := moa.materializeSilences(ctx, orgID, am, now); err != nil {
+			moa.logger.Error("Failed to materialize silence definitions", "org", orgID, "error", err)
+		}
+	}
+}
+
+// materializeSilences makes sure that the Alertmanager has exactly one silence for each window in which a silence
+// definition of the organization is active between now and the materialization horizon. Silences of definitions
+// that changed or no longer exist are expired.
+func (moa *MultiOrgAlertmanager) materializeSilences(ctx context.Context, orgID int64, am Alertmanager, now time.Time) error {
+	defs, exists, err := moa.silenceDefinitions.getSilenceDefinitions(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	// Organizations that never had silence definitions cannot have silences to materialize or expire.
+	if !exists || !am.Ready() {
+		return nil
+	}
+
+	silences, err := am.ListSilences(ctx, nil)
+	if err != nil {
+		return err
+	}
+	managed := map[string][]*apimodels.GettableSilence{}
+	for _, s := range silences {
+		if s.Status == nil || s.Status.State == nil || *s.Status.State == amv2.SilenceStatusStateExpired || s.CreatedBy == nil {
+			continue
+		}
+		if uid, ok := ProvisionedSilenceUID(*s.CreatedBy); ok {
+			managed[uid] = append(managed[uid], s)
+		}
+	}
+	// Sort by ID so that all replicas keep the same silence if there are duplicates.
+	for _, list := range managed {
+		sort.Slice(list, func(i, j int) bool {
+			return *list[i].ID < *list[j].ID
+		})
+	}
+
+	logger := moa.logger.New("org", orgID)
+	until := now.Add(silenceMaterializationHorizon)
+	for _, def := range defs {
+		windows, err := silenceWindows(def, now, until)
+		if err != nil {
+			logger.Error("Failed to compute silences of silence definition", "uid", def.UID, "error", err)
+			continue
+		}
+		matchers := silenceMatchers(def)
+		existing := managed[def.UID]
+		delete(managed, def.UID)
+
+		kept := make(map[string]struct{}, len(existing))
+		for _, w := range windows {
+			found := false
+			for _, s := range existing {
+				if _, ok := kept[*s.ID]; ok {
+					continue
+				}
+				if silenceMatchesWindow(s, matchers, def.Comment, w, now) {
+					kept[*s.ID] = struct{}{}
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+			id, err := am.CreateSilence(ctx, newProvisionedSilence(def, matchers, w))
+			if err != nil {
+				logger.Error("Failed to create silence from silence definition", "uid", def.UID, "error", err)
+				continue
+			}
+			logger.Debug("Created silence from silence definition", "uid", def.UID, "id", id, "startsAt", w.start, "endsAt", w.end)
+		}
+
+		for _, s := range existing {
+			if _, ok := kept[*s.ID]; ok {
+				continue
+			}
+			moa.expireProvisionedSilence(ctx, logger, am, s)
+		}
+	}
+
+	// Whatever remains belongs to silence definitions that were deleted.
+	for _, list := range managed {
+		for _, s := range list {
+			moa.expireProvisionedSilence(ctx, logger, am, s)
+		}
+	}
+	return nil
+}
+
+func (moa *MultiOrgAlertmanager) expireProvisionedSilence(ctx context.Context, logger log.Logger, am Alertmanager, s *apimodels.GettableSilence) {
+	if err := am.DeleteSilence(ctx, *s.ID); err != nil {
+		logger.Error("Failed to expire silence of silence definition", "id", *s.ID, "createdBy", *s.CreatedBy, "error", err)
+		return
+	}
+	logger.Debug("Expired silence of silence definition", "id", *s.ID, "createdBy", *s.CreatedBy)
+}
+
+type silenceWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// silenceWindows returns the windows in which the silence definition is active, that end after now and start before until.
+// Overlapping and adjacent windows are merged.
+func silenceWindows(def apimodels.SilenceDefinition, now, until time.Time) ([]silenceWindow, error) {
+	var windows []silenceWindow
+	add := func(w silenceWindow) {
+		if n := len(windows); n > 0 && !w.start.After(windows[n-1].end) {
+			if w.end.After(windows[n-1].end) {
+				windows[n-1].end = w.end
+			}
+			return
+		}
+		windows = append(windows, w)
+	}
+
+	switch {
+	case def.StartsAt != nil && def.EndsAt != nil:
+		if def.EndsAt.After(now) && def.StartsAt.Before(until) {
+			add(silenceWindow{start: *def.StartsAt, end: *def.EndsAt})
+		}
+	case def.Schedule != "":
+		schedule, err := cron.ParseStandard(def.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		// Schedules without a time zone are evaluated in UTC.
+		d := time.Duration(def.Duration)
+		for next := schedule.Next(now.UTC().Add(-d)); !next.IsZero() && next.Before(until) && len(windows) <= maxSilenceWindows; next = schedule.Next(next) {
+			add(silenceWindow{start: next, end: next.Add(d)})
+		}
+	case len(def.TimeIntervals) > 0:
+		// Time intervals have a resolution of one minute.
+		for t := now.Truncate(time.Minute); t.Before(until) && len(windows) <= maxSilenceWindows; t = t.Add(time.Minute) {
+			for _, ti := range def.TimeIntervals {
+				if ti.ContainsTime(t) {
+					add(silenceWindow{start: t, end: t.Add(time.Minute)})
+					break
+				}
+			}
+		}
+	}
+
+	if len(windows) > maxSilenceWindows {
+		windows = windows[:maxSilenceWindows]
+	}
+	return windows, nil
+}
+
+func silenceMatchers(def apimodels.SilenceDefinition) amv2.Matchers {
+	matchers := make(amv2.Matchers, 0, len(def.Matchers))
+	for _, m := range def.Matchers {
+		name, value := m.Name, m.Value
+		isEqual := m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp
+		isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
+		matchers = append(matchers, &amv2.Matcher{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex})
+	}
+	return matchers
+}
+
+func newProvisionedSilence(def apimodels.SilenceDefinition, matchers amv2.Matchers, w silenceWindow) *apimodels.PostableSilence {
+	comment := def.Comment
+	createdBy := ProvisionedSilenceCreatedByPrefix + def.UID
+	startsAt := strfmt.DateTime(w.start)
+	endsAt := strfmt.DateTime(w.end)
+	return &apimodels.PostableSilence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			Matchers:  matchers,
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+		},
+	}
+}
+
+// silenceMatchesWindow returns true if the silence has the given matchers and comment and covers the window.
+// The Alertmanager moves the start of silences created in the past to their creation time, so the start of windows
+// that already started is not compared.
+func silenceMatchesWindow(s *apimodels.GettableSilence, matchers amv2.Matchers, comment string, w silenceWindow, now time.Time) bool {
+	if s.Comment == nil || *s.Comment != comment || s.StartsAt == nil || s.EndsAt == nil {
+		return false
+	}
+	if !time.Time(*s.EndsAt).Truncate(time.Second).Equal(w.end.Truncate(time.Second)) {
+		return false
+	}
+	if w.start.After(now) && !time.Time(*s.StartsAt).Truncate(time.Second).Equal(w.start.Truncate(time.Second)) {
+		return false
+	}
+	return matchersKey(s.Matchers) == matchersKey(matchers)
+}
+
+func matchersKey(matchers amv2.Matchers) string {
+	keys := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		keys = append(keys, fmt.Sprintf("%s|%t|%t|%s", *m.Name, isEqual, isRegex, *m.Value))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
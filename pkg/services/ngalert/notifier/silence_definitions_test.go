@@ -0,0 +1,248 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSilenceWindows(t *testing.T) {
+	// Monday, 10:30 UTC.
+	now := time.Date(2024, 1, 8, 10, 30, 0, 0, time.UTC)
+	until := now.Add(silenceMaterializationHorizon)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		def      apimodels.SilenceDefinition
+		expected []silenceWindow
+	}{
+		{
+			name:     "one-off silence in the past has no window",
+			def:      apimodels.SilenceDefinition{StartsAt: timePtr(at(1, 0, 0)), EndsAt: timePtr(at(1, 1, 0))},
+			expected: nil,
+		},
+		{
+			name:     "active one-off silence keeps its start",
+			def:      apimodels.SilenceDefinition{StartsAt: timePtr(at(8, 10, 0)), EndsAt: timePtr(at(8, 11, 0))},
+			expected: []silenceWindow{{start: at(8, 10, 0), end: at(8, 11, 0)}},
+		},
+		{
+			name:     "one-off silence after the horizon has no window",
+			def:      apimodels.SilenceDefinition{StartsAt: timePtr(at(20, 0, 0)), EndsAt: timePtr(at(21, 0, 0))},
+			expected: nil,
+		},
+		{
+			name: "weekly schedule",
+			def:  apimodels.SilenceDefinition{Schedule: "0 2 * * SAT", Duration: model.Duration(4 * time.Hour)},
+			expected: []silenceWindow{
+				{start: at(13, 2, 0), end: at(13, 6, 0)},
+			},
+		},
+		{
+			name: "schedule includes the window that is active now",
+			def:  apimodels.SilenceDefinition{Schedule: "0 10 * * MON", Duration: model.Duration(time.Hour)},
+			expected: []silenceWindow{
+				{start: at(8, 10, 0), end: at(8, 11, 0)},
+				{start: at(15, 10, 0), end: at(15, 11, 0)},
+			},
+		},
+		{
+			name: "overlapping occurrences are merged",
+			def:  apimodels.SilenceDefinition{Schedule: "0 * * * *", Duration: model.Duration(2 * time.Hour)},
+			expected: []silenceWindow{
+				{start: at(8, 9, 0), end: at(15, 12, 0)},
+			},
+		},
+		{
+			name: "time intervals",
+			def:  apimodels.SilenceDefinition{TimeIntervals: parseTimeIntervals(t, `[{times: [{start_time: "02:00", end_time: "04:00"}], weekdays: ["saturday", "sunday"]}]`)},
+			expected: []silenceWindow{
+				{start: at(13, 2, 0), end: at(13, 4, 0)},
+				{start: at(14, 2, 0), end: at(14, 4, 0)},
+			},
+		},
+		{
+			name: "time interval that is active now starts now",
+			def:  apimodels.SilenceDefinition{TimeIntervals: parseTimeIntervals(t, `[{weekdays: ["monday"]}]`)},
+			expected: []silenceWindow{
+				{start: at(8, 10, 30), end: at(9, 0, 0)},
+				{start: at(15, 0, 0), end: at(15, 10, 30)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			windows, err := silenceWindows(tc.def, now, until)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, windows)
+		})
+	}
+
+	t.Run("number of windows is limited", func(t *testing.T) {
+		windows, err := silenceWindows(apimodels.SilenceDefinition{Schedule: "*/5 * * * *", Duration: model.Duration(time.Minute)}, now, until)
+		require.NoError(t, err)
+		require.Len(t, windows, maxSilenceWindows)
+	})
+}
+
+func TestSilenceDefinitionStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewSilenceDefinitionStore(ngfakes.NewFakeKVStore(t))
+
+	defs, err := store.GetSilenceDefinitions(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, defs)
+
+	matcher, err := labels.NewMatcher(labels.MatchRegexp, "cluster", "prod-.*")
+	require.NoError(t, err)
+	expected := []apimodels.SilenceDefinition{
+		{
+			UID:           "b",
+			Matchers:      config.Matchers{matcher},
+			Comment:       "Time intervals",
+			TimeIntervals: parseTimeIntervals(t, `[{times: [{start_time: "02:00", end_time: "04:00"}], weekdays: ["monday:friday"], days_of_month: ["1:7"], months: ["january"], years: ["2024"], location: "Europe/Berlin"}]`),
+		},
+		{
+			UID:      "a",
+			Matchers: config.Matchers{matcher},
+			Comment:  "Schedule",
+			Schedule: "0 2 * * SAT",
+			Duration: model.Duration(4 * time.Hour),
+		},
+	}
+	require.NoError(t, store.SaveSilenceDefinitions(ctx, 1, expected))
+
+	defs, err = store.GetSilenceDefinitions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, defs, 2)
+	require.Equal(t, "a", defs[0].UID)
+	require.Equal(t, "b", defs[1].UID)
+	require.Equal(t, `cluster=~"prod-.*"`, defs[1].Matchers[0].String())
+	require.Equal(t, expected[0].TimeIntervals, defs[1].TimeIntervals)
+
+	other, err := store.GetSilenceDefinitions(ctx, 2)
+	require.NoError(t, err)
+	require.Empty(t, other)
+}
+
+func TestMultiOrgAlertmanager_MaterializeSilences(t *testing.T) {
+	configStore := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	orgStore := &FakeOrgStore{
+		orgs: []int64{1},
+	}
+	cfg := &setting.Cfg{
+		DataPath:        t.TempDir(),
+		UnifiedAlerting: setting.UnifiedAlertingSettings{AlertmanagerConfigPollInterval: 3 * time.Minute, DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration()}, // do not poll in tests.
+	}
+	kvStore := ngfakes.NewFakeKVStore(t)
+	provStore := provisioning.NewFakeProvisioningStore()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	decryptFn := secretsService.GetDecryptedValue
+	m := metrics.NewNGAlert(prometheus.NewPedanticRegistry())
+	mam, err := NewMultiOrgAlertmanager(cfg, configStore, orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+	am, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	// Materialize as of tomorrow at noon, so that no occurrence is active.
+	now := time.Now().UTC().Truncate(24 * time.Hour).Add(36 * time.Hour)
+
+	activeSilences := func(t *testing.T) []*apimodels.GettableSilence {
+		t.Helper()
+		silences, err := am.ListSilences(ctx, nil)
+		require.NoError(t, err)
+		var result []*apimodels.GettableSilence
+		for _, s := range silences {
+			if *s.Status.State != "expired" {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "cluster", "prod")
+	require.NoError(t, err)
+	def := apimodels.SilenceDefinition{
+		UID:      "maintenance",
+		Matchers: config.Matchers{matcher},
+		Comment:  "Daily maintenance",
+		Schedule: "0 2 * * *",
+		Duration: model.Duration(time.Hour),
+	}
+
+	t.Run("materializes one silence per occurrence", func(t *testing.T) {
+		require.NoError(t, mam.silenceDefinitions.SaveSilenceDefinitions(ctx, 1, []apimodels.SilenceDefinition{def}))
+		require.NoError(t, mam.materializeSilences(ctx, 1, am, now))
+
+		silences := activeSilences(t)
+		require.Len(t, silences, 7)
+		for _, s := range silences {
+			require.Equal(t, "provisioning/maintenance", *s.CreatedBy)
+			require.Equal(t, "Daily maintenance", *s.Comment)
+			require.Equal(t, time.Hour, time.Time(*s.EndsAt).Sub(time.Time(*s.StartsAt)))
+			uid, ok := ProvisionedSilenceUID(*s.CreatedBy)
+			require.True(t, ok)
+			require.Equal(t, "maintenance", uid)
+		}
+	})
+
+	t.Run("materializing again is idempotent", func(t *testing.T) {
+		before := activeSilences(t)
+		require.NoError(t, mam.materializeSilences(ctx, 1, am, now))
+		require.Equal(t, before, activeSilences(t))
+	})
+
+	t.Run("changed definition replaces its silences", func(t *testing.T) {
+		updated := def
+		updated.Comment = "Nightly maintenance"
+		require.NoError(t, mam.silenceDefinitions.SaveSilenceDefinitions(ctx, 1, []apimodels.SilenceDefinition{updated}))
+		require.NoError(t, mam.materializeSilences(ctx, 1, am, now))
+
+		silences := activeSilences(t)
+		require.Len(t, silences, 7)
+		for _, s := range silences {
+			require.Equal(t, "Nightly maintenance", *s.Comment)
+		}
+	})
+
+	t.Run("silences of deleted definitions are expired", func(t *testing.T) {
+		require.NoError(t, mam.silenceDefinitions.SaveSilenceDefinitions(ctx, 1, nil))
+		require.NoError(t, mam.materializeSilences(ctx, 1, am, now))
+
+		require.Empty(t, activeSilences(t))
+	})
+}
+
+func parseTimeIntervals(t *testing.T, s string) []timeinterval.TimeInterval {
+	t.Helper()
+	var intervals []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(s), &intervals))
+	return intervals
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
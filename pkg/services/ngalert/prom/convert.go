@@ -0,0 +1,186 @@
+// Package prom converts Prometheus rule groups to Grafana-managed alert rule groups.
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	queryRefID     = "A"
+	conditionRefID = "B"
+
+	// conditionExpression fires for every series returned by the query, whatever its value, the same way as a Prometheus alerting rule.
+	// Threshold and reduce expressions cannot be used: a threshold compares the value with its parameters, so it never fires
+	// for every value including NaN and infinities, and reducing the numbers returned by an instant query is a no-op.
+	conditionExpression = "is_number($A) || is_nan($A) || is_inf($A)"
+
+	defaultQueryRange = 10 * time.Minute
+)
+
+var (
+	ErrInvalidRuleGroup = errors.New("invalid Prometheus rule group")
+
+	// valueRegex matches the value of the alert in Prometheus templates, such as {{ $value }} or {{ .Value }}.
+	valueRegex = regexp.MustCompile(`(\$value|\.Value)\b`)
+)
+
+// Config configures the conversion of Prometheus rules.
+type Config struct {
+	// DatasourceUID is the UID of the Prometheus data source queried by the converted rules.
+	DatasourceUID string
+	// DatasourceType is the type of the data source, prometheus by default.
+	DatasourceType string
+	// DefaultInterval is the evaluation interval of the converted groups that do not define one.
+	DefaultInterval time.Duration
+	// QueryRange is the time range of the queries, 10 minutes by default.
+	QueryRange time.Duration
+}
+
+// Converter converts Prometheus rule groups to Grafana-managed alert rule groups.
+type Converter struct {
+	cfg Config
+}
+
+func NewConverter(cfg Config) (*Converter, error) {
+	if cfg.DatasourceUID == "" {
+		return nil, errors.New("data source UID is required")
+	}
+	if cfg.DefaultInterval <= 0 {
+		return nil, errors.New("default evaluation interval must be greater than zero")
+	}
+	if cfg.DatasourceType == "" {
+		cfg.DatasourceType = "prometheus"
+	}
+	if cfg.QueryRange == 0 {
+		cfg.QueryRange = defaultQueryRange
+	}
+	return &Converter{cfg: cfg}, nil
+}
+
+// PrometheusRulesToGrafana converts the alerting rules of a Prometheus rule group to a Grafana-managed rule group in the given folder.
+// Each rule queries the data source with the expression of the Prometheus rule, and fires for every series the query returns.
+// Recording rules are skipped. The converted rules have no UID.
+func (c *Converter) PrometheusRulesToGrafana(orgID int64, folderUID string, group apimodels.PrometheusRuleGroup) (*models.AlertRuleGroup, error) {
+	if group.Name == "" {
+		return nil, fmt.Errorf("%w: the name of the group is required", ErrInvalidRuleGroup)
+	}
+	interval := time.Duration(group.Interval)
+	if interval == 0 {
+		interval = c.cfg.DefaultInterval
+	}
+
+	result := &models.AlertRuleGroup{
+		Title:     group.Name,
+		FolderUID: folderUID,
+		Interval:  int64(interval.Seconds()),
+		Rules:     make([]models.AlertRule, 0, len(group.Rules)),
+	}
+	for _, rule := range group.Rules {
+		if rule.Record != "" {
+			if rule.Alert != "" {
+				return nil, fmt.Errorf("%w: rule %q in group %q cannot be both an alerting and a recording rule", ErrInvalidRuleGroup, rule.Alert, group.Name)
+			}
+			continue
+		}
+		r, err := c.convertRule(orgID, folderUID, group.Name, rule)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %q in group %q: %s", ErrInvalidRuleGroup, rule.Alert, group.Name, err.Error())
+		}
+		r.IntervalSeconds = result.Interval
+		r.RuleGroupIndex = len(result.Rules) + 1
+		result.Rules = append(result.Rules, r)
+	}
+	return result, nil
+}
+
+func (c *Converter) convertRule(orgID int64, folderUID, groupName string, rule apimodels.PrometheusRule) (models.AlertRule, error) {
+	if rule.Alert == "" {
+		return models.AlertRule{}, errors.New("the name of the alert is required")
+	}
+	if rule.Expr == "" {
+		return models.AlertRule{}, errors.New("the expression is required")
+	}
+	if _, err := parser.ParseExpr(rule.Expr); err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	query, err := json.Marshal(map[string]any{
+		"refId": queryRefID,
+		"datasource": map[string]string{
+			"type": c.cfg.DatasourceType,
+			"uid":  c.cfg.DatasourceUID,
+		},
+		"expr":    rule.Expr,
+		"instant": true,
+		"range":   false,
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	condition, err := json.Marshal(map[string]any{
+		"refId": conditionRefID,
+		"datasource": map[string]string{
+			"type": expr.DatasourceType,
+			"uid":  expr.DatasourceUID,
+		},
+		"type":       "math",
+		"expression": conditionExpression,
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	return models.AlertRule{
+		OrgID:        orgID,
+		Title:        rule.Alert,
+		NamespaceUID: folderUID,
+		RuleGroup:    groupName,
+		Condition:    conditionRefID,
+		Data: []models.AlertQuery{
+			{
+				RefID:             queryRefID,
+				DatasourceUID:     c.cfg.DatasourceUID,
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(c.cfg.QueryRange)},
+				Model:             query,
+			},
+			{
+				RefID:         conditionRefID,
+				DatasourceUID: expr.DatasourceUID,
+				Model:         condition,
+			},
+		},
+		For:          time.Duration(rule.For),
+		Labels:       convertTemplates(rule.Labels),
+		Annotations:  convertTemplates(rule.Annotations),
+		NoDataState:  models.OK,
+		ExecErrState: models.ErrorErrState,
+	}, nil
+}
+
+// convertTemplates replaces the value of the alert in Prometheus templates with the value of the query of the converted rule.
+// Labels are available to Grafana templates the same way as to Prometheus templates.
+func convertTemplates(templates map[string]string) map[string]string {
+	if templates == nil {
+		return nil
+	}
+	result := make(map[string]string, len(templates))
+	for k, v := range templates {
+		result[k] = valueRegex.ReplaceAllStringFunc(v, func(s string) string {
+			if s == "$value" {
+				return "$values." + queryRefID + ".Value"
+			}
+			return ".Values." + queryRefID + ".Value"
+		})
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewConverter(t *testing.T) {
+	_, err := NewConverter(Config{DefaultInterval: time.Minute})
+	require.Error(t, err)
+
+	_, err = NewConverter(Config{DatasourceUID: "prometheus"})
+	require.Error(t, err)
+
+	c, err := NewConverter(Config{DatasourceUID: "prometheus", DefaultInterval: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, "prometheus", c.cfg.DatasourceType)
+	assert.Equal(t, defaultQueryRange, c.cfg.QueryRange)
+}
+
+func TestPrometheusRulesToGrafana(t *testing.T) {
+	c, err := NewConverter(Config{DatasourceUID: "mimir", DefaultInterval: time.Minute})
+	require.NoError(t, err)
+
+	group := apimodels.PrometheusRuleGroup{
+		Name:     "node",
+		Interval: prommodel.Duration(30 * time.Second),
+		Rules: []apimodels.PrometheusRule{
+			{
+				Record: "instance:node_cpu:rate5m",
+				Expr:   "rate(node_cpu_seconds_total[5m])",
+			},
+			{
+				Alert:  "HighCPU",
+				Expr:   "instance:node_cpu:rate5m > 0.9",
+				For:    prommodel.Duration(5 * time.Minute),
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "CPU of {{ $labels.instance }} is {{ humanizePercentage $value }}",
+					"description": "{{ .Value }} {{ $values }}",
+				},
+			},
+			{
+				Alert:  "HighCPU",
+				Expr:   "instance:node_cpu:rate5m > 0.99",
+				Labels: map[string]string{"severity": "critical"},
+			},
+		},
+	}
+
+	result, err := c.PrometheusRulesToGrafana(1, "folder", group)
+	require.NoError(t, err)
+	assert.Equal(t, "node", result.Title)
+	assert.Equal(t, "folder", result.FolderUID)
+	assert.Equal(t, int64(30), result.Interval)
+	require.Len(t, result.Rules, 2)
+
+	t.Run("should convert alerting rules", func(t *testing.T) {
+		rule := result.Rules[0]
+		assert.Equal(t, "HighCPU", rule.Title)
+		assert.Equal(t, "node", rule.RuleGroup)
+		assert.Equal(t, "folder", rule.NamespaceUID)
+		assert.Equal(t, 1, rule.RuleGroupIndex)
+		assert.Equal(t, int64(30), rule.IntervalSeconds)
+		assert.Equal(t, 5*time.Minute, rule.For)
+		assert.Equal(t, map[string]string{"severity": "warning"}, rule.Labels)
+		assert.Equal(t, models.OK, rule.NoDataState)
+		assert.Equal(t, models.ErrorErrState, rule.ExecErrState)
+		require.NoError(t, rule.ValidateAlertRule(setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}))
+	})
+
+	t.Run("should query the data source and fire for every series", func(t *testing.T) {
+		rule := result.Rules[0]
+		assert.Equal(t, conditionRefID, rule.Condition)
+		require.Len(t, rule.Data, 2)
+
+		query := map[string]any{}
+		require.NoError(t, json.Unmarshal(rule.Data[0].Model, &query))
+		assert.Equal(t, "mimir", rule.Data[0].DatasourceUID)
+		assert.Equal(t, "instance:node_cpu:rate5m > 0.9", query["expr"])
+		assert.Equal(t, true, query["instant"])
+		assert.Equal(t, models.Duration(defaultQueryRange), rule.Data[0].RelativeTimeRange.From)
+
+		condition := map[string]any{}
+		require.NoError(t, json.Unmarshal(rule.Data[1].Model, &condition))
+		assert.Equal(t, expr.DatasourceUID, rule.Data[1].DatasourceUID)
+		assert.Equal(t, "math", condition["type"])
+		assert.Equal(t, conditionExpression, condition["expression"])
+	})
+
+	t.Run("should convert the value in templates", func(t *testing.T) {
+		assert.Equal(t, map[string]string{
+			"summary":     "CPU of {{ $labels.instance }} is {{ humanizePercentage $values.A.Value }}",
+			"description": "{{ .Values.A.Value }} {{ $values }}",
+		}, result.Rules[0].Annotations)
+	})
+
+	t.Run("should use the default interval", func(t *testing.T) {
+		result, err := c.PrometheusRulesToGrafana(1, "folder", apimodels.PrometheusRuleGroup{Name: "empty"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(60), result.Interval)
+		assert.Empty(t, result.Rules)
+	})
+}
+
+func TestPrometheusRulesToGrafana_Invalid(t *testing.T) {
+	c, err := NewConverter(Config{DatasourceUID: "mimir", DefaultInterval: time.Minute})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		group apimodels.PrometheusRuleGroup
+	}{
+		{
+			name:  "group without name",
+			group: apimodels.PrometheusRuleGroup{Rules: []apimodels.PrometheusRule{{Alert: "Up", Expr: "up == 0"}}},
+		},
+		{
+			name:  "rule without expression",
+			group: apimodels.PrometheusRuleGroup{Name: "group", Rules: []apimodels.PrometheusRule{{Alert: "Up"}}},
+		},
+		{
+			name:  "rule with invalid expression",
+			group: apimodels.PrometheusRuleGroup{Name: "group", Rules: []apimodels.PrometheusRule{{Alert: "Up", Expr: "up =="}}},
+		},
+		{
+			name:  "rule that is both alerting and recording",
+			group: apimodels.PrometheusRuleGroup{Name: "group", Rules: []apimodels.PrometheusRule{{Alert: "Up", Record: "up:sum", Expr: "sum(up)"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := c.PrometheusRulesToGrafana(1, "folder", tc.group)
+			require.ErrorIs(t, err, ErrInvalidRuleGroup)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type InhibitRuleService struct {
+	amStore         AMConfigStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+}
+
+func NewInhibitRuleService(am AMConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *InhibitRuleService {
+	return &InhibitRuleService{
+		amStore:         am,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+	}
+}
+
+// GetInhibitRules returns the inhibition rules of the org's Alertmanager configuration.
+func (svc *InhibitRuleService) GetInhibitRules(ctx context.Context, orgID int64) (definitions.InhibitRules, error) {
+	revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+	if err != nil {
+		return definitions.InhibitRules{}, err
+	}
+
+	result := definitions.InhibitRules{Rules: revision.cfg.AlertmanagerConfig.InhibitRules}
+	if result.Rules == nil {
+		result.Rules = []config.InhibitRule{}
+	}
+
+	provenance, err := svc.provenanceStore.GetProvenance(ctx, &result, orgID)
+	if err != nil {
+		return definitions.InhibitRules{}, err
+	}
+	result.Provenance = definitions.Provenance(provenance)
+
+	return result, nil
+}
+
+// UpdateInhibitRules replaces the inhibition rules of the org's Alertmanager configuration.
+func (svc *InhibitRuleService) UpdateInhibitRules(ctx context.Context, orgID int64, rules definitions.InhibitRules, p models.Provenance) error {
+	if err := rules.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	return svc.save(ctx, orgID, rules.Rules, func(ctx context.Context) error {
+		return svc.provenanceStore.SetProvenance(ctx, &rules, orgID, p)
+	})
+}
+
+// ResetInhibitRules removes all inhibition rules of the org's Alertmanager configuration, and their provenance.
+func (svc *InhibitRuleService) ResetInhibitRules(ctx context.Context, orgID int64) error {
+	return svc.save(ctx, orgID, nil, func(ctx context.Context) error {
+		return svc.provenanceStore.DeleteProvenance(ctx, &definitions.InhibitRules{}, orgID)
+	})
+}
+
+func (svc *InhibitRuleService) save(ctx context.Context, orgID int64, rules []config.InhibitRule, updateProvenance func(ctx context.Context) error) error {
+	revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+	if err != nil {
+		return err
+	}
+
+	revision.cfg.AlertmanagerConfig.InhibitRules = rules
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := PersistConfig(ctx, svc.amStore, &cmd); err != nil {
+			return err
+		}
+		return updateProvenance(ctx)
+	})
+}
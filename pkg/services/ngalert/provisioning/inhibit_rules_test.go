@@ -0,0 +1,114 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestInhibitRuleService(t *testing.T) {
+	t.Run("service returns empty inhibition rules by default", func(t *testing.T) {
+		sut := createInhibitRuleServiceSut()
+
+		rules, err := sut.GetInhibitRules(context.Background(), 1)
+		require.NoError(t, err)
+
+		require.Empty(t, rules.Rules)
+		require.Equal(t, models.ProvenanceNone, models.Provenance(rules.Provenance))
+	})
+
+	t.Run("service stitches inhibition rules into org's AM config", func(t *testing.T) {
+		sut := createInhibitRuleServiceSut()
+
+		err := sut.UpdateInhibitRules(context.Background(), 1, createTestInhibitRules(t), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		updated, err := sut.GetInhibitRules(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, updated.Rules, 1)
+		require.Equal(t, model.LabelNames{"cluster"}, updated.Rules[0].Equal)
+		require.Equal(t, "alertname=\"ClusterDown\"", updated.Rules[0].SourceMatchers[0].String())
+		require.Equal(t, models.ProvenanceAPI, models.Provenance(updated.Provenance))
+	})
+
+	t.Run("service respects concurrency token when updating", func(t *testing.T) {
+		sut := createInhibitRuleServiceSut()
+		config, err := sut.amStore.GetLatestAlertmanagerConfiguration(context.Background(), 1)
+		require.NoError(t, err)
+		expectedConcurrencyToken := config.ConfigurationHash
+
+		err = sut.UpdateInhibitRules(context.Background(), 1, createTestInhibitRules(t), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		intercepted := sut.amStore.(*fakeAMConfigStore).lastSaveCommand
+		require.Equal(t, expectedConcurrencyToken, intercepted.FetchedConfigurationHash)
+	})
+
+	t.Run("updating rule without target matchers returns ValidationError", func(t *testing.T) {
+		sut := createInhibitRuleServiceSut()
+		invalid := createTestInhibitRules(t)
+		invalid.Rules[0].TargetMatchers = nil
+
+		err := sut.UpdateInhibitRules(context.Background(), 1, invalid, models.ProvenanceNone)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("updating rule with invalid label name returns ValidationError", func(t *testing.T) {
+		sut := createInhibitRuleServiceSut()
+		invalid := createTestInhibitRules(t)
+		invalid.Rules[0].SourceMatch = map[string]string{"invalid-label": "value"}
+
+		err := sut.UpdateInhibitRules(context.Background(), 1, invalid, models.ProvenanceNone)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("resetting removes inhibition rules and provenance", func(t *testing.T) {
+		sut := createInhibitRuleServiceSut()
+		err := sut.UpdateInhibitRules(context.Background(), 1, createTestInhibitRules(t), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		err = sut.ResetInhibitRules(context.Background(), 1)
+		require.NoError(t, err)
+
+		rules, err := sut.GetInhibitRules(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, rules.Rules)
+		require.Equal(t, models.ProvenanceNone, models.Provenance(rules.Provenance))
+	})
+}
+
+func createInhibitRuleServiceSut() *InhibitRuleService {
+	return &InhibitRuleService{
+		amStore:         newFakeAMConfigStore(defaultAlertmanagerConfigJSON),
+		provenanceStore: NewFakeProvisioningStore(),
+		xact:            newNopTransactionManager(),
+		log:             log.NewNopLogger(),
+	}
+}
+
+func createTestInhibitRules(t *testing.T) definitions.InhibitRules {
+	t.Helper()
+	source, err := labels.NewMatcher(labels.MatchEqual, "alertname", "ClusterDown")
+	require.NoError(t, err)
+	target, err := labels.NewMatcher(labels.MatchNotEqual, "alertname", "ClusterDown")
+	require.NoError(t, err)
+	return definitions.InhibitRules{
+		Rules: []config.InhibitRule{
+			{
+				SourceMatchers: config.Matchers{source},
+				TargetMatchers: config.Matchers{target},
+				Equal:          model.LabelNames{"cluster"},
+			},
+		},
+	}
+}
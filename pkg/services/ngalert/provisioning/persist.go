@@ -28,6 +28,12 @@ type ProvisioningStore interface {
 	DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error
 }
 
+// SilenceDefinitionStore is a store of provisioned silence definitions.
+type SilenceDefinitionStore interface {
+	GetSilenceDefinitions(ctx context.Context, orgID int64) ([]definitions.SilenceDefinition, error)
+	SaveSilenceDefinitions(ctx context.Context, orgID int64, defs []definitions.SilenceDefinition) error
+}
+
 // TransactionManager represents the ability to issue and close transactions through contexts.
 type TransactionManager interface {
 	InTransaction(ctx context.Context, work func(ctx context.Context) error) error
@@ -0,0 +1,102 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type SilenceService struct {
+	store           SilenceDefinitionStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+}
+
+func NewSilenceService(store SilenceDefinitionStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *SilenceService {
+	return &SilenceService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+	}
+}
+
+// GetSilenceDefinitions returns the silence definitions of an organization.
+func (svc *SilenceService) GetSilenceDefinitions(ctx context.Context, orgID int64) ([]definitions.SilenceDefinition, error) {
+	defs, err := svc.store.GetSilenceDefinitions(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&definitions.SilenceDefinition{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+	for i := range defs {
+		if p, ok := provenances[defs[i].UID]; ok {
+			defs[i].Provenance = definitions.Provenance(p)
+		}
+	}
+	return defs, nil
+}
+
+// UpsertSilenceDefinition creates a silence definition, or replaces the silence definition with the same UID.
+// The multi-org Alertmanager materializes the definition into silences on its next sync.
+func (svc *SilenceService) UpsertSilenceDefinition(ctx context.Context, orgID int64, def definitions.SilenceDefinition, p models.Provenance) (definitions.SilenceDefinition, error) {
+	if err := def.Validate(); err != nil {
+		return definitions.SilenceDefinition{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	defs, err := svc.store.GetSilenceDefinitions(ctx, orgID)
+	if err != nil {
+		return definitions.SilenceDefinition{}, err
+	}
+	updated := make([]definitions.SilenceDefinition, 0, len(defs)+1)
+	for _, existing := range defs {
+		if existing.UID != def.UID {
+			updated = append(updated, existing)
+		}
+	}
+	updated = append(updated, def)
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.SaveSilenceDefinitions(ctx, orgID, updated); err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &def, orgID, p)
+	})
+	if err != nil {
+		return definitions.SilenceDefinition{}, err
+	}
+	def.Provenance = definitions.Provenance(p)
+	return def, nil
+}
+
+// DeleteSilenceDefinition deletes the silence definition with the given UID, if it exists.
+// The multi-org Alertmanager expires the silences of the definition on its next sync.
+func (svc *SilenceService) DeleteSilenceDefinition(ctx context.Context, orgID int64, uid string) error {
+	defs, err := svc.store.GetSilenceDefinitions(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	updated := make([]definitions.SilenceDefinition, 0, len(defs))
+	for _, existing := range defs {
+		if existing.UID != uid {
+			updated = append(updated, existing)
+		}
+	}
+	if len(updated) == len(defs) {
+		return nil
+	}
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.SaveSilenceDefinitions(ctx, orgID, updated); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, &definitions.SilenceDefinition{UID: uid}, orgID)
+	})
+}
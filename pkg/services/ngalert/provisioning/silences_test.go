@@ -0,0 +1,114 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestSilenceService(t *testing.T) {
+	t.Run("service returns no silence definitions by default", func(t *testing.T) {
+		sut := createSilenceServiceSut()
+
+		defs, err := sut.GetSilenceDefinitions(context.Background(), 1)
+		require.NoError(t, err)
+
+		require.Empty(t, defs)
+	})
+
+	t.Run("service creates silence definition with provenance", func(t *testing.T) {
+		sut := createSilenceServiceSut()
+
+		created, err := sut.UpsertSilenceDefinition(context.Background(), 1, createTestSilenceDefinition(t), models.ProvenanceFile)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, models.Provenance(created.Provenance))
+
+		defs, err := sut.GetSilenceDefinitions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, defs, 1)
+		require.Equal(t, "maintenance", defs[0].UID)
+		require.Equal(t, models.ProvenanceFile, models.Provenance(defs[0].Provenance))
+	})
+
+	t.Run("upserting the same UID again replaces the silence definition", func(t *testing.T) {
+		sut := createSilenceServiceSut()
+		def := createTestSilenceDefinition(t)
+		_, err := sut.UpsertSilenceDefinition(context.Background(), 1, def, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		def.Comment = "updated"
+		_, err = sut.UpsertSilenceDefinition(context.Background(), 1, def, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		defs, err := sut.GetSilenceDefinitions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, defs, 1)
+		require.Equal(t, "updated", defs[0].Comment)
+	})
+
+	t.Run("invalid silence definition returns ValidationError", func(t *testing.T) {
+		sut := createSilenceServiceSut()
+		def := createTestSilenceDefinition(t)
+		now := time.Now()
+		def.StartsAt = &now
+
+		_, err := sut.UpsertSilenceDefinition(context.Background(), 1, def, models.ProvenanceFile)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("deleting removes silence definition and provenance", func(t *testing.T) {
+		sut := createSilenceServiceSut()
+		_, err := sut.UpsertSilenceDefinition(context.Background(), 1, createTestSilenceDefinition(t), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		err = sut.DeleteSilenceDefinition(context.Background(), 1, "maintenance")
+		require.NoError(t, err)
+
+		defs, err := sut.GetSilenceDefinitions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, defs)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), &definitions.SilenceDefinition{UID: "maintenance"}, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("deleting unknown silence definition succeeds", func(t *testing.T) {
+		sut := createSilenceServiceSut()
+
+		err := sut.DeleteSilenceDefinition(context.Background(), 1, "unknown")
+
+		require.NoError(t, err)
+	})
+}
+
+func createSilenceServiceSut() *SilenceService {
+	return &SilenceService{
+		store:           newFakeSilenceDefinitionStore(),
+		provenanceStore: NewFakeProvisioningStore(),
+		xact:            newNopTransactionManager(),
+		log:             log.NewNopLogger(),
+	}
+}
+
+func createTestSilenceDefinition(t *testing.T) definitions.SilenceDefinition {
+	t.Helper()
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "cluster", "prod")
+	require.NoError(t, err)
+	return definitions.SilenceDefinition{
+		UID:      "maintenance",
+		Matchers: config.Matchers{matcher},
+		Comment:  "Weekly maintenance",
+		Schedule: "0 2 * * SAT",
+		Duration: model.Duration(4 * time.Hour),
+	}
+}
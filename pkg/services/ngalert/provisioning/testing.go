@@ -8,6 +8,7 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -135,6 +136,25 @@ func (f *fakeProvisioningStore) DeleteProvenance(ctx context.Context, o models.P
 	return nil
 }
 
+type fakeSilenceDefinitionStore struct {
+	defs map[int64][]definitions.SilenceDefinition
+}
+
+func newFakeSilenceDefinitionStore() *fakeSilenceDefinitionStore {
+	return &fakeSilenceDefinitionStore{
+		defs: map[int64][]definitions.SilenceDefinition{},
+	}
+}
+
+func (f *fakeSilenceDefinitionStore) GetSilenceDefinitions(ctx context.Context, orgID int64) ([]definitions.SilenceDefinition, error) {
+	return append([]definitions.SilenceDefinition{}, f.defs[orgID]...), nil
+}
+
+func (f *fakeSilenceDefinitionStore) SaveSilenceDefinitions(ctx context.Context, orgID int64, defs []definitions.SilenceDefinition) error {
+	f.defs[orgID] = defs
+	return nil
+}
+
 type NopTransactionManager struct{}
 
 func newNopTransactionManager() *NopTransactionManager {
@@ -3,25 +3,42 @@ package remote
 import (
 	"context"
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/go-openapi/strfmt"
+	alertingClusterPB "github.com/grafana/alerting/cluster/clusterpb"
+	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	remoteClient "github.com/grafana/grafana/pkg/services/ngalert/remote/client"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	amalert "github.com/prometheus/alertmanager/api/v2/client/alert"
 	amalertgroup "github.com/prometheus/alertmanager/api/v2/client/alertgroup"
+	amgeneral "github.com/prometheus/alertmanager/api/v2/client/general"
 	amreceiver "github.com/prometheus/alertmanager/api/v2/client/receiver"
 	amsilence "github.com/prometheus/alertmanager/api/v2/client/silence"
 )
 
+// statusTimeout is the maximum time spent waiting for the status of the remote Alertmanager.
+const statusTimeout = 10 * time.Second
+
 type stateStore interface {
 	GetFullState(ctx context.Context, keys ...string) (string, error)
+	Persist(ctx context.Context, filename string, st alertingNotify.State) (int64, error)
+}
+
+// alertmanagerConfigStore saves the configurations that are applied through the remote Alertmanager.
+type alertmanagerConfigStore interface {
+	SaveAlertmanagerConfigurationWithCallback(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd, callback store.SaveCallback) error
 }
 
 type Alertmanager struct {
@@ -33,6 +50,16 @@ type Alertmanager struct {
 	tenantID string
 	url      string
 
+	remoteOnly    bool
+	defaultConfig string
+	configStore   alertmanagerConfigStore
+	decrypt       apimodels.DecryptFn
+
+	// mtx protects the readiness and the configuration that the remote Alertmanager was last verified to use.
+	mtx        sync.RWMutex
+	config     *apimodels.PostableUserConfig
+	configHash string
+
 	amClient    *remoteClient.Alertmanager
 	mimirClient remoteClient.MimirClient
 }
@@ -42,6 +69,13 @@ type AlertmanagerConfig struct {
 	URL               string
 	TenantID          string
 	BasicAuthPassword string
+
+	// DefaultConfig is the configuration that is saved and applied for organizations without a configuration.
+	DefaultConfig string
+	// RemoteOnly is set when the remote Alertmanager is the only Alertmanager of the organization.
+	// The state of the remote Alertmanager is then the source of truth, and it is copied to the local state store
+	// instead of being overwritten with it.
+	RemoteOnly bool
 }
 
 func (cfg *AlertmanagerConfig) Validate() error {
@@ -59,7 +93,9 @@ func (cfg *AlertmanagerConfig) Validate() error {
 	return nil
 }
 
-func NewAlertmanager(cfg AlertmanagerConfig, store stateStore) (*Alertmanager, error) {
+// NewAlertmanager creates a client for a remote Alertmanager. In remote only mode, the secure settings of the configurations
+// are decrypted with decryptFn right before the configurations are sent to the remote Alertmanager.
+func NewAlertmanager(cfg AlertmanagerConfig, store stateStore, configStore alertmanagerConfigStore, decryptFn apimodels.DecryptFn) (*Alertmanager, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -105,50 +141,77 @@ func NewAlertmanager(cfg AlertmanagerConfig, store stateStore) (*Alertmanager, e
 	}
 
 	return &Alertmanager{
-		log:         logger,
-		mimirClient: mc,
-		state:       store,
-		amClient:    amc,
-		sender:      s,
-		orgID:       cfg.OrgID,
-		tenantID:    cfg.TenantID,
-		url:         cfg.URL,
+		log:           logger,
+		mimirClient:   mc,
+		state:         store,
+		amClient:      amc,
+		sender:        s,
+		orgID:         cfg.OrgID,
+		tenantID:      cfg.TenantID,
+		url:           cfg.URL,
+		remoteOnly:    cfg.RemoteOnly,
+		defaultConfig: cfg.DefaultConfig,
+		configStore:   configStore,
+		decrypt:       decryptFn,
 	}, nil
 }
 
 // ApplyConfig is called everytime we've determined we need to apply an existing configuration to the Alertmanager,
 // including the first time the Alertmanager is started. In the context of a "remote Alertmanager" it's as good of a heuristic,
-// for "a function that gets called when the Alertmanager starts". As a result we do two things:
+// for "a function that gets called when the Alertmanager starts". As a result we do three things:
 // 1. Execute a readiness check to make sure the remote Alertmanager we're about to communicate with is up and ready.
-// 2. Upload the configuration and state we currently hold.
+// 2. Upload the configuration we currently hold, unless the remote Alertmanager already uses it.
+// 3. Synchronize the state. The state we currently hold is uploaded when the Alertmanager starts. In remote only mode,
+// the state of the remote Alertmanager is downloaded instead, and on every following call.
 func (am *Alertmanager) ApplyConfig(ctx context.Context, config *models.AlertConfiguration) error {
-	if am.ready {
+	firstApply := !am.readinessChecked()
+	if firstApply {
+		// First, execute a readiness check to make sure the remote Alertmanager is ready.
+		am.log.Debug("Start readiness check for remote Alertmanager", "url", am.url)
+		if err := am.checkReadiness(ctx); err != nil {
+			am.log.Error("Unable to pass the readiness check", "err", err)
+			return err
+		}
+		am.log.Debug("Completed readiness check for remote Alertmanager", "url", am.url)
+	} else if !am.remoteOnly && am.Ready() {
 		am.log.Debug("Alertmanager previously marked as ready, skipping readiness check and config + state update")
 		return nil
 	}
 
-	// First, execute a readiness check to make sure the remote Alertmanager is ready.
-	am.log.Debug("Start readiness check for remote Alertmanager", "url", am.url)
-	if err := am.checkReadiness(ctx); err != nil {
-		am.log.Error("Unable to pass the readiness check", "err", err)
-		return err
-	}
-	am.log.Debug("Completed readiness check for remote Alertmanager", "url", am.url)
-
 	// Send configuration and base64-encoded state if necessary.
 	am.log.Debug("Start configuration upload to remote Alertmanager", "url", am.url)
-	if err := am.CompareAndSendConfiguration(ctx, config); err != nil {
-		am.log.Error("Unable to upload the configuration to the remote Alertmanager", "err", err)
+	cfgErr := am.CompareAndSendConfiguration(ctx, config)
+	if cfgErr != nil {
+		am.log.Error("Unable to upload the configuration to the remote Alertmanager", "err", cfgErr)
 	}
 	am.log.Debug("Completed configuration upload to remote Alertmanager", "url", am.url)
 
-	am.log.Debug("Start state upload to remote Alertmanager", "url", am.url)
-	if err := am.CompareAndSendState(ctx); err != nil {
-		am.log.Error("Unable to upload the state to the remote Alertmanager", "err", err)
+	am.log.Debug("Start state synchronization with remote Alertmanager", "url", am.url)
+	if err := am.syncState(ctx, firstApply); err != nil {
+		am.log.Error("Unable to synchronize the state with the remote Alertmanager", "err", err)
 	}
-	am.log.Debug("Completed state upload to remote Alertmanager", "url", am.url)
+	am.log.Debug("Completed state synchronization with remote Alertmanager", "url", am.url)
 
-	return nil
+	return cfgErr
+}
+
+// syncState uploads the state we currently hold when the Alertmanager starts. In remote only mode, the state of
+// the remote Alertmanager is downloaded instead, unless it does not have a state yet, e.g. because the organization
+// used the internal Alertmanager until now.
+func (am *Alertmanager) syncState(ctx context.Context, firstApply bool) error {
+	if !am.remoteOnly {
+		if !firstApply {
+			return nil
+		}
+		return am.CompareAndSendState(ctx)
+	}
+
+	err := am.PullState(ctx)
+	if remoteClient.IsNotFound(err) {
+		am.log.Info("Remote Alertmanager has no state, uploading the state we currently hold", "url", am.url)
+		return am.CompareAndSendState(ctx)
+	}
+	return err
 }
 
 func (am *Alertmanager) checkReadiness(ctx context.Context) error {
@@ -159,28 +222,94 @@ func (am *Alertmanager) checkReadiness(ctx context.Context) error {
 
 	if ready {
 		am.log.Debug("Alertmanager readiness check successful")
+		am.mtx.Lock()
 		am.ready = true
+		am.mtx.Unlock()
 		return nil
 	}
 
 	return notifier.ErrAlertmanagerNotReady
 }
 
+// readinessChecked returns true if the remote Alertmanager passed a readiness check.
+func (am *Alertmanager) readinessChecked() bool {
+	am.mtx.RLock()
+	defer am.mtx.RUnlock()
+	return am.ready
+}
+
 // CompareAndSendConfiguration checks whether a given configuration is being used by the remote Alertmanager.
 // If not, it sends the configuration to the remote Alertmanager.
 func (am *Alertmanager) CompareAndSendConfiguration(ctx context.Context, config *models.AlertConfiguration) error {
-	if am.shouldSendConfig(ctx, config) {
-		if err := am.mimirClient.CreateGrafanaAlertmanagerConfig(
-			ctx,
-			config.AlertmanagerConfiguration,
-			config.ConfigurationHash,
-			config.ID,
-			config.CreatedAt,
-			config.Default,
-		); err != nil {
+	if !am.shouldSendConfig(ctx, config) {
+		return am.setConfig(config)
+	}
+	return am.sendConfiguration(ctx, config)
+}
+
+// sendConfiguration sends the configuration to the remote Alertmanager, and verifies that the remote Alertmanager uses
+// it afterwards. The secure settings are only decrypted in remote only mode, when the remote Alertmanager sends the
+// notifications. Otherwise, they are sent as they are stored.
+func (am *Alertmanager) sendConfiguration(ctx context.Context, config *models.AlertConfiguration) error {
+	rawConfig := config.AlertmanagerConfiguration
+	if am.remoteOnly {
+		decrypted, err := am.decryptConfiguration(ctx, rawConfig)
+		if err != nil {
 			return err
 		}
+		rawConfig = decrypted
+	}
+
+	if err := am.mimirClient.CreateGrafanaAlertmanagerConfig(
+		ctx,
+		rawConfig,
+		config.ConfigurationHash,
+		config.ID,
+		config.CreatedAt,
+		config.Default,
+	); err != nil {
+		return err
 	}
+
+	rc, err := am.mimirClient.GetGrafanaAlertmanagerConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to verify the configuration of the remote Alertmanager: %w", err)
+	}
+	if rc.Hash != config.ConfigurationHash {
+		return fmt.Errorf("the remote Alertmanager uses the configuration with hash %q instead of %q", rc.Hash, config.ConfigurationHash)
+	}
+	return am.setConfig(config)
+}
+
+// decryptConfiguration returns the configuration with the plain text values of all secure settings.
+// The remote Alertmanager does not have access to the secrets of Grafana, so it needs the decrypted values to send notifications.
+func (am *Alertmanager) decryptConfiguration(ctx context.Context, rawConfig string) (string, error) {
+	cfg, err := notifier.Load([]byte(rawConfig))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the configuration: %w", err)
+	}
+	if err := notifier.DecryptReceiverConfigs(cfg.AlertmanagerConfig.Receivers, func(_ context.Context, payload []byte) ([]byte, error) {
+		return am.decrypt(ctx, payload)
+	}); err != nil {
+		return "", err
+	}
+	decrypted, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the configuration: %w", err)
+	}
+	return string(decrypted), nil
+}
+
+// setConfig records the configuration that the remote Alertmanager was verified to use.
+func (am *Alertmanager) setConfig(config *models.AlertConfiguration) error {
+	cfg, err := notifier.Load([]byte(config.AlertmanagerConfiguration))
+	if err != nil {
+		return fmt.Errorf("failed to parse the configuration: %w", err)
+	}
+	am.mtx.Lock()
+	defer am.mtx.Unlock()
+	am.config = cfg
+	am.configHash = config.ConfigurationHash
 	return nil
 }
 
@@ -200,12 +329,84 @@ func (am *Alertmanager) CompareAndSendState(ctx context.Context) error {
 	return nil
 }
 
-func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig) error {
+// PullState gets the remote Alertmanager's state and saves its silences and notification log in the local state store,
+// so that they are not lost when switching back to the internal Alertmanager.
+func (am *Alertmanager) PullState(ctx context.Context) error {
+	rs, err := am.mimirClient.GetGrafanaAlertmanagerState(ctx)
+	if err != nil {
+		return err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(rs.State)
+	if err != nil {
+		return fmt.Errorf("failed to decode the remote state: %w", err)
+	}
+	var fs alertingClusterPB.FullState
+	if err := fs.Unmarshal(b); err != nil {
+		return fmt.Errorf("failed to unmarshal the remote state: %w", err)
+	}
+
+	for _, part := range fs.Parts {
+		if part.Key != notifier.SilencesFilename && part.Key != notifier.NotificationLogFilename {
+			am.log.Debug("Ignoring unknown part of the remote state", "key", part.Key)
+			continue
+		}
+		if _, err := am.state.Persist(ctx, part.Key, rawState(part.Data)); err != nil {
+			return fmt.Errorf("failed to save %s: %w", part.Key, err)
+		}
+	}
 	return nil
 }
 
+// rawState is a part of the Alertmanager state that is already serialized.
+type rawState []byte
+
+func (s rawState) MarshalBinary() ([]byte, error) {
+	return s, nil
+}
+
+// SaveAndApplyConfig saves the configuration to the database and sends it to the remote Alertmanager.
+// It rolls back the save if the remote Alertmanager does not accept the configuration.
+func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig) error {
+	rawConfig, err := json.Marshal(&cfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize to the Alertmanager configuration: %w", err)
+	}
+	return am.saveAndApplyConfig(ctx, string(rawConfig), false)
+}
+
+// SaveAndApplyDefaultConfig saves the default configuration to the database and sends it to the remote Alertmanager.
+// It rolls back the save if the remote Alertmanager does not accept the configuration.
 func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
-	return nil
+	return am.saveAndApplyConfig(ctx, am.defaultConfig, true)
+}
+
+func (am *Alertmanager) saveAndApplyConfig(ctx context.Context, rawConfig string, isDefault bool) error {
+	// Organizations without a configuration get the default one instead of ApplyConfig being called at startup,
+	// so we might not have checked whether the remote Alertmanager is ready yet.
+	if !am.readinessChecked() {
+		if err := am.checkReadiness(ctx); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC().Unix()
+	cmd := &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: rawConfig,
+		ConfigurationVersion:      fmt.Sprintf("v%d", models.AlertConfigurationVersion),
+		Default:                   isDefault,
+		OrgID:                     am.orgID,
+		LastApplied:               now,
+	}
+	return am.configStore.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
+		return am.sendConfiguration(ctx, &models.AlertConfiguration{
+			AlertmanagerConfiguration: rawConfig,
+			ConfigurationHash:         fmt.Sprintf("%x", md5.Sum([]byte(rawConfig))),
+			CreatedAt:                 now,
+			Default:                   isDefault,
+			OrgID:                     am.orgID,
+		})
+	})
 }
 
 func (am *Alertmanager) CreateSilence(ctx context.Context, silence *apimodels.PostableSilence) (string, error) {
@@ -321,8 +522,34 @@ func (am *Alertmanager) PutAlerts(ctx context.Context, alerts apimodels.Postable
 	return nil
 }
 
+// GetStatus returns the status of the remote Alertmanager, along with the configuration it was last verified to use.
 func (am *Alertmanager) GetStatus() apimodels.GettableStatus {
-	return apimodels.GettableStatus{}
+	am.mtx.RLock()
+	config := am.config
+	am.mtx.RUnlock()
+	if config == nil {
+		config = &apimodels.PostableUserConfig{}
+	}
+	status := apimodels.NewGettableStatus(&config.AlertmanagerConfig)
+
+	defer func() {
+		if r := recover(); r != nil {
+			am.log.Error("Panic while getting status", "err", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+	res, err := am.amClient.General.GetStatus(amgeneral.NewGetStatusParamsWithContext(ctx))
+	if err != nil {
+		am.log.Error("Unable to get the status of the remote Alertmanager", "err", err)
+		return *status
+	}
+
+	status.Cluster = res.Payload.Cluster
+	status.Uptime = res.Payload.Uptime
+	status.VersionInfo = res.Payload.VersionInfo
+	return *status
 }
 
 func (am *Alertmanager) GetReceivers(ctx context.Context) ([]apimodels.Receiver, error) {
@@ -339,12 +566,14 @@ func (am *Alertmanager) GetReceivers(ctx context.Context) ([]apimodels.Receiver,
 	return rcvs, nil
 }
 
+// TestReceivers is not supported, the remote Alertmanager has no API to send test notifications.
 func (am *Alertmanager) TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error) {
-	return &notifier.TestReceiversResult{}, nil
+	return nil, fmt.Errorf("testing contact points is %w", notifier.ErrNotSupported)
 }
 
+// TestTemplate is not supported, the remote Alertmanager has no API to render templates.
 func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error) {
-	return &notifier.TestTemplatesResults{}, nil
+	return nil, fmt.Errorf("testing templates is %w", notifier.ErrNotSupported)
 }
 
 // StopAndWait is called when the grafana server is instructed to shut down or an org is deleted.
@@ -353,24 +582,31 @@ func (am *Alertmanager) StopAndWait() {
 	am.sender.Stop()
 }
 
+// Ready returns true once the remote Alertmanager passed the readiness check and it was verified to use
+// one of our configurations, by comparing its configuration hash with ours.
 func (am *Alertmanager) Ready() bool {
-	return am.ready
+	am.mtx.RLock()
+	defer am.mtx.RUnlock()
+	return am.ready && am.configHash != ""
 }
 
 // CleanUp does not have an equivalent in a "remote Alertmanager" context, we don't have files on disk, no-op.
 func (am *Alertmanager) CleanUp() {}
 
-// shouldSendConfig compares the remote Alertmanager configuration with our local one.
+// shouldSendConfig compares the hash of the remote Alertmanager configuration with the hash of our local one.
+// The configurations themselves can't be compared, as the remote one holds decrypted secure settings.
 // It returns true if the configurations are different.
 func (am *Alertmanager) shouldSendConfig(ctx context.Context, config *models.AlertConfiguration) bool {
 	rc, err := am.mimirClient.GetGrafanaAlertmanagerConfig(ctx)
 	if err != nil {
 		// Log the error and return true so we try to upload our config anyway.
-		am.log.Error("Unable to get the remote Alertmanager Configuration for comparison", "err", err)
+		if !remoteClient.IsNotFound(err) {
+			am.log.Error("Unable to get the remote Alertmanager Configuration for comparison", "err", err)
+		}
 		return true
 	}
 
-	return md5.Sum([]byte(rc.GrafanaAlertmanagerConfig)) != md5.Sum([]byte(config.AlertmanagerConfiguration))
+	return rc.Hash != config.ConfigurationHash
 }
 
 // shouldSendState compares the remote Alertmanager state with our local one.
@@ -6,8 +6,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math/rand"
-	"net/http"
-	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -17,6 +15,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	secretsfakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/util"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/cluster/clusterpb"
@@ -26,6 +25,10 @@ import (
 // Valid Grafana Alertmanager configuration.
 const testGrafanaConfig = `{"template_files":{},"alertmanager_config":{"route":{"receiver":"grafana-default-email","group_by":["grafana_folder","alertname"]},"templates":null,"receivers":[{"name":"grafana-default-email","grafana_managed_receiver_configs":[{"uid":"","name":"some other name","type":"email","disableResolveMessage":false,"settings":{"addresses":"\u003cexample@email.com\u003e"},"secureSettings":null}]}]}}`
 
+// Valid Grafana Alertmanager configuration with a template and an encrypted secure setting.
+// The fake secrets service doesn't encrypt, so the password is the base64-encoded "secret".
+const testGrafanaConfigWithSecret = `{"template_files":{"test":"{{ define \"test\" }}test{{ end }}"},"alertmanager_config":{"route":{"receiver":"webhook"},"receivers":[{"name":"webhook","grafana_managed_receiver_configs":[{"uid":"webhook-uid","name":"webhook","type":"webhook","settings":{"url":"http://localhost"},"secureSettings":{"password":"c2VjcmV0"}}]}]}}`
+
 func TestNewAlertmanager(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -68,7 +71,7 @@ func TestNewAlertmanager(t *testing.T) {
 				TenantID:          test.tenantID,
 				BasicAuthPassword: test.password,
 			}
-			am, err := NewAlertmanager(cfg, nil)
+			am, err := NewAlertmanager(cfg, nil, nil, nil)
 			if test.expErr != "" {
 				require.EqualError(tt, err, test.expErr)
 				return
@@ -84,44 +87,192 @@ func TestNewAlertmanager(t *testing.T) {
 }
 
 func TestApplyConfig(t *testing.T) {
-	errorHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	})
-	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
-
-	// ApplyConfig performs a readiness check at startup.
-	// A non-200 response should result in an error.
-	server := httptest.NewServer(errorHandler)
+	ctx := context.Background()
+	fakeAM := newFakeRemoteAlertmanager(t)
 	cfg := AlertmanagerConfig{
 		OrgID:    1,
 		TenantID: "test",
-		URL:      server.URL,
+		URL:      fakeAM.server.URL,
 	}
 
-	ctx := context.Background()
 	store := fakes.NewFakeKVStore(t)
 	fstore := notifier.NewFileStore(1, store, "")
 	require.NoError(t, store.Set(ctx, cfg.OrgID, "alertmanager", notifier.SilencesFilename, "test"))
 	require.NoError(t, store.Set(ctx, cfg.OrgID, "alertmanager", notifier.NotificationLogFilename, "test"))
 
-	am, err := NewAlertmanager(cfg, fstore)
+	am, err := NewAlertmanager(cfg, fstore, nil, secretsfakes.NewFakeSecretsService().Decrypt)
 	require.NoError(t, err)
 
-	config := &ngmodels.AlertConfiguration{}
+	// ApplyConfig performs a readiness check at startup.
+	// A non-200 response should result in an error.
+	fakeAM.setReady(false)
+	config := testAlertConfiguration(testGrafanaConfigWithSecret)
 	require.Error(t, am.ApplyConfig(ctx, config))
 	require.False(t, am.Ready())
 
 	// A 200 status code response should make the check succeed.
-	server.Config.Handler = okHandler
+	// The configuration is sent with encrypted secure settings, and the state is sent too.
+	fakeAM.setReady(true)
 	require.NoError(t, am.ApplyConfig(ctx, config))
 	require.True(t, am.Ready())
 
+	remoteConfig := fakeAM.getConfig()
+	require.Equal(t, config.ConfigurationHash, remoteConfig.Hash)
+	sent, err := notifier.Load([]byte(remoteConfig.GrafanaAlertmanagerConfig))
+	require.NoError(t, err)
+	require.Equal(t, "c2VjcmV0", sent.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["password"])
+	require.Contains(t, sent.TemplateFiles, "test")
+	require.NotEmpty(t, fakeAM.getState())
+
 	// If we already got a 200 status code response, we shouldn't make the HTTP request again.
-	server.Config.Handler = errorHandler
+	fakeAM.setReady(false)
 	require.NoError(t, am.ApplyConfig(ctx, config))
 	require.True(t, am.Ready())
+
+	t.Run("not ready if the remote Alertmanager rejects the configuration", func(t *testing.T) {
+		fakeAM := newFakeRemoteAlertmanager(t)
+		fakeAM.setRejectConfig(true)
+		cfg.URL = fakeAM.server.URL
+		am, err := NewAlertmanager(cfg, fstore, nil, secretsfakes.NewFakeSecretsService().Decrypt)
+		require.NoError(t, err)
+
+		require.Error(t, am.ApplyConfig(ctx, config))
+		require.False(t, am.Ready())
+
+		// The configuration is sent again on the next call.
+		fakeAM.setRejectConfig(false)
+		require.NoError(t, am.ApplyConfig(ctx, config))
+		require.True(t, am.Ready())
+		require.Equal(t, config.ConfigurationHash, fakeAM.getConfig().Hash)
+	})
+}
+
+func TestRemoteOnlyAlertmanager(t *testing.T) {
+	ctx := context.Background()
+	encodeState := func(t *testing.T, silences, nflog string) string {
+		t.Helper()
+		fs := clusterpb.FullState{
+			Parts: []clusterpb.Part{
+				{Key: notifier.SilencesFilename, Data: []byte(silences)},
+				{Key: notifier.NotificationLogFilename, Data: []byte(nflog)},
+			},
+		}
+		b, err := fs.Marshal()
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	localValue := func(t *testing.T, store *fakes.FakeKVStore, key string) string {
+		t.Helper()
+		value, ok, err := store.Get(ctx, 1, "alertmanager", key)
+		require.NoError(t, err)
+		require.True(t, ok)
+		b, err := base64.StdEncoding.DecodeString(value)
+		require.NoError(t, err)
+		return string(b)
+	}
+	setup := func(t *testing.T) (*fakeRemoteAlertmanager, *Alertmanager, *fakes.FakeKVStore, configStore) {
+		t.Helper()
+		fakeAM := newFakeRemoteAlertmanager(t)
+		cfg := AlertmanagerConfig{
+			OrgID:         1,
+			TenantID:      "test",
+			URL:           fakeAM.server.URL,
+			DefaultConfig: testGrafanaConfig,
+			RemoteOnly:    true,
+		}
+		store := fakes.NewFakeKVStore(t)
+		require.NoError(t, store.Set(ctx, 1, "alertmanager", notifier.SilencesFilename, base64.StdEncoding.EncodeToString([]byte("local-silences"))))
+		require.NoError(t, store.Set(ctx, 1, "alertmanager", notifier.NotificationLogFilename, base64.StdEncoding.EncodeToString([]byte("local-nflog"))))
+		configStore := notifier.NewFakeConfigStore(t, map[int64]*ngmodels.AlertConfiguration{})
+		am, err := NewAlertmanager(cfg, notifier.NewFileStore(1, store, ""), configStore, secretsfakes.NewFakeSecretsService().Decrypt)
+		require.NoError(t, err)
+		return fakeAM, am, store, configStore
+	}
+
+	t.Run("downloads the state of the remote Alertmanager", func(t *testing.T) {
+		fakeAM, am, store, _ := setup(t)
+		remoteState := encodeState(t, "remote-silences", "remote-nflog")
+		fakeAM.setState(remoteState)
+
+		require.NoError(t, am.ApplyConfig(ctx, testAlertConfiguration(testGrafanaConfig)))
+		require.True(t, am.Ready())
+		require.Equal(t, remoteState, fakeAM.getState())
+		require.Equal(t, "remote-silences", localValue(t, store, notifier.SilencesFilename))
+		require.Equal(t, "remote-nflog", localValue(t, store, notifier.NotificationLogFilename))
+
+		// The state is downloaded on every call.
+		fakeAM.setState(encodeState(t, "new-silences", "new-nflog"))
+		require.NoError(t, am.ApplyConfig(ctx, testAlertConfiguration(testGrafanaConfig)))
+		require.Equal(t, "new-silences", localValue(t, store, notifier.SilencesFilename))
+		require.Equal(t, "new-nflog", localValue(t, store, notifier.NotificationLogFilename))
+	})
+
+	t.Run("uploads the local state if the remote Alertmanager has none", func(t *testing.T) {
+		fakeAM, am, store, _ := setup(t)
+
+		require.NoError(t, am.ApplyConfig(ctx, testAlertConfiguration(testGrafanaConfig)))
+		require.Equal(t, encodeState(t, "local-silences", "local-nflog"), fakeAM.getState())
+		require.Equal(t, "local-silences", localValue(t, store, notifier.SilencesFilename))
+	})
+
+	t.Run("saves and applies configurations", func(t *testing.T) {
+		fakeAM, am, _, configStore := setup(t)
+
+		require.NoError(t, am.SaveAndApplyDefaultConfig(ctx))
+		require.True(t, am.Ready())
+		require.True(t, fakeAM.getConfig().Default)
+		stored, err := configStore.GetLatestAlertmanagerConfiguration(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, stored.ConfigurationHash, fakeAM.getConfig().Hash)
+
+		cfg, err := notifier.Load([]byte(testGrafanaConfigWithSecret))
+		require.NoError(t, err)
+		require.NoError(t, am.SaveAndApplyConfig(ctx, cfg))
+		remoteConfig := fakeAM.getConfig()
+		require.False(t, remoteConfig.Default)
+		require.Contains(t, remoteConfig.GrafanaAlertmanagerConfig, `"password":"secret"`)
+
+		// The status holds the configuration and the status of the remote Alertmanager.
+		status := am.GetStatus()
+		require.Equal(t, "webhook", status.Config.Receivers[0].Name)
+		require.Equal(t, "fake", *status.VersionInfo.Version)
+
+		// A configuration that the remote Alertmanager rejects is not applied.
+		fakeAM.setRejectConfig(true)
+		require.Error(t, am.SaveAndApplyConfig(ctx, cfg))
+		require.Equal(t, remoteConfig, fakeAM.getConfig())
+	})
+
+	t.Run("proxies silences", func(t *testing.T) {
+		_, am, _, _ := setup(t)
+
+		silence := genSilence("test")
+		id, err := am.CreateSilence(ctx, &silence)
+		require.NoError(t, err)
+
+		silences, err := am.ListSilences(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+		require.Equal(t, id, *silences[0].ID)
+	})
+
+	t.Run("does not support testing contact points and templates", func(t *testing.T) {
+		_, am, _, _ := setup(t)
+
+		_, err := am.TestReceivers(ctx, apimodels.TestReceiversConfigBodyParams{})
+		require.ErrorIs(t, err, notifier.ErrNotSupported)
+		_, err = am.TestTemplate(ctx, apimodels.TestTemplatesConfigBodyParams{})
+		require.ErrorIs(t, err, notifier.ErrNotSupported)
+	})
+}
+
+func testAlertConfiguration(rawConfig string) *ngmodels.AlertConfiguration {
+	return &ngmodels.AlertConfiguration{
+		AlertmanagerConfiguration: rawConfig,
+		ConfigurationHash:         fmt.Sprintf("%x", md5.Sum([]byte(rawConfig))),
+		ConfigurationVersion:      "v1",
+		OrgID:                     1,
+	}
 }
 
 func TestIntegrationRemoteAlertmanagerApplyConfigOnlyUploadsOnce(t *testing.T) {
@@ -175,7 +326,7 @@ func TestIntegrationRemoteAlertmanagerApplyConfigOnlyUploadsOnce(t *testing.T) {
 	require.NoError(t, err)
 	encodedFullState := base64.StdEncoding.EncodeToString(fullState)
 
-	am, err := NewAlertmanager(cfg, fstore)
+	am, err := NewAlertmanager(cfg, fstore, nil, secretsfakes.NewFakeSecretsService().Decrypt)
 	require.NoError(t, err)
 
 	// We should have no configuration or state at first.
@@ -259,7 +410,7 @@ func TestIntegrationRemoteAlertmanagerSilences(t *testing.T) {
 		TenantID:          tenantID,
 		BasicAuthPassword: password,
 	}
-	am, err := NewAlertmanager(cfg, nil)
+	am, err := NewAlertmanager(cfg, nil, nil, nil)
 	require.NoError(t, err)
 
 	// We should have no silences at first.
@@ -339,7 +490,7 @@ func TestIntegrationRemoteAlertmanagerAlerts(t *testing.T) {
 		TenantID:          tenantID,
 		BasicAuthPassword: password,
 	}
-	am, err := NewAlertmanager(cfg, nil)
+	am, err := NewAlertmanager(cfg, nil, nil, nil)
 	require.NoError(t, err)
 
 	// Wait until the Alertmanager is ready to send alerts.
@@ -405,7 +556,7 @@ func TestIntegrationRemoteAlertmanagerReceivers(t *testing.T) {
 		BasicAuthPassword: password,
 	}
 
-	am, err := NewAlertmanager(cfg, nil)
+	am, err := NewAlertmanager(cfg, nil, nil, nil)
 	require.NoError(t, err)
 
 	// We should start with the default config.
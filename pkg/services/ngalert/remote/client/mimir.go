@@ -50,6 +50,8 @@ type errorResponse struct {
 	Status string `json:"status"`
 	Error1 string `json:"error"`
 	Error2 string `json:"Error"`
+
+	statusCode int
 }
 
 func (e *errorResponse) Error() string {
@@ -60,6 +62,13 @@ func (e *errorResponse) Error() string {
 	return e.Error2
 }
 
+// IsNotFound returns true if the error is a response of the Mimir API saying that the requested object does not exist,
+// for example when no configuration or state was uploaded yet.
+func IsNotFound(err error) bool {
+	var errResponse *errorResponse
+	return errors.As(err, &errResponse) && errResponse.statusCode == http.StatusNotFound
+}
+
 func New(cfg *Config) (*Mimir, error) {
 	rt := &MimirAuthRoundTripper{
 		TenantID: cfg.TenantID,
@@ -132,6 +141,7 @@ func (mc *Mimir) do(ctx context.Context, p, method string, payload io.Reader, ou
 		err = json.Unmarshal(body, errResponse)
 
 		if err == nil && errResponse.Error() != "" {
+			errResponse.statusCode = resp.StatusCode
 			msg := "Error response from the Mimir API"
 			mc.logger.Error(msg, "err", errResponse, "url", r.URL.String(), "method", r.Method, "status", resp.StatusCode)
 			return nil, fmt.Errorf("%s: %w", msg, errResponse)
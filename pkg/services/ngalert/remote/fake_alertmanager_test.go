@@ -0,0 +1,197 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/remote/client"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// fakeRemoteAlertmanager is an in-process stand-in for a remote Alertmanager. It implements the readiness check,
+// the Grafana configuration and state endpoints of the Mimir API, and the parts of the Alertmanager API used by Grafana.
+type fakeRemoteAlertmanager struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mtx          sync.Mutex
+	ready        bool
+	rejectConfig bool
+	config       *client.UserGrafanaConfig
+	state        string
+	silences     []*amv2.GettableSilence
+}
+
+func newFakeRemoteAlertmanager(t *testing.T) *fakeRemoteAlertmanager {
+	t.Helper()
+
+	f := &fakeRemoteAlertmanager{t: t, ready: true}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/alertmanager/-/ready", f.handleReady)
+	mux.HandleFunc("/api/v1/grafana/config", f.handleConfig)
+	mux.HandleFunc("/api/v1/grafana/state", f.handleState)
+	mux.HandleFunc("/alertmanager/api/v2/status", f.handleStatus)
+	mux.HandleFunc("/alertmanager/api/v2/silences", f.handleSilences)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeRemoteAlertmanager) setReady(ready bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.ready = ready
+}
+
+func (f *fakeRemoteAlertmanager) setRejectConfig(reject bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.rejectConfig = reject
+}
+
+func (f *fakeRemoteAlertmanager) getConfig() *client.UserGrafanaConfig {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.config
+}
+
+func (f *fakeRemoteAlertmanager) getState() string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.state
+}
+
+func (f *fakeRemoteAlertmanager) setState(state string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.state = state
+}
+
+func (f *fakeRemoteAlertmanager) handleReady(w http.ResponseWriter, _ *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if !f.ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeRemoteAlertmanager) handleConfig(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		if f.config == nil {
+			f.writeError(w, http.StatusNotFound, "alertmanager storage object not found")
+			return
+		}
+		f.writeSuccess(w, f.config)
+	case http.MethodPost:
+		if f.rejectConfig {
+			f.writeError(w, http.StatusBadRequest, "invalid configuration")
+			return
+		}
+		var config client.UserGrafanaConfig
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f.config = &config
+		f.writeSuccess(w, nil)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeRemoteAlertmanager) handleState(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		if f.state == "" {
+			f.writeError(w, http.StatusNotFound, "alertmanager storage object not found")
+			return
+		}
+		f.writeSuccess(w, client.UserGrafanaState{State: f.state})
+	case http.MethodPost:
+		var state client.UserGrafanaState
+		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f.state = state.State
+		f.writeSuccess(w, nil)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeRemoteAlertmanager) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	clusterStatus := amv2.ClusterStatusStatusReady
+	version := "fake"
+	uptime := strfmt.DateTime(time.Now())
+	f.writeJSON(w, http.StatusOK, amv2.AlertmanagerStatus{
+		Cluster: &amv2.ClusterStatus{Status: &clusterStatus, Peers: []*amv2.PeerStatus{}},
+		Config:  &amv2.AlertmanagerConfig{Original: new(string)},
+		Uptime:  &uptime,
+		VersionInfo: &amv2.VersionInfo{
+			Branch:    &version,
+			BuildDate: &version,
+			BuildUser: &version,
+			GoVersion: &version,
+			Revision:  &version,
+			Version:   &version,
+		},
+	})
+}
+
+func (f *fakeRemoteAlertmanager) handleSilences(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		f.writeJSON(w, http.StatusOK, f.silences)
+	case http.MethodPost:
+		var silence apimodels.PostableSilence
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			f.writeJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		id := util.GenerateShortUID()
+		state := amv2.SilenceStatusStateActive
+		updatedAt := strfmt.DateTime(time.Now())
+		f.silences = append(f.silences, &amv2.GettableSilence{
+			ID:        &id,
+			Status:    &amv2.SilenceStatus{State: &state},
+			UpdatedAt: &updatedAt,
+			Silence:   silence.Silence,
+		})
+		f.writeJSON(w, http.StatusOK, map[string]string{"silenceID": id})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeRemoteAlertmanager) writeSuccess(w http.ResponseWriter, data any) {
+	f.writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": data})
+}
+
+func (f *fakeRemoteAlertmanager) writeError(w http.ResponseWriter, status int, msg string) {
+	f.writeJSON(w, status, map[string]any{"status": "error", "error": msg})
+}
+
+func (f *fakeRemoteAlertmanager) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(f.t, json.NewEncoder(w).Encode(v))
+}
@@ -335,6 +335,12 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	} else {
 		writeInt(0)
 	}
+	for _, d := range rule.Dependencies {
+		writeString(d.RuleUID)
+		for _, m := range d.Matchers {
+			writeString(m)
+		}
+	}
 
 	// fields that do not affect the state.
 	// TODO consider removing fields below from the fingerprint
@@ -415,6 +415,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				"key-label": "value-label",
 			},
 			IsPaused: false,
+			Dependencies: []models.RuleDependency{
+				{RuleUID: "upstream"},
+			},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -450,11 +453,15 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				"key-label": "value-label23",
 			},
 			IsPaused: true,
+			Dependencies: []models.RuleDependency{
+				{RuleUID: "upstream-2", Matchers: []string{`region="eu"`}},
+			},
 		}
 
 		excludedFields := map[string]struct{}{
-			"Version": {},
-			"Updated": {},
+			"Version":   {},
+			"Updated":   {},
+			"UpdatedBy": {},
 		}
 
 		tp := reflect.TypeOf(rule).Elem()
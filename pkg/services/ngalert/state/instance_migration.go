@@ -0,0 +1,49 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// MigrateInstances moves all alert instances from one instance store to a rule instance store, one rule at a time.
+// The instances of a rule are deleted from the source store once they are saved, so an interrupted migration
+// continues where it stopped, and a completed migration does nothing. It replaces the instances that the
+// destination store has for the same rules.
+func MigrateInstances(ctx context.Context, from InstanceStore, to RuleInstanceStore, logger log.Logger) error {
+	orgIDs, err := from.FetchOrgIds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch organizations: %w", err)
+	}
+
+	migrated := 0
+	for _, orgID := range orgIDs {
+		instances, err := from.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		if err != nil {
+			return fmt.Errorf("failed to list alert instances of organization %d: %w", orgID, err)
+		}
+
+		byRule := make(map[models.AlertRuleKey][]models.AlertInstance)
+		for _, instance := range instances {
+			key := models.AlertRuleKey{OrgID: instance.RuleOrgID, UID: instance.RuleUID}
+			byRule[key] = append(byRule[key], *instance)
+		}
+
+		for key, ruleInstances := range byRule {
+			if err := to.SaveAlertInstancesForRule(ctx, key, ruleInstances); err != nil {
+				return fmt.Errorf("failed to save alert instances of rule %s: %w", key.UID, err)
+			}
+			if err := from.DeleteAlertInstancesByRule(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete migrated alert instances of rule %s: %w", key.UID, err)
+			}
+			migrated += len(ruleInstances)
+		}
+	}
+
+	if migrated > 0 {
+		logger.Info("Migrated alert instances", "instances", migrated)
+	}
+	return nil
+}
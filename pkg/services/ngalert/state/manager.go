@@ -8,6 +8,7 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/grafana/dskit/concurrency"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
@@ -39,9 +40,11 @@ type Manager struct {
 	ResendDelay time.Duration
 
 	instanceStore InstanceStore
-	images        ImageCapturer
-	historian     Historian
-	externalURL   *url.URL
+	// ruleInstanceStore is set if the instance store saves all instances of a rule at once.
+	ruleInstanceStore RuleInstanceStore
+	images            ImageCapturer
+	historian         Historian
+	externalURL       *url.URL
 
 	doNotSaveNormalState           bool
 	maxStateSaveConcurrency        int
@@ -91,6 +94,10 @@ func NewManager(cfg ManagerCfg) *Manager {
 		tracer:                         cfg.Tracer,
 	}
 
+	if s, ok := cfg.InstanceStore.(RuleInstanceStore); ok {
+		m.ruleInstanceStore = s
+	}
+
 	if m.applyNoDataAndErrorToAllStates {
 		m.log.Info("Running in alternative execution of Error/NoData mode")
 	}
@@ -269,7 +276,9 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	))
 
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
-	st.deleteAlertStates(tracingCtx, logger, staleStates)
+	if st.ruleInstanceStore == nil {
+		st.deleteAlertStates(tracingCtx, logger, staleStates)
+	}
 
 	if len(staleStates) > 0 {
 		span.AddEvent("deleted stale states", trace.WithAttributes(
@@ -277,7 +286,11 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 		))
 	}
 
-	st.saveAlertStates(tracingCtx, logger, states...)
+	if st.ruleInstanceStore != nil {
+		st.saveRuleStates(tracingCtx, logger, alertRule.GetKey())
+	} else {
+		st.saveAlertStates(tracingCtx, logger, states...)
+	}
 	span.AddEvent("updated database")
 
 	allChanges := append(states, staleStates...)
@@ -288,16 +301,17 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 }
 
 func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) []StateTransition {
+	suppressedBy := st.alertingUpstreamRule(alertRule, logger)
 	if st.applyNoDataAndErrorToAllStates && results.IsNoData() && (alertRule.NoDataState == ngModels.Alerting || alertRule.NoDataState == ngModels.OK) { // If it is no data, check the mapping and switch all results to the new state
 		// TODO aggregate UID of datasources that returned NoData into one and provide as auxiliary info, probably annotation
-		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
+		transitions := st.setNextStateForAll(ctx, alertRule, results[0], suppressedBy, logger)
 		if len(transitions) > 0 {
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
 	}
 	if st.applyNoDataAndErrorToAllStates && results.IsError() && (alertRule.ExecErrState == ngModels.AlertingErrState || alertRule.ExecErrState == ngModels.OkErrState) {
 		// TODO squash all errors into one, and provide as annotation
-		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
+		transitions := st.setNextStateForAll(ctx, alertRule, results[0], suppressedBy, logger)
 		if len(transitions) > 0 {
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
@@ -305,24 +319,51 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 	transitions := make([]StateTransition, 0, len(results))
 	for _, result := range results {
 		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
-		s := st.setNextState(ctx, alertRule, currentState, result, logger)
+		s := st.setNextState(ctx, alertRule, currentState, result, suppressedBy, logger)
 		transitions = append(transitions, s)
 	}
 	return transitions
 }
 
-func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger) []StateTransition {
+func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, suppressedBy string, logger log.Logger) []StateTransition {
 	currentStates := st.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID, false)
 	transitions := make([]StateTransition, 0, len(currentStates))
 	for _, currentState := range currentStates {
-		t := st.setNextState(ctx, alertRule, currentState, result, logger)
+		t := st.setNextState(ctx, alertRule, currentState, result, suppressedBy, logger)
 		transitions = append(transitions, t)
 	}
 	return transitions
 }
 
-// Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, logger log.Logger) StateTransition {
+// alertingUpstreamRule returns the UID of the first upstream rule of the alert rule that has a firing instance that
+// matches the matchers of the dependency, or an empty string if there is none. Suppressed instances are firing too,
+// so that a rule is suppressed while any rule upstream of it in a chain of dependencies is firing.
+func (st *Manager) alertingUpstreamRule(alertRule *ngModels.AlertRule, logger log.Logger) string {
+	for _, d := range alertRule.Dependencies {
+		matchers, err := d.ParseMatchers()
+		if err != nil {
+			logger.Warn("Ignoring invalid dependency", "upstream", d.RuleUID, "error", err)
+			continue
+		}
+		for _, s := range st.cache.getStatesForRuleUID(alertRule.OrgID, d.RuleUID, true) {
+			if s.State != eval.Alerting && s.State != eval.Suppressed {
+				continue
+			}
+			lset := make(model.LabelSet, len(s.Labels))
+			for name, value := range s.Labels {
+				lset[model.LabelName(name)] = model.LabelValue(value)
+			}
+			if matchers.Matches(lset) {
+				return d.RuleUID
+			}
+		}
+	}
+	return ""
+}
+
+// Set the current state based on evaluation results. Instances that would be Alerting are Suppressed instead if suppressedBy,
+// the UID of a firing upstream rule, is not empty.
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, suppressedBy string, logger log.Logger) StateTransition {
 	start := st.clock.Now()
 	currentState.LastEvaluationTime = result.EvaluatedAt
 	currentState.EvaluationDuration = result.EvaluationDuration
@@ -361,6 +402,11 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		}
 	}
 
+	// Suppressed instances are Alerting again once no upstream rule is firing, and are handled as such.
+	if currentState.State == eval.Suppressed && suppressedBy == "" {
+		currentState.State = eval.Alerting
+	}
+
 	switch result.State {
 	case eval.Normal:
 		logger.Debug("Setting next state", "handler", "resultNormal")
@@ -387,9 +433,21 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		currentState.StateReason = result.State.String()
 	}
 
+	// Suppressed instances are kept by resultAlerting, resultError and resultNoData while an upstream rule is firing.
+	if currentState.State == eval.Suppressed {
+		currentState.State = eval.Alerting
+	}
+	if currentState.State == eval.Alerting && suppressedBy != "" {
+		logger.Debug("Suppressing state because an upstream rule is firing", "upstream", suppressedBy)
+		currentState.State = eval.Suppressed
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
-	// to Alertmanager.
-	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
+	// to Alertmanager. Alerts that are suppressed are resolved too, as they are no longer sent.
+	currentState.Resolved = oldState == eval.Alerting && (currentState.State == eval.Normal || currentState.State == eval.Suppressed)
+	if currentState.Resolved && currentState.State == eval.Suppressed {
+		currentState.EndsAt = result.EvaluatedAt
+	}
 
 	if shouldTakeImage(currentState.State, oldState, currentState.Image, currentState.Resolved) {
 		image, err := takeImage(ctx, st.images, alertRule)
@@ -445,20 +503,11 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 			return nil
 		}
 
-		key, err := s.GetAlertInstanceKey()
+		instance, err := newAlertInstance(s.State)
 		if err != nil {
 			logger.Error("Failed to create a key for alert state to save it to database. The state will be ignored ", "cacheID", s.CacheID, "error", err, "labels", s.Labels.String())
 			return nil
 		}
-		instance := ngModels.AlertInstance{
-			AlertInstanceKey:  key,
-			Labels:            ngModels.InstanceLabels(s.Labels),
-			CurrentState:      ngModels.InstanceStateType(s.State.State.String()),
-			CurrentReason:     s.StateReason,
-			LastEvalTime:      s.LastEvaluationTime,
-			CurrentStateSince: s.StartsAt,
-			CurrentStateEnd:   s.EndsAt,
-		}
 
 		err = st.instanceStore.SaveAlertInstance(ctx, instance)
 		if err != nil {
@@ -474,6 +523,43 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 	logger.Debug("Saving alert states done", "count", len(states), "max_state_save_concurrency", st.maxStateSaveConcurrency, "duration", time.Since(start))
 }
 
+// saveRuleStates saves all current states of the rule at once to the rule instance store.
+func (st *Manager) saveRuleStates(ctx context.Context, logger log.Logger, key ngModels.AlertRuleKey) {
+	states := st.cache.getStatesForRuleUID(key.OrgID, key.UID, st.doNotSaveNormalState)
+	instances := make([]ngModels.AlertInstance, 0, len(states))
+	for _, s := range states {
+		instance, err := newAlertInstance(s)
+		if err != nil {
+			logger.Error("Failed to create a key for alert state to save it to database. The state will be ignored ", "cacheID", s.CacheID, "error", err, "labels", s.Labels.String())
+			continue
+		}
+		instances = append(instances, instance)
+	}
+
+	start := time.Now()
+	if err := st.ruleInstanceStore.SaveAlertInstancesForRule(ctx, key, instances); err != nil {
+		logger.Error("Failed to save alert states", "count", len(instances), "error", err)
+		return
+	}
+	logger.Debug("Saving alert states done", "count", len(instances), "duration", time.Since(start))
+}
+
+func newAlertInstance(s *State) (ngModels.AlertInstance, error) {
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return ngModels.AlertInstance{}, err
+	}
+	return ngModels.AlertInstance{
+		AlertInstanceKey:  key,
+		Labels:            ngModels.InstanceLabels(s.Labels),
+		CurrentState:      ngModels.InstanceStateType(s.State.String()),
+		CurrentReason:     s.StateReason,
+		LastEvalTime:      s.LastEvaluationTime,
+		CurrentStateSince: s.StartsAt,
+		CurrentStateEnd:   s.EndsAt,
+	}, nil
+}
+
 func (st *Manager) deleteAlertStates(ctx context.Context, logger log.Logger, states []StateTransition) {
 	if st.instanceStore == nil || len(states) == 0 {
 		return
@@ -509,6 +595,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.NoData
 	case ngModels.InstanceStatePending:
 		return eval.Pending
+	case ngModels.InstanceStateSuppressed:
+		return eval.Suppressed
 	default:
 		return eval.Error
 	}
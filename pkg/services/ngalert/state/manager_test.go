@@ -1344,6 +1344,51 @@ func TestProcessEvalResults(t *testing.T) {
 			require.Contains(t, savedStates, s.CacheID)
 		}
 	})
+
+	t.Run("should save all states of the rule at once to a rule instance store", func(t *testing.T) {
+		instanceStore := &state.FakeRuleInstanceStore{}
+		clk := clock.NewMock()
+		cfg := state.ManagerCfg{
+			Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			ExternalURL:             nil,
+			InstanceStore:           instanceStore,
+			Images:                  &state.NotAvailableImageService{},
+			Clock:                   clk,
+			Historian:               &state.FakeHistorian{},
+			MaxStateSaveConcurrency: 1,
+			Tracer:                  tracing.InitializeTracerForTest(),
+			Log:                     log.New("ngalert.state.manager"),
+		}
+		st := state.NewManager(cfg)
+		rule := models.AlertRuleGen(models.WithInterval(10 * time.Second))()
+
+		results := eval.GenerateResults(2, eval.ResultGen(eval.WithEvaluatedAt(clk.Now())))
+		st.ProcessEvalResults(context.Background(), clk.Now(), rule, results, make(data.Labels))
+
+		// the first results become stale after two missed evaluations
+		clk.Add(30 * time.Second)
+		results = eval.GenerateResults(1, eval.ResultGen(eval.WithEvaluatedAt(clk.Now())))
+		transitions := st.ProcessEvalResults(context.Background(), clk.Now(), rule, results, make(data.Labels))
+		require.Len(t, transitions, 3)
+
+		var saved [][]models.AlertInstance
+		for _, op := range instanceStore.RecordedOps {
+			switch q := op.(type) {
+			case state.FakeInstanceStoreOp:
+				require.Equal(t, "SaveAlertInstancesForRule", q.Name)
+				require.Equal(t, rule.GetKey(), q.Args[0])
+				saved = append(saved, q.Args[1].([]models.AlertInstance))
+			default:
+				require.Failf(t, "unexpected operation", "%v", op)
+			}
+		}
+		require.Len(t, saved, 2)
+		require.Len(t, saved[0], 2)
+		require.Len(t, saved[1], 1)
+		cacheID, err := saved[1][0].Labels.StringKey()
+		require.NoError(t, err)
+		require.Equal(t, transitions[0].CacheID, cacheID)
+	})
 }
 
 func printAllAnnotations(annos map[int64]annotations.Item) string {
@@ -1633,6 +1678,190 @@ func TestStaleResults(t *testing.T) {
 	})
 }
 
+func TestUpstreamDependencySuppression(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:           &state.FakeInstanceStore{},
+		Images:                  &state.NoopImageService{},
+		Clock:                   clk,
+		Historian:               &state.FakeHistorian{},
+		MaxStateSaveConcurrency: 1,
+		Tracer:                  tracing.InitializeTracerForTest(),
+		Log:                     log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg)
+
+	upstream := models.AlertRuleGen(models.WithOrgID(1), models.WithFor(0), models.WithInterval(time.Minute))()
+	downstream := models.AlertRuleGen(models.WithOrgID(1), models.WithFor(0), models.WithInterval(time.Minute))()
+	downstream.Dependencies = []models.RuleDependency{{RuleUID: upstream.UID, Matchers: []string{`region="eu"`}}}
+
+	evaluate := func(rule *models.AlertRule, state eval.State, labels data.Labels) state.StateTransition {
+		t.Helper()
+		results := eval.Results{eval.ResultGen(eval.WithState(state), eval.WithLabels(labels), eval.WithEvaluatedAt(clk.Now()))()}
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, results, nil)
+		// the first transition is the evaluated instance, the others are stale instances
+		require.NotEmpty(t, transitions)
+		return transitions[0]
+	}
+
+	t.Run("should be alerting while upstream is normal", func(t *testing.T) {
+		evaluate(upstream, eval.Normal, data.Labels{"region": "eu"})
+		s := evaluate(downstream, eval.Alerting, data.Labels{"service": "api"})
+		require.Equal(t, eval.Alerting, s.State.State)
+		require.True(t, s.NeedsSending(0))
+	})
+
+	t.Run("should be alerting while upstream instances that do not match are alerting", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(upstream, eval.Alerting, data.Labels{"region": "us"})
+		s := evaluate(downstream, eval.Alerting, data.Labels{"service": "api"})
+		require.Equal(t, eval.Alerting, s.State.State)
+	})
+
+	var startsAt time.Time
+	t.Run("should be suppressed while upstream is alerting", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(upstream, eval.Alerting, data.Labels{"region": "eu"})
+		s := evaluate(downstream, eval.Alerting, data.Labels{"service": "api"})
+		require.Equal(t, eval.Suppressed, s.State.State)
+		require.Equal(t, eval.Alerting, s.PreviousState)
+		startsAt = s.StartsAt
+
+		// the alert is resolved in the Alertmanager once, and not sent while it's suppressed
+		require.True(t, s.Resolved)
+		require.True(t, s.NeedsSending(0))
+		require.Equal(t, clk.Now(), s.EndsAt)
+		clk.Add(time.Minute)
+		evaluate(upstream, eval.Alerting, data.Labels{"region": "eu"})
+		s = evaluate(downstream, eval.Alerting, data.Labels{"service": "api"})
+		require.Equal(t, eval.Suppressed, s.State.State)
+		require.False(t, s.Resolved)
+		require.False(t, s.NeedsSending(0))
+	})
+
+	t.Run("should be alerting again once upstream is normal", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(upstream, eval.Normal, data.Labels{"region": "eu"})
+		s := evaluate(downstream, eval.Alerting, data.Labels{"service": "api"})
+		require.Equal(t, eval.Alerting, s.State.State)
+		require.Equal(t, eval.Suppressed, s.PreviousState)
+		require.Equal(t, startsAt, s.StartsAt)
+	})
+
+	t.Run("should be normal if the condition is not met", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(upstream, eval.Alerting, data.Labels{"region": "eu"})
+		s := evaluate(downstream, eval.Normal, data.Labels{"service": "api"})
+		require.Equal(t, eval.Normal, s.State.State)
+	})
+
+	t.Run("should handle error and no data results of suppressed instances", func(t *testing.T) {
+		testCases := []struct {
+			desc            string
+			execErrState    models.ExecutionErrorState
+			noDataState     models.NoDataState
+			result          eval.State
+			upstream        eval.State
+			expectedState   eval.State
+			expectedReason  string
+			keepsStartsAt   bool
+			expectedSending bool
+		}{
+			{
+				desc:          "error results keep the instance suppressed while upstream is alerting",
+				execErrState:  models.ErrorErrState,
+				result:        eval.Error,
+				upstream:      eval.Alerting,
+				expectedState: eval.Suppressed, expectedReason: eval.Error.String(), keepsStartsAt: true,
+			},
+			{
+				desc:          "error results with the Alerting error state keep the instance suppressed while upstream is alerting",
+				execErrState:  models.AlertingErrState,
+				result:        eval.Error,
+				upstream:      eval.Alerting,
+				expectedState: eval.Suppressed, expectedReason: models.StateReasonError, keepsStartsAt: true,
+			},
+			{
+				desc:          "no data results keep the instance suppressed while upstream is alerting",
+				noDataState:   models.NoData,
+				result:        eval.NoData,
+				upstream:      eval.Alerting,
+				expectedState: eval.Suppressed, expectedReason: eval.NoData.String(), keepsStartsAt: true,
+			},
+			{
+				desc:          "error results change the state once upstream is normal",
+				execErrState:  models.ErrorErrState,
+				result:        eval.Error,
+				upstream:      eval.Normal,
+				expectedState: eval.Error, expectedReason: "", expectedSending: true,
+			},
+			{
+				desc:          "no data results change the state once upstream is normal",
+				noDataState:   models.NoData,
+				result:        eval.NoData,
+				upstream:      eval.Normal,
+				expectedState: eval.NoData, expectedReason: "", expectedSending: true,
+			},
+			{
+				desc:          "normal results are not resolved again",
+				result:        eval.Normal,
+				upstream:      eval.Normal,
+				expectedState: eval.Normal, expectedReason: "",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				rule := models.AlertRuleGen(models.WithOrgID(1), models.WithFor(0), models.WithInterval(time.Minute))()
+				rule.Dependencies = []models.RuleDependency{{RuleUID: upstream.UID}}
+				if tc.execErrState != "" {
+					rule.ExecErrState = tc.execErrState
+				}
+				if tc.noDataState != "" {
+					rule.NoDataState = tc.noDataState
+				}
+
+				clk.Add(time.Minute)
+				evaluate(upstream, eval.Normal, data.Labels{"region": "eu"})
+				alerting := evaluate(rule, eval.Alerting, data.Labels{"service": "api"})
+				require.Equal(t, eval.Alerting, alerting.State.State)
+				startsAt := alerting.StartsAt
+
+				clk.Add(time.Minute)
+				evaluate(upstream, eval.Alerting, data.Labels{"region": "eu"})
+				require.Equal(t, eval.Suppressed, evaluate(rule, eval.Alerting, data.Labels{"service": "api"}).State.State)
+
+				clk.Add(time.Minute)
+				evaluate(upstream, tc.upstream, data.Labels{"region": "eu"})
+				s := evaluate(rule, tc.result, data.Labels{"service": "api"})
+				require.Equal(t, tc.expectedState, s.State.State)
+				require.Equal(t, eval.Suppressed, s.PreviousState)
+				require.Equal(t, tc.expectedReason, s.StateReason)
+				require.False(t, s.Resolved)
+				require.Equal(t, tc.expectedSending, s.NeedsSending(0))
+				if tc.keepsStartsAt {
+					require.Equal(t, startsAt, s.StartsAt)
+				} else {
+					require.Equal(t, clk.Now(), s.StartsAt)
+				}
+			})
+		}
+	})
+
+	t.Run("should be suppressed while a suppressed upstream is firing", func(t *testing.T) {
+		third := models.AlertRuleGen(models.WithOrgID(1), models.WithFor(0), models.WithInterval(time.Minute))()
+		third.Dependencies = []models.RuleDependency{{RuleUID: downstream.UID}}
+
+		clk.Add(time.Minute)
+		evaluate(upstream, eval.Alerting, data.Labels{"region": "eu"})
+		require.Equal(t, eval.Suppressed, evaluate(downstream, eval.Alerting, data.Labels{"service": "api"}).State.State)
+		s := evaluate(third, eval.Alerting, data.Labels{"service": "web"})
+		require.Equal(t, eval.Suppressed, s.State.State)
+	})
+}
+
 func TestDeleteStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
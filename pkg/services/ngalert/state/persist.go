@@ -16,6 +16,13 @@ type InstanceStore interface {
 	DeleteAlertInstancesByRule(ctx context.Context, key models.AlertRuleKey) error
 }
 
+// RuleInstanceStore is an InstanceStore that stores all instances of a rule together. The state manager saves all
+// current instances of a rule at once after each evaluation instead of saving and deleting single instances.
+type RuleInstanceStore interface {
+	InstanceStore
+	SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error
+}
+
 // RuleReader represents the ability to fetch alert rules.
 type RuleReader interface {
 	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
@@ -208,7 +208,7 @@ func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger
 
 func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	switch state.State {
-	case eval.Alerting:
+	case eval.Alerting, eval.Suppressed:
 		prevEndsAt := state.EndsAt
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 		logger.Debug("Keeping state",
@@ -270,7 +270,8 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 		state.Error = result.Error
 		state.StateReason = models.StateReasonError
 	case models.ErrorErrState:
-		if state.State == eval.Error {
+		// Suppressed instances are kept while an upstream rule is firing, the error is likely caused by it
+		if state.State == eval.Error || state.State == eval.Suppressed {
 			prevEndsAt := state.EndsAt
 			state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 			logger.Debug("Keeping state",
@@ -328,7 +329,8 @@ func resultNoData(state *State, rule *models.AlertRule, result eval.Result, logg
 		resultAlerting(state, rule, result, logger)
 		state.StateReason = models.StateReasonNoData
 	case models.NoData:
-		if state.State == eval.NoData {
+		// Suppressed instances are kept while an upstream rule is firing, the missing data is likely caused by it
+		if state.State == eval.NoData || state.State == eval.Suppressed {
 			prevEndsAt := state.EndsAt
 			state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 			logger.Debug("Keeping state",
@@ -368,6 +370,9 @@ func (a *State) NeedsSending(resendDelay time.Duration) bool {
 	case eval.Pending:
 		// We do not send notifications for pending states
 		return false
+	case eval.Suppressed:
+		// We do not send notifications for suppressed states, except to resolve the alert once it's suppressed
+		return a.Resolved
 	case eval.Normal:
 		// We should send a notification if the state is Normal because it was resolved
 		return a.Resolved
@@ -53,6 +53,25 @@ func (f *FakeInstanceStore) DeleteAlertInstancesByRule(ctx context.Context, key
 	return nil
 }
 
+var _ RuleInstanceStore = &FakeRuleInstanceStore{}
+
+// FakeRuleInstanceStore is a FakeInstanceStore that also saves all instances of a rule at once.
+type FakeRuleInstanceStore struct {
+	FakeInstanceStore
+}
+
+func (f *FakeRuleInstanceStore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, FakeInstanceStoreOp{
+		Name: "SaveAlertInstancesForRule", Args: []any{
+			key,
+			instances,
+		},
+	})
+	return nil
+}
+
 type FakeRuleReader struct{}
 
 func (f *FakeRuleReader) ListAlertRules(_ context.Context, q *models.ListAlertRulesQuery) (models.RulesGroup, error) {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -67,6 +68,14 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("Deleted alert instances", "count", rows)
+
+		for _, uid := range ruleUID {
+			sess.PublishAfterCommit(&events.AlertRuleDeleted{
+				Timestamp: TimeNow(),
+				UID:       uid,
+				OrgID:     orgID,
+			})
+		}
 		return nil
 	})
 }
@@ -116,6 +125,37 @@ func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmode
 	return result, err
 }
 
+// GetAlertRuleVersions returns all stored versions of an alert rule, the most recent first.
+func (st DBstore) GetAlertRuleVersions(ctx context.Context, query *ngmodels.GetAlertRuleVersionsQuery) (result []*ngmodels.AlertRuleVersion, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		versions := make([]*ngmodels.AlertRuleVersion, 0)
+		if err := sess.Table("alert_rule_version").Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.RuleUID).Desc("version").Find(&versions); err != nil {
+			return err
+		}
+		result = versions
+		return nil
+	})
+	return result, err
+}
+
+// GetAlertRuleVersion returns a specific version of an alert rule.
+// Returns models.ErrAlertRuleVersionNotFound if the version does not exist.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) (result *ngmodels.AlertRuleVersion, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		version := ngmodels.AlertRuleVersion{}
+		has, err := sess.Table("alert_rule_version").Where("rule_org_id = ? AND rule_uid = ? AND version = ?", query.OrgID, query.RuleUID, query.Version).Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		result = &version
+		return nil
+	})
+	return result, err
+}
+
 // InsertAlertRules is a handler for creating/updating alert rules.
 // Returns the UID and ID of rules that were created in the same order as the input rules.
 func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRule) ([]ngmodels.AlertRuleKeyWithId, error) {
@@ -145,6 +185,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				RuleOrgID:        r.OrgID,
 				RuleNamespaceUID: r.NamespaceUID,
 				RuleGroup:        r.RuleGroup,
+				RuleGroupIndex:   r.RuleGroupIndex,
 				ParentVersion:    0,
 				Version:          r.Version,
 				Created:          r.Updated,
@@ -157,6 +198,9 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				For:              r.For,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				IsPaused:         r.IsPaused,
+				CreatedBy:        r.UpdatedBy,
+				Dependencies:     r.Dependencies,
 			})
 		}
 		if len(newRules) > 0 {
@@ -173,6 +217,14 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 					AlertRuleKey: newRules[i].GetKey(),
 					ID:           newRules[i].ID,
 				})
+				sess.PublishAfterCommit(&events.AlertRuleCreated{
+					Timestamp:    newRules[i].Updated,
+					Title:        newRules[i].Title,
+					UID:          newRules[i].UID,
+					OrgID:        newRules[i].OrgID,
+					NamespaceUID: newRules[i].NamespaceUID,
+					RuleGroup:    newRules[i].RuleGroup,
+				})
 			}
 		}
 
@@ -181,7 +233,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				return fmt.Errorf("failed to create new rule versions: %w", err)
 			}
 		}
-		return nil
+		return validateRuleDependencies(sess, newRules)
 	})
 }
 
@@ -215,6 +267,15 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
 			parentVersion = r.Existing.Version
+			sess.PublishAfterCommit(&events.AlertRuleUpdated{
+				Timestamp:    r.New.Updated,
+				Title:        r.New.Title,
+				UID:          r.New.UID,
+				OrgID:        r.New.OrgID,
+				NamespaceUID: r.New.NamespaceUID,
+				RuleGroup:    r.New.RuleGroup,
+				Version:      r.New.Version + 1,
+			})
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
 				RuleOrgID:        r.New.OrgID,
 				RuleUID:          r.New.UID,
@@ -233,6 +294,9 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				For:              r.New.For,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				IsPaused:         r.New.IsPaused,
+				CreatedBy:        r.New.UpdatedBy,
+				Dependencies:     r.New.Dependencies,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -240,10 +304,39 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				return fmt.Errorf("failed to create new rule versions: %w", err)
 			}
 		}
-		return nil
+		updated := make([]ngmodels.AlertRule, 0, len(rules))
+		for _, r := range rules {
+			updated = append(updated, r.New)
+		}
+		return validateRuleDependencies(sess, updated)
 	})
 }
 
+// validateRuleDependencies checks that the saved rules only depend on rules of their organization, and that the
+// dependencies of all rules in the organizations of the saved rules do not form a cycle. Only saved rules with
+// dependencies can introduce a cycle, so other organizations are not checked.
+func validateRuleDependencies(sess *db.Session, saved []ngmodels.AlertRule) error {
+	byOrg := make(map[int64][]ngmodels.AlertRule)
+	for _, r := range saved {
+		if len(r.Dependencies) > 0 {
+			byOrg[r.OrgID] = append(byOrg[r.OrgID], r)
+		}
+	}
+	for orgID, orgSaved := range byOrg {
+		var rules []*ngmodels.AlertRule
+		if err := sess.Table(ngmodels.AlertRule{}).Cols("uid", "dependencies").Where("org_id = ?", orgID).Find(&rules); err != nil {
+			return fmt.Errorf("failed to get dependencies of alert rules: %w", err)
+		}
+		if err := ngmodels.ValidateDependenciesExist(rules, orgSaved); err != nil {
+			return err
+		}
+		if err := ngmodels.ValidateRuleDependencies(rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // preventIntermediateUniqueConstraintViolations prevents unique constraint violations caused by an intermediate update.
 // The uniqueness constraint for titles within an org+folder is enforced on every update within a transaction
 // instead of on commit (deferred constraint). This means that there could be a set of updates that will throw
@@ -572,6 +572,132 @@ func TestIntegrationInsertAlertRules(t *testing.T) {
 	}
 }
 
+func TestIntegrationAlertRuleVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	rule := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))()
+	rule.UpdatedBy = "creator"
+	ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+	require.NoError(t, err)
+	rule.ID = ids[0].ID
+	rule.UID = ids[0].UID
+	rule.Version = 1
+
+	updated := models.CopyRule(rule)
+	updated.Title = util.GenerateShortUID()
+	updated.IsPaused = !rule.IsPaused
+	updated.UpdatedBy = "editor"
+	require.NoError(t, store.UpdateAlertRules(context.Background(), []models.UpdateRule{{Existing: rule, New: *updated}}))
+
+	t.Run("should list versions of the rule, the most recent first", func(t *testing.T) {
+		versions, err := store.GetAlertRuleVersions(context.Background(), &models.GetAlertRuleVersionsQuery{OrgID: 1, RuleUID: rule.UID})
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, int64(2), versions[0].Version)
+		require.Equal(t, int64(1), versions[0].ParentVersion)
+		require.Equal(t, updated.Title, versions[0].Title)
+		require.Equal(t, updated.IsPaused, versions[0].IsPaused)
+		require.Equal(t, "editor", versions[0].CreatedBy)
+		require.Equal(t, int64(1), versions[1].Version)
+		require.Equal(t, "creator", versions[1].CreatedBy)
+	})
+
+	t.Run("should get a version of the rule", func(t *testing.T) {
+		version, err := store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{OrgID: 1, RuleUID: rule.UID, Version: 1})
+		require.NoError(t, err)
+		versionRule := version.ToAlertRule()
+		// the generator does not keep the dashboard and panel in sync with the annotations
+		require.Empty(t, rule.Diff(&versionRule, append(AlertRuleFieldsToIgnoreInDiff[:], "DashboardUID", "PanelID")...))
+
+		_, err = store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{OrgID: 1, RuleUID: rule.UID, Version: 3})
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+
+		_, err = store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{OrgID: 2, RuleUID: rule.UID, Version: 1})
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+	})
+}
+
+func TestIntegrationAlertRuleDependencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	gen := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))
+	upstream, downstream := gen(), gen()
+	downstream.Dependencies = []models.RuleDependency{{RuleUID: upstream.UID, Matchers: []string{`region="eu"`}}}
+	ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*upstream, *downstream})
+	require.NoError(t, err)
+	upstream.ID, downstream.ID = ids[0].ID, ids[1].ID
+	upstream.Version, downstream.Version = 1, 1
+
+	t.Run("should store dependencies", func(t *testing.T) {
+		rule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: downstream.UID})
+		require.NoError(t, err)
+		require.Equal(t, downstream.Dependencies, rule.Dependencies)
+	})
+
+	t.Run("should reject an update that creates a cycle", func(t *testing.T) {
+		updated := models.CopyRule(upstream)
+		updated.Dependencies = []models.RuleDependency{{RuleUID: downstream.UID}}
+		err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{Existing: upstream, New: *updated}})
+		require.ErrorIs(t, err, models.ErrAlertRuleDependencyCycle)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		rule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: upstream.UID})
+		require.NoError(t, err)
+		require.Empty(t, rule.Dependencies)
+	})
+
+	t.Run("should reject an insert that creates a cycle", func(t *testing.T) {
+		a, b := gen(), gen()
+		a.Dependencies = []models.RuleDependency{{RuleUID: b.UID}}
+		b.Dependencies = []models.RuleDependency{{RuleUID: a.UID}}
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*a, *b})
+		require.ErrorIs(t, err, models.ErrAlertRuleDependencyCycle)
+	})
+
+	t.Run("should reject dependencies on unknown rules", func(t *testing.T) {
+		rule := gen()
+		rule.Dependencies = []models.RuleDependency{{RuleUID: "unknown"}}
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.ErrorIs(t, err, models.ErrAlertRuleUnknownDependency)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject dependencies on rules of other organizations", func(t *testing.T) {
+		other := models.AlertRuleGen(models.WithOrgID(2), withIntervalMatching(store.Cfg.BaseInterval))()
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*other})
+		require.NoError(t, err)
+
+		updated := models.CopyRule(downstream)
+		updated.Dependencies = []models.RuleDependency{{RuleUID: other.UID}}
+		err = store.UpdateAlertRules(context.Background(), []models.UpdateRule{{Existing: downstream, New: *updated}})
+		require.ErrorIs(t, err, models.ErrAlertRuleUnknownDependency)
+	})
+}
+
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
 	t.Helper()
 	if generate == nil {
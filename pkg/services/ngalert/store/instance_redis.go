@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const redisInstancesKey = "alert_instances"
+
+type RedisInstanceStoreConfig struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	// Prefix is prepended to all keys, so that several Grafana installations can share a Redis database.
+	Prefix string
+}
+
+// RedisInstanceStore is an instance store that keeps the alert instances of a rule in a Redis hash, with one field
+// per alert instance. The keys of the hashes are <prefix>alert_instances:<org ID>:<rule UID>.
+type RedisInstanceStore struct {
+	client *redis.Client
+	prefix string
+	logger log.Logger
+}
+
+func NewRedisInstanceStore(cfg RedisInstanceStoreConfig, logger log.Logger) *RedisInstanceStore {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		logger.Error("Failed to ping redis - the state of alert rules may not be persisted", "error", err)
+	}
+
+	// Make sure that the prefix uses a colon at the end as deliminator.
+	prefix := cfg.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, ":") {
+		prefix = prefix + ":"
+	}
+	return &RedisInstanceStore{
+		client: client,
+		prefix: prefix,
+		logger: logger,
+	}
+}
+
+func (st *RedisInstanceStore) ruleKey(orgID int64, ruleUID string) string {
+	return fmt.Sprintf("%s%s:%d:%s", st.prefix, redisInstancesKey, orgID, ruleUID)
+}
+
+// parseRuleKey returns the organization and rule of a key created by ruleKey.
+func (st *RedisInstanceStore) parseRuleKey(key string) (int64, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(key, st.prefix+redisInstancesKey+":"), ":", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("invalid key %s", key)
+	}
+	orgID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid key %s: %w", key, err)
+	}
+	return orgID, parts[1], nil
+}
+
+func (st *RedisInstanceStore) scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := st.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+func (st *RedisInstanceStore) FetchOrgIds(ctx context.Context) ([]int64, error) {
+	keys, err := st.scan(ctx, st.prefix+redisInstancesKey+":*")
+	if err != nil {
+		return nil, err
+	}
+	orgs := make(map[int64]struct{})
+	for _, key := range keys {
+		orgID, _, err := st.parseRuleKey(key)
+		if err != nil {
+			st.logger.Warn("Ignoring unexpected key", "key", key, "error", err)
+			continue
+		}
+		orgs[orgID] = struct{}{}
+	}
+	orgIds := make([]int64, 0, len(orgs))
+	for orgID := range orgs {
+		orgIds = append(orgIds, orgID)
+	}
+	sort.Slice(orgIds, func(i, j int) bool { return orgIds[i] < orgIds[j] })
+	return orgIds, nil
+}
+
+// ListAlertInstances returns the alert instances of all rules, or of a single rule, of an organization.
+func (st *RedisInstanceStore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var keys []string
+	if cmd.RuleUID != "" {
+		keys = []string{st.ruleKey(cmd.RuleOrgID, cmd.RuleUID)}
+	} else {
+		var err error
+		keys, err = st.scan(ctx, fmt.Sprintf("%s%s:%d:*", st.prefix, redisInstancesKey, cmd.RuleOrgID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*models.AlertInstance, 0)
+	for _, key := range keys {
+		orgID, ruleUID, err := st.parseRuleKey(key)
+		if err != nil {
+			st.logger.Warn("Ignoring unexpected key", "key", key, "error", err)
+			continue
+		}
+		fields, err := st.client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range fields {
+			var record instanceRecord
+			if err := json.Unmarshal([]byte(value), &record); err != nil {
+				st.logger.Error("Failed to decode alert instance, the instance is ignored", "key", key, "error", err)
+				continue
+			}
+			instance, err := record.toAlertInstance(orgID, ruleUID)
+			if err != nil {
+				st.logger.Error("Failed to decode alert instance, the instance is ignored", "key", key, "error", err)
+				continue
+			}
+			result = append(result, instance)
+		}
+	}
+	return result, nil
+}
+
+func (st *RedisInstanceStore) SaveAlertInstance(ctx context.Context, instance models.AlertInstance) error {
+	if err := models.ValidateAlertInstance(instance); err != nil {
+		return err
+	}
+	value, err := json.Marshal(newInstanceRecord(instance))
+	if err != nil {
+		return err
+	}
+	return st.client.HSet(ctx, st.ruleKey(instance.RuleOrgID, instance.RuleUID), instance.LabelsHash, value).Err()
+}
+
+// SaveAlertInstancesForRule replaces all instances of the rule with the given instances in a single transaction.
+func (st *RedisInstanceStore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	values := make([]any, 0, 2*len(instances))
+	for _, instance := range instances {
+		value, err := json.Marshal(newInstanceRecord(instance))
+		if err != nil {
+			return err
+		}
+		values = append(values, instance.LabelsHash, value)
+	}
+	redisKey := st.ruleKey(key.OrgID, key.UID)
+	_, err := st.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, redisKey)
+		if len(values) > 0 {
+			pipe.HSet(ctx, redisKey, values...)
+		}
+		return nil
+	})
+	return err
+}
+
+func (st *RedisInstanceStore) DeleteAlertInstances(ctx context.Context, keys ...models.AlertInstanceKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := st.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, k := range keys {
+			pipe.HDel(ctx, st.ruleKey(k.RuleOrgID, k.RuleUID), k.LabelsHash)
+		}
+		return nil
+	})
+	return err
+}
+
+func (st *RedisInstanceStore) DeleteAlertInstancesByRule(ctx context.Context, key models.AlertRuleKey) error {
+	return st.client.Del(ctx, st.ruleKey(key.OrgID, key.UID)).Err()
+}
@@ -0,0 +1,171 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationRuleInstanceStores(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	stores := map[string]state.RuleInstanceStore{
+		"snapshot": store.NewSnapshotInstanceStore(dbstore.SQLStore, log.NewNopLogger()),
+		"redis":    store.NewRedisInstanceStore(store.RedisInstanceStoreConfig{Addr: mr.Addr(), Prefix: "test"}, log.NewNopLogger()),
+	}
+	for name, instanceStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			testRuleInstanceStore(t, instanceStore)
+		})
+	}
+}
+
+func testRuleInstanceStore(t *testing.T, instanceStore state.RuleInstanceStore) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	instance := func(orgID int64, ruleUID string, value string) models.AlertInstance {
+		labels := models.InstanceLabels{"test": value}
+		_, hash, err := labels.StringAndHash()
+		require.NoError(t, err)
+		return models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  orgID,
+				RuleUID:    ruleUID,
+				LabelsHash: hash,
+			},
+			Labels:            labels,
+			CurrentState:      models.InstanceStateFiring,
+			CurrentReason:     value,
+			CurrentStateSince: now.Add(-time.Minute),
+			CurrentStateEnd:   now.Add(time.Minute),
+			LastEvalTime:      now,
+		}
+	}
+	list := func(t *testing.T, orgID int64, ruleUID string) map[string]models.AlertInstance {
+		t.Helper()
+		instances, err := instanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: ruleUID})
+		require.NoError(t, err)
+		result := make(map[string]models.AlertInstance, len(instances))
+		for _, i := range instances {
+			result[fmt.Sprintf("%s/%s", i.RuleUID, i.Labels["test"])] = *i
+		}
+		return result
+	}
+
+	rule1 := models.AlertRuleKey{OrgID: 1, UID: "rule-1"}
+	rule2 := models.AlertRuleKey{OrgID: 1, UID: "rule-2"}
+	rule3 := models.AlertRuleKey{OrgID: 2, UID: "rule-3"}
+
+	t.Run("should save and list the instances of rules", func(t *testing.T) {
+		a := instance(rule1.OrgID, rule1.UID, "a")
+		require.NoError(t, instanceStore.SaveAlertInstancesForRule(ctx, rule1, []models.AlertInstance{a, instance(rule1.OrgID, rule1.UID, "b")}))
+		require.NoError(t, instanceStore.SaveAlertInstancesForRule(ctx, rule2, []models.AlertInstance{instance(rule2.OrgID, rule2.UID, "c")}))
+		require.NoError(t, instanceStore.SaveAlertInstancesForRule(ctx, rule3, []models.AlertInstance{instance(rule3.OrgID, rule3.UID, "d")}))
+
+		instances := list(t, 1, "")
+		require.Len(t, instances, 3)
+		require.Contains(t, instances, "rule-2/c")
+		require.Equal(t, a, instances["rule-1/a"])
+
+		require.Len(t, list(t, 1, rule1.UID), 2)
+		require.Len(t, list(t, 2, ""), 1)
+
+		orgIDs, err := instanceStore.FetchOrgIds(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []int64{1, 2}, orgIDs)
+	})
+
+	t.Run("should replace the instances of a rule", func(t *testing.T) {
+		require.NoError(t, instanceStore.SaveAlertInstancesForRule(ctx, rule1, []models.AlertInstance{instance(rule1.OrgID, rule1.UID, "e")}))
+
+		instances := list(t, 1, rule1.UID)
+		require.Len(t, instances, 1)
+		require.Contains(t, instances, "rule-1/e")
+	})
+
+	t.Run("should save and delete single instances", func(t *testing.T) {
+		updated := instance(rule1.OrgID, rule1.UID, "e")
+		updated.CurrentState = models.InstanceStateNormal
+		require.NoError(t, instanceStore.SaveAlertInstance(ctx, updated))
+		f := instance(rule1.OrgID, rule1.UID, "f")
+		require.NoError(t, instanceStore.SaveAlertInstance(ctx, f))
+
+		instances := list(t, 1, rule1.UID)
+		require.Len(t, instances, 2)
+		require.Equal(t, models.InstanceStateNormal, instances["rule-1/e"].CurrentState)
+
+		require.NoError(t, instanceStore.DeleteAlertInstances(ctx, f.AlertInstanceKey, instance(rule2.OrgID, rule2.UID, "c").AlertInstanceKey))
+		instances = list(t, 1, "")
+		require.Len(t, instances, 1)
+		require.Contains(t, instances, "rule-1/e")
+	})
+
+	t.Run("should delete the instances of a rule", func(t *testing.T) {
+		require.NoError(t, instanceStore.DeleteAlertInstancesByRule(ctx, rule3))
+		require.Empty(t, list(t, 2, ""))
+
+		orgIDs, err := instanceStore.FetchOrgIds(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int64{1}, orgIDs)
+	})
+}
+
+func TestIntegrationMigrateInstances(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	snapshotStore := store.NewSnapshotInstanceStore(dbstore.SQLStore, log.NewNopLogger())
+
+	rule1 := tests.CreateTestAlertRule(t, ctx, dbstore, 60, 1)
+	rule2 := tests.CreateTestAlertRule(t, ctx, dbstore, 60, 1)
+	for i, rule := range []*models.AlertRule{rule1, rule1, rule2} {
+		labels := models.InstanceLabels{"test": fmt.Sprint(i)}
+		_, hash, err := labels.StringAndHash()
+		require.NoError(t, err)
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash},
+			Labels:           labels,
+			CurrentState:     models.InstanceStateFiring,
+			LastEvalTime:     time.Now(),
+		}))
+	}
+
+	require.NoError(t, state.MigrateInstances(ctx, dbstore, snapshotStore, log.NewNopLogger()))
+
+	migrated, err := snapshotStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: 1, RuleUID: rule1.UID})
+	require.NoError(t, err)
+	require.Len(t, migrated, 2)
+	migrated, err = snapshotStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: 1, RuleUID: rule2.UID})
+	require.NoError(t, err)
+	require.Len(t, migrated, 1)
+
+	remaining, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: 1})
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+
+	// a completed migration does nothing
+	require.NoError(t, state.MigrateInstances(ctx, dbstore, snapshotStore, log.NewNopLogger()))
+	migrated, err = snapshotStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: 1})
+	require.NoError(t, err)
+	require.Len(t, migrated, 3)
+}
@@ -0,0 +1,260 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// instanceRecord is the compact representation of an alert instance that is used by the snapshot and Redis instance
+// stores. Times are stored with the precision of a second like in the alert_instance table.
+type instanceRecord struct {
+	Labels            models.InstanceLabels    `json:"l"`
+	CurrentState      models.InstanceStateType `json:"s"`
+	CurrentReason     string                   `json:"r,omitempty"`
+	CurrentStateSince int64                    `json:"since"`
+	CurrentStateEnd   int64                    `json:"end"`
+	LastEvalTime      int64                    `json:"eval"`
+}
+
+func newInstanceRecord(instance models.AlertInstance) instanceRecord {
+	return instanceRecord{
+		Labels:            instance.Labels,
+		CurrentState:      instance.CurrentState,
+		CurrentReason:     instance.CurrentReason,
+		CurrentStateSince: instance.CurrentStateSince.Unix(),
+		CurrentStateEnd:   instance.CurrentStateEnd.Unix(),
+		LastEvalTime:      instance.LastEvalTime.Unix(),
+	}
+}
+
+func (r instanceRecord) toAlertInstance(orgID int64, ruleUID string) (*models.AlertInstance, error) {
+	_, hash, err := r.Labels.StringAndHash()
+	if err != nil {
+		return nil, err
+	}
+	return &models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  orgID,
+			RuleUID:    ruleUID,
+			LabelsHash: hash,
+		},
+		Labels:            r.Labels,
+		CurrentState:      r.CurrentState,
+		CurrentReason:     r.CurrentReason,
+		CurrentStateSince: time.Unix(r.CurrentStateSince, 0),
+		CurrentStateEnd:   time.Unix(r.CurrentStateEnd, 0),
+		LastEvalTime:      time.Unix(r.LastEvalTime, 0),
+	}, nil
+}
+
+// alertRuleState is a row of the alert_rule_state table. Data holds the gzip-compressed JSON array of the instance
+// records of the rule.
+type alertRuleState struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	RuleUID   string `xorm:"rule_uid"`
+	Data      []byte `xorm:"data"`
+	UpdatedAt int64  `xorm:"updated_at"`
+}
+
+func (alertRuleState) TableName() string {
+	return "alert_rule_state"
+}
+
+func encodeSnapshot(instances []models.AlertInstance) ([]byte, error) {
+	records := make([]instanceRecord, 0, len(instances))
+	for _, instance := range instances {
+		records = append(records, newInstanceRecord(instance))
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(orgID int64, ruleUID string, data []byte) ([]*models.AlertInstance, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var records []instanceRecord
+	if err := json.Unmarshal(b, &records); err != nil {
+		return nil, err
+	}
+	result := make([]*models.AlertInstance, 0, len(records))
+	for _, record := range records {
+		instance, err := record.toAlertInstance(orgID, ruleUID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, instance)
+	}
+	return result, nil
+}
+
+// SnapshotInstanceStore is an instance store that keeps all alert instances of a rule in a single compressed row
+// of the alert_rule_state table. It is written once per rule evaluation instead of once per alert instance.
+type SnapshotInstanceStore struct {
+	SQLStore db.DB
+	Logger   log.Logger
+}
+
+func NewSnapshotInstanceStore(sqlStore db.DB, logger log.Logger) *SnapshotInstanceStore {
+	return &SnapshotInstanceStore{
+		SQLStore: sqlStore,
+		Logger:   logger,
+	}
+}
+
+func (st *SnapshotInstanceStore) FetchOrgIds(ctx context.Context) ([]int64, error) {
+	orgIds := []int64{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL("SELECT DISTINCT org_id FROM alert_rule_state").Find(&orgIds)
+	})
+	return orgIds, err
+}
+
+// ListAlertInstances returns the alert instances of all rules, or of a single rule, of an organization.
+func (st *SnapshotInstanceStore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var rows []alertRuleState
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", cmd.RuleOrgID)
+		if cmd.RuleUID != "" {
+			q = q.And("rule_uid = ?", cmd.RuleUID)
+		}
+		return q.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.AlertInstance, 0, len(rows))
+	for _, row := range rows {
+		instances, err := decodeSnapshot(row.OrgID, row.RuleUID, row.Data)
+		if err != nil {
+			st.Logger.Error("Failed to decode the state of the rule, the state is ignored", "org_id", row.OrgID, "rule_uid", row.RuleUID, "error", err)
+			continue
+		}
+		result = append(result, instances...)
+	}
+	return result, nil
+}
+
+// SaveAlertInstance adds the instance to the snapshot of its rule, or replaces the instance with the same labels.
+func (st *SnapshotInstanceStore) SaveAlertInstance(ctx context.Context, instance models.AlertInstance) error {
+	if err := models.ValidateAlertInstance(instance); err != nil {
+		return err
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return st.updateSnapshot(sess, instance.RuleOrgID, instance.RuleUID, func(instances []models.AlertInstance) []models.AlertInstance {
+			for i := range instances {
+				if instances[i].LabelsHash == instance.LabelsHash {
+					instances[i] = instance
+					return instances
+				}
+			}
+			return append(instances, instance)
+		})
+	})
+}
+
+// SaveAlertInstancesForRule replaces the snapshot of the rule with the given instances.
+func (st *SnapshotInstanceStore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return st.writeSnapshot(sess, key.OrgID, key.UID, instances)
+	})
+}
+
+// DeleteAlertInstances removes the instances with the provided keys from the snapshots of their rules.
+func (st *SnapshotInstanceStore) DeleteAlertInstances(ctx context.Context, keys ...models.AlertInstanceKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	byRule := make(map[models.AlertRuleKey]map[string]struct{})
+	for _, k := range keys {
+		ruleKey := models.AlertRuleKey{OrgID: k.RuleOrgID, UID: k.RuleUID}
+		if _, ok := byRule[ruleKey]; !ok {
+			byRule[ruleKey] = make(map[string]struct{})
+		}
+		byRule[ruleKey][k.LabelsHash] = struct{}{}
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for ruleKey, hashes := range byRule {
+			err := st.updateSnapshot(sess, ruleKey.OrgID, ruleKey.UID, func(instances []models.AlertInstance) []models.AlertInstance {
+				result := instances[:0]
+				for _, instance := range instances {
+					if _, ok := hashes[instance.LabelsHash]; !ok {
+						result = append(result, instance)
+					}
+				}
+				return result
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (st *SnapshotInstanceStore) DeleteAlertInstancesByRule(ctx context.Context, key models.AlertRuleKey) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_rule_state WHERE org_id = ? AND rule_uid = ?", key.OrgID, key.UID)
+		return err
+	})
+}
+
+func (st *SnapshotInstanceStore) updateSnapshot(sess *db.Session, orgID int64, ruleUID string, update func([]models.AlertInstance) []models.AlertInstance) error {
+	row := alertRuleState{}
+	has, err := sess.Where("org_id = ? AND rule_uid = ?", orgID, ruleUID).Get(&row)
+	if err != nil {
+		return err
+	}
+	var instances []models.AlertInstance
+	if has {
+		decoded, err := decodeSnapshot(orgID, ruleUID, row.Data)
+		if err != nil {
+			return fmt.Errorf("failed to decode the state of rule %s: %w", ruleUID, err)
+		}
+		for _, instance := range decoded {
+			instances = append(instances, *instance)
+		}
+	}
+	return st.writeSnapshot(sess, orgID, ruleUID, update(instances))
+}
+
+func (st *SnapshotInstanceStore) writeSnapshot(sess *db.Session, orgID int64, ruleUID string, instances []models.AlertInstance) error {
+	if len(instances) == 0 {
+		_, err := sess.Exec("DELETE FROM alert_rule_state WHERE org_id = ? AND rule_uid = ?", orgID, ruleUID)
+		return err
+	}
+	data, err := encodeSnapshot(instances)
+	if err != nil {
+		return fmt.Errorf("failed to encode the state of rule %s: %w", ruleUID, err)
+	}
+	upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
+		"alert_rule_state",
+		[]string{"org_id", "rule_uid"},
+		[]string{"org_id", "rule_uid", "data", "updated_at"})
+	_, err = sess.SQL(upsertSQL, orgID, ruleUID, data, time.Now().Unix()).Query()
+	return err
+}
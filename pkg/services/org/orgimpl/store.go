@@ -819,6 +819,9 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_totp WHERE user_id = ?",
+		"DELETE FROM user_totp_recovery_code WHERE user_id = ?",
+		"DELETE FROM scim_external_id WHERE resource_type = 'User' AND resource_id = ?",
 	}
 	return deletes
 }
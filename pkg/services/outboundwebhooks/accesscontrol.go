@@ -0,0 +1,47 @@
+package outboundwebhooks
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	ActionRead  = "webhooks:read"
+	ActionWrite = "webhooks:write"
+)
+
+var (
+	webhookReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:webhooks:reader",
+		DisplayName: "Webhook reader",
+		Description: "List outbound webhooks and read their delivery logs",
+		Group:       "Webhooks",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+		},
+	}
+
+	webhookWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:webhooks:writer",
+		DisplayName: "Webhook writer",
+		Description: "Create, update, delete and list outbound webhooks and read their delivery logs",
+		Group:       "Webhooks",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+			{Action: ActionWrite},
+		},
+	}
+)
+
+func (s *Service) declareFixedRoles(ac accesscontrol.Service) error {
+	webhookReader := accesscontrol.RoleRegistration{
+		Role:   webhookReaderRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+	webhookWriter := accesscontrol.RoleRegistration{
+		Role:   webhookWriterRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	return ac.DeclareFixedRoles(webhookWriter, webhookReader)
+}
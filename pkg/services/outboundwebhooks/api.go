@@ -0,0 +1,94 @@
+package outboundwebhooks
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/webhooks", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/", authorize(ac.EvalPermission(ActionRead)), routing.Wrap(s.handleList))
+		subrouter.Post("/", authorize(ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleCreate))
+		subrouter.Get("/:uid", authorize(ac.EvalPermission(ActionRead)), routing.Wrap(s.handleGet))
+		subrouter.Put("/:uid", authorize(ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleUpdate))
+		subrouter.Delete("/:uid", authorize(ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleDelete))
+		subrouter.Get("/:uid/deliveries", authorize(ac.EvalPermission(ActionRead)), routing.Wrap(s.handleListDeliveries))
+	}, middleware.ReqSignedIn)
+}
+
+func toDTO(w *Webhook) WebhookDTO {
+	return WebhookDTO{Webhook: *w, SecretSet: w.Secret != ""}
+}
+
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	webhooks, err := s.ListWebhooks(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list webhooks", err)
+	}
+
+	dtos := make([]WebhookDTO, 0, len(webhooks))
+	for _, w := range webhooks {
+		dtos = append(dtos, toDTO(w))
+	}
+	return response.JSON(http.StatusOK, dtos)
+}
+
+func (s *Service) handleCreate(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateWebhookCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+
+	w, err := s.CreateWebhook(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create webhook", err)
+	}
+	return response.JSON(http.StatusCreated, toDTO(w))
+}
+
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	w, err := s.GetWebhook(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get webhook", err)
+	}
+	return response.JSON(http.StatusOK, toDTO(w))
+}
+
+func (s *Service) handleUpdate(c *contextmodel.ReqContext) response.Response {
+	cmd := UpdateWebhookCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UID = web.Params(c.Req)[":uid"]
+
+	w, err := s.UpdateWebhook(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update webhook", err)
+	}
+	return response.JSON(http.StatusOK, toDTO(w))
+}
+
+func (s *Service) handleDelete(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteWebhook(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete webhook", err)
+	}
+	return response.Success("Webhook deleted")
+}
+
+func (s *Service) handleListDeliveries(c *contextmodel.ReqContext) response.Response {
+	deliveries, err := s.ListDeliveries(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list webhook deliveries", err)
+	}
+	return response.JSON(http.StatusOK, deliveries)
+}
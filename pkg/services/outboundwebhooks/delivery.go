@@ -0,0 +1,221 @@
+package outboundwebhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	headerEvent     = "X-Grafana-Event"
+	headerDelivery  = "X-Grafana-Delivery"
+	headerSignature = "X-Grafana-Signature"
+
+	redactedValue = "[REDACTED]"
+	// maxErrorBodySize is the size of the response body of a failed delivery that is kept in the delivery log.
+	maxErrorBodySize = 1024
+)
+
+var errForbiddenAddress = errors.New("webhooks can't be sent to loopback, private or link-local addresses")
+
+// secureFields are substrings of field names whose values are redacted from payloads.
+var secureFields = []string{"password", "secret", "token", "apikey", "api_key", "securejson", "privatekey", "private_key"}
+
+// redact returns a copy of the JSON value v with the values of secure fields replaced.
+func redact(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(value))
+		for k, field := range value {
+			if isSecureField(k) {
+				redacted[k] = redactedValue
+				continue
+			}
+			redacted[k] = redact(field)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(value))
+		for i, item := range value {
+			redacted[i] = redact(item)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+func isSecureField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range secureFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPayload returns the JSON body of a delivery, with secure fields redacted from the event data.
+func buildPayload(deliveryUID string, eventType EventType, orgID int64, timestamp time.Time, data any) (string, error) {
+	// round trip through JSON to redact fields of any type
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(Event{
+		ID:        deliveryUID,
+		Type:      eventType,
+		Timestamp: timestamp,
+		OrgID:     orgID,
+		Data:      redact(generic),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
+
+// sign returns the signature of the body with the secret, in the form sha256=<hex encoded HMAC-SHA256>.
+func sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns how long to wait before the next attempt after the given number of failed attempts.
+func backoff(attempts int, initial, max time.Duration) time.Duration {
+	wait := initial
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
+
+// newDeliveryClient returns the client that posts deliveries. Unless private networks are allowed, it refuses to
+// connect to loopback, private, link-local and unspecified addresses. The check is made on the resolved address of
+// every connection, so it also applies to redirects and to host names that resolve to such addresses.
+func newDeliveryClient(allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivateNetworks {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isForbiddenIP(ip) {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if !isAllowedHost(req.URL.Host) {
+				return fmt.Errorf("redirect to %s, which is not included in the data source proxy whitelist", req.URL.Host)
+			}
+			return nil
+		},
+	}
+}
+
+func isForbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+// isAllowedHost returns true if the host, as host:port or host, can be called by webhooks. Like the data source
+// proxy, all hosts are allowed unless the data source proxy whitelist is set.
+func isAllowedHost(host string) bool {
+	if len(setting.DataProxyWhiteList) == 0 {
+		return true
+	}
+	return setting.DataProxyWhiteList[host]
+}
+
+// attempt posts the delivery to the webhook. It returns the status code of the response, if any.
+func (s *Service) attempt(ctx context.Context, w *Webhook, d *Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	// the whitelist may have changed since the webhook was saved
+	if !isAllowedHost(req.URL.Host) {
+		return 0, fmt.Errorf("host %s is not included in the data source proxy whitelist", req.URL.Host)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Grafana")
+	req.Header.Set(headerEvent, string(d.EventType))
+	req.Header.Set(headerDelivery, d.UID)
+
+	if w.Secret != "" {
+		secret, err := s.decryptSecret(ctx, w.Secret)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+		}
+		req.Header.Set(headerSignature, sign(secret, []byte(d.Payload)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *Service) encryptSecret(ctx context.Context, secret string) (string, error) {
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (s *Service) decryptSecret(ctx context.Context, secret string) ([]byte, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, err
+	}
+	return s.secrets.Decrypt(ctx, encrypted)
+}
@@ -0,0 +1,106 @@
+package outboundwebhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	data := map[string]any{
+		"name":     "prometheus",
+		"password": "hunter2",
+		"jsonData": map[string]any{
+			"httpHeaderName1": "Authorization",
+			"apiKey":          "abc",
+		},
+		"secureJsonFields": map[string]any{"basicAuthPassword": true},
+		"items":            []any{map[string]any{"accessToken": "xyz", "id": 1.0}},
+	}
+
+	assert.Equal(t, map[string]any{
+		"name":     "prometheus",
+		"password": redactedValue,
+		"jsonData": map[string]any{
+			"httpHeaderName1": "Authorization",
+			"apiKey":          redactedValue,
+		},
+		"secureJsonFields": redactedValue,
+		"items":            []any{map[string]any{"accessToken": redactedValue, "id": 1.0}},
+	}, redact(data))
+	assert.Equal(t, "hunter2", data["password"], "redact must not modify its input")
+}
+
+func TestBuildPayload(t *testing.T) {
+	timestamp := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	data := struct {
+		UID    string `json:"uid"`
+		Secret string `json:"secret"`
+	}{UID: "abc", Secret: "s3cr3t"}
+
+	payload, err := buildPayload("delivery", EventDataSourceUpdated, 2, timestamp, data)
+	require.NoError(t, err)
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal([]byte(payload), &event))
+	assert.Equal(t, map[string]any{
+		"id":        "delivery",
+		"event":     "datasource.updated",
+		"timestamp": "2023-10-01T12:00:00Z",
+		"orgId":     2.0,
+		"data":      map[string]any{"uid": "abc", "secret": redactedValue},
+	}, event)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=6146142a2ce0159e84c0767881e4ec80bc397da62526e7d19f70795eb79460c0", sign([]byte("secret"), []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, sign([]byte("secret"), []byte(`{"id":"1"}`)), sign([]byte("other"), []byte(`{"id":"1"}`)))
+}
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 10 * time.Second},
+		{attempts: 2, expected: 20 * time.Second},
+		{attempts: 3, expected: 40 * time.Second},
+		{attempts: 10, expected: time.Minute},
+		{attempts: 100, expected: time.Minute},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, backoff(tc.attempts, 10*time.Second, time.Minute), "attempts: %d", tc.attempts)
+	}
+}
+
+func TestDeliveryClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	post := func(client *http.Client, url string) error {
+		resp, err := client.Post(url, "application/json", nil)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	t.Run("should refuse loopback, private and link-local addresses", func(t *testing.T) {
+		client := newDeliveryClient(false)
+		for _, url := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1", "http://[::1]:80"} {
+			assert.ErrorIs(t, post(client, url), errForbiddenAddress, url)
+		}
+	})
+
+	t.Run("should connect to private addresses if they are allowed", func(t *testing.T) {
+		assert.NoError(t, post(newDeliveryClient(true), server.URL))
+	})
+}
@@ -0,0 +1,126 @@
+package outboundwebhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+)
+
+func (s *Service) registerEventListeners(bus bus.Bus) {
+	bus.AddEventListener(s.onDashboardSaved)
+	bus.AddEventListener(s.onDashboardDeleted)
+	bus.AddEventListener(s.onDataSourceCreated)
+	bus.AddEventListener(s.onDataSourceUpdated)
+	bus.AddEventListener(s.onDataSourceDeleted)
+	bus.AddEventListener(s.onAlertRuleCreated)
+	bus.AddEventListener(s.onAlertRuleUpdated)
+	bus.AddEventListener(s.onAlertRuleDeleted)
+	bus.AddEventListener(s.onUserCreated)
+	bus.AddEventListener(s.onUserUpdated)
+	bus.AddEventListener(s.onUserDeleted)
+	bus.AddEventListener(s.onTeamCreated)
+	bus.AddEventListener(s.onTeamUpdated)
+	bus.AddEventListener(s.onTeamDeleted)
+}
+
+// handle enqueues deliveries of an event. Errors are logged rather than returned, so that a failure
+// to enqueue webhook deliveries never fails the change that published the event.
+func (s *Service) handle(ctx context.Context, orgID int64, eventType EventType, timestamp time.Time, data any) error {
+	if err := s.enqueue(ctx, orgID, eventType, timestamp, data); err != nil {
+		s.log.Error("Failed to enqueue webhook deliveries", "org_id", orgID, "event", eventType, "error", err)
+	}
+	return nil
+}
+
+func (s *Service) onDashboardSaved(ctx context.Context, e *events.DashboardSaved) error {
+	eventType := EventDashboardUpdated
+	switch {
+	case e.IsFolder && e.Created:
+		eventType = EventFolderCreated
+	case e.IsFolder:
+		eventType = EventFolderUpdated
+	case e.Created:
+		eventType = EventDashboardCreated
+	}
+	return s.handle(ctx, e.OrgID, eventType, e.Timestamp, e)
+}
+
+func (s *Service) onDashboardDeleted(ctx context.Context, e *events.DashboardDeleted) error {
+	eventType := EventDashboardDeleted
+	if e.IsFolder {
+		eventType = EventFolderDeleted
+	}
+	return s.handle(ctx, e.OrgID, eventType, e.Timestamp, e)
+}
+
+func (s *Service) onDataSourceCreated(ctx context.Context, e *events.DataSourceCreated) error {
+	return s.handle(ctx, e.OrgID, EventDataSourceCreated, e.Timestamp, e)
+}
+
+func (s *Service) onDataSourceUpdated(ctx context.Context, e *events.DataSourceUpdated) error {
+	return s.handle(ctx, e.OrgID, EventDataSourceUpdated, e.Timestamp, e)
+}
+
+func (s *Service) onDataSourceDeleted(ctx context.Context, e *events.DataSourceDeleted) error {
+	return s.handle(ctx, e.OrgID, EventDataSourceDeleted, e.Timestamp, e)
+}
+
+func (s *Service) onAlertRuleCreated(ctx context.Context, e *events.AlertRuleCreated) error {
+	return s.handle(ctx, e.OrgID, EventAlertRuleCreated, e.Timestamp, e)
+}
+
+func (s *Service) onAlertRuleUpdated(ctx context.Context, e *events.AlertRuleUpdated) error {
+	return s.handle(ctx, e.OrgID, EventAlertRuleUpdated, e.Timestamp, e)
+}
+
+func (s *Service) onAlertRuleDeleted(ctx context.Context, e *events.AlertRuleDeleted) error {
+	return s.handle(ctx, e.OrgID, EventAlertRuleDeleted, e.Timestamp, e)
+}
+
+func (s *Service) onUserCreated(ctx context.Context, e *events.UserCreated) error {
+	return s.handle(ctx, e.OrgID, EventUserCreated, e.Timestamp, e)
+}
+
+// onUserUpdated sends the event to the webhooks of every organization the user is a member of. The payload sent
+// to an organization only contains the ID of that organization.
+func (s *Service) onUserUpdated(ctx context.Context, e *events.UserUpdated) error {
+	for _, orgID := range memberOrgIDs(e.OrgID, e.OrgIDs) {
+		payload := *e
+		payload.OrgID = orgID
+		_ = s.handle(ctx, orgID, EventUserUpdated, e.Timestamp, &payload)
+	}
+	return nil
+}
+
+// onUserDeleted sends the event to the webhooks of every organization the user was a member of, like onUserUpdated.
+func (s *Service) onUserDeleted(ctx context.Context, e *events.UserDeleted) error {
+	for _, orgID := range memberOrgIDs(e.OrgID, e.OrgIDs) {
+		payload := *e
+		payload.OrgID = orgID
+		_ = s.handle(ctx, orgID, EventUserDeleted, e.Timestamp, &payload)
+	}
+	return nil
+}
+
+// memberOrgIDs returns the organizations of a user event, which are the organizations of the user's memberships,
+// or the user's default organization if they aren't known.
+func memberOrgIDs(orgID int64, orgIDs []int64) []int64 {
+	if len(orgIDs) == 0 {
+		return []int64{orgID}
+	}
+	return orgIDs
+}
+
+func (s *Service) onTeamCreated(ctx context.Context, e *events.TeamCreated) error {
+	return s.handle(ctx, e.OrgID, EventTeamCreated, e.Timestamp, e)
+}
+
+func (s *Service) onTeamUpdated(ctx context.Context, e *events.TeamUpdated) error {
+	return s.handle(ctx, e.OrgID, EventTeamUpdated, e.Timestamp, e)
+}
+
+func (s *Service) onTeamDeleted(ctx context.Context, e *events.TeamDeleted) error {
+	return s.handle(ctx, e.OrgID, EventTeamDeleted, e.Timestamp, e)
+}
@@ -0,0 +1,163 @@
+package outboundwebhooks
+
+import (
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrWebhookNotFound     = errutil.NotFound("outboundwebhooks.notFound", errutil.WithPublicMessage("Webhook not found"))
+	ErrInvalidWebhook      = errutil.BadRequest("outboundwebhooks.invalid").MustTemplate("invalid webhook: {{ .Public.Reason }}", errutil.WithPublic("Invalid webhook: {{ .Public.Reason }}"))
+	ErrWebhooksUnavailable = errutil.NotFound("outboundwebhooks.disabled", errutil.WithPublicMessage("Outbound webhooks are disabled"))
+)
+
+// EventType is the type of a resource change event webhooks can subscribe to, in the form <resource>.<change>.
+type EventType string
+
+const (
+	EventDashboardCreated  EventType = "dashboard.created"
+	EventDashboardUpdated  EventType = "dashboard.updated"
+	EventDashboardDeleted  EventType = "dashboard.deleted"
+	EventFolderCreated     EventType = "folder.created"
+	EventFolderUpdated     EventType = "folder.updated"
+	EventFolderDeleted     EventType = "folder.deleted"
+	EventDataSourceCreated EventType = "datasource.created"
+	EventDataSourceUpdated EventType = "datasource.updated"
+	EventDataSourceDeleted EventType = "datasource.deleted"
+	EventAlertRuleCreated  EventType = "alert_rule.created"
+	EventAlertRuleUpdated  EventType = "alert_rule.updated"
+	EventAlertRuleDeleted  EventType = "alert_rule.deleted"
+	EventUserCreated       EventType = "user.created"
+	EventUserUpdated       EventType = "user.updated"
+	EventUserDeleted       EventType = "user.deleted"
+	EventTeamCreated       EventType = "team.created"
+	EventTeamUpdated       EventType = "team.updated"
+	EventTeamDeleted       EventType = "team.deleted"
+)
+
+// EventTypes are all the event types webhooks can subscribe to.
+var EventTypes = []EventType{
+	EventDashboardCreated, EventDashboardUpdated, EventDashboardDeleted,
+	EventFolderCreated, EventFolderUpdated, EventFolderDeleted,
+	EventDataSourceCreated, EventDataSourceUpdated, EventDataSourceDeleted,
+	EventAlertRuleCreated, EventAlertRuleUpdated, EventAlertRuleDeleted,
+	EventUserCreated, EventUserUpdated, EventUserDeleted,
+	EventTeamCreated, EventTeamUpdated, EventTeamDeleted,
+}
+
+// validEventType returns true if t is a known event type, or a wildcard for all changes of a resource such as dashboard.*.
+func validEventType(t EventType) bool {
+	for _, known := range EventTypes {
+		if t == known || string(t) == resourceOf(known)+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceOf(t EventType) string {
+	resource, _, _ := strings.Cut(string(t), ".")
+	return resource
+}
+
+// DeliveryStatus is the state of a webhook delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Webhook is an org-level subscription to resource change events.
+type Webhook struct {
+	ID         int64       `xorm:"pk autoincr 'id'" json:"-"`
+	UID        string      `xorm:"uid" json:"uid"`
+	OrgID      int64       `xorm:"org_id" json:"orgId"`
+	Name       string      `xorm:"name" json:"name"`
+	URL        string      `xorm:"url" json:"url"`
+	EventTypes []EventType `xorm:"event_types" json:"events"`
+	// Secret is the encrypted, base64 encoded HMAC secret.
+	Secret  string    `xorm:"secret" json:"-"`
+	Enabled bool      `xorm:"enabled" json:"enabled"`
+	Created time.Time `xorm:"created" json:"created"`
+	Updated time.Time `xorm:"updated" json:"updated"`
+}
+
+func (w Webhook) TableName() string {
+	return "outbound_webhook"
+}
+
+// Subscribes returns true if the webhook is subscribed to the event type.
+func (w Webhook) Subscribes(t EventType) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == t || string(subscribed) == resourceOf(t)+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is a delivery of an event to a webhook, and the log of its delivery attempts.
+type Delivery struct {
+	ID             int64          `xorm:"pk autoincr 'id'" json:"-"`
+	UID            string         `xorm:"uid" json:"uid"`
+	OrgID          int64          `xorm:"org_id" json:"-"`
+	WebhookUID     string         `xorm:"webhook_uid" json:"webhookUid"`
+	EventType      EventType      `xorm:"event_type" json:"event"`
+	Payload        string         `xorm:"payload" json:"payload"`
+	Status         DeliveryStatus `xorm:"status" json:"status"`
+	Attempts       int            `xorm:"attempts" json:"attempts"`
+	NextAttempt    time.Time      `xorm:"next_attempt" json:"nextAttempt"`
+	LastStatusCode int            `xorm:"last_status_code" json:"lastStatusCode"`
+	LastError      string         `xorm:"last_error" json:"lastError,omitempty"`
+	Created        time.Time      `xorm:"created" json:"created"`
+	Updated        time.Time      `xorm:"updated" json:"updated"`
+}
+
+func (d Delivery) TableName() string {
+	return "outbound_webhook_delivery"
+}
+
+// Event is the JSON body posted to webhooks.
+type Event struct {
+	// ID is the UID of the delivery. It is the same for all attempts of a delivery.
+	ID        string    `json:"id"`
+	Type      EventType `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	OrgID     int64     `json:"orgId"`
+	Data      any       `json:"data"`
+}
+
+// WebhookDTO is a webhook as returned by the API. The secret is never returned.
+type WebhookDTO struct {
+	Webhook
+	SecretSet bool `json:"secretSet"`
+}
+
+// CreateWebhookCommand is the payload to create a webhook.
+type CreateWebhookCommand struct {
+	Name   string      `json:"name"`
+	URL    string      `json:"url"`
+	Events []EventType `json:"events"`
+	// Secret is used to sign deliveries with HMAC-SHA256.
+	Secret  string `json:"secret"`
+	Enabled *bool  `json:"enabled"`
+
+	OrgID int64 `json:"-"`
+}
+
+// UpdateWebhookCommand is the payload to update a webhook. An empty secret keeps the current secret.
+type UpdateWebhookCommand struct {
+	Name        string      `json:"name"`
+	URL         string      `json:"url"`
+	Events      []EventType `json:"events"`
+	Secret      string      `json:"secret"`
+	ResetSecret bool        `json:"resetSecret"`
+	Enabled     *bool       `json:"enabled"`
+
+	UID   string `json:"-"`
+	OrgID int64  `json:"-"`
+}
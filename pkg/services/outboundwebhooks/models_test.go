@@ -0,0 +1,45 @@
+package outboundwebhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestWebhookSubscribes(t *testing.T) {
+	w := Webhook{EventTypes: []EventType{EventDashboardCreated, "folder.*"}}
+
+	assert.True(t, w.Subscribes(EventDashboardCreated))
+	assert.False(t, w.Subscribes(EventDashboardUpdated))
+	assert.True(t, w.Subscribes(EventFolderCreated))
+	assert.True(t, w.Subscribes(EventFolderDeleted))
+	assert.False(t, w.Subscribes(EventTeamDeleted))
+}
+
+func TestValidateWebhook(t *testing.T) {
+	events := []EventType{EventDashboardCreated}
+
+	assert.NoError(t, validateWebhook("hook", "https://example.com/hook", events))
+	assert.NoError(t, validateWebhook("hook", "http://example.com", []EventType{"alert_rule.*"}))
+
+	assert.ErrorIs(t, validateWebhook("", "https://example.com", events), ErrInvalidWebhook)
+	assert.ErrorIs(t, validateWebhook("hook", "example.com/hook", events), ErrInvalidWebhook)
+	assert.ErrorIs(t, validateWebhook("hook", "ftp://example.com", events), ErrInvalidWebhook)
+	assert.ErrorIs(t, validateWebhook("hook", "https://example.com", nil), ErrInvalidWebhook)
+	assert.ErrorIs(t, validateWebhook("hook", "https://example.com", []EventType{"dashboard.starred"}), ErrInvalidWebhook)
+	assert.ErrorIs(t, validateWebhook("hook", "https://example.com", []EventType{"*"}), ErrInvalidWebhook)
+}
+
+func TestValidateWebhookWhitelist(t *testing.T) {
+	whitelist := setting.DataProxyWhiteList
+	t.Cleanup(func() { setting.DataProxyWhiteList = whitelist })
+	setting.DataProxyWhiteList = map[string]bool{"example.com": true, "example.com:8443": true}
+	events := []EventType{EventDashboardCreated}
+
+	assert.NoError(t, validateWebhook("hook", "https://example.com/hook", events))
+	assert.NoError(t, validateWebhook("hook", "https://example.com:8443/hook", events))
+	assert.ErrorIs(t, validateWebhook("hook", "https://example.org/hook", events), ErrInvalidWebhook)
+	assert.ErrorIs(t, validateWebhook("hook", "https://example.com:9000/hook", events), ErrInvalidWebhook)
+}
@@ -0,0 +1,367 @@
+package outboundwebhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	// deliveryBatchSize is the maximum number of deliveries attempted in one run of the delivery loop.
+	deliveryBatchSize = 100
+	// deliveryConcurrency is the maximum number of deliveries attempted concurrently.
+	deliveryConcurrency = 10
+	// deliveryLogLimit is the number of most recent deliveries returned by the API.
+	deliveryLogLimit = 100
+	cleanUpInterval  = time.Hour
+)
+
+type config struct {
+	enabled        bool
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	timeout        time.Duration
+	pollInterval   time.Duration
+	retention      time.Duration
+	// allowPrivateNetworks allows webhooks on loopback, private and link-local addresses.
+	allowPrivateNetworks bool
+}
+
+func readConfig(cfg *setting.Cfg) config {
+	section := cfg.SectionWithEnvOverrides("outbound_webhooks")
+	c := config{
+		enabled:        section.Key("enabled").MustBool(false),
+		maxAttempts:    section.Key("max_attempts").MustInt(5),
+		initialBackoff: section.Key("initial_retry_backoff").MustDuration(10 * time.Second),
+		maxBackoff:     section.Key("max_retry_backoff").MustDuration(time.Hour),
+		timeout:        section.Key("timeout").MustDuration(10 * time.Second),
+		pollInterval:   section.Key("poll_interval").MustDuration(5 * time.Second),
+		retention:      section.Key("delivery_log_retention").MustDuration(7 * 24 * time.Hour),
+
+		allowPrivateNetworks: section.Key("allow_private_networks").MustBool(false),
+	}
+	if c.maxAttempts < 1 {
+		c.maxAttempts = 1
+	}
+	return c
+}
+
+// Service sends resource change events to webhooks that organizations subscribe to.
+//
+// Events are stored as deliveries when they are published on the bus, and are then posted by a background
+// loop, with retries and exponential backoff. Stored deliveries make up the delivery log of a webhook.
+type Service struct {
+	cfg           config
+	store         *store
+	secrets       secrets.Service
+	accessControl ac.AccessControl
+	client        *http.Client
+	log           log.Logger
+
+	// notify wakes up the delivery loop when new deliveries are created.
+	notify chan struct{}
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl,
+	accesscontrolService ac.Service, bus bus.Bus, secretsService secrets.Service) (*Service, error) {
+	c := readConfig(cfg)
+	s := &Service{
+		cfg:           c,
+		store:         &store{db: sqlStore},
+		secrets:       secretsService,
+		accessControl: accessControl,
+		client:        newDeliveryClient(c.allowPrivateNetworks),
+		log:           log.New("outbound-webhooks"),
+		notify:        make(chan struct{}, 1),
+	}
+
+	if !s.cfg.enabled {
+		return s, nil
+	}
+
+	if err := s.declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+	s.registerEventListeners(bus)
+
+	return s, nil
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.enabled
+}
+
+// Run attempts the deliveries that are due until ctx is done.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.pollInterval)
+	defer ticker.Stop()
+	cleanUp := time.NewTicker(cleanUpInterval)
+	defer cleanUp.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.deliverDue(ctx)
+		case <-s.notify:
+			s.deliverDue(ctx)
+		case <-cleanUp.C:
+			s.cleanUp(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) cleanUp(ctx context.Context) {
+	deleted, err := s.store.deleteDeliveriesOlderThan(ctx, time.Now().Add(-s.cfg.retention))
+	if err != nil {
+		s.log.Error("Failed to delete old webhook deliveries", "error", err)
+		return
+	}
+	s.log.Debug("Deleted old webhook deliveries", "count", deleted)
+}
+
+// deliverDue attempts the deliveries that are due.
+func (s *Service) deliverDue(ctx context.Context) {
+	deliveries, err := s.store.listDueDeliveries(ctx, time.Now(), deliveryBatchSize)
+	if err != nil {
+		s.log.Error("Failed to list webhook deliveries", "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, deliveryConcurrency)
+	for _, d := range deliveries {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(d *Delivery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.deliver(ctx, d)
+		}(d)
+	}
+	wg.Wait()
+}
+
+// deliver makes one attempt of the delivery and records its outcome.
+func (s *Service) deliver(ctx context.Context, d *Delivery) {
+	logger := s.log.New("org_id", d.OrgID, "webhook_uid", d.WebhookUID, "delivery_uid", d.UID)
+
+	// Leave enough time for the attempt to complete before another attempt can claim the delivery.
+	claimed, err := s.store.claimDelivery(ctx, d, time.Now(), 2*s.cfg.timeout)
+	if err != nil {
+		logger.Error("Failed to claim webhook delivery", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	w, err := s.store.getWebhook(ctx, d.OrgID, d.WebhookUID)
+	switch {
+	case err != nil:
+		d.LastStatusCode, d.LastError = 0, err.Error()
+	case !w.Enabled:
+		d.LastStatusCode, d.LastError = 0, "webhook is disabled"
+	default:
+		d.LastStatusCode, err = s.attempt(ctx, w, d)
+		d.LastError = ""
+		if err != nil {
+			d.LastError = err.Error()
+		}
+	}
+
+	now := time.Now()
+	d.Updated = now
+	switch {
+	case d.LastError == "":
+		d.Status = DeliveryStatusSucceeded
+	case w == nil || !w.Enabled || d.Attempts >= s.cfg.maxAttempts:
+		d.Status = DeliveryStatusFailed
+		logger.Warn("Webhook delivery failed", "attempts", d.Attempts, "error", d.LastError)
+	default:
+		d.NextAttempt = now.Add(backoff(d.Attempts, s.cfg.initialBackoff, s.cfg.maxBackoff))
+		logger.Debug("Webhook delivery attempt failed, retrying", "attempts", d.Attempts, "next_attempt", d.NextAttempt, "error", d.LastError)
+	}
+
+	if err := s.store.updateDelivery(ctx, d); err != nil {
+		logger.Error("Failed to update webhook delivery", "error", err)
+	}
+}
+
+// enqueue creates deliveries of an event to the webhooks of the org that subscribe to it.
+func (s *Service) enqueue(ctx context.Context, orgID int64, eventType EventType, timestamp time.Time, data any) error {
+	webhooks, err := s.store.listEnabledWebhooks(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*Delivery, 0, len(webhooks))
+	for _, w := range webhooks {
+		if !w.Subscribes(eventType) {
+			continue
+		}
+
+		uid := util.GenerateShortUID()
+		payload, err := buildPayload(uid, eventType, orgID, timestamp, data)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, &Delivery{
+			UID:         uid,
+			OrgID:       orgID,
+			WebhookUID:  w.UID,
+			EventType:   eventType,
+			Payload:     payload,
+			Status:      DeliveryStatusPending,
+			NextAttempt: now,
+			Created:     now,
+			Updated:     now,
+		})
+	}
+
+	if err := s.store.createDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	if len(deliveries) > 0 {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *Service) CreateWebhook(ctx context.Context, cmd *CreateWebhookCommand) (*Webhook, error) {
+	if err := validateWebhook(cmd.Name, cmd.URL, cmd.Events); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	w := &Webhook{
+		UID:        util.GenerateShortUID(),
+		OrgID:      cmd.OrgID,
+		Name:       cmd.Name,
+		URL:        cmd.URL,
+		EventTypes: cmd.Events,
+		Enabled:    cmd.Enabled == nil || *cmd.Enabled,
+		Created:    now,
+		Updated:    now,
+	}
+
+	if cmd.Secret != "" {
+		secret, err := s.encryptSecret(ctx, cmd.Secret)
+		if err != nil {
+			return nil, err
+		}
+		w.Secret = secret
+	}
+
+	if err := s.store.createWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Service) UpdateWebhook(ctx context.Context, cmd *UpdateWebhookCommand) (*Webhook, error) {
+	if err := validateWebhook(cmd.Name, cmd.URL, cmd.Events); err != nil {
+		return nil, err
+	}
+
+	w, err := s.store.getWebhook(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Name = cmd.Name
+	w.URL = cmd.URL
+	w.EventTypes = cmd.Events
+	if cmd.Enabled != nil {
+		w.Enabled = *cmd.Enabled
+	}
+	w.Updated = time.Now()
+
+	switch {
+	case cmd.Secret != "":
+		secret, err := s.encryptSecret(ctx, cmd.Secret)
+		if err != nil {
+			return nil, err
+		}
+		w.Secret = secret
+	case cmd.ResetSecret:
+		w.Secret = ""
+	}
+
+	if err := s.store.updateWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, orgID int64, uid string) error {
+	return s.store.deleteWebhook(ctx, orgID, uid)
+}
+
+func (s *Service) GetWebhook(ctx context.Context, orgID int64, uid string) (*Webhook, error) {
+	return s.store.getWebhook(ctx, orgID, uid)
+}
+
+func (s *Service) ListWebhooks(ctx context.Context, orgID int64) ([]*Webhook, error) {
+	return s.store.listWebhooks(ctx, orgID)
+}
+
+// ListDeliveries returns the most recent deliveries to a webhook.
+func (s *Service) ListDeliveries(ctx context.Context, orgID int64, uid string) ([]*Delivery, error) {
+	if _, err := s.store.getWebhook(ctx, orgID, uid); err != nil {
+		return nil, err
+	}
+	return s.store.listDeliveries(ctx, orgID, uid, deliveryLogLimit)
+}
+
+func invalidWebhook(reason string) error {
+	return ErrInvalidWebhook.Build(errutil.TemplateData{Public: map[string]any{"Reason": reason}})
+}
+
+func validateWebhook(name, rawURL string, events []EventType) error {
+	if name == "" {
+		return invalidWebhook("name is required")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalidWebhook("url must be an absolute http or https URL")
+	}
+	if !isAllowedHost(u.Host) {
+		return invalidWebhook("url host is not included in the data source proxy whitelist")
+	}
+
+	if len(events) == 0 {
+		return invalidWebhook("at least one event is required")
+	}
+	for _, t := range events {
+		if !validEventType(t) {
+			return invalidWebhook(fmt.Sprintf("unknown event %q", t))
+		}
+	}
+	return nil
+}
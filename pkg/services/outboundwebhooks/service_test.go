@@ -0,0 +1,210 @@
+package outboundwebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// testReceiver is a webhook receiver that responds with the given status codes in turn, then with 200.
+type testReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, receivedRequest{header: req.Header, body: body})
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func setupTestService(t *testing.T, maxAttempts int) *Service {
+	t.Helper()
+	return &Service{
+		cfg: config{
+			enabled:        true,
+			maxAttempts:    maxAttempts,
+			initialBackoff: time.Millisecond,
+			maxBackoff:     time.Millisecond,
+			timeout:        5 * time.Second,
+		},
+		store:   &store{db: db.InitTestDB(t)},
+		secrets: fakes.NewFakeSecretsService(),
+		client:  &http.Client{},
+		log:     log.NewNopLogger(),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+func TestIntegrationOutboundWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+
+	t.Run("delivers subscribed events with retries", func(t *testing.T) {
+		s := setupTestService(t, 3)
+		receiver := &testReceiver{statuses: []int{http.StatusInternalServerError}}
+		server := httptest.NewServer(receiver)
+		t.Cleanup(server.Close)
+
+		w, err := s.CreateWebhook(ctx, &CreateWebhookCommand{
+			Name:   "dashboards",
+			URL:    server.URL,
+			Events: []EventType{"dashboard.*"},
+			Secret: "s3cr3t",
+			OrgID:  1,
+		})
+		require.NoError(t, err)
+		assert.True(t, w.Enabled)
+		assert.NotEqual(t, "s3cr3t", w.Secret)
+
+		_, err = s.CreateWebhook(ctx, &CreateWebhookCommand{Name: "teams", URL: server.URL, Events: []EventType{EventTeamCreated}, OrgID: 1})
+		require.NoError(t, err)
+		_, err = s.CreateWebhook(ctx, &CreateWebhookCommand{Name: "other org", URL: server.URL, Events: []EventType{"dashboard.*"}, OrgID: 2})
+		require.NoError(t, err)
+
+		require.NoError(t, s.onDashboardSaved(ctx, &events.DashboardSaved{Timestamp: time.Now(), UID: "dash", Title: "Dashboard", OrgID: 1, Created: true}))
+
+		deliveries, err := s.ListDeliveries(ctx, 1, w.UID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, EventDashboardCreated, deliveries[0].EventType)
+		assert.Equal(t, DeliveryStatusPending, deliveries[0].Status)
+
+		s.deliverDue(ctx)
+		deliveries, err = s.ListDeliveries(ctx, 1, w.UID)
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryStatusPending, deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].LastStatusCode)
+		assert.NotEmpty(t, deliveries[0].LastError)
+
+		time.Sleep(10 * time.Millisecond)
+		s.deliverDue(ctx)
+		deliveries, err = s.ListDeliveries(ctx, 1, w.UID)
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryStatusSucceeded, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusOK, deliveries[0].LastStatusCode)
+		assert.Empty(t, deliveries[0].LastError)
+
+		require.Len(t, receiver.requests, 2)
+		req := receiver.requests[1]
+		assert.Equal(t, string(EventDashboardCreated), req.header.Get(headerEvent))
+		assert.Equal(t, deliveries[0].UID, req.header.Get(headerDelivery))
+		assert.Equal(t, sign([]byte("s3cr3t"), req.body), req.header.Get(headerSignature))
+		assert.Equal(t, deliveries[0].Payload, string(req.body))
+	})
+
+	t.Run("marks deliveries as failed after the maximum number of attempts", func(t *testing.T) {
+		s := setupTestService(t, 2)
+		receiver := &testReceiver{statuses: []int{http.StatusBadGateway, http.StatusBadGateway}}
+		server := httptest.NewServer(receiver)
+		t.Cleanup(server.Close)
+
+		w, err := s.CreateWebhook(ctx, &CreateWebhookCommand{Name: "teams", URL: server.URL, Events: []EventType{EventTeamDeleted}, OrgID: 1})
+		require.NoError(t, err)
+		require.NoError(t, s.onTeamDeleted(ctx, &events.TeamDeleted{Timestamp: time.Now(), ID: 1, OrgID: 1}))
+
+		s.deliverDue(ctx)
+		time.Sleep(10 * time.Millisecond)
+		s.deliverDue(ctx)
+		time.Sleep(10 * time.Millisecond)
+		s.deliverDue(ctx)
+
+		deliveries, err := s.ListDeliveries(ctx, 1, w.UID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, DeliveryStatusFailed, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusBadGateway, deliveries[0].LastStatusCode)
+		assert.Len(t, receiver.requests, 2)
+	})
+
+	t.Run("sends user events to every organization of the user", func(t *testing.T) {
+		s := setupTestService(t, 1)
+
+		hooks := map[int64]*Webhook{}
+		for _, orgID := range []int64{1, 2, 3} {
+			w, err := s.CreateWebhook(ctx, &CreateWebhookCommand{Name: "users", URL: "https://example.com", Events: []EventType{"user.*"}, OrgID: orgID})
+			require.NoError(t, err)
+			hooks[orgID] = w
+		}
+
+		require.NoError(t, s.onUserUpdated(ctx, &events.UserUpdated{Timestamp: time.Now(), Id: 1, OrgID: 1, OrgIDs: []int64{1, 2}}))
+		require.NoError(t, s.onUserDeleted(ctx, &events.UserDeleted{Timestamp: time.Now(), Id: 1, OrgID: 1, OrgIDs: []int64{1, 2}}))
+
+		for orgID, expected := range map[int64]int{1: 2, 2: 2, 3: 0} {
+			deliveries, err := s.ListDeliveries(ctx, orgID, hooks[orgID].UID)
+			require.NoError(t, err)
+			require.Len(t, deliveries, expected)
+			for _, d := range deliveries {
+				var payload struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal([]byte(d.Payload), &payload))
+				assert.Equal(t, float64(orgID), payload.Data["org_id"])
+				assert.NotContains(t, payload.Data, "OrgIDs")
+			}
+		}
+	})
+
+	t.Run("updates and deletes webhooks", func(t *testing.T) {
+		s := setupTestService(t, 1)
+
+		w, err := s.CreateWebhook(ctx, &CreateWebhookCommand{Name: "hook", URL: "https://example.com", Events: []EventType{EventUserCreated}, Secret: "s3cr3t", OrgID: 1})
+		require.NoError(t, err)
+
+		disabled := false
+		updated, err := s.UpdateWebhook(ctx, &UpdateWebhookCommand{UID: w.UID, OrgID: 1, Name: "renamed", URL: "https://example.com/hook", Events: []EventType{"user.*"}, Enabled: &disabled})
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", updated.Name)
+		assert.False(t, updated.Enabled)
+		assert.Equal(t, w.Secret, updated.Secret, "an empty secret keeps the current secret")
+
+		updated, err = s.UpdateWebhook(ctx, &UpdateWebhookCommand{UID: w.UID, OrgID: 1, Name: "renamed", URL: "https://example.com/hook", Events: []EventType{"user.*"}, ResetSecret: true})
+		require.NoError(t, err)
+		assert.Empty(t, updated.Secret)
+
+		_, err = s.UpdateWebhook(ctx, &UpdateWebhookCommand{UID: w.UID, OrgID: 2, Name: "renamed", URL: "https://example.com/hook", Events: []EventType{"user.*"}})
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+
+		// disabled webhooks are not sent events
+		require.NoError(t, s.onUserCreated(ctx, &events.UserCreated{Timestamp: time.Now(), Id: 1, OrgID: 1}))
+		deliveries, err := s.ListDeliveries(ctx, 1, w.UID)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+
+		require.NoError(t, s.DeleteWebhook(ctx, 1, w.UID))
+		_, err = s.GetWebhook(ctx, 1, w.UID)
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+		assert.ErrorIs(t, s.DeleteWebhook(ctx, 1, w.UID), ErrWebhookNotFound)
+	})
+}
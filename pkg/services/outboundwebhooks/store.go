@@ -0,0 +1,143 @@
+package outboundwebhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store struct {
+	db db.DB
+}
+
+func (s *store) createWebhook(ctx context.Context, w *Webhook) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(w)
+		return err
+	})
+}
+
+func (s *store) updateWebhook(ctx context.Context, w *Webhook) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(w.ID).Where("org_id = ?", w.OrgID).AllCols().Update(w)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrWebhookNotFound.Errorf("webhook %s not found", w.UID)
+		}
+		return nil
+	})
+}
+
+func (s *store) deleteWebhook(ctx context.Context, orgID int64, uid string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&Webhook{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrWebhookNotFound.Errorf("webhook %s not found", uid)
+		}
+		_, err = sess.Where("org_id = ? AND webhook_uid = ?", orgID, uid).Delete(&Delivery{})
+		return err
+	})
+}
+
+func (s *store) getWebhook(ctx context.Context, orgID int64, uid string) (*Webhook, error) {
+	w := &Webhook{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(w)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrWebhookNotFound.Errorf("webhook %s not found", uid)
+		}
+		return nil
+	})
+	return w, err
+}
+
+func (s *store) listWebhooks(ctx context.Context, orgID int64) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).OrderBy("name").Find(&webhooks)
+	})
+	return webhooks, err
+}
+
+func (s *store) listEnabledWebhooks(ctx context.Context, orgID int64) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND enabled = ?", orgID, true).Find(&webhooks)
+	})
+	return webhooks, err
+}
+
+func (s *store) createDeliveries(ctx context.Context, deliveries []*Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(&deliveries)
+		return err
+	})
+}
+
+// listDeliveries returns the most recent deliveries to a webhook.
+func (s *store) listDeliveries(ctx context.Context, orgID int64, webhookUID string, limit int) ([]*Delivery, error) {
+	deliveries := make([]*Delivery, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND webhook_uid = ?", orgID, webhookUID).Desc("id").Limit(limit).Find(&deliveries)
+	})
+	return deliveries, err
+}
+
+// listDueDeliveries returns pending deliveries whose next attempt is due.
+func (s *store) listDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*Delivery, error) {
+	deliveries := make([]*Delivery, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("status = ? AND next_attempt <= ?", DeliveryStatusPending, now).Asc("next_attempt").Limit(limit).Find(&deliveries)
+	})
+	return deliveries, err
+}
+
+// claimDelivery starts a new attempt of the delivery, and postpones its next attempt to lease in case the
+// attempt doesn't complete. It returns false if the delivery was claimed by another attempt in the meantime,
+// for example by another Grafana instance.
+func (s *store) claimDelivery(ctx context.Context, d *Delivery, now time.Time, lease time.Duration) (bool, error) {
+	var claimed bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE outbound_webhook_delivery SET attempts = ?, next_attempt = ?, updated = ? WHERE id = ? AND attempts = ? AND status = ?",
+			d.Attempts+1, now.Add(lease), now, d.ID, d.Attempts, DeliveryStatusPending)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		claimed = affected == 1
+		return err
+	})
+	if claimed {
+		d.Attempts++
+	}
+	return claimed, err
+}
+
+func (s *store) updateDelivery(ctx context.Context, d *Delivery) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(d.ID).Cols("status", "next_attempt", "last_status_code", "last_error", "updated").Update(d)
+		return err
+	})
+}
+
+func (s *store) deleteDeliveriesOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("created < ? AND status <> ?", before, DeliveryStatusPending).Delete(&Delivery{})
+		return err
+	})
+	return deleted, err
+}
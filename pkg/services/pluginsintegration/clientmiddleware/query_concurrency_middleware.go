@@ -0,0 +1,254 @@
+package clientmiddleware
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	ngalertmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// queryLane identifies the traffic class a query belongs to. Each lane has its own
+// concurrency cap per data source, so that for example a burst of alert evaluations
+// cannot starve dashboards of query slots and vice versa.
+type queryLane string
+
+const (
+	queryLaneDashboard       queryLane = "dashboard"
+	queryLaneAlerting        queryLane = "alerting"
+	queryLanePublicDashboard queryLane = "public_dashboard"
+)
+
+var errQueryQueueTimeout = errutil.TooManyRequests("plugin.queryQueueTimeout",
+	errutil.WithPublicMessage("Too many concurrent queries to the data source, please try again later"))
+
+// queryConcurrencyLimiter holds the semaphores and metrics that are shared by all the
+// client chains the QueryConcurrencyMiddleware is part of.
+type queryConcurrencyLimiter struct {
+	limits       map[queryLane]int
+	queueTimeout time.Duration
+
+	mu         sync.Mutex
+	semaphores map[string]*fifoSemaphore
+
+	queueDepth *prometheus.GaugeVec
+	queueWait  *prometheus.HistogramVec
+	rejected   *prometheus.CounterVec
+}
+
+// QueryConcurrencyMiddleware is a middleware that limits the number of concurrent
+// QueryData requests per data source UID and traffic lane. Requests over the limit
+// wait in a first-in-first-out queue until a slot frees up or the queue timeout expires.
+type QueryConcurrencyMiddleware struct {
+	*queryConcurrencyLimiter
+	next plugins.Client
+}
+
+func newQueryConcurrencyLimiter(cfg *setting.Cfg, promRegisterer prometheus.Registerer) *queryConcurrencyLimiter {
+	queueDepth := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_query_queue_depth",
+		Help:      "Number of data source queries waiting for a concurrency slot",
+	}, []string{"plugin_id", "lane"})
+	queueWait := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "grafana",
+		Name:      "plugin_query_queue_wait_duration_seconds",
+		Help:      "Time data source queries spent waiting for a concurrency slot",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"plugin_id", "lane"})
+	rejected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "plugin_query_queue_timeouts_total",
+		Help:      "Number of data source queries rejected because they waited longer than the queue timeout",
+	}, []string{"plugin_id", "lane"})
+	promRegisterer.MustRegister(queueDepth, queueWait, rejected)
+
+	return &queryConcurrencyLimiter{
+		limits: map[queryLane]int{
+			queryLaneDashboard:       cfg.DataSourceConcurrentQueryLimit,
+			queryLaneAlerting:        cfg.DataSourceConcurrentQueryLimitAlerting,
+			queryLanePublicDashboard: cfg.DataSourceConcurrentQueryLimitPublicDashboard,
+		},
+		queueTimeout: cfg.DataSourceConcurrentQueryQueueTimeout,
+		semaphores:   map[string]*fifoSemaphore{},
+		queueDepth:   queueDepth,
+		queueWait:    queueWait,
+		rejected:     rejected,
+	}
+}
+
+// NewQueryConcurrencyMiddleware returns a new QueryConcurrencyMiddleware. The client chain is built
+// for every request, so each chain gets its own middleware that shares the limiter of the others.
+func NewQueryConcurrencyMiddleware(cfg *setting.Cfg, promRegisterer prometheus.Registerer) plugins.ClientMiddleware {
+	limiter := newQueryConcurrencyLimiter(cfg, promRegisterer)
+	return plugins.ClientMiddlewareFunc(func(next plugins.Client) plugins.Client {
+		return &QueryConcurrencyMiddleware{
+			queryConcurrencyLimiter: limiter,
+			next:                    next,
+		}
+	})
+}
+
+// QueryConcurrencyLimitEnabled returns true if any of the traffic lanes has a concurrency limit
+// configured and the QueryConcurrencyMiddleware should be installed.
+func QueryConcurrencyLimitEnabled(cfg *setting.Cfg) bool {
+	return cfg.DataSourceConcurrentQueryLimit > 0 ||
+		cfg.DataSourceConcurrentQueryLimitAlerting > 0 ||
+		cfg.DataSourceConcurrentQueryLimitPublicDashboard > 0
+}
+
+func laneFromRequest(ctx context.Context, req *backend.QueryDataRequest) queryLane {
+	if v, ok := req.Headers[ngalertmodels.FromAlertHeaderName]; ok && v == "true" {
+		return queryLaneAlerting
+	}
+	if reqCtx := contexthandler.FromContext(ctx); reqCtx != nil && reqCtx.IsPublicDashboardView() {
+		return queryLanePublicDashboard
+	}
+	return queryLaneDashboard
+}
+
+func (m *queryConcurrencyLimiter) semaphore(dsUID string, lane queryLane, limit int) *fifoSemaphore {
+	key := string(lane) + "/" + dsUID
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.semaphores[key]
+	if !ok {
+		s = newFIFOSemaphore(limit)
+		m.semaphores[key] = s
+	}
+	return s
+}
+
+func (m *QueryConcurrencyMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil || req.PluginContext.DataSourceInstanceSettings == nil {
+		return m.next.QueryData(ctx, req)
+	}
+
+	lane := laneFromRequest(ctx, req)
+	limit := m.limits[lane]
+	if limit <= 0 {
+		return m.next.QueryData(ctx, req)
+	}
+
+	pluginID := req.PluginContext.PluginID
+	sem := m.semaphore(req.PluginContext.DataSourceInstanceSettings.UID, lane, limit)
+
+	waitCtx := ctx
+	if m.queueTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, m.queueTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	depth := m.queueDepth.WithLabelValues(pluginID, string(lane))
+	err := sem.acquire(waitCtx, depth.Inc, depth.Dec)
+	m.queueWait.WithLabelValues(pluginID, string(lane)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		// The caller gave up, report its own error rather than a queue timeout.
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		m.rejected.WithLabelValues(pluginID, string(lane)).Inc()
+		return nil, errQueryQueueTimeout.Errorf("query to data source %s waited more than %s for one of %d %s query slots",
+			req.PluginContext.DataSourceInstanceSettings.UID, m.queueTimeout, limit, lane)
+	}
+	defer sem.release()
+
+	return m.next.QueryData(ctx, req)
+}
+
+func (m *QueryConcurrencyMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return m.next.CallResource(ctx, req, sender)
+}
+
+func (m *QueryConcurrencyMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return m.next.CheckHealth(ctx, req)
+}
+
+func (m *QueryConcurrencyMiddleware) CollectMetrics(ctx context.Context, req *backend.CollectMetricsRequest) (*backend.CollectMetricsResult, error) {
+	return m.next.CollectMetrics(ctx, req)
+}
+
+func (m *QueryConcurrencyMiddleware) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return m.next.SubscribeStream(ctx, req)
+}
+
+func (m *QueryConcurrencyMiddleware) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return m.next.PublishStream(ctx, req)
+}
+
+func (m *QueryConcurrencyMiddleware) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return m.next.RunStream(ctx, req, sender)
+}
+
+// fifoSemaphore is a counting semaphore that hands out free slots to waiters
+// in the order they arrived.
+type fifoSemaphore struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiters *list.List
+}
+
+func newFIFOSemaphore(limit int) *fifoSemaphore {
+	return &fifoSemaphore{limit: limit, waiters: list.New()}
+}
+
+// acquire blocks until a slot is available or ctx is done. onQueue and onDequeue
+// are called when the caller starts and stops waiting in the queue.
+func (s *fifoSemaphore) acquire(ctx context.Context, onQueue, onDequeue func()) error {
+	s.mu.Lock()
+	if s.active < s.limit && s.waiters.Len() == 0 {
+		s.active++
+		s.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(ready)
+	s.mu.Unlock()
+
+	onQueue()
+	defer onDequeue()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// The slot was handed over while we were timing out, give it back.
+			s.mu.Unlock()
+			s.release()
+		default:
+			s.waiters.Remove(elem)
+			s.mu.Unlock()
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees a slot, handing it directly to the oldest waiter if there is one.
+func (s *fifoSemaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if front := s.waiters.Front(); front != nil {
+		s.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+		return
+	}
+	s.active--
+}
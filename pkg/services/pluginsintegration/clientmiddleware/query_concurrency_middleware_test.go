@@ -0,0 +1,245 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	ngalertmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// blockingClient is a plugins.Client whose QueryData blocks until release is closed.
+type blockingClient struct {
+	clienttest.TestClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingClient() *blockingClient {
+	c := &blockingClient{started: make(chan struct{}, 100), release: make(chan struct{})}
+	c.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+		c.started <- struct{}{}
+		<-c.release
+		return &backend.QueryDataResponse{}, nil
+	}
+	return c
+}
+
+func newTestQueryConcurrencyClient(t *testing.T, cfg *setting.Cfg, next plugins.Client) (*QueryConcurrencyMiddleware, plugins.Client) {
+	t.Helper()
+	c := NewQueryConcurrencyMiddleware(cfg, prometheus.NewRegistry()).CreateClientMiddleware(next)
+	mw, ok := c.(*QueryConcurrencyMiddleware)
+	require.True(t, ok)
+	return mw, c
+}
+
+func queryReq(dsUID string, headers map[string]string) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			PluginID:                   "test-datasource",
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: dsUID},
+		},
+		Headers: headers,
+	}
+}
+
+func TestQueryConcurrencyMiddleware(t *testing.T) {
+	t.Run("Should reject queries that wait longer than the queue timeout", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DataSourceConcurrentQueryLimit = 1
+		cfg.DataSourceConcurrentQueryQueueTimeout = 50 * time.Millisecond
+
+		next := newBlockingClient()
+		mw, c := newTestQueryConcurrencyClient(t, cfg, next)
+
+		go func() {
+			_, _ = c.QueryData(context.Background(), queryReq("ds1", nil))
+		}()
+		<-next.started
+
+		_, err := c.QueryData(context.Background(), queryReq("ds1", nil))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errQueryQueueTimeout))
+
+		var grafanaErr errutil.Error
+		require.True(t, errors.As(err, &grafanaErr))
+		require.Equal(t, errutil.StatusTooManyRequests, grafanaErr.Reason.Status())
+		require.Equal(t, 1.0, testutil.ToFloat64(mw.rejected.WithLabelValues("test-datasource", string(queryLaneDashboard))))
+
+		close(next.release)
+	})
+
+	t.Run("Should limit per data source UID", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DataSourceConcurrentQueryLimit = 1
+		cfg.DataSourceConcurrentQueryQueueTimeout = time.Second
+
+		next := newBlockingClient()
+		_, c := newTestQueryConcurrencyClient(t, cfg, next)
+
+		var wg sync.WaitGroup
+		for _, uid := range []string{"ds1", "ds2"} {
+			wg.Add(1)
+			go func(uid string) {
+				defer wg.Done()
+				_, err := c.QueryData(context.Background(), queryReq(uid, nil))
+				require.NoError(t, err)
+			}(uid)
+		}
+
+		<-next.started
+		<-next.started
+		close(next.release)
+		wg.Wait()
+	})
+
+	t.Run("Should use separate lanes for alerting and public dashboards", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DataSourceConcurrentQueryLimit = 1
+		cfg.DataSourceConcurrentQueryLimitAlerting = 1
+		cfg.DataSourceConcurrentQueryLimitPublicDashboard = 1
+		cfg.DataSourceConcurrentQueryQueueTimeout = time.Second
+
+		next := newBlockingClient()
+		_, c := newTestQueryConcurrencyClient(t, cfg, next)
+
+		publicCtx := context.WithValue(context.Background(), ctxkey.Key{}, &contextmodel.ReqContext{
+			Context:                    &web.Context{Req: &http.Request{}},
+			PublicDashboardAccessToken: "token",
+		})
+
+		var wg sync.WaitGroup
+		run := func(ctx context.Context, req *backend.QueryDataRequest) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := c.QueryData(ctx, req)
+				require.NoError(t, err)
+			}()
+		}
+		run(context.Background(), queryReq("ds1", nil))
+		run(context.Background(), queryReq("ds1", map[string]string{ngalertmodels.FromAlertHeaderName: "true"}))
+		run(publicCtx, queryReq("ds1", nil))
+
+		for i := 0; i < 3; i++ {
+			<-next.started
+		}
+		close(next.release)
+		wg.Wait()
+	})
+
+	t.Run("Should not limit lanes without a limit", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DataSourceConcurrentQueryLimitAlerting = 1
+		cfg.DataSourceConcurrentQueryQueueTimeout = time.Second
+
+		next := newBlockingClient()
+		_, c := newTestQueryConcurrencyClient(t, cfg, next)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := c.QueryData(context.Background(), queryReq("ds1", nil))
+				require.NoError(t, err)
+			}()
+		}
+		for i := 0; i < 3; i++ {
+			<-next.started
+		}
+		close(next.release)
+		wg.Wait()
+	})
+
+	t.Run("Should return the caller's error when its context is cancelled while queued", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DataSourceConcurrentQueryLimit = 1
+		cfg.DataSourceConcurrentQueryQueueTimeout = time.Minute
+
+		next := newBlockingClient()
+		_, c := newTestQueryConcurrencyClient(t, cfg, next)
+
+		go func() {
+			_, _ = c.QueryData(context.Background(), queryReq("ds1", nil))
+		}()
+		<-next.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := c.QueryData(ctx, queryReq("ds1", nil))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(next.release)
+	})
+
+	t.Run("Should share the limits between the client chains of every request", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DataSourceConcurrentQueryLimit = 1
+		cfg.DataSourceConcurrentQueryQueueTimeout = 50 * time.Millisecond
+
+		mw := NewQueryConcurrencyMiddleware(cfg, prometheus.NewRegistry())
+		first, second := newBlockingClient(), newBlockingClient()
+
+		go func() {
+			_, _ = mw.CreateClientMiddleware(first).QueryData(context.Background(), queryReq("ds1", nil))
+		}()
+		<-first.started
+
+		_, err := mw.CreateClientMiddleware(second).QueryData(context.Background(), queryReq("ds1", nil))
+		require.ErrorIs(t, err, errQueryQueueTimeout)
+		require.Empty(t, second.started)
+
+		close(first.release)
+		close(second.release)
+	})
+}
+
+func TestFIFOSemaphore(t *testing.T) {
+	sem := newFIFOSemaphore(1)
+	nop := func() {}
+	require.NoError(t, sem.acquire(context.Background(), nop, nop))
+
+	order := make(chan int, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			require.NoError(t, sem.acquire(context.Background(), nop, nop))
+			order <- i
+			sem.release()
+		}(i)
+		// wait until the goroutine is queued before starting the next one
+		require.Eventually(t, func() bool {
+			sem.mu.Lock()
+			defer sem.mu.Unlock()
+			return sem.waiters.Len() == i+1
+		}, time.Second, time.Millisecond)
+	}
+
+	sem.release()
+	wg.Wait()
+	close(order)
+
+	var got []int
+	for i := range order {
+		got = append(got, i)
+	}
+	require.Equal(t, []int{0, 1, 2}, got)
+}
@@ -187,6 +187,11 @@ func CreateMiddlewares(cfg *setting.Cfg, oAuthTokenService oauthtoken.OAuthToken
 		middlewares = append(middlewares, clientmiddleware.NewUserHeaderMiddleware())
 	}
 
+	// Placed below the caching middleware so that cache hits do not take up a query slot
+	if clientmiddleware.QueryConcurrencyLimitEnabled(cfg) {
+		middlewares = append(middlewares, clientmiddleware.NewQueryConcurrencyMiddleware(cfg, promRegisterer))
+	}
+
 	middlewares = append(middlewares, clientmiddleware.NewHTTPClientMiddleware())
 
 	if features.IsEnabledGlobally(featuremgmt.FlagPluginsInstrumentationStatusSource) {
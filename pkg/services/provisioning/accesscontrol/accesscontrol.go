@@ -0,0 +1,208 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/ossaccesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// RoleService is the subset of ossaccesscontrol.RoleService used to provision custom roles.
+type RoleService interface {
+	GetRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error)
+	GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error)
+	CreateRole(ctx context.Context, orgID int64, cmd ossaccesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error)
+	UpdateRole(ctx context.Context, orgID int64, uid string, cmd ossaccesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error)
+	DeleteRole(ctx context.Context, orgID int64, uid string, force bool) error
+	AddUserRole(ctx context.Context, orgID, userID int64, uid string) error
+	RemoveUserRole(ctx context.Context, orgID, userID int64, uid string) error
+	AddTeamRole(ctx context.Context, orgID, teamID int64, uid string) error
+	RemoveTeamRole(ctx context.Context, orgID, teamID int64, uid string) error
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the custom roles and role assignments in those files.
+func Provision(ctx context.Context, configDirectory string, roleService RoleService, teamService team.Service, userService user.Service) error {
+	logger := log.New("provisioning.accesscontrol")
+	p := rolesProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger},
+		roleService: roleService,
+		teamService: teamService,
+		userService: userService,
+	}
+	return p.applyChanges(ctx, configDirectory)
+}
+
+// rolesProvisioner is responsible for provisioning custom roles and their assignments based on
+// configuration read by the `configReader`
+type rolesProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	roleService RoleService
+	teamService team.Service
+	userService user.Service
+}
+
+func (p *rolesProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	// Roles are provisioned first so that assignments can reference roles from any file
+	for _, cfg := range configs {
+		for _, role := range cfg.Roles {
+			if err := p.applyRole(ctx, role); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, a := range cfg.Teams {
+			if err := p.applyTeamAssignments(ctx, a); err != nil {
+				return err
+			}
+		}
+		for _, a := range cfg.Users {
+			if err := p.applyUserAssignments(ctx, a); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *rolesProvisioner) applyRole(ctx context.Context, role *roleFromConfig) error {
+	orgID := role.OrgID
+	if role.Global {
+		orgID = accesscontrol.GlobalOrgID
+	}
+	uid := role.UID
+	if uid == "" {
+		uid = accesscontrol.PrefixedRoleUID(role.Name)
+	}
+
+	stored, err := p.roleService.GetRole(ctx, orgID, uid)
+	if err != nil && !errors.Is(err, ossaccesscontrol.ErrRoleNotFound) {
+		return err
+	}
+
+	if role.Absent {
+		if stored == nil {
+			return nil
+		}
+		p.log.Info("Deleting role from configuration", "name", role.Name, "uid", uid, "force", role.Force)
+		return p.roleService.DeleteRole(ctx, orgID, uid, role.Force)
+	}
+
+	if stored == nil {
+		p.log.Info("Creating role from configuration", "name", role.Name, "uid", uid, "version", role.Version)
+		_, err := p.roleService.CreateRole(ctx, orgID, ossaccesscontrol.CreateRoleCommand{
+			UID:         uid,
+			Name:        role.Name,
+			DisplayName: role.DisplayName,
+			Description: role.Description,
+			Group:       role.Group,
+			Global:      role.Global,
+			Version:     role.Version,
+			Permissions: role.Permissions,
+		})
+		return err
+	}
+
+	if role.Version <= stored.Version {
+		p.log.Debug("Skipping role, the stored version is up to date", "name", role.Name, "uid", uid, "version", stored.Version)
+		return nil
+	}
+
+	p.log.Info("Updating role from configuration", "name", role.Name, "uid", uid, "version", role.Version)
+	_, err = p.roleService.UpdateRole(ctx, orgID, uid, ossaccesscontrol.UpdateRoleCommand{
+		Name:        role.Name,
+		DisplayName: role.DisplayName,
+		Description: role.Description,
+		Group:       role.Group,
+		Version:     role.Version,
+		Permissions: role.Permissions,
+	})
+	return err
+}
+
+func (p *rolesProvisioner) applyTeamAssignments(ctx context.Context, a *assignmentsFromConfig) error {
+	result, err := p.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID: a.OrgID,
+		Name:  a.Name,
+		Limit: 1,
+		SignedInUser: accesscontrol.BackgroundUser("access_control_provisioning", a.OrgID, org.RoleAdmin, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	if len(result.Teams) == 0 {
+		return fmt.Errorf("team %q not found in organization %d", a.Name, a.OrgID)
+	}
+	teamID := result.Teams[0].ID
+
+	return p.applyAssignments(ctx, a, func(uid string, absent bool) error {
+		if absent {
+			return p.roleService.RemoveTeamRole(ctx, a.OrgID, teamID, uid)
+		}
+		return p.roleService.AddTeamRole(ctx, a.OrgID, teamID, uid)
+	})
+}
+
+func (p *rolesProvisioner) applyUserAssignments(ctx context.Context, a *assignmentsFromConfig) error {
+	usr, err := p.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: a.Name})
+	if err != nil {
+		return fmt.Errorf("failed to get user %q: %w", a.Name, err)
+	}
+
+	return p.applyAssignments(ctx, a, func(uid string, absent bool) error {
+		if absent {
+			return p.roleService.RemoveUserRole(ctx, a.OrgID, usr.ID, uid)
+		}
+		return p.roleService.AddUserRole(ctx, a.OrgID, usr.ID, uid)
+	})
+}
+
+func (p *rolesProvisioner) applyAssignments(ctx context.Context, a *assignmentsFromConfig, apply func(uid string, absent bool) error) error {
+	for _, ref := range a.Roles {
+		uid, err := p.resolveRoleUID(ctx, a.OrgID, ref)
+		if err == nil {
+			p.log.Debug("Applying role assignment from configuration", "assignee", a.Name, "role", uid, "absent", ref.Absent)
+			err = apply(uid, ref.Absent)
+		}
+		// Removing the assignment of a role that doesn't exist is a no-op
+		if err != nil && !(ref.Absent && errors.Is(err, ossaccesscontrol.ErrRoleNotFound)) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *rolesProvisioner) resolveRoleUID(ctx context.Context, orgID int64, ref *roleRefFromConfig) (string, error) {
+	if ref.UID != "" {
+		return ref.UID, nil
+	}
+
+	roles, err := p.roleService.GetRoles(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range roles {
+		if r.Name == ref.Name && r.Global() == ref.Global {
+			return r.UID, nil
+		}
+	}
+	return "", ossaccesscontrol.ErrRoleNotFound.Errorf("role %s not found", ref.Name)
+}
@@ -0,0 +1,156 @@
+package accesscontrol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/ossaccesscontrol"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+const (
+	rolesConfig         = "testdata/roles"
+	brokenVersionConfig = "testdata/broken-version"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := &configReader{log: log.NewNopLogger()}
+
+	t.Run("should read roles and assignments", func(t *testing.T) {
+		configs, err := reader.readConfig(rolesConfig)
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+
+		cfg := configs[0]
+		require.Len(t, cfg.Roles, 2)
+		assert.Equal(t, "alerting_editor", cfg.Roles[0].UID)
+		assert.Equal(t, int64(2), cfg.Roles[0].Version)
+		assert.Equal(t, int64(1), cfg.Roles[0].OrgID)
+		assert.Len(t, cfg.Roles[0].Permissions, 2)
+		assert.True(t, cfg.Roles[1].Absent)
+
+		require.Len(t, cfg.Teams, 1)
+		assert.Equal(t, "SRE", cfg.Teams[0].Name)
+		assert.Equal(t, int64(1), cfg.Teams[0].OrgID)
+
+		require.Len(t, cfg.Users, 1)
+		assert.Equal(t, "oncall", cfg.Users[0].Name)
+		assert.Equal(t, int64(2), cfg.Users[0].OrgID)
+		assert.True(t, cfg.Users[0].Roles[0].Absent)
+	})
+
+	t.Run("should reject unsupported versions", func(t *testing.T) {
+		_, err := reader.readConfig(brokenVersionConfig)
+		assert.ErrorContains(t, err, "unsupported apiVersion 3")
+	})
+}
+
+func TestRolesProvisioner(t *testing.T) {
+	newProvisioner := func(roles *fakeRoleService) *rolesProvisioner {
+		return &rolesProvisioner{
+			log:         log.NewNopLogger(),
+			cfgProvider: &configReader{log: log.NewNopLogger()},
+			roleService: roles,
+			teamService: &teamtest.FakeService{ExpectedSearchTeams: team.SearchTeamQueryResult{Teams: []*team.TeamDTO{{ID: 3}}}},
+			userService: &usertest.FakeUserService{ExpectedUser: &user.User{ID: 4}},
+		}
+	}
+
+	t.Run("should create missing roles and assign them", func(t *testing.T) {
+		roles := &fakeRoleService{roles: map[string]*accesscontrol.RoleDTO{}}
+		require.NoError(t, newProvisioner(roles).applyChanges(context.Background(), rolesConfig))
+
+		require.Len(t, roles.created, 1)
+		assert.Equal(t, "alerting_editor", roles.created[0].UID)
+		assert.Empty(t, roles.updated)
+		assert.Empty(t, roles.deleted)
+		assert.Equal(t, []string{"alerting_editor"}, roles.teamRoles[3])
+	})
+
+	t.Run("should only update roles with a greater version", func(t *testing.T) {
+		roles := &fakeRoleService{roles: map[string]*accesscontrol.RoleDTO{
+			"alerting_editor": {UID: "alerting_editor", Name: "custom:alerting:editor", Version: 1},
+			accesscontrol.PrefixedRoleUID("custom:legacy"): {UID: accesscontrol.PrefixedRoleUID("custom:legacy"), Name: "custom:legacy", Version: 1},
+		}}
+		require.NoError(t, newProvisioner(roles).applyChanges(context.Background(), rolesConfig))
+		assert.Equal(t, []string{"alerting_editor"}, roles.updated)
+		assert.Equal(t, []string{accesscontrol.PrefixedRoleUID("custom:legacy")}, roles.deleted)
+
+		roles.updated = nil
+		roles.roles["alerting_editor"].Version = 2
+		require.NoError(t, newProvisioner(roles).applyChanges(context.Background(), rolesConfig))
+		assert.Empty(t, roles.updated)
+	})
+}
+
+type fakeRoleService struct {
+	roles     map[string]*accesscontrol.RoleDTO
+	created   []ossaccesscontrol.CreateRoleCommand
+	updated   []string
+	deleted   []string
+	teamRoles map[int64][]string
+}
+
+func (f *fakeRoleService) GetRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	result := make([]*accesscontrol.RoleDTO, 0, len(f.roles))
+	for _, r := range f.roles {
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (f *fakeRoleService) GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	if r, ok := f.roles[uid]; ok {
+		return r, nil
+	}
+	return nil, ossaccesscontrol.ErrRoleNotFound.Errorf("role not found")
+}
+
+func (f *fakeRoleService) CreateRole(ctx context.Context, orgID int64, cmd ossaccesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.created = append(f.created, cmd)
+	role := &accesscontrol.RoleDTO{OrgID: orgID, UID: cmd.UID, Name: cmd.Name, Version: cmd.Version}
+	f.roles[cmd.UID] = role
+	return role, nil
+}
+
+func (f *fakeRoleService) UpdateRole(ctx context.Context, orgID int64, uid string, cmd ossaccesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.updated = append(f.updated, uid)
+	return f.roles[uid], nil
+}
+
+func (f *fakeRoleService) DeleteRole(ctx context.Context, orgID int64, uid string, force bool) error {
+	f.deleted = append(f.deleted, uid)
+	delete(f.roles, uid)
+	return nil
+}
+
+func (f *fakeRoleService) AddUserRole(ctx context.Context, orgID, userID int64, uid string) error {
+	return nil
+}
+
+func (f *fakeRoleService) RemoveUserRole(ctx context.Context, orgID, userID int64, uid string) error {
+	if _, ok := f.roles[uid]; !ok {
+		return ossaccesscontrol.ErrRoleNotFound.Errorf("role not found")
+	}
+	return nil
+}
+
+func (f *fakeRoleService) AddTeamRole(ctx context.Context, orgID, teamID int64, uid string) error {
+	if f.teamRoles == nil {
+		f.teamRoles = map[int64][]string{}
+	}
+	f.teamRoles[teamID] = append(f.teamRoles[teamID], uid)
+	return nil
+}
+
+func (f *fakeRoleService) RemoveTeamRole(ctx context.Context, orgID, teamID int64, uid string) error {
+	return nil
+}
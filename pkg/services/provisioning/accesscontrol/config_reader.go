@@ -0,0 +1,100 @@
+package accesscontrol
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*rolesAsConfig, error) {
+	var configs []*rolesAsConfig
+	cr.log.Debug("Looking for access control provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read access control provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		cr.log.Debug("Parsing access control provisioning file", "path", path, "file.Name", file.Name())
+		cfg, err := cr.parseConfig(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Name(), err)
+		}
+		if err := validateConfig(cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration in %s: %w", file.Name(), err)
+		}
+		configs = append(configs, cfg)
+	}
+
+	return configs, nil
+}
+
+func (cr *configReader) parseConfig(filename string) (*rolesAsConfig, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var version configVersion
+	if err := yaml.Unmarshal(yamlFile, &version); err != nil {
+		return nil, err
+	}
+	if v := version.APIVersion.Value(); v != 1 && v != 2 {
+		return nil, fmt.Errorf("unsupported apiVersion %d", v)
+	}
+
+	var cfg *rolesAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToRolesFromConfig(), nil
+}
+
+func validateConfig(cfg *rolesAsConfig) error {
+	var errs []error
+	for i, role := range cfg.Roles {
+		if role.Name == "" {
+			errs = append(errs, fmt.Errorf("role item %d doesn't contain required field name", i+1))
+		}
+		if role.OrgID < 1 && !role.Global {
+			role.OrgID = 1
+		}
+	}
+
+	for _, assignments := range [][]*assignmentsFromConfig{cfg.Teams, cfg.Users} {
+		for i, a := range assignments {
+			if a.Name == "" {
+				errs = append(errs, fmt.Errorf("assignment item %d doesn't contain required field name or login", i+1))
+			}
+			if a.OrgID < 1 {
+				a.OrgID = 1
+			}
+			for j, ref := range a.Roles {
+				if ref.UID == "" && ref.Name == "" {
+					errs = append(errs, fmt.Errorf("role %d of %s doesn't contain required field uid or name", j+1, a.Name))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,156 @@
+package accesscontrol
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// rolesAsConfig is a normalized data object for access control config data. Any config version should be mappable
+// to this type.
+type rolesAsConfig struct {
+	Roles []*roleFromConfig
+	Teams []*assignmentsFromConfig
+	Users []*assignmentsFromConfig
+}
+
+type roleFromConfig struct {
+	UID         string
+	Name        string
+	DisplayName string
+	Description string
+	Group       string
+	Version     int64
+	OrgID       int64
+	Global      bool
+	Absent      bool
+	Force       bool
+	Permissions []accesscontrol.Permission
+}
+
+// assignmentsFromConfig are the roles of a team, identified by name, or of a user or service account,
+// identified by login.
+type assignmentsFromConfig struct {
+	Name  string
+	OrgID int64
+	Roles []*roleRefFromConfig
+}
+
+type roleRefFromConfig struct {
+	UID    string
+	Name   string
+	Global bool
+	Absent bool
+}
+
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// rolesAsConfigV1 is a mapping for the access control configs. The second version of the format,
+// used by Grafana Enterprise, shares the same layout for custom roles and their assignments.
+type rolesAsConfigV1 struct {
+	configVersion
+
+	Roles []*roleFromConfigV1        `json:"roles" yaml:"roles"`
+	Teams []*teamAssignmentsConfigV1 `json:"teams" yaml:"teams"`
+	Users []*userAssignmentsConfigV1 `json:"users" yaml:"users"`
+}
+
+type roleFromConfigV1 struct {
+	UID         values.StringValue      `json:"uid" yaml:"uid"`
+	Name        values.StringValue      `json:"name" yaml:"name"`
+	DisplayName values.StringValue      `json:"displayName" yaml:"displayName"`
+	Description values.StringValue      `json:"description" yaml:"description"`
+	Group       values.StringValue      `json:"group" yaml:"group"`
+	Version     values.Int64Value       `json:"version" yaml:"version"`
+	OrgID       values.Int64Value       `json:"orgId" yaml:"orgId"`
+	Global      values.BoolValue        `json:"global" yaml:"global"`
+	State       values.StringValue      `json:"state" yaml:"state"`
+	Force       values.BoolValue        `json:"force" yaml:"force"`
+	Permissions []*permissionFromConfig `json:"permissions" yaml:"permissions"`
+}
+
+type permissionFromConfig struct {
+	Action values.StringValue `json:"action" yaml:"action"`
+	Scope  values.StringValue `json:"scope" yaml:"scope"`
+}
+
+type teamAssignmentsConfigV1 struct {
+	Name  values.StringValue `json:"name" yaml:"name"`
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Roles []*roleRefConfigV1 `json:"roles" yaml:"roles"`
+}
+
+type userAssignmentsConfigV1 struct {
+	Login values.StringValue `json:"login" yaml:"login"`
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Roles []*roleRefConfigV1 `json:"roles" yaml:"roles"`
+}
+
+type roleRefConfigV1 struct {
+	UID    values.StringValue `json:"uid" yaml:"uid"`
+	Name   values.StringValue `json:"name" yaml:"name"`
+	Global values.BoolValue   `json:"global" yaml:"global"`
+	State  values.StringValue `json:"state" yaml:"state"`
+}
+
+const stateAbsent = "absent"
+
+// mapToRolesFromConfig maps config syntax to a normalized rolesAsConfig object.
+func (cfg *rolesAsConfigV1) mapToRolesFromConfig() *rolesAsConfig {
+	r := &rolesAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, role := range cfg.Roles {
+		permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			permissions = append(permissions, accesscontrol.Permission{Action: p.Action.Value(), Scope: p.Scope.Value()})
+		}
+		r.Roles = append(r.Roles, &roleFromConfig{
+			UID:         role.UID.Value(),
+			Name:        role.Name.Value(),
+			DisplayName: role.DisplayName.Value(),
+			Description: role.Description.Value(),
+			Group:       role.Group.Value(),
+			Version:     role.Version.Value(),
+			OrgID:       role.OrgID.Value(),
+			Global:      role.Global.Value(),
+			Absent:      role.State.Value() == stateAbsent,
+			Force:       role.Force.Value(),
+			Permissions: permissions,
+		})
+	}
+
+	for _, team := range cfg.Teams {
+		r.Teams = append(r.Teams, &assignmentsFromConfig{
+			Name:  team.Name.Value(),
+			OrgID: team.OrgID.Value(),
+			Roles: mapRoleRefs(team.Roles),
+		})
+	}
+
+	for _, user := range cfg.Users {
+		r.Users = append(r.Users, &assignmentsFromConfig{
+			Name:  user.Login.Value(),
+			OrgID: user.OrgID.Value(),
+			Roles: mapRoleRefs(user.Roles),
+		})
+	}
+
+	return r
+}
+
+func mapRoleRefs(refs []*roleRefConfigV1) []*roleRefFromConfig {
+	result := make([]*roleRefFromConfig, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, &roleRefFromConfig{
+			UID:    ref.UID.Value(),
+			Name:   ref.Name.Value(),
+			Global: ref.Global.Value(),
+			Absent: ref.State.Value() == stateAbsent,
+		})
+	}
+	return result
+}
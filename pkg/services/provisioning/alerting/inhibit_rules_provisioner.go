@@ -0,0 +1,55 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type InhibitRulesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultInhibitRulesProvisioner struct {
+	logger             log.Logger
+	inhibitRuleService provisioning.InhibitRuleService
+}
+
+func NewInhibitRulesProvisioner(logger log.Logger,
+	inhibitRuleService provisioning.InhibitRuleService) InhibitRulesProvisioner {
+	return &defaultInhibitRulesProvisioner{
+		logger:             logger,
+		inhibitRuleService: inhibitRuleService,
+	}
+}
+
+func (c *defaultInhibitRulesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, ir := range file.InhibitRules {
+			err := c.inhibitRuleService.UpdateInhibitRules(ctx, ir.OrgID,
+				ir.Rules, models.ProvenanceFile)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultInhibitRulesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, orgID := range file.ResetInhibitRules {
+			err := c.inhibitRuleService.ResetInhibitRules(ctx, int64(orgID))
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package alerting
+
+import (
+	"encoding/json"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type InhibitRulesV1 struct {
+	OrgID values.Int64Value `json:"orgId" yaml:"orgId"`
+	// We use JSONSliceValue here, as we want to have interpolation the values.
+	Rules values.JSONSliceValue `json:"rules" yaml:"rules"`
+}
+
+func (v1 *InhibitRulesV1) mapToModel() (InhibitRules, error) {
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	// We need the string json representation, so we marshal the rules back
+	// as a string and take the interpolated values at the same time.
+	data, err := json.Marshal(v1.Rules.Value())
+	if err != nil {
+		return InhibitRules{}, err
+	}
+	rules := []config.InhibitRule{}
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return InhibitRules{}, err
+	}
+	// We don't need any further validation here as it's done by
+	// the inhibition rule service.
+	return InhibitRules{
+		OrgID: orgID,
+		Rules: definitions.InhibitRules{Rules: rules},
+	}, nil
+}
+
+type InhibitRules struct {
+	OrgID int64
+	Rules definitions.InhibitRules
+}
@@ -0,0 +1,49 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInhibitRules(t *testing.T) {
+	const (
+		envKey   = "INHIBIT_CLUSTER_LABEL"
+		envValue = "cluster"
+	)
+	t.Setenv(envKey, envValue)
+
+	data := `orgId: 123
+rules:
+  - source_matchers:
+      - alertname = ClusterDown
+    target_match_re:
+      severity: warning|info
+    equal:
+      - ${INHIBIT_CLUSTER_LABEL}
+`
+	var model InhibitRulesV1
+
+	err := yaml.Unmarshal([]byte(data), &model)
+	require.NoError(t, err)
+	ir, err := model.mapToModel()
+	require.NoError(t, err)
+	require.Equal(t, int64(123), ir.OrgID)
+	require.Len(t, ir.Rules.Rules, 1)
+	rule := ir.Rules.Rules[0]
+	require.Equal(t, `alertname="ClusterDown"`, rule.SourceMatchers[0].String())
+	require.True(t, rule.TargetMatchRE["severity"].MatchString("info"))
+	require.Equal(t, envValue, string(rule.Equal[0]))
+	require.NoError(t, ir.Rules.Validate())
+}
+
+func TestInhibitRulesDefaultOrg(t *testing.T) {
+	var v1 InhibitRulesV1
+	err := yaml.Unmarshal([]byte("rules: []\n"), &v1)
+	require.NoError(t, err)
+	ir, err := v1.mapToModel()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), ir.OrgID)
+	require.Empty(t, ir.Rules.Rules)
+}
@@ -18,6 +18,8 @@ type ProvisionerConfig struct {
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
 	TemplateService            provisioning.TemplateService
+	InhibitRuleService         provisioning.InhibitRuleService
+	SilenceService             provisioning.SilenceService
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
@@ -58,6 +60,24 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("notification policies: %w", err)
 	}
+	irProvisioner := NewInhibitRulesProvisioner(logger, cfg.InhibitRuleService)
+	err = irProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("inhibition rules: %w", err)
+	}
+	sProvisioner := NewSilencesProvisioner(logger, cfg.SilenceService)
+	err = sProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silences: %w", err)
+	}
+	err = sProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silences: %w", err)
+	}
+	err = irProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("inhibition rules: %w", err)
+	}
 	err = npProvisioner.Unprovision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("notification policies: %w", err)
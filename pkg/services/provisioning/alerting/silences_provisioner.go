@@ -0,0 +1,55 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type SilencesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultSilencesProvisioner struct {
+	logger         log.Logger
+	silenceService provisioning.SilenceService
+}
+
+func NewSilencesProvisioner(logger log.Logger,
+	silenceService provisioning.SilenceService) SilencesProvisioner {
+	return &defaultSilencesProvisioner{
+		logger:         logger,
+		silenceService: silenceService,
+	}
+}
+
+func (c *defaultSilencesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, silence := range file.Silences {
+			_, err := c.silenceService.UpsertSilenceDefinition(ctx, silence.OrgID,
+				silence.Silence, models.ProvenanceFile)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultSilencesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteSilence := range file.DeleteSilences {
+			err := c.silenceService.DeleteSilenceDefinition(ctx, deleteSilence.OrgID, deleteSilence.UID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
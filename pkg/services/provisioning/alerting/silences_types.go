@@ -0,0 +1,57 @@
+package alerting
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type SilenceV1 struct {
+	OrgID   values.Int64Value             `json:"orgId" yaml:"orgId"`
+	Silence definitions.SilenceDefinition `json:",inline" yaml:",inline"`
+}
+
+func (v1 *SilenceV1) mapToModel() Silence {
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	// We don't need any further validation here as it's done by
+	// the silence service.
+	return Silence{
+		OrgID:   orgID,
+		Silence: v1.Silence,
+	}
+}
+
+type Silence struct {
+	OrgID   int64
+	Silence definitions.SilenceDefinition
+}
+
+type DeleteSilenceV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteSilenceV1) mapToModel() (DeleteSilence, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteSilence{}, errors.New("delete silence missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteSilence{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteSilence struct {
+	OrgID int64
+	UID   string
+}
@@ -0,0 +1,91 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSilences(t *testing.T) {
+	data := `orgId: 123
+uid: weekly-maintenance
+matchers:
+  - cluster = prod
+  - team =~ "ops|infra"
+comment: Weekly maintenance window
+schedule: 0 2 * * SAT
+duration: 4h
+`
+	var model SilenceV1
+
+	err := yaml.Unmarshal([]byte(data), &model)
+	require.NoError(t, err)
+	s := model.mapToModel()
+	require.Equal(t, int64(123), s.OrgID)
+	require.Equal(t, "weekly-maintenance", s.Silence.UID)
+	require.Len(t, s.Silence.Matchers, 2)
+	require.Equal(t, `cluster="prod"`, s.Silence.Matchers[0].String())
+	require.Equal(t, `team=~"ops|infra"`, s.Silence.Matchers[1].String())
+	require.Equal(t, "0 2 * * SAT", s.Silence.Schedule)
+	require.Equal(t, 4*time.Hour, time.Duration(s.Silence.Duration))
+	require.NoError(t, s.Silence.Validate())
+}
+
+func TestOneOffSilences(t *testing.T) {
+	data := `uid: release-freeze
+matchers:
+  - team = backend
+comment: Release freeze
+startsAt: 2024-12-24T00:00:00Z
+endsAt: 2024-12-27T00:00:00Z
+`
+	var model SilenceV1
+
+	err := yaml.Unmarshal([]byte(data), &model)
+	require.NoError(t, err)
+	s := model.mapToModel()
+	require.Equal(t, time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), *s.Silence.StartsAt)
+	require.Equal(t, time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC), *s.Silence.EndsAt)
+	require.NoError(t, s.Silence.Validate())
+}
+
+func TestSilencesWithTimeIntervals(t *testing.T) {
+	data := `uid: nightly-backup
+matchers:
+  - job = backup
+comment: Nightly backup
+time_intervals:
+  - times:
+      - start_time: "01:00"
+        end_time: "03:00"
+    location: Europe/Berlin
+`
+	var model SilenceV1
+
+	err := yaml.Unmarshal([]byte(data), &model)
+	require.NoError(t, err)
+	s := model.mapToModel()
+	require.Equal(t, int64(1), s.OrgID)
+	require.Len(t, s.Silence.TimeIntervals, 1)
+	require.Equal(t, "Europe/Berlin", s.Silence.TimeIntervals[0].Location.String())
+	require.NoError(t, s.Silence.Validate())
+}
+
+func TestDeleteSilences(t *testing.T) {
+	t.Run("a valid delete silence should not error", func(t *testing.T) {
+		var model DeleteSilenceV1
+		require.NoError(t, yaml.Unmarshal([]byte("orgId: 2\nuid: weekly-maintenance\n"), &model))
+		ds, err := model.mapToModel()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), ds.OrgID)
+		require.Equal(t, "weekly-maintenance", ds.UID)
+	})
+	t.Run("a delete silence without uid should error", func(t *testing.T) {
+		var model DeleteSilenceV1
+		require.NoError(t, yaml.Unmarshal([]byte("orgId: 2\n"), &model))
+		_, err := model.mapToModel()
+		require.Error(t, err)
+	})
+}
@@ -26,6 +26,10 @@ type AlertingFile struct {
 	DeleteMuteTimes     []DeleteMuteTime
 	Templates           []Template
 	DeleteTemplates     []DeleteTemplate
+	InhibitRules        []InhibitRules
+	ResetInhibitRules   []OrgID
+	Silences            []Silence
+	DeleteSilences      []DeleteSilence
 }
 
 type AlertingFileV1 struct {
@@ -41,6 +45,10 @@ type AlertingFileV1 struct {
 	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
 	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
 	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	InhibitRules        []InhibitRulesV1        `json:"inhibitRules" yaml:"inhibitRules"`
+	ResetInhibitRules   []values.Int64Value     `json:"resetInhibitRules" yaml:"resetInhibitRules"`
+	Silences            []SilenceV1             `json:"silences" yaml:"silences"`
+	DeleteSilences      []DeleteSilenceV1       `json:"deleteSilences" yaml:"deleteSilences"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,9 +69,43 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapInhibitRules(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing inhibition rules: %w", err)
+	}
+	if err := fileV1.mapSilences(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing silences: %w", err)
+	}
 	return alertingFile, nil
 }
 
+func (fileV1 *AlertingFileV1) mapSilences(alertingFile *AlertingFile) error {
+	for _, sV1 := range fileV1.Silences {
+		alertingFile.Silences = append(alertingFile.Silences, sV1.mapToModel())
+	}
+	for _, deleteV1 := range fileV1.DeleteSilences {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteSilences = append(alertingFile.DeleteSilences, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AlertingFileV1) mapInhibitRules(alertingFile *AlertingFile) error {
+	for _, irV1 := range fileV1.InhibitRules {
+		ir, err := irV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.InhibitRules = append(alertingFile.InhibitRules, ir)
+	}
+	for _, orgIDV1 := range fileV1.ResetInhibitRules {
+		alertingFile.ResetInhibitRules = append(alertingFile.ResetInhibitRules, OrgID(orgIDV1.Value()))
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapTemplates(alertingFile *AlertingFile) error {
 	for _, ttV1 := range fileV1.Templates {
 		alertingFile.Templates = append(alertingFile.Templates, ttV1.mapToModel())
@@ -104,6 +104,11 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 			dashboard.Type = "file"
 		}
 
+		// Dashboards synced with git are edited in the UI and committed back to the repository.
+		if dashboard.Type == "git" {
+			dashboard.AllowUIUpdates = true
+		}
+
 		if dashboard.UpdateIntervalSeconds == 0 {
 			dashboard.UpdateIntervalSeconds = 10
 		}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
@@ -20,6 +21,8 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	CheckDashboardGitConflict(ctx context.Context, dash *dashboards.Dashboard) error
+	SaveDashboardToGit(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, message string) error
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
@@ -133,7 +136,7 @@ func getFileReaders(
 
 	for _, config := range configs {
 		switch config.Type {
-		case "file":
+		case "file", "git":
 			fileReader, err := NewDashboardFileReader(config, logger.New("type", config.Type, "name", config.Name), service, store)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
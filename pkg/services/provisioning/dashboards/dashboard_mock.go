@@ -1,6 +1,11 @@
 package dashboards
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
 
 // Calls is a mock implementation of the provisioner interface
 type calls struct {
@@ -8,6 +13,8 @@ type calls struct {
 	PollChanges                 []any
 	GetProvisionerResolvedPath  []any
 	GetAllowUIUpdatesFromConfig []any
+	CheckDashboardGitConflict   []any
+	SaveDashboardToGit          []any
 }
 
 // ProvisionerMock is a mock implementation of `Provisioner`
@@ -17,6 +24,8 @@ type ProvisionerMock struct {
 	PollChangesFunc                 func(ctx context.Context)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
+	CheckDashboardGitConflictFunc   func(ctx context.Context, dash *dashboards.Dashboard) error
+	SaveDashboardToGitFunc          func(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, message string) error
 }
 
 // NewDashboardProvisionerMock returns a new dashboardprovisionermock
@@ -67,3 +76,21 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// CheckDashboardGitConflict is a mock implementation of `Provisioner.CheckDashboardGitConflict`
+func (dpm *ProvisionerMock) CheckDashboardGitConflict(ctx context.Context, dash *dashboards.Dashboard) error {
+	dpm.Calls.CheckDashboardGitConflict = append(dpm.Calls.CheckDashboardGitConflict, dash)
+	if dpm.CheckDashboardGitConflictFunc != nil {
+		return dpm.CheckDashboardGitConflictFunc(ctx, dash)
+	}
+	return nil
+}
+
+// SaveDashboardToGit is a mock implementation of `Provisioner.SaveDashboardToGit`
+func (dpm *ProvisionerMock) SaveDashboardToGit(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, message string) error {
+	dpm.Calls.SaveDashboardToGit = append(dpm.Calls.SaveDashboardToGit, dash)
+	if dpm.SaveDashboardToGitFunc != nil {
+		return dpm.SaveDashboardToGitFunc(ctx, dash, user, message)
+	}
+	return nil
+}
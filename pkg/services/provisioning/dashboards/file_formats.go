@@ -0,0 +1,112 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/go-jsonnet"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	extJSON    = ".json"
+	extYAML    = ".yaml"
+	extYML     = ".yml"
+	extJsonnet = ".jsonnet"
+)
+
+// jsonnetOptions configures how .jsonnet dashboard files are evaluated.
+type jsonnetOptions struct {
+	enabled bool
+	// libPaths are the directories searched for imported files, in addition to the directory of the dashboard file.
+	libPaths []string
+}
+
+func newJsonnetOptions(options map[string]any) (jsonnetOptions, error) {
+	enabled, _ := options["jsonnet"].(bool)
+	opts := jsonnetOptions{enabled: enabled}
+
+	switch libPaths := options["jsonnetLibPaths"].(type) {
+	case nil:
+	case string:
+		opts.libPaths = []string{libPaths}
+	case []any:
+		for _, p := range libPaths {
+			libPath, ok := p.(string)
+			if !ok {
+				return opts, fmt.Errorf("failed to load dashboards, jsonnetLibPaths must be a list of strings")
+			}
+			opts.libPaths = append(opts.libPaths, libPath)
+		}
+	default:
+		return opts, fmt.Errorf("failed to load dashboards, jsonnetLibPaths must be a list of strings")
+	}
+
+	return opts, nil
+}
+
+// fileExtensions returns the extensions of the dashboard files the reader provisions.
+func (fr *FileReader) fileExtensions() []string {
+	extensions := []string{extJSON, extYAML, extYML}
+	if fr.jsonnet.enabled {
+		extensions = append(extensions, extJsonnet)
+	}
+	return extensions
+}
+
+// renderDashboardFile returns the JSON model of the dashboard defined in the file at path with the given content,
+// and the paths of the other files the model was rendered from, such as the files imported by a jsonnet file.
+func (fr *FileReader) renderDashboardFile(path string, content []byte) ([]byte, []string, error) {
+	switch filepath.Ext(path) {
+	case extYAML, extYML:
+		data, err := yamlToJSON(content)
+		return data, nil, err
+	case extJsonnet:
+		return fr.evaluateJsonnet(path, content)
+	default:
+		return content, nil, nil
+	}
+}
+
+func yamlToJSON(content []byte) ([]byte, error) {
+	var model map[string]any
+	if err := yaml.Unmarshal(content, &model); err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert yaml to json: %w", err)
+	}
+	return data, nil
+}
+
+func (fr *FileReader) evaluateJsonnet(path string, content []byte) ([]byte, []string, error) {
+	vm := jsonnet.MakeVM()
+	// Imports of anonymous snippets aren't resolved relative to their file, so the directory of the dashboard file is
+	// added as the last library path, which is searched first.
+	jpaths := append(append([]string{}, fr.jsonnet.libPaths...), filepath.Dir(path))
+	importer := &recordingImporter{FileImporter: &jsonnet.FileImporter{JPaths: jpaths}}
+	vm.Importer(importer)
+
+	data, err := vm.EvaluateAnonymousSnippet(path, string(content))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to evaluate jsonnet: %w", err)
+	}
+	return []byte(data), importer.imported, nil
+}
+
+// recordingImporter is a jsonnet file importer that keeps track of the files that were imported.
+type recordingImporter struct {
+	*jsonnet.FileImporter
+	imported []string
+}
+
+func (i *recordingImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	contents, foundAt, err := i.FileImporter.Import(importedFrom, importedPath)
+	if err == nil {
+		i.imported = append(i.imported, foundAt)
+	}
+	return contents, foundAt, err
+}
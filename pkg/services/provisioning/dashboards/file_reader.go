@@ -35,9 +35,17 @@ type FileReader struct {
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
 
+	// jsonnet configures the evaluation of .jsonnet dashboard files, which are skipped unless it's enabled.
+	jsonnet jsonnetOptions
+
+	// git is set for readers of type git, which sync the dashboards with a Git repository.
+	git *gitRepository
+
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
+	folderID                int64
+	folderUID               string
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
@@ -58,15 +66,35 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
 	}
 
-	return &FileReader{
+	jsonnet, err := newJsonnetOptions(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	fr := &FileReader{
 		Cfg:                          cfg,
 		Path:                         path,
 		log:                          log,
 		dashboardProvisioningService: service,
 		dashboardStore:               dashboardStore,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		jsonnet:                      jsonnet,
 		usageTracker:                 newUsageTracker(),
-	}, nil
+	}
+
+	if cfg.Type == "git" {
+		if foldersFromFilesStructure {
+			return nil, fmt.Errorf("'foldersFromFilesStructure' option is not supported for git providers")
+		}
+		if cfg.Folder == "" {
+			return nil, fmt.Errorf("'folder' is required for git providers")
+		}
+		branch, _ := cfg.Options["branch"].(string)
+		remote, _ := cfg.Options["remote"].(string)
+		fr.git = newGitRepository(fr.resolvedPath(), branch, remote)
+	}
+
+	return fr, nil
 }
 
 // pollChanges periodically runs walkDisk based on interval specified in the config.
@@ -93,6 +121,13 @@ func (fr *FileReader) walkDisk(ctx context.Context) error {
 		return err
 	}
 
+	if fr.git != nil {
+		// On conflicts the working tree is left untouched, so the dashboards on disk can still be applied.
+		if err := fr.git.pull(ctx); err != nil {
+			fr.log.Error("Failed to pull dashboards from git", "path", resolvedPath, "branch", fr.git.branch, "error", err)
+		}
+	}
+
 	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(ctx, fr.dashboardProvisioningService, fr.Cfg.Name)
 	if err != nil {
 		return err
@@ -100,7 +135,7 @@ func (fr *FileReader) walkDisk(ctx context.Context) error {
 
 	// Find relevant files
 	filesFoundOnDisk := map[string]os.FileInfo{}
-	if err := filepath.Walk(resolvedPath, createWalkFn(filesFoundOnDisk)); err != nil {
+	if err := filepath.Walk(resolvedPath, createWalkFn(filesFoundOnDisk, fr.fileExtensions())); err != nil {
 		return err
 	}
 
@@ -116,6 +151,14 @@ func (fr *FileReader) walkDisk(ctx context.Context) error {
 		return err
 	}
 
+	if fr.git != nil {
+		files := make([]string, 0, len(filesFoundOnDisk))
+		for path := range filesFoundOnDisk {
+			files = append(files, path)
+		}
+		fr.git.remember(ctx, files)
+	}
+
 	fr.mux.Lock()
 	defer fr.mux.Unlock()
 
@@ -145,6 +188,10 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 		return err
 	}
 
+	fr.mux.Lock()
+	fr.folderID, fr.folderUID = folderID, folderUID
+	fr.mux.Unlock()
+
 	// save dashboards based on json files
 	for path, fileInfo := range filesFoundOnDisk {
 		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, folderUID, fileInfo, dashboardRefs)
@@ -284,7 +331,7 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		dp := &dashboards.DashboardProvisioning{
 			ExternalID: path,
 			Name:       fr.Cfg.Name,
-			Updated:    resolvedFileInfo.ModTime().Unix(),
+			Updated:    jsonFile.lastModified.Unix(),
 			CheckSum:   jsonFile.checkSum,
 		}
 		_, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(ctx, dash, dp)
@@ -372,13 +419,13 @@ func resolveSymlink(fileinfo os.FileInfo, path string) (os.FileInfo, error) {
 	return fileinfo, err
 }
 
-func createWalkFn(filesOnDisk map[string]os.FileInfo) filepath.WalkFunc {
+func createWalkFn(filesOnDisk map[string]os.FileInfo, extensions []string) filepath.WalkFunc {
 	return func(path string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		isValid, err := validateWalkablePath(fileInfo)
+		isValid, err := validateWalkablePath(fileInfo, extensions)
 		if !isValid {
 			return err
 		}
@@ -388,7 +435,7 @@ func createWalkFn(filesOnDisk map[string]os.FileInfo) filepath.WalkFunc {
 	}
 }
 
-func validateWalkablePath(fileInfo os.FileInfo) (bool, error) {
+func validateWalkablePath(fileInfo os.FileInfo, extensions []string) (bool, error) {
 	if fileInfo.IsDir() {
 		if strings.HasPrefix(fileInfo.Name(), ".") {
 			return false, filepath.SkipDir
@@ -396,11 +443,13 @@ func validateWalkablePath(fileInfo os.FileInfo) (bool, error) {
 		return false, nil
 	}
 
-	if !strings.HasSuffix(fileInfo.Name(), ".json") {
-		return false, nil
+	for _, ext := range extensions {
+		if strings.HasSuffix(fileInfo.Name(), ext) {
+			return true, nil
+		}
 	}
 
-	return true, nil
+	return false, nil
 }
 
 type dashboardJSONFile struct {
@@ -427,6 +476,21 @@ func (fr *FileReader) readDashboardFromFile(path string, lastModified time.Time,
 		return nil, err
 	}
 
+	all, dependencies, err := fr.renderDashboardFile(path, all)
+	if err != nil {
+		return nil, err
+	}
+	// the dashboard was last modified when the newest of the files it was rendered from was modified
+	for _, dependency := range dependencies {
+		info, err := os.Stat(dependency)
+		if err != nil {
+			return nil, err
+		}
+		if info.ModTime().After(lastModified) {
+			lastModified = info.ModTime()
+		}
+	}
+
 	checkSum, err := util.Md5SumString(string(all))
 	if err != nil {
 		return nil, err
@@ -24,6 +24,9 @@ const (
 	containingID              = "testdata/test-dashboards/containing-id"
 	unprovision               = "testdata/test-dashboards/unprovision"
 	foldersFromFilesStructure = "testdata/test-dashboards/folders-from-files-structure"
+	otherFormats              = "testdata/test-dashboards/other-formats"
+	jsonnetLib                = "testdata/test-dashboards/jsonnet-lib"
+	brokenJsonnet             = "testdata/test-dashboards/broken-jsonnet"
 	configName                = "default"
 )
 
@@ -304,6 +307,100 @@ func TestDashboardFileReader(t *testing.T) {
 			require.NoError(t, err)
 		})
 
+		t.Run("Can read yaml and jsonnet dashboards", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = otherFormats
+			cfg.Options["jsonnet"] = true
+			cfg.Options["jsonnetLibPaths"] = []any{jsonnetLib}
+
+			titles := []string{}
+			fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&dashboards.Dashboard{}, nil).Times(2).
+				Run(func(args mock.Arguments) {
+					dto := args.Get(1).(*dashboards.SaveDashboardDTO)
+					titles = append(titles, dto.Dashboard.Title)
+					require.Equal(t, "Welcome", dto.Dashboard.Data.Get("panels").GetIndex(0).Get("title").MustString())
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"YAML dashboard", "Jsonnet dashboard"}, titles)
+		})
+
+		t.Run("Skips jsonnet dashboards unless enabled", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = otherFormats
+
+			fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&dashboards.Dashboard{}, nil).Once().
+				Run(func(args mock.Arguments) {
+					require.Equal(t, "YAML dashboard", args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Jsonnet evaluation errors should be returned for the file", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = brokenJsonnet
+			cfg.Options["jsonnet"] = true
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.NoError(t, err)
+
+			_, err = reader.readDashboardFromFile(filepath.Join(brokenJsonnet, "broken.jsonnet"), time.Now(), 0, "")
+			require.ErrorContains(t, err, "failed to evaluate jsonnet")
+			require.ErrorContains(t, err, "missing.libsonnet")
+		})
+
+		t.Run("Changes to imported jsonnet files should be detected", func(t *testing.T) {
+			setup()
+			dir := t.TempDir()
+			dashboardFile := filepath.Join(dir, "dashboard.jsonnet")
+			libFile := filepath.Join(dir, "lib.libsonnet")
+			require.NoError(t, os.WriteFile(dashboardFile, []byte(`(import 'lib.libsonnet') + { uid: 'jsonnet' }`), 0600))
+			require.NoError(t, os.WriteFile(libFile, []byte(`{ title: 'Before' }`), 0600))
+			cfg.Options["path"] = dir
+			cfg.Options["jsonnet"] = true
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.NoError(t, err)
+
+			fileModified := time.Now().Add(-time.Hour).Truncate(time.Second)
+			before, err := reader.readDashboardFromFile(dashboardFile, fileModified, 0, "")
+			require.NoError(t, err)
+
+			libModified := time.Now().Truncate(time.Second)
+			require.NoError(t, os.WriteFile(libFile, []byte(`{ title: 'After' }`), 0600))
+			require.NoError(t, os.Chtimes(libFile, libModified, libModified))
+
+			after, err := reader.readDashboardFromFile(dashboardFile, fileModified, 0, "")
+			require.NoError(t, err)
+			require.NotEqual(t, before.checkSum, after.checkSum)
+			require.Equal(t, "After", after.dashboard.Dashboard.Title)
+			require.True(t, libModified.Equal(after.lastModified))
+		})
+
+		t.Run("Invalid jsonnet library paths should return error", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = otherFormats
+			cfg.Options["jsonnetLibPaths"] = 1
+
+			_, err := NewDashboardFileReader(cfg, logger, nil, nil)
+			require.Error(t, err)
+		})
+
 		t.Run("Invalid configuration should return error", func(t *testing.T) {
 			setup()
 			cfg := &config{
@@ -413,12 +510,12 @@ func TestDashboardFileReader(t *testing.T) {
 		noFiles := map[string]os.FileInfo{}
 
 		t.Run("should skip dirs that starts with .", func(t *testing.T) {
-			shouldSkip := createWalkFn(noFiles)("path", &FakeFileInfo{isDirectory: true, name: ".folder"}, nil)
+			shouldSkip := createWalkFn(noFiles, []string{".json"})("path", &FakeFileInfo{isDirectory: true, name: ".folder"}, nil)
 			require.Equal(t, shouldSkip, filepath.SkipDir)
 		})
 
 		t.Run("should keep walking if file is not .json", func(t *testing.T) {
-			shouldSkip := createWalkFn(noFiles)("path", &FakeFileInfo{isDirectory: true, name: "folder"}, nil)
+			shouldSkip := createWalkFn(noFiles, []string{".json"})("path", &FakeFileInfo{isDirectory: true, name: "folder"}, nil)
 			require.Nil(t, shouldSkip)
 		})
 	})
@@ -0,0 +1,383 @@
+package dashboards
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+var (
+	// ErrGitSyncConflict is returned when a dashboard can't be written to, or pulled from, a Git repository
+	// without overwriting changes made elsewhere.
+	ErrGitSyncConflict = errors.New("git sync conflict")
+)
+
+// gitAuthor is the author of a commit made on behalf of a Grafana user.
+type gitAuthor struct {
+	Name  string
+	Email string
+}
+
+func (a gitAuthor) String() string {
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+}
+
+// gitRepository syncs a directory of dashboards with a branch of a local Git repository, and optionally
+// with the same branch of a remote. It shells out to the git binary.
+type gitRepository struct {
+	path   string
+	branch string
+	remote string
+
+	mu sync.Mutex
+	// known holds the blob hash of every dashboard file as Grafana last applied or committed it,
+	// keyed by path relative to the repository root. It is used to detect conflicting changes.
+	known map[string]string
+	// conflict is set when pulling changes failed because of a conflict. It is cleared by the next
+	// successful pull.
+	conflict error
+}
+
+func newGitRepository(path, branch, remote string) *gitRepository {
+	if branch == "" {
+		branch = "main"
+	}
+	return &gitRepository{
+		path:   path,
+		branch: branch,
+		remote: remote,
+		known:  map[string]string{},
+	}
+}
+
+func (g *gitRepository) run(ctx context.Context, args ...string) (string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G204 warning on this one because the arguments come from the provisioning configuration
+	// and from Grafana itself.
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.path
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// checkout makes sure the configured branch is checked out.
+func (g *gitRepository) checkout(ctx context.Context) error {
+	current, err := g.run(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return err
+	}
+	if current == g.branch {
+		return nil
+	}
+	_, err = g.run(ctx, "checkout", g.branch)
+	return err
+}
+
+// pull fetches the branch from the remote and fast-forwards or rebases local commits on top of it.
+// If the rebase conflicts, it is aborted, the working tree is left as it was and ErrGitSyncConflict is returned.
+func (g *gitRepository) pull(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkout(ctx); err != nil {
+		return err
+	}
+	if g.remote == "" {
+		g.conflict = nil
+		return nil
+	}
+
+	if _, err := g.run(ctx, "fetch", g.remote, g.branch); err != nil {
+		return err
+	}
+	if _, err := g.run(ctx, "merge", "--ff-only", "FETCH_HEAD"); err != nil {
+		if _, err := g.run(ctx, "rebase", "FETCH_HEAD"); err != nil {
+			if _, abortErr := g.run(ctx, "rebase", "--abort"); abortErr != nil {
+				return fmt.Errorf("failed to abort rebase after %v: %w", err, abortErr)
+			}
+			g.conflict = fmt.Errorf("%w: local commits on branch %s conflict with %s/%s: %v", ErrGitSyncConflict, g.branch, g.remote, g.branch, err)
+			return g.conflict
+		}
+	}
+	g.conflict = nil
+
+	// Push commits that could not be pushed before, e.g. because the remote was ahead.
+	ahead, err := g.run(ctx, "rev-list", "--count", "FETCH_HEAD..HEAD")
+	if err != nil {
+		return err
+	}
+	if ahead != "0" {
+		if _, err := g.run(ctx, "push", g.remote, "HEAD:"+g.branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remember records the current content of the dashboard files as known to Grafana.
+func (g *gitRepository) remember(ctx context.Context, files []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, file := range files {
+		rel, err := filepath.Rel(g.path, file)
+		if err != nil {
+			continue
+		}
+		hash, err := g.run(ctx, "hash-object", rel)
+		if err != nil {
+			continue
+		}
+		g.known[filepath.ToSlash(rel)] = hash
+	}
+}
+
+// blobAt returns the hash of the file at the given revision, or an empty string if it doesn't exist there.
+func (g *gitRepository) blobAt(ctx context.Context, rev, rel string) string {
+	hash, err := g.run(ctx, "rev-parse", "--verify", "--quiet", rev+":"+rel)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// check returns ErrGitSyncConflict if file can't be committed because it was changed in the repository since
+// Grafana last applied it, or because the branch conflicts with the remote.
+func (g *gitRepository) check(ctx context.Context, file string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, err := g.checkFile(ctx, file)
+	return err
+}
+
+// checkFile does the checks of check and returns the path of file relative to the repository root.
+// It must be called with mu held.
+func (g *gitRepository) checkFile(ctx context.Context, file string) (string, error) {
+	if g.conflict != nil {
+		return "", g.conflict
+	}
+
+	rel, err := filepath.Rel(g.path, file)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+
+	if err := g.checkout(ctx); err != nil {
+		return "", err
+	}
+
+	revs := []string{"HEAD"}
+	if g.remote != "" {
+		if _, err := g.run(ctx, "fetch", g.remote, g.branch); err != nil {
+			return "", err
+		}
+		revs = append(revs, "FETCH_HEAD")
+	}
+	for _, rev := range revs {
+		if g.blobAt(ctx, rev, rel) != g.known[rel] {
+			return "", fmt.Errorf("%w: %s was changed in %s since it was last provisioned, reload the dashboard and try again", ErrGitSyncConflict, rel, rev)
+		}
+	}
+	return rel, nil
+}
+
+// commit writes content to file, commits it to the branch with the given author and pushes it to the remote.
+// ErrGitSyncConflict is returned if the file was changed in the repository since Grafana last applied it.
+func (g *gitRepository) commit(ctx context.Context, file string, content []byte, author gitAuthor, message string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rel, err := g.checkFile(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+		return err
+	}
+	// nolint:gosec
+	// The file is within the configured repository path.
+	if err := os.WriteFile(file, content, 0640); err != nil {
+		return err
+	}
+	if _, err := g.run(ctx, "add", "--", rel); err != nil {
+		return err
+	}
+	// Nothing to commit if the dashboard didn't change.
+	if _, err := g.run(ctx, "diff", "--cached", "--quiet", "--", rel); err == nil {
+		return nil
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Update %s", rel)
+	}
+	if _, err := g.run(ctx,
+		"-c", "user.name="+author.Name, "-c", "user.email="+author.Email,
+		"commit", "--author", author.String(), "-m", message, "--", rel,
+	); err != nil {
+		return err
+	}
+
+	hash, err := g.run(ctx, "hash-object", rel)
+	if err != nil {
+		return err
+	}
+	g.known[rel] = hash
+
+	if g.remote == "" {
+		return nil
+	}
+	if _, err := g.run(ctx, "push", g.remote, "HEAD:"+g.branch); err != nil {
+		return fmt.Errorf("failed to push dashboard %s, it will be pushed with the next change: %w", rel, err)
+	}
+	return nil
+}
+
+// syncsFolder returns true if the reader syncs the given folder with a Git repository.
+func (fr *FileReader) syncsFolder(orgID int64, folderID int64, folderUID string) bool {
+	if fr.git == nil || fr.Cfg.OrgID != orgID {
+		return false
+	}
+
+	fr.mux.RLock()
+	defer fr.mux.RUnlock()
+
+	if folderUID != "" {
+		return folderUID == fr.folderUID
+	}
+	return folderID != 0 && folderID == fr.folderID
+}
+
+// dashboardFile returns the file of the reader's Git repository the dashboard is written to. Dashboards provisioned
+// by the reader are written back to the file they were provisioned from, new dashboards are written to <uid>.json.
+func (fr *FileReader) dashboardFile(ctx context.Context, dash *dashboards.Dashboard) (string, error) {
+	file := filepath.Join(fr.resolvedPath(), dash.UID+".json")
+	if dash.ID != 0 {
+		provisioned, err := getProvisionedDashboardsByPath(ctx, fr.dashboardProvisioningService, fr.Cfg.Name)
+		if err != nil {
+			return "", err
+		}
+		for path, p := range provisioned {
+			if p.DashboardID == dash.ID {
+				file = path
+				break
+			}
+		}
+	}
+	if filepath.Ext(file) == extJsonnet {
+		return "", fmt.Errorf("%s is rendered from jsonnet and can't be updated from Grafana", filepath.Base(file))
+	}
+	return file, nil
+}
+
+// checkDashboard returns an error if the dashboard can't be written to the reader's Git repository.
+func (fr *FileReader) checkDashboard(ctx context.Context, dash *dashboards.Dashboard) error {
+	file, err := fr.dashboardFile(ctx, dash)
+	if err != nil {
+		return err
+	}
+	return fr.git.check(ctx, file)
+}
+
+// commitDashboard writes the dashboard to the reader's Git repository.
+func (fr *FileReader) commitDashboard(ctx context.Context, dash *dashboards.Dashboard, author gitAuthor, message string) error {
+	file, err := fr.dashboardFile(ctx, dash)
+	if err != nil {
+		return err
+	}
+
+	// copy the top level so that the dashboard being saved is not modified
+	fields := map[string]any{}
+	for k, v := range dash.Data.MustMap() {
+		fields[k] = v
+	}
+	data := simplejson.NewFromAny(fields)
+	data.Del("id")
+	data.Set("uid", dash.UID)
+
+	var content []byte
+	switch filepath.Ext(file) {
+	case extYAML, extYML:
+		model, err := data.Map()
+		if err != nil {
+			return err
+		}
+		content, err = yaml.Marshal(model)
+		if err != nil {
+			return err
+		}
+	default:
+		pretty, err := data.EncodePretty()
+		if err != nil {
+			return err
+		}
+		content = append(pretty, '\n')
+	}
+
+	return fr.git.commit(ctx, file, content, author, message)
+}
+
+// CheckDashboardGitConflict returns an error if a dashboard about to be saved through the API can't be committed to
+// the Git repository its folder is bound to, so that the save can be rejected before the dashboard is stored.
+// It does nothing if the folder isn't synced with Git, or for new dashboards without UID, which get a new file.
+func (provider *Provisioner) CheckDashboardGitConflict(ctx context.Context, dash *dashboards.Dashboard) error {
+	if dash.ID == 0 && dash.UID == "" {
+		return nil
+	}
+	// nolint:staticcheck
+	folderID := dash.FolderID
+	for _, reader := range provider.fileReaders {
+		if !reader.syncsFolder(dash.OrgID, folderID, dash.FolderUID) {
+			continue
+		}
+		if err := reader.checkDashboard(ctx, dash); err != nil {
+			return fmt.Errorf("failed to commit dashboard %s to git: %w", dash.UID, err)
+		}
+	}
+	return nil
+}
+
+// SaveDashboardToGit commits a dashboard saved through the API to the Git repository its folder is bound to.
+// It does nothing if the folder isn't synced with Git. It must be called with the dashboard as it was saved.
+func (provider *Provisioner) SaveDashboardToGit(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, message string) error {
+	// nolint:staticcheck
+	folderID := dash.FolderID
+	for _, reader := range provider.fileReaders {
+		if !reader.syncsFolder(dash.OrgID, folderID, dash.FolderUID) {
+			continue
+		}
+
+		author := gitAuthor{Name: user.GetDisplayName(), Email: user.GetEmail()}
+		if author.Name == "" {
+			author.Name = user.GetLogin()
+		}
+		if author.Email == "" {
+			author.Email = user.GetLogin()
+		}
+
+		if err := reader.commitDashboard(ctx, dash, author, message); err != nil {
+			return fmt.Errorf("failed to commit dashboard %s to git: %w", dash.UID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupGitRepositories creates a bare remote repository with a single dashboard on branch main and
+// returns two clones of it.
+func setupGitRepositories(t *testing.T) (remote, first, second string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	remote = filepath.Join(root, "remote.git")
+	first = filepath.Join(root, "first")
+	second = filepath.Join(root, "second")
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	git(root, "init", "--bare", "--initial-branch=main", remote)
+	git(root, "clone", remote, first)
+	git(first, "checkout", "-B", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(first, "dash.json"), []byte(`{"title":"v1"}`), 0600))
+	git(first, "add", "dash.json")
+	git(first, "commit", "-m", "initial")
+	git(first, "push", "origin", "main")
+	git(root, "clone", "--branch", "main", remote, second)
+	return remote, first, second
+}
+
+func TestGitRepository(t *testing.T) {
+	ctx := context.Background()
+	author := gitAuthor{Name: "Jane", Email: "jane@example.com"}
+
+	t.Run("Should commit and push changes to known files", func(t *testing.T) {
+		_, first, second := setupGitRepositories(t)
+
+		repo := newGitRepository(first, "main", "origin")
+		require.NoError(t, repo.pull(ctx))
+		repo.remember(ctx, []string{filepath.Join(first, "dash.json")})
+
+		require.NoError(t, repo.check(ctx, filepath.Join(first, "dash.json")))
+		require.NoError(t, repo.commit(ctx, filepath.Join(first, "dash.json"), []byte(`{"title":"v2"}`), author, "update title"))
+
+		msg, err := repo.run(ctx, "log", "-1", "--format=%an <%ae> %s")
+		require.NoError(t, err)
+		require.Equal(t, "Jane <jane@example.com> update title", msg)
+
+		other := newGitRepository(second, "main", "origin")
+		require.NoError(t, other.pull(ctx))
+		content, err := os.ReadFile(filepath.Join(second, "dash.json"))
+		require.NoError(t, err)
+		require.Equal(t, `{"title":"v2"}`, string(content))
+	})
+
+	t.Run("Should not commit unchanged files", func(t *testing.T) {
+		_, first, _ := setupGitRepositories(t)
+
+		repo := newGitRepository(first, "main", "origin")
+		require.NoError(t, repo.pull(ctx))
+		repo.remember(ctx, []string{filepath.Join(first, "dash.json")})
+		before, err := repo.run(ctx, "rev-parse", "HEAD")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.commit(ctx, filepath.Join(first, "dash.json"), []byte(`{"title":"v1"}`), author, ""))
+
+		after, err := repo.run(ctx, "rev-parse", "HEAD")
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	})
+
+	t.Run("Should reject changes to files changed in the remote", func(t *testing.T) {
+		_, first, second := setupGitRepositories(t)
+
+		repo := newGitRepository(first, "main", "origin")
+		require.NoError(t, repo.pull(ctx))
+		repo.remember(ctx, []string{filepath.Join(first, "dash.json")})
+
+		other := newGitRepository(second, "main", "origin")
+		require.NoError(t, other.pull(ctx))
+		other.remember(ctx, []string{filepath.Join(second, "dash.json")})
+		require.NoError(t, other.commit(ctx, filepath.Join(second, "dash.json"), []byte(`{"title":"remote"}`), author, ""))
+
+		require.ErrorIs(t, repo.check(ctx, filepath.Join(first, "dash.json")), ErrGitSyncConflict)
+		err := repo.commit(ctx, filepath.Join(first, "dash.json"), []byte(`{"title":"local"}`), author, "")
+		require.ErrorIs(t, err, ErrGitSyncConflict)
+
+		// once the change is pulled and applied, the dashboard can be saved again
+		require.NoError(t, repo.pull(ctx))
+		repo.remember(ctx, []string{filepath.Join(first, "dash.json")})
+		require.NoError(t, repo.commit(ctx, filepath.Join(first, "dash.json"), []byte(`{"title":"local"}`), author, ""))
+	})
+
+	t.Run("Should commit new files", func(t *testing.T) {
+		_, first, _ := setupGitRepositories(t)
+
+		repo := newGitRepository(first, "main", "origin")
+		require.NoError(t, repo.pull(ctx))
+		require.NoError(t, repo.commit(ctx, filepath.Join(first, "new.json"), []byte(`{"title":"new"}`), author, ""))
+
+		msg, err := repo.run(ctx, "log", "-1", "--format=%s")
+		require.NoError(t, err)
+		require.Equal(t, "Update new.json", msg)
+	})
+
+	t.Run("Should block commits after a conflicting pull", func(t *testing.T) {
+		_, first, second := setupGitRepositories(t)
+
+		// commit locally without pushing
+		local := newGitRepository(first, "main", "")
+		local.remember(ctx, []string{filepath.Join(first, "dash.json")})
+		require.NoError(t, local.commit(ctx, filepath.Join(first, "dash.json"), []byte(`{"title":"local"}`), author, ""))
+
+		other := newGitRepository(second, "main", "origin")
+		require.NoError(t, other.pull(ctx))
+		other.remember(ctx, []string{filepath.Join(second, "dash.json")})
+		require.NoError(t, other.commit(ctx, filepath.Join(second, "dash.json"), []byte(`{"title":"remote"}`), author, ""))
+
+		repo := newGitRepository(first, "main", "origin")
+		require.ErrorIs(t, repo.pull(ctx), ErrGitSyncConflict)
+		require.ErrorIs(t, repo.commit(ctx, filepath.Join(first, "other.json"), []byte(`{}`), author, ""), ErrGitSyncConflict)
+
+		// the working tree is left as it was
+		content, err := os.ReadFile(filepath.Join(first, "dash.json"))
+		require.NoError(t, err)
+		require.Equal(t, `{"title":"local"}`, string(content))
+	})
+}
@@ -0,0 +1,9 @@
+{
+  text(id, content):: {
+    id: id,
+    type: 'text',
+    title: 'Welcome',
+    gridPos: { h: 8, w: 12, x: 0, y: 0 },
+    options: { content: content },
+  },
+}
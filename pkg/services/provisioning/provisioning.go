@@ -7,21 +7,26 @@ import (
 	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/ossaccesscontrol"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/correlations"
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	prov_accesscontrol "github.com/grafana/grafana/pkg/services/provisioning/accesscontrol"
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
@@ -30,6 +35,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -51,6 +58,9 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	roleService *ossaccesscontrol.RoleService,
+	teamService team.Service,
+	userService user.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -64,6 +74,7 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionAccessControl:       prov_accesscontrol.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
 		datasourceService:            datasourceService,
@@ -75,6 +86,9 @@ func ProvideService(
 		secretService:                secrectService,
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
+		roleService:                  roleService,
+		teamService:                  teamService,
+		userService:                  userService,
 	}
 	return s, nil
 }
@@ -87,8 +101,11 @@ type ProvisioningService interface {
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionAccessControl(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	CheckDashboardGitConflict(ctx context.Context, dash *dashboardservice.Dashboard) error
+	SaveDashboardToGit(ctx context.Context, dash *dashboardservice.Dashboard, user identity.Requester, message string) error
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -135,6 +152,7 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionAccessControl       func(context.Context, string, prov_accesscontrol.RoleService, team.Service, user.Service) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -145,6 +163,9 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	roleService                  *ossaccesscontrol.RoleService
+	teamService                  team.Service
+	userService                  user.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -160,6 +181,12 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionAccessControl(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision access control", "error", err)
+		return err
+	}
+
 	err = ps.ProvisionNotifications(ctx)
 	if err != nil {
 		ps.log.Error("Failed to provision alert notifications", "error", err)
@@ -227,6 +254,19 @@ func (ps *ProvisioningServiceImpl) ProvisionPlugins(ctx context.Context) error {
 	return nil
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAccessControl(ctx context.Context) error {
+	if ps.provisionAccessControl == nil {
+		return nil
+	}
+	accessControlPath := filepath.Join(ps.Cfg.ProvisioningPath, "access-control")
+	if err := ps.provisionAccessControl(ctx, accessControlPath, ps.roleService, ps.teamService, ps.userService); err != nil {
+		err = fmt.Errorf("%v: %w", "Access control provisioning error", err)
+		ps.log.Error("Failed to provision access control", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) error {
 	alertNotificationsPath := filepath.Join(ps.Cfg.ProvisioningPath, "notifiers")
 	if err := ps.provisionNotifiers(ctx, alertNotificationsPath, ps.alertingService, ps.orgService, ps.EncryptionService, ps.NotificationService); err != nil {
@@ -284,6 +324,9 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
+	inhibitRuleService := provisioning.NewInhibitRuleService(&st, st, &st, ps.log)
+	silenceDefinitionStore := notifier.NewSilenceDefinitionStore(kvstore.ProvideService(ps.SQLStore))
+	silenceService := provisioning.NewSilenceService(silenceDefinitionStore, st, &st, ps.log)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
@@ -293,6 +336,8 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
+		InhibitRuleService:         *inhibitRuleService,
+		SilenceService:             *silenceService,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
@@ -305,6 +350,22 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+// CheckDashboardGitConflict returns an error if a dashboard can't be committed to the Git repository its folder is synced with.
+func (ps *ProvisioningServiceImpl) CheckDashboardGitConflict(ctx context.Context, dash *dashboardservice.Dashboard) error {
+	if ps.dashboardProvisioner == nil {
+		return nil
+	}
+	return ps.dashboardProvisioner.CheckDashboardGitConflict(ctx, dash)
+}
+
+// SaveDashboardToGit commits a dashboard to the Git repository its folder is synced with, if any.
+func (ps *ProvisioningServiceImpl) SaveDashboardToGit(ctx context.Context, dash *dashboardservice.Dashboard, user identity.Requester, message string) error {
+	if ps.dashboardProvisioner == nil {
+		return nil
+	}
+	return ps.dashboardProvisioner.SaveDashboardToGit(ctx, dash, user, message)
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
@@ -1,6 +1,11 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []any
@@ -9,8 +14,11 @@ type Calls struct {
 	ProvisionNotifications              []any
 	ProvisionDashboards                 []any
 	ProvisionAlerting                   []any
+	ProvisionAccessControl              []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
+	CheckDashboardGitConflict           []any
+	SaveDashboardToGit                  []any
 	Run                                 []any
 }
 
@@ -23,6 +31,8 @@ type ProvisioningServiceMock struct {
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	CheckDashboardGitConflictFunc           func(ctx context.Context, dash *dashboards.Dashboard) error
+	SaveDashboardToGitFunc                  func(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, message string) error
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -77,6 +87,11 @@ func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) erro
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionAccessControl(ctx context.Context) error {
+	mock.Calls.ProvisionAccessControl = append(mock.Calls.ProvisionAccessControl, nil)
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
@@ -93,6 +108,22 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) CheckDashboardGitConflict(ctx context.Context, dash *dashboards.Dashboard) error {
+	mock.Calls.CheckDashboardGitConflict = append(mock.Calls.CheckDashboardGitConflict, dash)
+	if mock.CheckDashboardGitConflictFunc != nil {
+		return mock.CheckDashboardGitConflictFunc(ctx, dash)
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) SaveDashboardToGit(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, message string) error {
+	mock.Calls.SaveDashboardToGit = append(mock.Calls.SaveDashboardToGit, dash)
+	if mock.SaveDashboardToGitFunc != nil {
+		return mock.SaveDashboardToGitFunc(ctx, dash, user, message)
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
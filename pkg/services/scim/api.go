@@ -0,0 +1,262 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	contentType = "application/scim+json"
+	// maxResults is the maximum number of resources returned by a list request.
+	maxResults = 1000
+	// maxBodySize is the maximum size of the body of a request.
+	maxBodySize = 1 << 20
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Group("/api/scim/v2", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/ServiceProviderConfig", routing.Wrap(s.handleGetServiceProviderConfig))
+
+		subrouter.Get("/Users", routing.Wrap(s.handleListUsers))
+		subrouter.Post("/Users", routing.Wrap(s.handleCreateUser))
+		subrouter.Get("/Users/:id", routing.Wrap(s.handleGetUser))
+		subrouter.Put("/Users/:id", routing.Wrap(s.handleReplaceUser))
+		subrouter.Patch("/Users/:id", routing.Wrap(s.handlePatchUser))
+		subrouter.Delete("/Users/:id", routing.Wrap(s.handleDeleteUser))
+
+		subrouter.Get("/Groups", routing.Wrap(s.handleListGroups))
+		subrouter.Post("/Groups", routing.Wrap(s.handleCreateGroup))
+		subrouter.Get("/Groups/:id", routing.Wrap(s.handleGetGroup))
+		subrouter.Put("/Groups/:id", routing.Wrap(s.handleReplaceGroup))
+		subrouter.Patch("/Groups/:id", routing.Wrap(s.handlePatchGroup))
+		subrouter.Delete("/Groups/:id", routing.Wrap(s.handleDeleteGroup))
+	}, middleware.ReqSignedIn, s.requireProvisioner)
+}
+
+// requireProvisioner only allows service accounts with the Admin role to use the SCIM API.
+func (s *Service) requireProvisioner(c *contextmodel.ReqContext) {
+	namespace, _ := c.SignedInUser.GetNamespacedID()
+	if namespace != identity.NamespaceServiceAccount || !c.SignedInUser.HasRole(org.RoleAdmin) {
+		c.JSON(http.StatusForbidden, newErrorResponse(http.StatusForbidden, "",
+			"the SCIM API requires the token of a service account with the Admin role"))
+	}
+}
+
+func (s *Service) handleGetServiceProviderConfig(c *contextmodel.ReqContext) response.Response {
+	return scimResponse(http.StatusOK, ServiceProviderConfig{
+		Schemas:        []string{SchemaServiceProviderConfig},
+		Patch:          supported{Supported: true},
+		Bulk:           bulkConfig{Supported: false},
+		Filter:         filterConfig{Supported: true, MaxResults: maxResults},
+		ChangePassword: supported{Supported: false},
+		Sort:           supported{Supported: false},
+		ETag:           supported{Supported: false},
+		AuthenticationSchemes: []authScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Service account token",
+			Description: "Authentication with the token of a service account with the Admin role",
+		}},
+	})
+}
+
+func (s *Service) handleListUsers(c *contextmodel.ReqContext) response.Response {
+	startIndex, count := pagination(c)
+	result, err := s.ListUsers(c.Req.Context(), c.SignedInUser, c.Query("filter"), startIndex, count)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, result)
+}
+
+func (s *Service) handleGetUser(c *contextmodel.ReqContext) response.Response {
+	u, err := s.GetUser(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"])
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, u)
+}
+
+func (s *Service) handleCreateUser(c *contextmodel.ReqContext) response.Response {
+	var in User
+	if err := decodeBody(c, &in); err != nil {
+		return s.errorResponse(c, err)
+	}
+	u, err := s.CreateUser(c.Req.Context(), c.SignedInUser, &in)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusCreated, u).SetHeader("Location", u.Meta.Location)
+}
+
+func (s *Service) handleReplaceUser(c *contextmodel.ReqContext) response.Response {
+	var in User
+	if err := decodeBody(c, &in); err != nil {
+		return s.errorResponse(c, err)
+	}
+	u, err := s.ReplaceUser(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"], &in)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, u)
+}
+
+func (s *Service) handlePatchUser(c *contextmodel.ReqContext) response.Response {
+	var in PatchRequest
+	if err := decodeBody(c, &in); err != nil {
+		return s.errorResponse(c, err)
+	}
+	u, err := s.PatchUser(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"], in.Operations)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, u)
+}
+
+func (s *Service) handleDeleteUser(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteUser(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"]); err != nil {
+		return s.errorResponse(c, err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (s *Service) handleListGroups(c *contextmodel.ReqContext) response.Response {
+	startIndex, count := pagination(c)
+	excludeMembers := false
+	for _, attr := range strings.Split(c.Query("excludedAttributes"), ",") {
+		if normalizeAttribute(strings.TrimSpace(attr)) == "members.value" {
+			excludeMembers = true
+		}
+	}
+
+	result, err := s.ListGroups(c.Req.Context(), c.SignedInUser, c.Query("filter"), excludeMembers, startIndex, count)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, result)
+}
+
+func (s *Service) handleGetGroup(c *contextmodel.ReqContext) response.Response {
+	g, err := s.GetGroup(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"])
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, g)
+}
+
+func (s *Service) handleCreateGroup(c *contextmodel.ReqContext) response.Response {
+	var in Group
+	if err := decodeBody(c, &in); err != nil {
+		return s.errorResponse(c, err)
+	}
+	g, err := s.CreateGroup(c.Req.Context(), c.SignedInUser, &in)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusCreated, g).SetHeader("Location", g.Meta.Location)
+}
+
+func (s *Service) handleReplaceGroup(c *contextmodel.ReqContext) response.Response {
+	var in Group
+	if err := decodeBody(c, &in); err != nil {
+		return s.errorResponse(c, err)
+	}
+	g, err := s.ReplaceGroup(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"], &in)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, g)
+}
+
+func (s *Service) handlePatchGroup(c *contextmodel.ReqContext) response.Response {
+	var in PatchRequest
+	if err := decodeBody(c, &in); err != nil {
+		return s.errorResponse(c, err)
+	}
+	g, err := s.PatchGroup(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"], in.Operations)
+	if err != nil {
+		return s.errorResponse(c, err)
+	}
+	return scimResponse(http.StatusOK, g)
+}
+
+func (s *Service) handleDeleteGroup(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteGroup(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":id"]); err != nil {
+		return s.errorResponse(c, err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// decodeBody decodes the JSON body of a request. web.Bind isn't used as identity providers send
+// application/scim+json bodies.
+func decodeBody(c *contextmodel.ReqContext, v any) error {
+	if err := json.NewDecoder(io.LimitReader(c.Req.Body, maxBodySize)).Decode(v); err != nil {
+		return ErrInvalidValue.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// pagination returns the 1-based start index and the count of the resources to return.
+func pagination(c *contextmodel.ReqContext) (int, int) {
+	startIndex, err := strconv.Atoi(c.Query("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.Query("count"))
+	if err != nil || count > maxResults {
+		count = maxResults
+	}
+	if count < 0 {
+		count = 0
+	}
+	return startIndex, count
+}
+
+func scimResponse(status int, body any) *response.NormalResponse {
+	return response.JSON(status, body).SetHeader("Content-Type", contentType)
+}
+
+// errorResponse renders an error as a SCIM error, as defined in RFC 7644 section 3.12.
+func (s *Service) errorResponse(c *contextmodel.ReqContext, err error) response.Response {
+	var grafanaErr errutil.Error
+	if !errors.As(err, &grafanaErr) {
+		s.log.FromContext(c.Req.Context()).Error("SCIM request failed", "method", c.Req.Method, "path", c.Req.URL.Path, "error", err)
+		return scimResponse(http.StatusInternalServerError, newErrorResponse(http.StatusInternalServerError, "", "internal server error"))
+	}
+
+	status := grafanaErr.Reason.Status().HTTPStatus()
+	if status >= http.StatusInternalServerError {
+		s.log.FromContext(c.Req.Context()).Error("SCIM request failed", "method", c.Req.Method, "path", c.Req.URL.Path, "error", err)
+		return scimResponse(status, newErrorResponse(status, "", "internal server error"))
+	}
+
+	return scimResponse(status, newErrorResponse(status, scimType(err), grafanaErr.LogMessage))
+}
+
+func scimType(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidFilter):
+		return scimTypeInvalidFilter
+	case errors.Is(err, ErrInvalidValue):
+		return scimTypeInvalidValue
+	case errors.Is(err, ErrInvalidPath):
+		return scimTypeInvalidPath
+	case errors.Is(err, ErrUniqueness):
+		return scimTypeUniqueness
+	case errors.Is(err, ErrMutability):
+		return scimTypeMutability
+	}
+	return ""
+}
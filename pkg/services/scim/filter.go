@@ -0,0 +1,175 @@
+package scim
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// comparison is an attribute comparison of a SCIM filter, e.g. userName eq "admin".
+type comparison struct {
+	attr  string
+	op    string
+	value string
+}
+
+// filter is a SCIM filter. Only comparisons combined with "and" are supported, which covers the filters that identity
+// providers send to find existing users and groups.
+type filter []comparison
+
+var (
+	// filterTokens matches quoted values, and attribute paths with their value filters.
+	filterTokens = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|[^\s\[]+(?:\[[^\]]*\]\S*)?`)
+	// valueFilter matches the value filters of multi-valued attributes, e.g. emails[type eq "work"].value,
+	// which are ignored so that all values of the attribute are compared.
+	valueFilter = regexp.MustCompile(`\[[^\]]*\]`)
+)
+
+// caseExactAttributes are compared case sensitively, other attributes aren't.
+var caseExactAttributes = map[string]bool{"id": true, "externalid": true}
+
+// parseFilter parses a SCIM filter, as defined in RFC 7644 section 3.4.2.2.
+func parseFilter(raw string) (filter, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	tokens := filterTokens.FindAllString(raw, -1)
+
+	var f filter
+	for len(tokens) > 0 {
+		if len(f) > 0 {
+			if !strings.EqualFold(tokens[0], "and") {
+				return nil, ErrInvalidFilter.Errorf("unsupported filter %q: only comparisons combined with and are supported", raw)
+			}
+			tokens = tokens[1:]
+		}
+
+		if len(tokens) < 2 {
+			return nil, ErrInvalidFilter.Errorf("invalid filter %q", raw)
+		}
+		c := comparison{
+			attr: normalizeAttribute(tokens[0]),
+			op:   strings.ToLower(tokens[1]),
+		}
+
+		if c.op == "pr" {
+			tokens = tokens[2:]
+			f = append(f, c)
+			continue
+		}
+
+		switch c.op {
+		case "eq", "ne", "co", "sw", "ew":
+		default:
+			return nil, ErrInvalidFilter.Errorf("unsupported operator %q in filter %q", tokens[1], raw)
+		}
+		if len(tokens) < 3 {
+			return nil, ErrInvalidFilter.Errorf("missing value in filter %q", raw)
+		}
+
+		value, err := parseFilterValue(tokens[2])
+		if err != nil {
+			return nil, ErrInvalidFilter.Errorf("invalid value in filter %q: %w", raw, err)
+		}
+		c.value = value
+		tokens = tokens[3:]
+		f = append(f, c)
+	}
+	return f, nil
+}
+
+func parseFilterValue(token string) (string, error) {
+	if strings.HasPrefix(token, `"`) {
+		return strconv.Unquote(token)
+	}
+	switch strings.ToLower(token) {
+	case "true", "false", "null":
+		return strings.ToLower(token), nil
+	}
+	return token, nil
+}
+
+// normalizeAttribute lower-cases attribute paths and strips schema URNs and value filters,
+// so that urn:ietf:params:scim:schemas:core:2.0:User:userName and emails[type eq "work"].value can be compared.
+func normalizeAttribute(attr string) string {
+	attr = valueFilter.ReplaceAllString(attr, "")
+	for _, schema := range []string{SchemaUser, SchemaGroup} {
+		if len(attr) > len(schema) && strings.EqualFold(attr[:len(schema)+1], schema+":") {
+			attr = attr[len(schema)+1:]
+		}
+	}
+	attr = strings.ToLower(attr)
+	switch attr {
+	case "emails", "members":
+		return attr + ".value"
+	}
+	return attr
+}
+
+// valueFor returns the value of an eq comparison of the attribute, if the filter has one.
+func (f filter) valueFor(attr string) (string, bool) {
+	for _, c := range f {
+		if c.attr == attr && c.op == "eq" {
+			return c.value, true
+		}
+	}
+	return "", false
+}
+
+// matches returns true if the attributes match all comparisons of the filter.
+// attributes returns the values of an attribute, given its normalized path.
+func (f filter) matches(attributes func(attr string) ([]string, bool)) (bool, error) {
+	for _, c := range f {
+		values, ok := attributes(c.attr)
+		if !ok {
+			return false, ErrInvalidFilter.Errorf("unsupported attribute %q in filter", c.attr)
+		}
+		if !c.matches(values) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c comparison) matches(values []string) bool {
+	if c.op == "pr" {
+		for _, v := range values {
+			if v != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if c.op == "ne" {
+		return !comparison{attr: c.attr, op: "eq", value: c.value}.matches(values)
+	}
+
+	expected := c.value
+	for _, v := range values {
+		if !caseExactAttributes[c.attr] {
+			v, expected = strings.ToLower(v), strings.ToLower(expected)
+		}
+		switch c.op {
+		case "eq":
+			if v == expected {
+				return true
+			}
+		case "co":
+			if strings.Contains(v, expected) {
+				return true
+			}
+		case "sw":
+			if strings.HasPrefix(v, expected) {
+				return true
+			}
+		case "ew":
+			if strings.HasSuffix(v, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		raw      string
+		expected filter
+		err      bool
+	}{
+		{
+			desc: "empty filter",
+			raw:  "",
+		},
+		{
+			desc:     "eq comparison",
+			raw:      `userName eq "alice"`,
+			expected: filter{{attr: "username", op: "eq", value: "alice"}},
+		},
+		{
+			desc:     "schema URN is stripped",
+			raw:      `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice"`,
+			expected: filter{{attr: "username", op: "eq", value: "alice"}},
+		},
+		{
+			desc:     "value filters are stripped",
+			raw:      `emails[type eq "work"].value eq "alice@example.org"`,
+			expected: filter{{attr: "emails.value", op: "eq", value: "alice@example.org"}},
+		},
+		{
+			desc: "comparisons combined with and",
+			raw:  `displayName sw "Dev" and externalId pr and active eq true`,
+			expected: filter{
+				{attr: "displayname", op: "sw", value: "Dev"},
+				{attr: "externalid", op: "pr"},
+				{attr: "active", op: "eq", value: "true"},
+			},
+		},
+		{
+			desc:     "escaped quotes",
+			raw:      `displayName eq "say \"hi\""`,
+			expected: filter{{attr: "displayname", op: "eq", value: `say "hi"`}},
+		},
+		{
+			desc: "or is not supported",
+			raw:  `userName eq "alice" or userName eq "bob"`,
+			err:  true,
+		},
+		{
+			desc: "unknown operator",
+			raw:  `userName gt "alice"`,
+			err:  true,
+		},
+		{
+			desc: "missing value",
+			raw:  `userName eq`,
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := parseFilter(tc.raw)
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	u := &User{
+		ID:         "42",
+		ExternalID: "ABC",
+		UserName:   "Alice",
+		Emails:     []MultiValued{{Value: "alice@example.org"}, {Value: "alice@example.com"}},
+		Active:     boolPtr(true),
+	}
+
+	testCases := []struct {
+		raw      string
+		expected bool
+		err      bool
+	}{
+		{raw: `userName eq "alice"`, expected: true},
+		{raw: `userName ne "alice"`, expected: false},
+		{raw: `userName co "lic"`, expected: true},
+		{raw: `emails.value ew "example.com"`, expected: true},
+		{raw: `emails sw "bob"`, expected: false},
+		{raw: `externalId eq "abc"`, expected: false},
+		{raw: `externalId eq "ABC" and active eq true`, expected: true},
+		{raw: `displayName pr`, expected: false},
+		{raw: `title eq "engineer"`, err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			f, err := parseFilter(tc.raw)
+			require.NoError(t, err)
+
+			ok, err := f.matches(userAttributes(u))
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ok)
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+const teamMemberPermission = "Member"
+
+// ListGroups returns the teams of the organization of the requester that match the filter.
+// Members are loaded for each team, unless they are excluded and not filtered on.
+func (s *Service) ListGroups(ctx context.Context, requester identity.Requester, rawFilter string, excludeMembers bool, startIndex, count int) (*ListResponse, error) {
+	f, err := parseFilter(rawFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID := requester.GetOrgID()
+	query := &team.SearchTeamsQuery{OrgID: orgID, SignedInUser: requester}
+	if v, ok := f.valueFor("displayname"); ok {
+		query.Query = v
+	}
+	if v, ok := f.valueFor("id"); ok {
+		if teamID, err := strconv.ParseInt(v, 10, 64); err == nil {
+			query.TeamIds = []int64{teamID}
+		}
+	}
+	if v, ok := f.valueFor("externalid"); ok {
+		ids, err := s.store.getResourceIDs(ctx, orgID, resourceTypeGroup, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return newListResponse(nil, startIndex, count), nil
+		}
+		query.TeamIds = ids
+	}
+
+	result, err := s.teamService.SearchTeams(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	externalIDs, err := s.store.getExternalIDs(ctx, orgID, resourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	withMembers := !excludeMembers
+	for _, c := range f {
+		if c.attr == "members.value" {
+			withMembers = true
+		}
+	}
+
+	resources := make([]any, 0, len(result.Teams))
+	for _, t := range result.Teams {
+		var members []MultiValued
+		if withMembers {
+			if members, err = s.getMembers(ctx, requester, t.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		g := s.toSCIMGroup(t, externalIDs[t.ID], members)
+		ok, err := f.matches(groupAttributes(g))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if excludeMembers {
+				g.Members = nil
+			}
+			resources = append(resources, g)
+		}
+	}
+	return newListResponse(resources, startIndex, count), nil
+}
+
+// GetGroup returns a team of the organization of the requester, with its members.
+func (s *Service) GetGroup(ctx context.Context, requester identity.Requester, id string) (*Group, error) {
+	t, err := s.getTeam(ctx, requester, id)
+	if err != nil {
+		return nil, err
+	}
+
+	external, err := s.store.getExternalID(ctx, t.OrgID, resourceTypeGroup, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.getMembers(ctx, requester, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toSCIMGroup(t, external, members), nil
+}
+
+// CreateGroup creates a team in the organization of the requester.
+func (s *Service) CreateGroup(ctx context.Context, requester identity.Requester, in *Group) (*Group, error) {
+	if in.DisplayName == "" {
+		return nil, ErrInvalidValue.Errorf("displayName is required")
+	}
+	members, err := parseMemberIDs(in.Members)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := make([]int64, 0, len(members))
+	for id := range members {
+		memberIDs = append(memberIDs, id)
+	}
+
+	orgID := requester.GetOrgID()
+	t, err := s.teamService.CreateTeam(in.DisplayName, "", orgID)
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNameTaken) {
+			return nil, ErrUniqueness.Errorf("team %q already exists", in.DisplayName)
+		}
+		return nil, err
+	}
+
+	if in.ExternalID != "" {
+		if err := s.store.setExternalID(ctx, orgID, resourceTypeGroup, t.ID, in.ExternalID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.setMembers(ctx, requester, t.ID, memberIDs, nil); err != nil {
+		return nil, err
+	}
+
+	s.log.FromContext(ctx).Info("Provisioned team", "teamId", t.ID, "name", t.Name, "orgId", orgID)
+	return s.GetGroup(ctx, requester, strconv.FormatInt(t.ID, 10))
+}
+
+// ReplaceGroup replaces the name, the external ID and the members of a team of the organization of the requester.
+func (s *Service) ReplaceGroup(ctx context.Context, requester identity.Requester, id string, in *Group) (*Group, error) {
+	current, err := s.GetGroup(ctx, requester, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateGroup(ctx, requester, current, in)
+}
+
+// PatchGroup applies patch operations to a team of the organization of the requester.
+func (s *Service) PatchGroup(ctx context.Context, requester identity.Requester, id string, operations []PatchOperation) (*Group, error) {
+	current, err := s.GetGroup(ctx, requester, id)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := *current
+	desired.Members = append([]MultiValued{}, current.Members...)
+	for _, op := range operations {
+		if err := applyGroupPatch(&desired, op); err != nil {
+			return nil, err
+		}
+	}
+	return s.updateGroup(ctx, requester, current, &desired)
+}
+
+// DeleteGroup deletes a team of the organization of the requester.
+func (s *Service) DeleteGroup(ctx context.Context, requester identity.Requester, id string) error {
+	t, err := s.getTeam(ctx, requester, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: t.OrgID, ID: t.ID}); err != nil {
+		return err
+	}
+
+	s.log.FromContext(ctx).Info("Deprovisioned team", "teamId", t.ID, "name", t.Name, "orgId", t.OrgID)
+	return nil
+}
+
+func (s *Service) updateGroup(ctx context.Context, requester identity.Requester, current, desired *Group) (*Group, error) {
+	if desired.DisplayName == "" {
+		return nil, ErrInvalidValue.Errorf("displayName is required")
+	}
+	desiredIDs, err := parseMemberIDs(desired.Members)
+	if err != nil {
+		return nil, err
+	}
+	currentIDs, err := parseMemberIDs(current.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.getTeam(ctx, requester, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	teamID, orgID := t.ID, t.OrgID
+
+	if desired.DisplayName != current.DisplayName {
+		if err := s.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: teamID, OrgID: orgID, Name: desired.DisplayName, Email: t.Email}); err != nil {
+			if errors.Is(err, team.ErrTeamNameTaken) {
+				return nil, ErrUniqueness.Errorf("team %q already exists", desired.DisplayName)
+			}
+			return nil, err
+		}
+	}
+
+	if desired.ExternalID != current.ExternalID {
+		if err := s.store.setExternalID(ctx, orgID, resourceTypeGroup, teamID, desired.ExternalID); err != nil {
+			return nil, err
+		}
+	}
+
+	var added, removed []int64
+	for id := range desiredIDs {
+		if !currentIDs[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range currentIDs {
+		if !desiredIDs[id] {
+			removed = append(removed, id)
+		}
+	}
+	if err := s.setMembers(ctx, requester, teamID, added, removed); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, requester, current.ID)
+}
+
+// setMembers adds users to a team and removes others from it. Only members of the organization can be added.
+func (s *Service) setMembers(ctx context.Context, requester identity.Requester, teamID int64, added, removed []int64) error {
+	orgID := requester.GetOrgID()
+	resourceID := strconv.FormatInt(teamID, 10)
+
+	for _, userID := range added {
+		if _, err := s.GetUser(ctx, requester, strconv.FormatInt(userID, 10)); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return ErrInvalidValue.Errorf("user %d is not a member of the organization", userID)
+			}
+			return err
+		}
+		if _, err := s.teamPermissions.SetUserPermission(ctx, orgID, ac.User{ID: userID}, resourceID, teamMemberPermission); err != nil {
+			return err
+		}
+	}
+
+	for _, userID := range removed {
+		if _, err := s.teamPermissions.SetUserPermission(ctx, orgID, ac.User{ID: userID}, resourceID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) getTeam(ctx context.Context, requester identity.Requester, id string) (*team.TeamDTO, error) {
+	teamID, err := parseResourceID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: requester.GetOrgID(), ID: teamID, SignedInUser: requester})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return nil, ErrNotFound.Errorf("team %d not found", teamID)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) getMembers(ctx context.Context, requester identity.Requester, teamID int64) ([]MultiValued, error) {
+	teamMembers, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{
+		OrgID:        requester.GetOrgID(),
+		TeamID:       teamID,
+		SignedInUser: requester,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]MultiValued, 0, len(teamMembers))
+	for _, m := range teamMembers {
+		id := strconv.FormatInt(m.UserID, 10)
+		members = append(members, MultiValued{Value: id, Display: m.Login, Ref: s.location("Users", id)})
+	}
+	return members, nil
+}
+
+func (s *Service) toSCIMGroup(t *team.TeamDTO, external string, members []MultiValued) *Group {
+	id := strconv.FormatInt(t.ID, 10)
+	return &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          id,
+		ExternalID:  external,
+		DisplayName: t.Name,
+		Members:     members,
+		Meta: &Meta{
+			ResourceType: resourceTypeGroup,
+			Location:     s.location("Groups", id),
+		},
+	}
+}
+
+// groupAttributes returns the values of the attributes of a group that can be filtered on.
+func groupAttributes(g *Group) func(attr string) ([]string, bool) {
+	return func(attr string) ([]string, bool) {
+		switch attr {
+		case "id":
+			return []string{g.ID}, true
+		case "externalid":
+			return []string{g.ExternalID}, true
+		case "displayname":
+			return []string{g.DisplayName}, true
+		case "members.value":
+			values := make([]string, 0, len(g.Members))
+			for _, m := range g.Members {
+				values = append(values, m.Value)
+			}
+			return values, true
+		}
+		return nil, false
+	}
+}
+
+// applyGroupPatch applies a patch operation to a group, as defined in RFC 7644 section 3.5.2.
+// Members can be removed with a value filter, e.g. members[value eq "42"].
+func applyGroupPatch(g *Group, op PatchOperation) error {
+	if err := validatePatchOp(op); err != nil {
+		return err
+	}
+
+	if op.Path == "" {
+		return forEachPatchValue(op, func(nested PatchOperation) error {
+			return applyGroupPatch(g, nested)
+		})
+	}
+
+	switch normalizeAttribute(op.Path) {
+	case "displayname":
+		var name string
+		if err := decodePatchValue(op, &name); err != nil {
+			return err
+		}
+		if name == "" {
+			return ErrMutability.Errorf("displayName can't be removed")
+		}
+		g.DisplayName = name
+	case "externalid":
+		return decodePatchValue(op, &g.ExternalID)
+	case "members.value":
+		return applyMembersPatch(g, op)
+	}
+	return nil
+}
+
+func applyMembersPatch(g *Group, op PatchOperation) error {
+	// a value filter selects the members to remove
+	if start := strings.Index(op.Path, "["); start >= 0 {
+		if !isRemove(op) {
+			return ErrInvalidPath.Errorf("value filters are only supported to remove members: %q", op.Path)
+		}
+		end := strings.LastIndex(op.Path, "]")
+		if end < start {
+			return ErrInvalidPath.Errorf("invalid path %q", op.Path)
+		}
+		f, err := parseFilter(op.Path[start+1 : end])
+		if err != nil {
+			return err
+		}
+		g.Members = filterMembers(g.Members, func(m MultiValued) bool {
+			ok, err := f.matches(func(attr string) ([]string, bool) {
+				return []string{m.Value}, attr == "value"
+			})
+			return err == nil && ok
+		})
+		return nil
+	}
+
+	var members []MultiValued
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &members); err != nil {
+			return ErrInvalidValue.Errorf("invalid value for %q: %w", op.Path, err)
+		}
+	}
+
+	switch strings.ToLower(op.Op) {
+	case "add":
+		g.Members = append(g.Members, members...)
+	case "replace":
+		g.Members = members
+	case "remove":
+		// without a value, all members are removed
+		if len(op.Value) == 0 {
+			g.Members = nil
+			return nil
+		}
+		removed := make(map[string]bool, len(members))
+		for _, m := range members {
+			removed[m.Value] = true
+		}
+		g.Members = filterMembers(g.Members, func(m MultiValued) bool {
+			return removed[m.Value]
+		})
+	}
+	return nil
+}
+
+// filterMembers returns the members for which remove returns false.
+func filterMembers(members []MultiValued, remove func(MultiValued) bool) []MultiValued {
+	kept := make([]MultiValued, 0, len(members))
+	for _, m := range members {
+		if !remove(m) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// parseMemberIDs returns the set of the user IDs of the members.
+func parseMemberIDs(members []MultiValued) (map[int64]bool, error) {
+	ids := make(map[int64]bool, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m.Value, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, ErrInvalidValue.Errorf("invalid member %q", m.Value)
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
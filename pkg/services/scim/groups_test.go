@@ -0,0 +1,66 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyGroupPatch(t *testing.T) {
+	current := func() *Group {
+		return &Group{
+			ID:          "1",
+			DisplayName: "Developers",
+			Members:     []MultiValued{{Value: "1"}, {Value: "2"}, {Value: "3"}},
+		}
+	}
+
+	values := func(g *Group) []string {
+		var v []string
+		for _, m := range g.Members {
+			v = append(v, m.Value)
+		}
+		return v
+	}
+
+	t.Run("should add members", func(t *testing.T) {
+		g := current()
+		require.NoError(t, applyGroupPatch(g, PatchOperation{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "4"}]`)}))
+		assert.Equal(t, []string{"1", "2", "3", "4"}, values(g))
+	})
+
+	t.Run("should remove members with a value filter", func(t *testing.T) {
+		g := current()
+		require.NoError(t, applyGroupPatch(g, PatchOperation{Op: "remove", Path: `members[value eq "2"]`}))
+		assert.Equal(t, []string{"1", "3"}, values(g))
+	})
+
+	t.Run("should remove members with a value", func(t *testing.T) {
+		g := current()
+		require.NoError(t, applyGroupPatch(g, PatchOperation{Op: "Remove", Path: "members", Value: json.RawMessage(`[{"value": "1"}, {"value": "3"}]`)}))
+		assert.Equal(t, []string{"2"}, values(g))
+	})
+
+	t.Run("should remove all members without a value", func(t *testing.T) {
+		g := current()
+		require.NoError(t, applyGroupPatch(g, PatchOperation{Op: "remove", Path: "members"}))
+		assert.Empty(t, g.Members)
+	})
+
+	t.Run("should replace the display name and the members", func(t *testing.T) {
+		g := current()
+		require.NoError(t, applyGroupPatch(g, PatchOperation{
+			Op:    "replace",
+			Value: json.RawMessage(`{"displayName": "Engineering", "members": [{"value": "5"}]}`),
+		}))
+		assert.Equal(t, "Engineering", g.DisplayName)
+		assert.Equal(t, []string{"5"}, values(g))
+	})
+
+	t.Run("should only support value filters to remove members", func(t *testing.T) {
+		err := applyGroupPatch(current(), PatchOperation{Op: "add", Path: `members[value eq "2"]`, Value: json.RawMessage(`{}`)})
+		require.ErrorIs(t, err, ErrInvalidPath)
+	})
+}
@@ -0,0 +1,248 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	resourceTypeUser  = "User"
+	resourceTypeGroup = "Group"
+)
+
+// SCIM error types, as defined in RFC 7644 section 3.12.
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeUniqueness    = "uniqueness"
+	scimTypeMutability    = "mutability"
+)
+
+var (
+	ErrNotFound      = errutil.NotFound("scim.notFound", errutil.WithPublicMessage("Resource not found"))
+	ErrInvalidFilter = errutil.BadRequest("scim.invalidFilter")
+	ErrInvalidValue  = errutil.BadRequest("scim.invalidValue")
+	ErrInvalidPath   = errutil.BadRequest("scim.invalidPath")
+	ErrUniqueness    = errutil.Conflict("scim.uniqueness")
+	ErrMutability    = errutil.BadRequest("scim.mutability")
+	ErrForbidden     = errutil.Forbidden("scim.forbidden")
+)
+
+// Meta is the metadata of a resource.
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// Name is the name of a user. Grafana users have a single name, which is returned as formatted.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// String returns the name of the user, the formatted name or the given and family names.
+func (n *Name) String() string {
+	if n == nil {
+		return ""
+	}
+	if n.Formatted != "" {
+		return n.Formatted
+	}
+	return strings.TrimSpace(n.GivenName + " " + n.FamilyName)
+}
+
+type MultiValued struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// User is a SCIM user, backed by a Grafana user who is a member of the organization of the service account.
+type User struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	UserName    string        `json:"userName"`
+	Name        *Name         `json:"name,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Emails      []MultiValued `json:"emails,omitempty"`
+	Active      *Bool         `json:"active,omitempty"`
+	Groups      []MultiValued `json:"groups,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary email of the user, or the first one if none is primary.
+func (u *User) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// FullName returns the name of the user, from the display name or the name.
+func (u *User) FullName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Name.String()
+}
+
+// Group is a SCIM group, backed by a Grafana team.
+type Group struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []MultiValued `json:"members,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// newListResponse returns the page of the resources starting at the 1-based start index.
+func newListResponse(resources []any, startIndex, count int) *ListResponse {
+	page := make([]any, 0)
+	if start := startIndex - 1; start < len(resources) {
+		end := start + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = append(page, resources[start:end]...)
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ErrorResponse is the body of SCIM error responses.
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func newErrorResponse(status int, scimType, detail string) ErrorResponse {
+	return ErrorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// Bool is a boolean that also accepts the strings "true" and "false", in any case, which some identity providers send.
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var v bool
+	if err := json.Unmarshal(data, &v); err == nil {
+		*b = Bool(v)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return err
+	}
+	*b = Bool(v)
+	return nil
+}
+
+func boolPtr(b bool) *Bool {
+	v := Bool(b)
+	return &v
+}
+
+// ServiceProviderConfig describes the SCIM features supported by Grafana.
+type ServiceProviderConfig struct {
+	Schemas               []string     `json:"schemas"`
+	Patch                 supported    `json:"patch"`
+	Bulk                  bulkConfig   `json:"bulk"`
+	Filter                filterConfig `json:"filter"`
+	ChangePassword        supported    `json:"changePassword"`
+	Sort                  supported    `json:"sort"`
+	ETag                  supported    `json:"etag"`
+	AuthenticationSchemes []authScheme `json:"authenticationSchemes"`
+}
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type bulkConfig struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type filterConfig struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type authScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// externalID links a user or a group to the ID of the resource in the identity provider.
+type externalID struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"org_id"`
+	ResourceType string `xorm:"resource_type"`
+	ResourceID   int64  `xorm:"resource_id"`
+	ExternalID   string `xorm:"external_id"`
+}
+
+func (externalID) TableName() string {
+	return "scim_external_id"
+}
@@ -0,0 +1,37 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBool_UnmarshalJSON(t *testing.T) {
+	for raw, expected := range map[string]bool{`true`: true, `false`: false, `"True"`: true, `"false"`: false} {
+		var b Bool
+		require.NoError(t, json.Unmarshal([]byte(raw), &b), raw)
+		assert.Equal(t, expected, bool(b), raw)
+	}
+
+	var b Bool
+	require.Error(t, json.Unmarshal([]byte(`"yes please"`), &b))
+}
+
+func TestNewListResponse(t *testing.T) {
+	resources := []any{1, 2, 3, 4, 5}
+
+	page := newListResponse(resources, 2, 2)
+	assert.Equal(t, 5, page.TotalResults)
+	assert.Equal(t, 2, page.StartIndex)
+	assert.Equal(t, 2, page.ItemsPerPage)
+	assert.Equal(t, []any{2, 3}, page.Resources)
+
+	page = newListResponse(resources, 4, 10)
+	assert.Equal(t, []any{4, 5}, page.Resources)
+
+	page = newListResponse(resources, 10, 10)
+	assert.Equal(t, 0, page.ItemsPerPage)
+	assert.Empty(t, page.Resources)
+}
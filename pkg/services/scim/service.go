@@ -0,0 +1,77 @@
+package scim
+
+import (
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type config struct {
+	enabled        bool
+	defaultOrgRole org.RoleType
+}
+
+func readConfig(cfg *setting.Cfg) config {
+	section := cfg.SectionWithEnvOverrides("auth.scim")
+	role := org.RoleType(section.Key("default_org_role").MustString(string(org.RoleViewer)))
+	if !role.IsValid() {
+		role = org.RoleViewer
+	}
+	return config{
+		enabled:        section.Key("enabled").MustBool(false),
+		defaultOrgRole: role,
+	}
+}
+
+// Service is a SCIM 2.0 server, as defined in RFC 7643 and RFC 7644, for identity providers to provision the users
+// and the teams of an organization.
+//
+// Identity providers authenticate with the token of a service account with the Admin role, and manage the users and
+// teams of its organization: SCIM users are the members of the organization, SCIM groups are its teams.
+type Service struct {
+	cfg              config
+	appURL           string
+	store            *store
+	userService      user.Service
+	orgService       org.Service
+	teamService      team.Service
+	teamPermissions  ac.TeamPermissionsService
+	userTokenService auth.UserTokenService
+	log              log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, userService user.Service,
+	orgService org.Service, teamService team.Service, teamPermissions ac.TeamPermissionsService,
+	userTokenService auth.UserTokenService) *Service {
+	s := &Service{
+		cfg:              readConfig(cfg),
+		appURL:           cfg.AppURL,
+		store:            &store{db: sqlStore},
+		userService:      userService,
+		orgService:       orgService,
+		teamService:      teamService,
+		teamPermissions:  teamPermissions,
+		userTokenService: userTokenService,
+		log:              log.New("scim"),
+	}
+
+	orgService.RegisterDelete("DELETE FROM scim_external_id WHERE org_id = ?")
+	teamService.RegisterDelete("DELETE FROM scim_external_id WHERE org_id = ? AND resource_type = 'Group' AND resource_id = ?")
+
+	if s.cfg.enabled {
+		s.registerAPIEndpoints(routeRegister)
+	}
+
+	return s
+}
+
+// location returns the URL of a resource.
+func (s *Service) location(endpoint, id string) string {
+	return s.appURL + "api/scim/v2/" + endpoint + "/" + id
+}
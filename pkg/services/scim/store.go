@@ -0,0 +1,64 @@
+package scim
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+// store persists the external IDs identity providers assign to users and groups.
+type store struct {
+	db db.DB
+}
+
+func (s *store) getExternalID(ctx context.Context, orgID int64, resourceType string, resourceID int64) (string, error) {
+	var row externalID
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND resource_type = ? AND resource_id = ?", orgID, resourceType, resourceID).Get(&row)
+		return err
+	})
+	return row.ExternalID, err
+}
+
+// getExternalIDs returns the external IDs of the resources of the type, by resource ID.
+func (s *store) getExternalIDs(ctx context.Context, orgID int64, resourceType string) (map[int64]string, error) {
+	var rows []externalID
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND resource_type = ?", orgID, resourceType).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		ids[row.ResourceID] = row.ExternalID
+	}
+	return ids, nil
+}
+
+// getResourceIDs returns the IDs of the resources with the external ID.
+func (s *store) getResourceIDs(ctx context.Context, orgID int64, resourceType, external string) ([]int64, error) {
+	ids := make([]int64, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("scim_external_id").Cols("resource_id").
+			Where("org_id = ? AND resource_type = ? AND external_id = ?", orgID, resourceType, external).
+			Find(&ids)
+	})
+	return ids, err
+}
+
+// setExternalID sets the external ID of a resource, or removes it if it's empty.
+func (s *store) setExternalID(ctx context.Context, orgID int64, resourceType string, resourceID int64, external string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND resource_type = ? AND resource_id = ?", orgID, resourceType, resourceID).
+			Delete(&externalID{}); err != nil {
+			return err
+		}
+		if external == "" {
+			return nil
+		}
+		_, err := sess.Insert(&externalID{OrgID: orgID, ResourceType: resourceType, ResourceID: resourceID, ExternalID: external})
+		return err
+	})
+}
@@ -0,0 +1,52 @@
+package scim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+func TestIntegrationStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &store{db: db.InitTestDB(t)}
+
+	require.NoError(t, s.setExternalID(ctx, 1, resourceTypeUser, 10, "00u10"))
+	require.NoError(t, s.setExternalID(ctx, 1, resourceTypeUser, 11, "00u11"))
+	require.NoError(t, s.setExternalID(ctx, 1, resourceTypeGroup, 10, "00g10"))
+	require.NoError(t, s.setExternalID(ctx, 2, resourceTypeUser, 10, "00u10"))
+
+	external, err := s.getExternalID(ctx, 1, resourceTypeUser, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "00u10", external)
+
+	externals, err := s.getExternalIDs(ctx, 1, resourceTypeUser)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]string{10: "00u10", 11: "00u11"}, externals)
+
+	ids, err := s.getResourceIDs(ctx, 1, resourceTypeUser, "00u11")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{11}, ids)
+
+	// setting the external ID replaces it, and an empty one removes it
+	require.NoError(t, s.setExternalID(ctx, 1, resourceTypeUser, 10, "00u12"))
+	external, err = s.getExternalID(ctx, 1, resourceTypeUser, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "00u12", external)
+
+	require.NoError(t, s.setExternalID(ctx, 1, resourceTypeUser, 10, ""))
+	external, err = s.getExternalID(ctx, 1, resourceTypeUser, 10)
+	require.NoError(t, err)
+	assert.Empty(t, external)
+
+	external, err = s.getExternalID(ctx, 2, resourceTypeUser, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "00u10", external)
+}
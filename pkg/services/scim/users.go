@@ -0,0 +1,522 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// ListUsers returns the members of the organization of the requester that match the filter.
+func (s *Service) ListUsers(ctx context.Context, requester identity.Requester, rawFilter string, startIndex, count int) (*ListResponse, error) {
+	f, err := parseFilter(rawFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID := requester.GetOrgID()
+	query := &org.SearchOrgUsersQuery{OrgID: orgID, User: requester}
+	// narrow the search down for the lookups identity providers do before provisioning a user,
+	// the filter is then applied to the results
+	if v, ok := f.valueFor("username"); ok {
+		query.Query = v
+	} else if v, ok := f.valueFor("emails.value"); ok {
+		query.Query = v
+	}
+	if v, ok := f.valueFor("id"); ok {
+		if userID, err := strconv.ParseInt(v, 10, 64); err == nil {
+			query.UserID = userID
+		}
+	}
+	if v, ok := f.valueFor("externalid"); ok {
+		ids, err := s.store.getResourceIDs(ctx, orgID, resourceTypeUser, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return newListResponse(nil, startIndex, count), nil
+		}
+		if len(ids) == 1 {
+			query.UserID = ids[0]
+		}
+	}
+
+	result, err := s.orgService.SearchOrgUsers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	externalIDs, err := s.store.getExternalIDs(ctx, orgID, resourceTypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]any, 0, len(result.OrgUsers))
+	for _, orgUser := range result.OrgUsers {
+		u := s.toSCIMUser(orgUser, externalIDs[orgUser.UserID])
+		ok, err := f.matches(userAttributes(u))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resources = append(resources, u)
+		}
+	}
+	return newListResponse(resources, startIndex, count), nil
+}
+
+// GetUser returns a member of the organization of the requester.
+func (s *Service) GetUser(ctx context.Context, requester identity.Requester, id string) (*User, error) {
+	userID, err := parseResourceID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID := requester.GetOrgID()
+	result, err := s.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+		OrgID:  orgID,
+		UserID: userID,
+		Page:   1,
+		Limit:  1,
+		User:   requester,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.OrgUsers) == 0 {
+		return nil, ErrNotFound.Errorf("user %d is not a member of organization %d", userID, orgID)
+	}
+
+	external, err := s.store.getExternalID(ctx, orgID, resourceTypeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toSCIMUser(result.OrgUsers[0], external), nil
+}
+
+// CreateUser creates a user and adds it to the organization of the requester with the default role.
+func (s *Service) CreateUser(ctx context.Context, requester identity.Requester, in *User) (*User, error) {
+	if in.UserName == "" {
+		return nil, ErrInvalidValue.Errorf("userName is required")
+	}
+
+	orgID := requester.GetOrgID()
+	usr, err := s.userService.Create(ctx, &user.CreateUserCommand{
+		Login:        in.UserName,
+		Email:        in.PrimaryEmail(),
+		Name:         in.FullName(),
+		OrgID:        orgID,
+		SkipOrgSetup: true,
+		IsDisabled:   in.Active != nil && !bool(*in.Active),
+	})
+	if err != nil {
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return nil, ErrUniqueness.Errorf("user %q already exists", in.UserName)
+		}
+		return nil, err
+	}
+
+	if err := s.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{
+		OrgID:  orgID,
+		UserID: usr.ID,
+		Role:   s.cfg.defaultOrgRole,
+	}); err != nil {
+		return nil, err
+	}
+
+	if in.ExternalID != "" {
+		if err := s.store.setExternalID(ctx, orgID, resourceTypeUser, usr.ID, in.ExternalID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.log.FromContext(ctx).Info("Provisioned user", "userId", usr.ID, "login", usr.Login, "orgId", orgID)
+	return s.GetUser(ctx, requester, strconv.FormatInt(usr.ID, 10))
+}
+
+// ReplaceUser replaces the attributes of a member of the organization of the requester.
+// Emails and active are kept when they are omitted.
+func (s *Service) ReplaceUser(ctx context.Context, requester identity.Requester, id string, in *User) (*User, error) {
+	current, err := s.GetUser(ctx, requester, id)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := *in
+	if len(desired.Emails) == 0 {
+		desired.Emails = current.Emails
+	}
+	if desired.Active == nil {
+		desired.Active = current.Active
+	}
+	return s.updateUser(ctx, requester, current, &desired)
+}
+
+// PatchUser applies patch operations to a member of the organization of the requester.
+func (s *Service) PatchUser(ctx context.Context, requester identity.Requester, id string, operations []PatchOperation) (*User, error) {
+	current, err := s.GetUser(ctx, requester, id)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := *current
+	for _, op := range operations {
+		if err := applyUserPatch(&desired, op); err != nil {
+			return nil, err
+		}
+	}
+	// the display name takes precedence over the name, which would otherwise never be applied
+	if desired.DisplayName == current.DisplayName && desired.Name.String() != current.Name.String() {
+		desired.DisplayName = ""
+	}
+	return s.updateUser(ctx, requester, current, &desired)
+}
+
+// DeleteUser removes a user from the organization of the requester and revokes its sessions.
+// Users who are no longer a member of any organization are disabled, rather than deleted,
+// so that their resources are kept and they can be provisioned again.
+func (s *Service) DeleteUser(ctx context.Context, requester identity.Requester, id string) error {
+	current, err := s.GetUser(ctx, requester, id)
+	if err != nil {
+		return err
+	}
+	usr, err := s.getManageableUser(ctx, current)
+	if err != nil {
+		return err
+	}
+	return s.removeUser(ctx, requester.GetOrgID(), usr)
+}
+
+// removeUser removes a user from an organization and revokes its sessions, and disables it if it's no longer a member
+// of any organization.
+func (s *Service) removeUser(ctx context.Context, orgID int64, usr *user.User) error {
+	if err := s.orgService.RemoveOrgUser(ctx, &org.RemoveOrgUserCommand{OrgID: orgID, UserID: usr.ID}); err != nil {
+		return err
+	}
+	if err := s.store.setExternalID(ctx, orgID, resourceTypeUser, usr.ID, ""); err != nil {
+		return err
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: usr.ID, IsDisabled: true}); err != nil {
+			return err
+		}
+	}
+	if err := s.userTokenService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+		return err
+	}
+
+	s.log.FromContext(ctx).Info("Deprovisioned user", "userId", usr.ID, "login", usr.Login, "orgId", orgID, "disabled", len(orgs) == 0)
+	return nil
+}
+
+func (s *Service) updateUser(ctx context.Context, requester identity.Requester, current, desired *User) (*User, error) {
+	usr, err := s.getManageableUser(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	login, email, name := desired.UserName, desired.PrimaryEmail(), desired.FullName()
+	if login == "" {
+		return nil, ErrInvalidValue.Errorf("userName is required")
+	}
+
+	// the attributes and the status of users are global, so they can only be changed for users who aren't members of
+	// other organizations
+	orgID := requester.GetOrgID()
+	shared, err := s.isMemberOfOtherOrgs(ctx, orgID, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if login != usr.Login || email != usr.Email || name != usr.Name {
+		if shared {
+			return nil, ErrForbidden.Errorf("user %d is a member of other organizations, its userName, name and emails can't be changed with SCIM", usr.ID)
+		}
+		if err := s.checkUnique(ctx, usr.ID, login, email); err != nil {
+			return nil, err
+		}
+		if err := s.userService.Update(ctx, &user.UpdateUserCommand{UserID: usr.ID, Login: login, Email: email, Name: name}); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.Active != nil && bool(*desired.Active) == usr.IsDisabled {
+		active := bool(*desired.Active)
+		if shared && active {
+			return nil, ErrForbidden.Errorf("user %d is a member of other organizations, it can't be activated with SCIM", usr.ID)
+		}
+		if shared {
+			// deactivating the user only removes it from the organization, as DeleteUser does
+			if err := s.removeUser(ctx, orgID, usr); err != nil {
+				return nil, err
+			}
+			removed := *current
+			removed.Active = boolPtr(false)
+			removed.ExternalID = ""
+			return &removed, nil
+		}
+		if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: usr.ID, IsDisabled: !active}); err != nil {
+			return nil, err
+		}
+		if !active {
+			if err := s.userTokenService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+				return nil, err
+			}
+		}
+		s.log.FromContext(ctx).Info("Changed user status", "userId", usr.ID, "login", login, "active", active)
+	}
+
+	if desired.ExternalID != current.ExternalID {
+		if err := s.store.setExternalID(ctx, orgID, resourceTypeUser, usr.ID, desired.ExternalID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetUser(ctx, requester, current.ID)
+}
+
+// getManageableUser returns the user, unless it's a Grafana server administrator:
+// server administrators are shared by all organizations and can't be managed by the administrators of one of them.
+func (s *Service) getManageableUser(ctx context.Context, u *User) (*user.User, error) {
+	userID, err := parseResourceID(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if usr.IsAdmin {
+		return nil, ErrForbidden.Errorf("user %d is a server administrator and can't be managed with SCIM", userID)
+	}
+	return usr, nil
+}
+
+// isMemberOfOtherOrgs returns true if the user is a member of other organizations than the given one.
+func (s *Service) isMemberOfOtherOrgs(ctx context.Context, orgID, userID int64) (bool, error) {
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		if o.OrgID != orgID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkUnique returns an error if the login or the email is used by another user.
+func (s *Service) checkUnique(ctx context.Context, userID int64, login, email string) error {
+	for _, v := range []string{login, email} {
+		if v == "" {
+			continue
+		}
+		other, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: v})
+		if errors.Is(err, user.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if other.ID != userID {
+			return ErrUniqueness.Errorf("%q is already used by another user", v)
+		}
+	}
+	return nil
+}
+
+func (s *Service) toSCIMUser(orgUser *org.OrgUserDTO, external string) *User {
+	id := strconv.FormatInt(orgUser.UserID, 10)
+	created, updated := orgUser.Created, orgUser.Updated
+
+	u := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          id,
+		ExternalID:  external,
+		UserName:    orgUser.Login,
+		DisplayName: orgUser.Name,
+		Active:      boolPtr(!orgUser.IsDisabled),
+		Meta: &Meta{
+			ResourceType: resourceTypeUser,
+			Created:      &created,
+			LastModified: &updated,
+			Location:     s.location("Users", id),
+		},
+	}
+	if orgUser.Name != "" {
+		u.Name = &Name{Formatted: orgUser.Name}
+	}
+	if orgUser.Email != "" {
+		u.Emails = []MultiValued{{Value: orgUser.Email, Primary: true}}
+	}
+	return u
+}
+
+// userAttributes returns the values of the attributes of a user that can be filtered on.
+func userAttributes(u *User) func(attr string) ([]string, bool) {
+	return func(attr string) ([]string, bool) {
+		switch attr {
+		case "id":
+			return []string{u.ID}, true
+		case "externalid":
+			return []string{u.ExternalID}, true
+		case "username":
+			return []string{u.UserName}, true
+		case "displayname", "name.formatted":
+			return []string{u.FullName()}, true
+		case "emails.value":
+			emails := make([]string, 0, len(u.Emails))
+			for _, e := range u.Emails {
+				emails = append(emails, e.Value)
+			}
+			return emails, true
+		case "active":
+			return []string{strconv.FormatBool(u.Active != nil && bool(*u.Active))}, true
+		}
+		return nil, false
+	}
+}
+
+// applyUserPatch applies a patch operation to a user, as defined in RFC 7644 section 3.5.2.
+// Attributes that Grafana users don't have, such as the title or the enterprise extension, are ignored.
+func applyUserPatch(u *User, op PatchOperation) error {
+	if err := validatePatchOp(op); err != nil {
+		return err
+	}
+
+	if op.Path == "" {
+		return forEachPatchValue(op, func(nested PatchOperation) error {
+			return applyUserPatch(u, nested)
+		})
+	}
+
+	switch attr := normalizeAttribute(op.Path); attr {
+	case "active":
+		if isRemove(op) {
+			return ErrMutability.Errorf("active can't be removed")
+		}
+		var active Bool
+		if err := decodePatchValue(op, &active); err != nil {
+			return err
+		}
+		u.Active = &active
+	case "username":
+		var login string
+		if err := decodePatchValue(op, &login); err != nil {
+			return err
+		}
+		if login == "" {
+			return ErrMutability.Errorf("userName can't be removed")
+		}
+		u.UserName = login
+	case "displayname":
+		return decodePatchValue(op, &u.DisplayName)
+	case "name", "name.formatted", "name.givenname", "name.familyname":
+		var name Name
+		if u.Name != nil {
+			name = *u.Name
+		}
+		var err error
+		switch attr {
+		case "name":
+			name = Name{}
+			err = decodePatchValue(op, &name)
+		case "name.formatted":
+			err = decodePatchValue(op, &name.Formatted)
+		case "name.givenname":
+			name.Formatted = ""
+			err = decodePatchValue(op, &name.GivenName)
+		case "name.familyname":
+			name.Formatted = ""
+			err = decodePatchValue(op, &name.FamilyName)
+		}
+		if err != nil {
+			return err
+		}
+		u.Name = &name
+	case "emails.value":
+		// emails can be patched as a list, or as the value of the work or primary email
+		if strings.HasPrefix(strings.TrimSpace(string(op.Value)), "[") {
+			var emails []MultiValued
+			if err := decodePatchValue(op, &emails); err != nil {
+				return err
+			}
+			u.Emails = emails
+			return nil
+		}
+		var email string
+		if err := decodePatchValue(op, &email); err != nil {
+			return err
+		}
+		u.Emails = nil
+		if email != "" {
+			u.Emails = []MultiValued{{Value: email, Primary: true}}
+		}
+	case "externalid":
+		return decodePatchValue(op, &u.ExternalID)
+	}
+	return nil
+}
+
+func validatePatchOp(op PatchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace", "remove":
+		return nil
+	}
+	return ErrInvalidValue.Errorf("unsupported patch operation %q", op.Op)
+}
+
+func isRemove(op PatchOperation) bool {
+	return strings.EqualFold(op.Op, "remove")
+}
+
+// forEachPatchValue calls fn with an operation for each attribute of the value of an operation without path.
+func forEachPatchValue(op PatchOperation, fn func(PatchOperation) error) error {
+	if isRemove(op) {
+		return ErrInvalidPath.Errorf("path is required to remove attributes")
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &values); err != nil {
+		return ErrInvalidValue.Errorf("the value of a patch operation without path must be an object: %w", err)
+	}
+	for path, value := range values {
+		if err := fn(PatchOperation{Op: op.Op, Path: path, Value: value}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodePatchValue decodes the value of a patch operation into v, which is reset if the operation is a remove.
+func decodePatchValue(op PatchOperation, v any) error {
+	if isRemove(op) {
+		rv := reflect.ValueOf(v).Elem()
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	if err := json.Unmarshal(op.Value, v); err != nil {
+		return ErrInvalidValue.Errorf("invalid value for %q: %w", op.Path, err)
+	}
+	return nil
+}
+
+// parseResourceID parses the ID of a user or a group, which is the ID of the Grafana user or team.
+func parseResourceID(id string) (int64, error) {
+	resourceID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || resourceID <= 0 {
+		return 0, ErrNotFound.Errorf("invalid id %q", id)
+	}
+	return resourceID, nil
+}
@@ -0,0 +1,171 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	current := func() *User {
+		return &User{
+			ID:          "1",
+			UserName:    "alice",
+			DisplayName: "Alice",
+			Name:        &Name{Formatted: "Alice"},
+			Emails:      []MultiValued{{Value: "alice@example.org", Primary: true}},
+			Active:      boolPtr(true),
+		}
+	}
+
+	t.Run("should replace attributes by path", func(t *testing.T) {
+		u := current()
+		require.NoError(t, applyUserPatch(u, PatchOperation{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)}))
+		require.NoError(t, applyUserPatch(u, PatchOperation{Op: "replace", Path: "userName", Value: json.RawMessage(`"alice.doe"`)}))
+		require.NoError(t, applyUserPatch(u, PatchOperation{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"alice@example.com"`)}))
+		require.NoError(t, applyUserPatch(u, PatchOperation{Op: "add", Path: "externalId", Value: json.RawMessage(`"00u1"`)}))
+
+		assert.False(t, bool(*u.Active))
+		assert.Equal(t, "alice.doe", u.UserName)
+		assert.Equal(t, "alice@example.com", u.PrimaryEmail())
+		assert.Equal(t, "00u1", u.ExternalID)
+	})
+
+	t.Run("should replace attributes without path", func(t *testing.T) {
+		u := current()
+		require.NoError(t, applyUserPatch(u, PatchOperation{
+			Op:    "replace",
+			Value: json.RawMessage(`{"name.givenName": "Alicia", "name.familyName": "Doe", "title": "Engineer"}`),
+		}))
+
+		assert.Equal(t, "Alicia Doe", u.Name.String())
+	})
+
+	t.Run("should remove attributes", func(t *testing.T) {
+		u := current()
+		require.NoError(t, applyUserPatch(u, PatchOperation{Op: "remove", Path: "displayName"}))
+		require.NoError(t, applyUserPatch(u, PatchOperation{Op: "remove", Path: "emails"}))
+
+		assert.Empty(t, u.DisplayName)
+		assert.Empty(t, u.Emails)
+	})
+
+	t.Run("should not remove the user name", func(t *testing.T) {
+		err := applyUserPatch(current(), PatchOperation{Op: "remove", Path: "userName"})
+		require.ErrorIs(t, err, ErrMutability)
+	})
+
+	t.Run("should reject unknown operations", func(t *testing.T) {
+		err := applyUserPatch(current(), PatchOperation{Op: "move", Path: "userName"})
+		require.ErrorIs(t, err, ErrInvalidValue)
+	})
+}
+
+func TestIntegrationUpdateUser(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	requester := &user.SignedInUser{OrgID: 1}
+
+	setup := func(orgs ...int64) (*Service, *usertest.FakeUserService, *orgtest.FakeOrgService, *[]*user.DisableUserCommand) {
+		disabled := []*user.DisableUserCommand{}
+		userService := usertest.NewUserServiceFake()
+		userService.ExpectedUser = &user.User{ID: 2, Login: "alice", Email: "alice@example.org", Name: "Alice"}
+		userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+			disabled = append(disabled, cmd)
+			return nil
+		}
+
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedSearchOrgUsersResult = &org.SearchOrgUsersQueryResult{OrgUsers: []*org.OrgUserDTO{
+			{OrgID: 1, UserID: 2, Login: "alice", Email: "alice@example.org", Name: "Alice"},
+		}}
+		for _, id := range orgs {
+			orgService.ExpectedUserOrgDTO = append(orgService.ExpectedUserOrgDTO, &org.UserOrgDTO{OrgID: id})
+		}
+		orgService.ExpectedOrgListResponse = orgtest.OrgListResponse{{OrgID: 1}}
+
+		s := &Service{
+			store:            &store{db: sqlStore},
+			userService:      userService,
+			orgService:       orgService,
+			userTokenService: authtest.NewFakeUserAuthTokenService(),
+			log:              log.NewNopLogger(),
+		}
+		return s, userService, orgService, &disabled
+	}
+
+	alice := func() *User {
+		return &User{
+			UserName: "alice",
+			Name:     &Name{Formatted: "Alice"},
+			Emails:   []MultiValued{{Value: "alice@example.org", Primary: true}},
+		}
+	}
+
+	t.Run("should disable users who are only members of the organization", func(t *testing.T) {
+		s, _, orgService, disabled := setup(1)
+
+		desired := alice()
+		desired.Active = boolPtr(false)
+		_, err := s.ReplaceUser(ctx, requester, "2", desired)
+		require.NoError(t, err)
+
+		require.Len(t, *disabled, 1)
+		assert.True(t, (*disabled)[0].IsDisabled)
+		// the user is still a member of the organization
+		assert.Len(t, orgService.ExpectedOrgListResponse, 1)
+	})
+
+	t.Run("should only remove deactivated users who are members of other organizations", func(t *testing.T) {
+		s, _, orgService, disabled := setup(1, 2)
+
+		desired := alice()
+		desired.Active = boolPtr(false)
+		updated, err := s.ReplaceUser(ctx, requester, "2", desired)
+		require.NoError(t, err)
+
+		assert.False(t, bool(*updated.Active))
+		assert.Empty(t, *disabled)
+		assert.Empty(t, orgService.ExpectedOrgListResponse)
+	})
+
+	t.Run("should not change the attributes of users who are members of other organizations", func(t *testing.T) {
+		s, _, _, _ := setup(1, 2)
+
+		desired := alice()
+		desired.UserName = "alice.doe"
+		_, err := s.ReplaceUser(ctx, requester, "2", desired)
+		require.ErrorIs(t, err, ErrForbidden)
+
+		desired = alice()
+		desired.Emails = []MultiValued{{Value: "alice@example.com", Primary: true}}
+		_, err = s.ReplaceUser(ctx, requester, "2", desired)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("should not activate disabled users who are members of other organizations", func(t *testing.T) {
+		s, userService, _, disabled := setup(1, 2)
+		userService.ExpectedUser.IsDisabled = true
+
+		desired := alice()
+		desired.Active = boolPtr(true)
+		_, err := s.ReplaceUser(ctx, requester, "2", desired)
+		require.ErrorIs(t, err, ErrForbidden)
+		assert.Empty(t, *disabled)
+	})
+}
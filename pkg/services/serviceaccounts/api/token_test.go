@@ -64,6 +64,7 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 		body           string
 		permissions    []accesscontrol.Permission
 		tokenTTL       int64
+		tokenPolicy    *serviceaccounts.TokenPolicy
 		expectedErr    error
 		expectedAPIKey *apikey.APIKey
 		expectedCode   int
@@ -104,6 +105,34 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			desc:         "should not be able to create token that never expires if the token policy requires expiration",
+			id:           1,
+			body:         `{"name": "test"}`,
+			tokenTTL:     -1,
+			tokenPolicy:  &serviceaccounts.TokenPolicy{RequireExpiration: true},
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:           "should be able to create token within the maximum lifetime of the token policy",
+			id:             1,
+			body:           `{"name": "test", "secondsToLive": 3600}`,
+			tokenTTL:       -1,
+			tokenPolicy:    &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600},
+			permissions:    []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedAPIKey: &apikey.APIKey{},
+			expectedCode:   http.StatusOK,
+		},
+		{
+			desc:         "should not be able to create token beyond the maximum lifetime of the token policy",
+			id:           1,
+			body:         `{"name": "test", "secondsToLive": 3601}`,
+			tokenTTL:     -1,
+			tokenPolicy:  &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600},
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -111,8 +140,9 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			server := setupTests(t, func(a *ServiceAccountsAPI) {
 				a.cfg.ApiKeyMaxSecondsToLive = tt.tokenTTL
 				a.service = &satests.FakeServiceAccountService{
-					ExpectedErr:    tt.expectedErr,
-					ExpectedAPIKey: tt.expectedAPIKey,
+					ExpectedErr:         tt.expectedErr,
+					ExpectedAPIKey:      tt.expectedAPIKey,
+					ExpectedTokenPolicy: tt.tokenPolicy,
 				}
 			})
 			req := server.NewRequest(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens", tt.id), strings.NewReader(tt.body))
@@ -177,3 +207,125 @@ func TestServiceAccountsAPI_DeleteToken(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAccountsAPI_RotateToken(t *testing.T) {
+	type TestCase struct {
+		desc         string
+		saID         int64
+		tokenID      int64
+		body         string
+		permissions  []accesscontrol.Permission
+		tokenPolicy  *serviceaccounts.TokenPolicy
+		expectedCode int
+	}
+
+	expires := time.Now().Add(time.Hour).Unix()
+	tokens := []apikey.APIKey{{ID: 1, Name: "ci", Created: time.Now(), Expires: &expires}, {ID: 2, Name: "never-expires", Created: time.Now()}}
+
+	tests := []TestCase{
+		{
+			desc:         "should be able to rotate service account token with correct permission",
+			saID:         1,
+			tokenID:      1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to rotate service account token with wrong permission",
+			saID:         2,
+			tokenID:      1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not be able to rotate service account token that doesn't exist",
+			saID:         1,
+			tokenID:      3,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "should keep the lifetime of the rotated token when it complies with the token policy",
+			saID:         1,
+			tokenID:      1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			tokenPolicy:  &serviceaccounts.TokenPolicy{RequireExpiration: true},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to rotate token into a token that never expires if the token policy requires expiration",
+			saID:         1,
+			tokenID:      2,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			tokenPolicy:  &serviceaccounts.TokenPolicy{RequireExpiration: true},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should be able to rotate token that never expires into a token that expires",
+			saID:         1,
+			tokenID:      2,
+			body:         `{"secondsToLive": 3600, "gracePeriodSeconds": 60}`,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			tokenPolicy:  &serviceaccounts.TokenPolicy{RequireExpiration: true},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.cfg.ApiKeyMaxSecondsToLive = -1
+				a.service = &satests.FakeServiceAccountService{
+					ExpectedAPIKey:               &apikey.APIKey{ID: 3, Name: "ci"},
+					ExpectedServiceAccountTokens: tokens,
+					ExpectedTokenPolicy:          tt.tokenPolicy,
+				}
+			})
+
+			req := server.NewRequest(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens/%d/rotate", tt.saID, tt.tokenID), strings.NewReader(tt.body))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestServiceAccountsAPI_UpdateTokenPolicy(t *testing.T) {
+	type TestCase struct {
+		desc         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []TestCase{
+		{
+			desc:         "should be able to update token policy with write permission on all service accounts",
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: serviceaccounts.ScopeAll}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to update token policy with write permission on a single service account",
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.service = &satests.FakeServiceAccountService{}
+			})
+
+			req := server.NewRequest(http.MethodPut, "/api/serviceaccounts/token-policy", strings.NewReader(`{"maxSecondsToLive": 2592000, "requireExpiration": true}`))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
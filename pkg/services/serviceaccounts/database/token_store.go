@@ -2,16 +2,24 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 )
 
-const maxRetrievedTokens = 300
+const (
+	maxRetrievedTokens = 300
+
+	kvStoreNamespace = "serviceaccounts"
+	tokenPolicyKey   = "token_policy"
+)
 
 func (s *ServiceAccountsStoreImpl) ListTokens(
 	ctx context.Context, query *serviceaccounts.GetSATokensQuery,
@@ -109,6 +117,102 @@ func (s *ServiceAccountsStoreImpl) RevokeServiceAccountToken(ctx context.Context
 	})
 }
 
+// RotateServiceAccountToken adds a token with the name of the rotated token, which is renamed and expires at the end
+// of the grace period, unless it expires before.
+func (s *ServiceAccountsStoreImpl) RotateServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64,
+	cmd *serviceaccounts.RotateServiceAccountTokenCommand, gracePeriod time.Duration) (*apikey.APIKey, error) {
+	var apiKey *apikey.APIKey
+
+	return apiKey, s.sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		rotated := apikey.APIKey{}
+		err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			exists, err := sess.Where("id=? AND org_id=? AND service_account_id=?", tokenID, orgID, serviceAccountID).Get(&rotated)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return serviceaccounts.ErrServiceAccountTokenNotFound.Errorf("service account token with id %d not found for service account with id %d", tokenID, serviceAccountID)
+			}
+
+			expires := now.Add(gracePeriod).Unix()
+			if rotated.Expires != nil && *rotated.Expires < expires {
+				expires = *rotated.Expires
+			}
+			renamed := apikey.APIKey{
+				Name:    fmt.Sprintf("%s (rotated %s)", rotated.Name, now.UTC().Format(time.RFC3339)),
+				Expires: &expires,
+				Updated: now,
+			}
+			_, err = sess.ID(rotated.ID).Cols("name", "expires", "updated").Update(&renamed)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		key, err := s.apiKeyService.AddAPIKey(ctx, &apikey.AddCommand{
+			Name:             rotated.Name,
+			Role:             org.RoleViewer,
+			OrgID:            orgID,
+			Key:              cmd.Key,
+			SecondsToLive:    cmd.SecondsToLive,
+			ServiceAccountID: &serviceAccountID,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, apikey.ErrDuplicate):
+				return serviceaccounts.ErrDuplicateToken.Errorf("service account token with name %s already exists in the organization", rotated.Name)
+			case errors.Is(err, apikey.ErrInvalidExpiration):
+				return serviceaccounts.ErrInvalidTokenExpiration.Errorf("invalid service account token expiration value %d", cmd.SecondsToLive)
+			}
+
+			return err
+		}
+
+		apiKey = key
+		return nil
+	})
+}
+
+// ListExpiringTokens returns the tokens of all organizations that expire in the queried time range.
+// Revoked tokens are left out.
+func (s *ServiceAccountsStoreImpl) ListExpiringTokens(ctx context.Context, query *serviceaccounts.GetExpiringSATokensQuery) ([]*serviceaccounts.ExpiringToken, error) {
+	result := make([]*serviceaccounts.ExpiringToken, 0)
+	err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		quotedUser := s.sqlStore.GetDialect().Quote("user")
+		return sess.Table("api_key").
+			Select("api_key.id, api_key.name, api_key.org_id, api_key.service_account_id, api_key.expires, "+quotedUser+".name AS service_account_name").
+			Join("inner", quotedUser, quotedUser+".id = api_key.service_account_id").
+			Where("api_key.expires > ? AND api_key.expires <= ?", query.ExpiresAfter.Unix(), query.ExpiresBefore.Unix()).
+			Where("(api_key.is_revoked IS NULL OR api_key.is_revoked = ?)", s.sqlStore.GetDialect().BooleanStr(false)).
+			Asc("api_key.org_id", "api_key.expires").
+			Find(&result)
+	})
+	return result, err
+}
+
+// GetTokenPolicy returns the token policy of the org. Orgs without a policy get an empty policy.
+func (s *ServiceAccountsStoreImpl) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	policy := &serviceaccounts.TokenPolicy{}
+	value, exists, err := kvstore.WithNamespace(s.kvStore, orgID, kvStoreNamespace).Get(ctx, tokenPolicyKey)
+	if err != nil || !exists {
+		return policy, err
+	}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("failed to parse token policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (s *ServiceAccountsStoreImpl) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(s.kvStore, orgID, kvStoreNamespace).Set(ctx, tokenPolicyKey, string(value))
+}
+
 // assignApiKeyToServiceAccount sets the API key service account ID
 func (s *ServiceAccountsStoreImpl) assignApiKeyToServiceAccount(ctx context.Context, apiKeyId int64, serviceAccountId int64) error {
 	return s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -2,11 +2,14 @@ package database
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
 )
@@ -176,3 +179,131 @@ func TestStore_DeleteServiceAccountToken(t *testing.T) {
 		}
 	}
 }
+
+func TestStore_RotateServiceAccountToken(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	addToken := func(t *testing.T, name string, secondsToLive int64) *apikey.APIKey {
+		t.Helper()
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:          name,
+			OrgId:         sa.OrgID,
+			Key:           key.HashedKey,
+			SecondsToLive: secondsToLive,
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	getToken := func(t *testing.T, id int64) apikey.APIKey {
+		t.Helper()
+		tokens, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{OrgID: &sa.OrgID, ServiceAccountID: &sa.ID})
+		require.NoError(t, err)
+		for _, token := range tokens {
+			if token.ID == id {
+				return token
+			}
+		}
+		require.Failf(t, "token not found", "token %d", id)
+		return apikey.APIKey{}
+	}
+
+	t.Run("the new token takes the name of the rotated token, which expires at the end of the grace period", func(t *testing.T) {
+		rotated := addToken(t, "ci", 0)
+		key, err := apikeygen.New(sa.OrgID, "ci")
+		require.NoError(t, err)
+
+		now := time.Now()
+		newToken, err := store.RotateServiceAccountToken(context.Background(), sa.OrgID, sa.ID, rotated.ID,
+			&serviceaccounts.RotateServiceAccountTokenCommand{OrgId: sa.OrgID, Key: key.HashedKey, SecondsToLive: 3600}, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, "ci", newToken.Name)
+		require.NotNil(t, newToken.Expires)
+
+		old := getToken(t, rotated.ID)
+		require.True(t, strings.HasPrefix(old.Name, "ci (rotated "), old.Name)
+		require.NotNil(t, old.Expires)
+		require.InDelta(t, now.Add(time.Hour).Unix(), *old.Expires, 5)
+	})
+
+	t.Run("the grace period doesn't extend the lifetime of the rotated token", func(t *testing.T) {
+		rotated := addToken(t, "short-lived", 60)
+		key, err := apikeygen.New(sa.OrgID, "short-lived")
+		require.NoError(t, err)
+
+		_, err = store.RotateServiceAccountToken(context.Background(), sa.OrgID, sa.ID, rotated.ID,
+			&serviceaccounts.RotateServiceAccountTokenCommand{OrgId: sa.OrgID, Key: key.HashedKey, SecondsToLive: 60}, time.Hour)
+		require.NoError(t, err)
+
+		old := getToken(t, rotated.ID)
+		require.Equal(t, *rotated.Expires, *old.Expires)
+	})
+
+	t.Run("should fail to rotate a token of another service account", func(t *testing.T) {
+		rotated := addToken(t, "other", 0)
+		key, err := apikeygen.New(sa.OrgID, "other")
+		require.NoError(t, err)
+
+		_, err = store.RotateServiceAccountToken(context.Background(), sa.OrgID, sa.ID+1, rotated.ID,
+			&serviceaccounts.RotateServiceAccountTokenCommand{OrgId: sa.OrgID, Key: key.HashedKey}, time.Hour)
+		require.ErrorIs(t, err, serviceaccounts.ErrServiceAccountTokenNotFound)
+
+		require.Equal(t, "other", getToken(t, rotated.ID).Name)
+	})
+}
+
+func TestStore_ListExpiringTokens(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	for name, secondsToLive := range map[string]int64{"never": 0, "soon": 3600, "later": 30 * 24 * 3600, "revoked": 3600} {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:          name,
+			OrgId:         sa.OrgID,
+			Key:           key.HashedKey,
+			SecondsToLive: secondsToLive,
+		})
+		require.NoError(t, err)
+		if name == "revoked" {
+			require.NoError(t, store.RevokeServiceAccountToken(context.Background(), sa.OrgID, sa.ID, token.ID))
+		}
+	}
+
+	now := time.Now()
+	expiring, err := store.ListExpiringTokens(context.Background(), &serviceaccounts.GetExpiringSATokensQuery{
+		ExpiresAfter:  now,
+		ExpiresBefore: now.Add(7 * 24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	require.Equal(t, "soon", expiring[0].Name)
+	require.Equal(t, sa.ID, expiring[0].ServiceAccountID)
+	require.Equal(t, sa.OrgID, expiring[0].OrgID)
+	require.Equal(t, sa.Name, expiring[0].ServiceAccountName)
+}
+
+func TestStore_TokenPolicy(t *testing.T) {
+	_, store := setupTestDatabase(t)
+
+	policy, err := store.GetTokenPolicy(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, &serviceaccounts.TokenPolicy{}, policy)
+
+	expected := &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600, RequireExpiration: true}
+	require.NoError(t, store.UpdateTokenPolicy(context.Background(), 1, expected))
+
+	policy, err = store.GetTokenPolicy(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, expected, policy)
+
+	policy, err = store.GetTokenPolicy(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, &serviceaccounts.TokenPolicy{}, policy)
+}
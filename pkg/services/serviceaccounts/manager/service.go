@@ -33,6 +33,8 @@ type ServiceAccountsService struct {
 
 	secretScanEnabled  bool
 	secretScanInterval time.Duration
+
+	tokenRotationGracePeriod time.Duration
 }
 
 func ProvideServiceAccountsService(
@@ -54,9 +56,10 @@ func ProvideServiceAccountsService(
 		orgService,
 	)
 	s := &ServiceAccountsService{
-		store:         serviceAccountsStore,
-		log:           log.New("serviceaccounts"),
-		backgroundLog: log.New("serviceaccounts.background"),
+		store:                    serviceAccountsStore,
+		log:                      log.New("serviceaccounts"),
+		backgroundLog:            log.New("serviceaccounts.background"),
+		tokenRotationGracePeriod: cfg.SATokenRotationGracePeriod,
 	}
 
 	if err := RegisterRoles(accesscontrolService); err != nil {
@@ -229,6 +232,48 @@ func (sa *ServiceAccountsService) DeleteServiceAccountToken(ctx context.Context,
 	return sa.store.DeleteServiceAccountToken(ctx, orgID, serviceAccountID, tokenID)
 }
 
+func (sa *ServiceAccountsService) RotateServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	if err := validOrgID(orgID); err != nil {
+		return nil, err
+	}
+	if err := validServiceAccountID(serviceAccountID); err != nil {
+		return nil, err
+	}
+	if err := validServiceAccountTokenID(tokenID); err != nil {
+		return nil, err
+	}
+
+	gracePeriod := sa.tokenRotationGracePeriod
+	if cmd.GracePeriodSeconds != nil {
+		if *cmd.GracePeriodSeconds < 0 {
+			return nil, serviceaccounts.ErrInvalidTokenExpiration.Errorf("invalid grace period %d", *cmd.GracePeriodSeconds)
+		}
+		gracePeriod = time.Duration(*cmd.GracePeriodSeconds) * time.Second
+	}
+	return sa.store.RotateServiceAccountToken(ctx, orgID, serviceAccountID, tokenID, cmd, gracePeriod)
+}
+
+func (sa *ServiceAccountsService) ListExpiringTokens(ctx context.Context, query *serviceaccounts.GetExpiringSATokensQuery) ([]*serviceaccounts.ExpiringToken, error) {
+	return sa.store.ListExpiringTokens(ctx, query)
+}
+
+func (sa *ServiceAccountsService) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	if err := validOrgID(orgID); err != nil {
+		return nil, err
+	}
+	return sa.store.GetTokenPolicy(ctx, orgID)
+}
+
+func (sa *ServiceAccountsService) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	if err := validOrgID(orgID); err != nil {
+		return err
+	}
+	if policy.MaxSecondsToLive < 0 {
+		return serviceaccounts.ErrInvalidTokenPolicy.Errorf("invalid maximum token lifetime %d", policy.MaxSecondsToLive)
+	}
+	return sa.store.UpdateTokenPolicy(ctx, orgID, policy)
+}
+
 func (sa *ServiceAccountsService) MigrateApiKey(ctx context.Context, orgID, keyID int64) error {
 	if err := validOrgID(orgID); err != nil {
 		return err
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -22,7 +23,12 @@ type FakeServiceAccountStore struct {
 	ExpectedAPIKeys                         []apikey.APIKey
 	ExpectedAPIKey                          *apikey.APIKey
 	ExpectedBoolean                         bool
+	ExpectedTokenPolicy                     *serviceaccounts.TokenPolicy
+	ExpectedExpiringTokens                  []*serviceaccounts.ExpiringToken
 	ExpectedError                           error
+
+	// RotatedGracePeriod is the grace period of the last rotated token.
+	RotatedGracePeriod time.Duration
 }
 
 var _ store = (*FakeServiceAccountStore)(nil)
@@ -102,6 +108,28 @@ func (f *FakeServiceAccountStore) DeleteServiceAccountToken(ctx context.Context,
 	return f.ExpectedError
 }
 
+// RotateServiceAccountToken is a fake rotating a service account token.
+func (f *FakeServiceAccountStore) RotateServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64,
+	cmd *serviceaccounts.RotateServiceAccountTokenCommand, gracePeriod time.Duration) (*apikey.APIKey, error) {
+	f.RotatedGracePeriod = gracePeriod
+	return f.ExpectedAPIKey, f.ExpectedError
+}
+
+// ListExpiringTokens is a fake listing expiring tokens.
+func (f *FakeServiceAccountStore) ListExpiringTokens(ctx context.Context, query *serviceaccounts.GetExpiringSATokensQuery) ([]*serviceaccounts.ExpiringToken, error) {
+	return f.ExpectedExpiringTokens, f.ExpectedError
+}
+
+// GetTokenPolicy is a fake getting the token policy of an org.
+func (f *FakeServiceAccountStore) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	return f.ExpectedTokenPolicy, f.ExpectedError
+}
+
+// UpdateTokenPolicy is a fake updating the token policy of an org.
+func (f *FakeServiceAccountStore) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	return f.ExpectedError
+}
+
 // GetUsageMetrics is a fake getting usage metrics.
 func (f *FakeServiceAccountStore) GetUsageMetrics(ctx context.Context) (*serviceaccounts.Stats, error) {
 	return f.ExpectedStats, f.ExpectedError
@@ -117,7 +145,7 @@ func (f *SecretsCheckerFake) CheckTokens(ctx context.Context) error {
 
 func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 	storeMock := newServiceAccountStoreFake()
-	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0}
+	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0, time.Hour}
 	testOrgId := 1
 
 	t.Run("should create service account", func(t *testing.T) {
@@ -144,3 +172,41 @@ func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestProvideServiceAccount_RotateServiceAccountToken(t *testing.T) {
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0, time.Hour}
+
+	t.Run("should use the configured grace period by default", func(t *testing.T) {
+		_, err := svc.RotateServiceAccountToken(context.Background(), 1, 1, 1, &serviceaccounts.RotateServiceAccountTokenCommand{})
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, storeMock.RotatedGracePeriod)
+	})
+
+	t.Run("should use the grace period of the command", func(t *testing.T) {
+		gracePeriod := int64(0)
+		_, err := svc.RotateServiceAccountToken(context.Background(), 1, 1, 1, &serviceaccounts.RotateServiceAccountTokenCommand{GracePeriodSeconds: &gracePeriod})
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), storeMock.RotatedGracePeriod)
+	})
+
+	t.Run("should reject a negative grace period", func(t *testing.T) {
+		gracePeriod := int64(-1)
+		_, err := svc.RotateServiceAccountToken(context.Background(), 1, 1, 1, &serviceaccounts.RotateServiceAccountTokenCommand{GracePeriodSeconds: &gracePeriod})
+		require.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenExpiration)
+	})
+
+	t.Run("should reject an invalid token ID", func(t *testing.T) {
+		_, err := svc.RotateServiceAccountToken(context.Background(), 1, 1, 0, &serviceaccounts.RotateServiceAccountTokenCommand{})
+		require.ErrorIs(t, err, serviceaccounts.ErrServiceAccountInvalidTokenID)
+	})
+}
+
+func TestProvideServiceAccount_UpdateTokenPolicy(t *testing.T) {
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0, time.Hour}
+
+	require.NoError(t, svc.UpdateTokenPolicy(context.Background(), 1, &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600, RequireExpiration: true}))
+	require.ErrorIs(t, svc.UpdateTokenPolicy(context.Background(), 1, &serviceaccounts.TokenPolicy{MaxSecondsToLive: -1}), serviceaccounts.ErrInvalidTokenPolicy)
+	require.ErrorIs(t, svc.UpdateTokenPolicy(context.Background(), 0, &serviceaccounts.TokenPolicy{}), serviceaccounts.ErrServiceAccountInvalidOrgID)
+}
@@ -1,6 +1,7 @@
 package serviceaccounts
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/grafana/grafana/pkg/models/roletype"
@@ -41,6 +42,8 @@ var (
 	ErrServiceAccountTokenNotFound       = errutil.NotFound("serviceaccounts.ErrTokenNotFound", errutil.WithPublicMessage("service account token not found"))
 	ErrInvalidTokenExpiration            = errutil.ValidationFailed("serviceaccounts.ErrInvalidInput", errutil.WithPublicMessage("invalid SecondsToLive value"))
 	ErrDuplicateToken                    = errutil.BadRequest("serviceaccounts.ErrTokenAlreadyExists", errutil.WithPublicMessage("service account token with given name already exists in the organization"))
+	ErrTokenPolicyViolation              = errutil.BadRequest("serviceaccounts.ErrTokenPolicyViolation").MustTemplate("token does not comply with the token policy: {{ .Public.Reason }}", errutil.WithPublic("Service account token does not comply with the token policy of the organization: {{ .Public.Reason }}"))
+	ErrInvalidTokenPolicy                = errutil.BadRequest("serviceaccounts.ErrInvalidTokenPolicy", errutil.WithPublicMessage("invalid token policy"))
 )
 
 type MigrationResult struct {
@@ -108,6 +111,60 @@ type AddServiceAccountTokenCommand struct {
 	SecondsToLive int64  `json:"secondsToLive"`
 }
 
+// swagger:model
+type RotateServiceAccountTokenCommand struct {
+	// SecondsToLive is the lifetime of the new token. It defaults to the lifetime of the rotated token.
+	SecondsToLive int64 `json:"secondsToLive"`
+	// GracePeriodSeconds is how long the rotated token remains valid. It defaults to the token_rotation_grace_period setting.
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds"`
+	OrgId              int64  `json:"-"`
+	Key                string `json:"-"`
+}
+
+// TokenPolicy restricts the tokens that can be added to the service accounts of an organization.
+// swagger:model
+type TokenPolicy struct {
+	// MaxSecondsToLive is the maximum lifetime of new tokens. 0 means no limit.
+	// example: 2592000
+	MaxSecondsToLive int64 `json:"maxSecondsToLive"`
+	// RequireExpiration rejects new tokens that never expire.
+	// example: true
+	RequireExpiration bool `json:"requireExpiration"`
+}
+
+// Validate returns an error if a token that lives for secondsToLive seconds doesn't comply with the policy.
+// A secondsToLive of 0 is a token that never expires.
+func (p TokenPolicy) Validate(secondsToLive int64) error {
+	if secondsToLive == 0 && (p.RequireExpiration || p.MaxSecondsToLive > 0) {
+		return tokenPolicyViolation("tokens must expire")
+	}
+	if p.MaxSecondsToLive > 0 && secondsToLive > p.MaxSecondsToLive {
+		return tokenPolicyViolation(fmt.Sprintf("tokens must expire within %d seconds", p.MaxSecondsToLive))
+	}
+	return nil
+}
+
+func tokenPolicyViolation(reason string) error {
+	return ErrTokenPolicyViolation.Build(errutil.TemplateData{Public: map[string]any{"Reason": reason}})
+}
+
+// GetExpiringSATokensQuery finds the tokens that expire in the time range (ExpiresAfter, ExpiresBefore].
+type GetExpiringSATokensQuery struct {
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+}
+
+// ExpiringToken is a service account token that is about to expire.
+type ExpiringToken struct {
+	ID                 int64  `xorm:"id"`
+	Name               string `xorm:"name"`
+	OrgID              int64  `xorm:"org_id"`
+	ServiceAccountID   int64  `xorm:"service_account_id"`
+	ServiceAccountName string `xorm:"service_account_name"`
+	// Expires is the unix timestamp at which the token expires.
+	Expires int64 `xorm:"expires"`
+}
+
 type SearchOrgServiceAccountsQuery struct {
 	OrgID        int64
 	Query        string
@@ -123,6 +123,33 @@ func (s *ServiceAccountsProxy) ListTokens(ctx context.Context, query *serviceacc
 	return s.proxiedService.ListTokens(ctx, query)
 }
 
+func (s *ServiceAccountsProxy) ListExpiringTokens(ctx context.Context, query *serviceaccounts.GetExpiringSATokensQuery) ([]*serviceaccounts.ExpiringToken, error) {
+	return s.proxiedService.ListExpiringTokens(ctx, query)
+}
+
+func (s *ServiceAccountsProxy) RotateServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	if s.isProxyEnabled {
+		sa, err := s.proxiedService.RetrieveServiceAccount(ctx, orgID, serviceAccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		if isExternalServiceAccount(sa.Login) {
+			s.log.Error("unable to rotate tokens for external service accounts", "serviceAccountID", serviceAccountID)
+			return nil, extsvcaccounts.ErrCannotCreateToken
+		}
+	}
+	return s.proxiedService.RotateServiceAccountToken(ctx, orgID, serviceAccountID, tokenID, cmd)
+}
+
+func (s *ServiceAccountsProxy) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	return s.proxiedService.GetTokenPolicy(ctx, orgID)
+}
+
+func (s *ServiceAccountsProxy) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	return s.proxiedService.UpdateTokenPolicy(ctx, orgID, policy)
+}
+
 func (s *ServiceAccountsProxy) MigrateApiKey(ctx context.Context, orgID int64, keyId int64) error {
 	return s.proxiedService.MigrateApiKey(ctx, orgID, keyId)
 }
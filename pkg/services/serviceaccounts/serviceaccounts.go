@@ -29,6 +29,13 @@ type Service interface {
 		cmd *AddServiceAccountTokenCommand) (*apikey.APIKey, error)
 	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
 	ListTokens(ctx context.Context, query *GetSATokensQuery) ([]apikey.APIKey, error)
+	// RotateServiceAccountToken adds a token that replaces tokenID, and expires tokenID at the end of the grace period
+	RotateServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64,
+		cmd *RotateServiceAccountTokenCommand) (*apikey.APIKey, error)
+	// ListExpiringTokens returns the tokens of all organizations that expire in the queried time range
+	ListExpiringTokens(ctx context.Context, query *GetExpiringSATokensQuery) ([]*ExpiringToken, error)
+	GetTokenPolicy(ctx context.Context, orgID int64) (*TokenPolicy, error)
+	UpdateTokenPolicy(ctx context.Context, orgID int64, policy *TokenPolicy) error
 
 	// API specific functions
 	MigrateApiKey(ctx context.Context, orgID int64, keyId int64) error
@@ -17,6 +17,8 @@ type FakeServiceAccountService struct {
 	ExpectedServiceAccountID               int64
 	ExpectedServiceAccountProfile          *serviceaccounts.ServiceAccountProfileDTO
 	ExpectedServiceAccountTokens           []apikey.APIKey
+	ExpectedExpiringTokens                 []*serviceaccounts.ExpiringToken
+	ExpectedTokenPolicy                    *serviceaccounts.TokenPolicy
 }
 
 var _ serviceaccounts.Service = new(FakeServiceAccountService)
@@ -71,3 +73,22 @@ func (f *FakeServiceAccountService) SearchOrgServiceAccounts(ctx context.Context
 func (f *FakeServiceAccountService) DeleteServiceAccountToken(ctx context.Context, orgID, id, tokenID int64) error {
 	return f.ExpectedErr
 }
+
+func (f *FakeServiceAccountService) RotateServiceAccountToken(ctx context.Context, orgID, id, tokenID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	return f.ExpectedAPIKey, f.ExpectedErr
+}
+
+func (f *FakeServiceAccountService) ListExpiringTokens(ctx context.Context, query *serviceaccounts.GetExpiringSATokensQuery) ([]*serviceaccounts.ExpiringToken, error) {
+	return f.ExpectedExpiringTokens, f.ExpectedErr
+}
+
+func (f *FakeServiceAccountService) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	if f.ExpectedTokenPolicy == nil {
+		return &serviceaccounts.TokenPolicy{}, f.ExpectedErr
+	}
+	return f.ExpectedTokenPolicy, f.ExpectedErr
+}
+
+func (f *FakeServiceAccountService) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	return f.ExpectedErr
+}
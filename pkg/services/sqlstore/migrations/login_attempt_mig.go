@@ -39,4 +39,14 @@ func addLoginAttemptMigrations(mg *Migrator) {
 		"username":   "username",
 		"ip_address": "ip_address",
 	})
+
+	mg.AddMigration("add column ip_subnet to login_attempt", NewAddColumnMigration(loginAttemptV2, &Column{
+		Name: "ip_subnet", Type: DB_NVarchar, Length: 50, Nullable: true,
+	}))
+	mg.AddMigration("add index login_attempt.ip_address", NewAddIndexMigration(loginAttemptV2, &Index{
+		Cols: []string{"ip_address"},
+	}))
+	mg.AddMigration("add index login_attempt.ip_subnet", NewAddIndexMigration(loginAttemptV2, &Index{
+		Cols: []string{"ip_subnet"},
+	}))
 }
@@ -6,8 +6,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/anonservice"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/oauthserver"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/outboundwebhooks"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/scim"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/signingkeys"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/ssosettings"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/totp"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/ualert"
 	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
@@ -112,6 +115,12 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 
 	ssosettings.AddMigration(mg)
 
+	outboundwebhooks.AddMigration(mg)
+
+	totp.AddMigration(mg)
+
+	scim.AddMigration(mg)
+
 	ualert.CreateOrgMigratedKVStoreEntries(mg)
 }
 
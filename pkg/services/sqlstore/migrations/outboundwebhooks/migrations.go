@@ -0,0 +1,56 @@
+package outboundwebhooks
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func AddMigration(mg *migrator.Migrator) {
+	var webhookV1 = migrator.Table{
+		Name: "outbound_webhook",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "url", Type: migrator.DB_Text, Nullable: false},
+			{Name: "event_types", Type: migrator.DB_Text, Nullable: false},
+			{Name: "secret", Type: migrator.DB_Text, Nullable: true},
+			{Name: "enabled", Type: migrator.DB_Bool, Nullable: false, Default: "1"},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create outbound_webhook table", migrator.NewAddTableMigration(webhookV1))
+	mg.AddMigration("add unique index outbound_webhook.org_id_uid", migrator.NewAddIndexMigration(webhookV1, webhookV1.Indices[0]))
+
+	var deliveryV1 = migrator.Table{
+		Name: "outbound_webhook_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "webhook_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "event_type", Type: migrator.DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "payload", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "attempts", Type: migrator.DB_Int, Nullable: false, Default: "0"},
+			{Name: "next_attempt", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "last_status_code", Type: migrator.DB_Int, Nullable: false, Default: "0"},
+			{Name: "last_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "webhook_uid"}},
+			{Cols: []string{"status", "next_attempt"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create outbound_webhook_delivery table", migrator.NewAddTableMigration(deliveryV1))
+	mg.AddMigration("add index outbound_webhook_delivery.org_id_webhook_uid", migrator.NewAddIndexMigration(deliveryV1, deliveryV1.Indices[0]))
+	mg.AddMigration("add index outbound_webhook_delivery.status_next_attempt", migrator.NewAddIndexMigration(deliveryV1, deliveryV1.Indices[1]))
+	mg.AddMigration("add index outbound_webhook_delivery.created", migrator.NewAddIndexMigration(deliveryV1, deliveryV1.Indices[2]))
+}
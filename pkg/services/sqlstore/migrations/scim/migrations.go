@@ -0,0 +1,24 @@
+package scim
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func AddMigration(mg *migrator.Migrator) {
+	var externalIDV1 = migrator.Table{
+		Name: "scim_external_id",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "resource_type", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "resource_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "external_id", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "resource_type", "resource_id"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "resource_type", "external_id"}},
+		},
+	}
+
+	mg.AddMigration("create scim_external_id table", migrator.NewAddTableMigration(externalIDV1))
+	mg.AddMigration("add unique index scim_external_id.org_id_resource_type_resource_id", migrator.NewAddIndexMigration(externalIDV1, externalIDV1.Indices[0]))
+	mg.AddMigration("add index scim_external_id.org_id_resource_type_external_id", migrator.NewAddIndexMigration(externalIDV1, externalIDV1.Indices[1]))
+}
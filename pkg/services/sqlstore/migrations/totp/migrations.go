@@ -0,0 +1,40 @@
+package totp
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func AddMigration(mg *migrator.Migrator) {
+	var userTOTPV1 = migrator.Table{
+		Name: "user_totp",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "secret", Type: migrator.DB_Text, Nullable: false},
+			{Name: "enabled", Type: migrator.DB_Bool, Nullable: false, Default: "0"},
+			{Name: "last_used_step", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"user_id"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_totp table", migrator.NewAddTableMigration(userTOTPV1))
+	mg.AddMigration("add unique index user_totp.user_id", migrator.NewAddIndexMigration(userTOTPV1, userTOTPV1.Indices[0]))
+
+	var recoveryCodeV1 = migrator.Table{
+		Name: "user_totp_recovery_code",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "code_hash", Type: migrator.DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"user_id", "code_hash"}},
+		},
+	}
+
+	mg.AddMigration("create user_totp_recovery_code table", migrator.NewAddTableMigration(recoveryCodeV1))
+	mg.AddMigration("add index user_totp_recovery_code.user_id_code_hash", migrator.NewAddIndexMigration(recoveryCodeV1, recoveryCodeV1.Indices[0]))
+}
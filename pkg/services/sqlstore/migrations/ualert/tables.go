@@ -24,6 +24,38 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("add updated_by column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "updated_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true,
+	}))
+
+	mg.AddMigration("add created_by column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true,
+	}))
+
+	mg.AddMigration("add dependencies column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "dependencies", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add dependencies column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "dependencies", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	alertRuleState := migrator.Table{
+		Name: "alert_rule_state",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongBlob, Nullable: false},
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+	mg.AddMigration("create alert_rule_state table", migrator.NewAddTableMigration(alertRuleState))
+	mg.AddMigration("add unique index on org_id and rule_uid to alert_rule_state table", migrator.NewAddIndexMigration(alertRuleState, alertRuleState.Indices[0]))
 	// End of migration log, add new migrations above this line.
 }
 
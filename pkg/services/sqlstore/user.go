@@ -107,6 +107,7 @@ func (ss *SQLStore) createUser(ctx context.Context, sess *DBSession, args user.C
 		Name:      usr.Name,
 		Login:     usr.Login,
 		Email:     usr.Email,
+		OrgID:     usr.OrgID,
 	})
 
 	orgUser := org.OrgUser{
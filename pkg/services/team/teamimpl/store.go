@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -95,8 +96,19 @@ func (ss *xormStore) Create(name, email string, orgID int64) (team.Team, error)
 			return team.ErrTeamNameTaken
 		}
 
-		_, err := sess.Insert(&t)
-		return err
+		if _, err := sess.Insert(&t); err != nil {
+			return err
+		}
+
+		sess.PublishAfterCommit(&events.TeamCreated{
+			Timestamp: t.Created,
+			Name:      t.Name,
+			Email:     t.Email,
+			ID:        t.ID,
+			UID:       t.UID,
+			OrgID:     t.OrgID,
+		})
+		return nil
 	})
 	return t, err
 }
@@ -127,6 +139,13 @@ func (ss *xormStore) Update(ctx context.Context, cmd *team.UpdateTeamCommand) er
 			return team.ErrTeamNotFound
 		}
 
+		sess.PublishAfterCommit(&events.TeamUpdated{
+			Timestamp: t.Updated,
+			Name:      cmd.Name,
+			Email:     cmd.Email,
+			ID:        cmd.ID,
+			OrgID:     cmd.OrgID,
+		})
 		return nil
 	})
 }
@@ -154,9 +173,16 @@ func (ss *xormStore) Delete(ctx context.Context, cmd *team.DeleteTeamCommand) er
 			}
 		}
 
-		_, err := sess.Exec("DELETE FROM permission WHERE scope=?", ac.Scope("teams", "id", fmt.Sprint(cmd.ID)))
+		if _, err := sess.Exec("DELETE FROM permission WHERE scope=?", ac.Scope("teams", "id", fmt.Sprint(cmd.ID))); err != nil {
+			return err
+		}
 
-		return err
+		sess.PublishAfterCommit(&events.TeamDeleted{
+			Timestamp: time.Now(),
+			ID:        cmd.ID,
+			OrgID:     cmd.OrgID,
+		})
+		return nil
 	})
 }
 
@@ -0,0 +1,138 @@
+package totp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const apiPath = "/api/user/totp"
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group(apiPath, func(subrouter routing.RouteRegister) {
+		subrouter.Get("/", routing.Wrap(s.handleStatus))
+		subrouter.Post("/enrol", routing.Wrap(s.handleEnrol))
+		subrouter.Post("/confirm", routing.Wrap(s.handleConfirm))
+		subrouter.Post("/recovery-codes", routing.Wrap(s.handleRegenerateRecoveryCodes))
+		subrouter.Post("/disable", routing.Wrap(s.handleDisable))
+	}, middleware.ReqSignedInNoAnonymous)
+
+	userIDScope := ac.Scope("global.users", "id", ac.Parameter(":id"))
+	routeRegister.Delete("/api/admin/users/:id/totp", middleware.ReqSignedIn,
+		authorize(ac.EvalPermission(ac.ActionUsersWrite, userIDScope)), routing.Wrap(s.handleReset))
+}
+
+// signedInUserID returns the ID of the signed in user, or an error response if the requester isn't a user.
+func signedInUserID(c *contextmodel.ReqContext) (int64, response.Response) {
+	namespace, id := c.SignedInUser.GetNamespacedID()
+	if namespace != identity.NamespaceUser {
+		return 0, response.Error(http.StatusForbidden, "Two-factor authentication is only available to users", nil)
+	}
+	userID, err := identity.IntIdentifier(namespace, id)
+	if err != nil {
+		return 0, response.Error(http.StatusBadRequest, "Invalid user ID", err)
+	}
+	return userID, nil
+}
+
+func (s *Service) handleStatus(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := signedInUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	status, err := s.Status(c.Req.Context(), c.SignedInUser, userID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get two-factor authentication status", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+func (s *Service) handleEnrol(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := signedInUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	account := c.SignedInUser.GetLogin()
+	if account == "" {
+		account = c.SignedInUser.GetEmail()
+	}
+	enrolment, err := s.Enrol(c.Req.Context(), userID, account)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enrol two-factor authentication", err)
+	}
+	return response.JSON(http.StatusOK, enrolment)
+}
+
+func (s *Service) handleConfirm(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := signedInUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+	cmd := CodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	codes, err := s.Confirm(c.Req.Context(), userID, cmd.Code)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enable two-factor authentication", err)
+	}
+	return response.JSON(http.StatusOK, codes)
+}
+
+func (s *Service) handleRegenerateRecoveryCodes(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := signedInUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+	cmd := CodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	codes, err := s.RegenerateRecoveryCodes(c.Req.Context(), userID, cmd.Code)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to generate recovery codes", err)
+	}
+	return response.JSON(http.StatusOK, codes)
+}
+
+func (s *Service) handleDisable(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := signedInUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+	cmd := CodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if err := s.Disable(c.Req.Context(), userID, cmd.Code); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to disable two-factor authentication", err)
+	}
+	return response.Success("Two-factor authentication disabled")
+}
+
+func (s *Service) handleReset(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := s.Reset(c.Req.Context(), userID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset two-factor authentication", err)
+	}
+	s.log.FromContext(c.Req.Context()).Info("Two-factor authentication reset by admin", "userId", userID, "adminLogin", c.SignedInUser.GetLogin())
+	return response.Success("Two-factor authentication reset")
+}
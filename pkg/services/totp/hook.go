@@ -0,0 +1,70 @@
+package totp
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+)
+
+// enrolmentPaths are the API paths users who must enrol can use before they have enrolled, besides the TOTP API.
+var enrolmentPaths = map[string]bool{
+	"/api/user":              true,
+	"/api/frontend/settings": true,
+	"/api/login/ping":        true,
+}
+
+// verifyHook requires a valid code from users who authenticate with their password and have enabled TOTP.
+// Users who must enrol can only use the API to enrol, until they do.
+func (s *Service) verifyHook(ctx context.Context, id *authn.Identity, r *authn.Request) error {
+	namespace, rawID := id.GetNamespacedID()
+	if namespace != authn.NamespaceUser {
+		return nil
+	}
+	userID, err := identity.IntIdentifier(namespace, rawID)
+	if err != nil {
+		return nil
+	}
+
+	if id.AuthenticatedBy == login.PasswordAuthModule {
+		enabled, err := s.enabled(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			return s.verifyRequest(ctx, userID, r)
+		}
+	}
+
+	if r.HTTPRequest == nil || !requiresEnrolment(r.HTTPRequest.URL.Path) {
+		return nil
+	}
+	must, err := s.mustEnrol(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if must {
+		return ErrEnrolmentRequired.Errorf("user %d must enable two-factor authentication", userID)
+	}
+	return nil
+}
+
+func (s *Service) verifyRequest(ctx context.Context, userID int64, r *authn.Request) error {
+	err := s.Verify(ctx, userID, r.GetMeta(authn.MetaKeyTOTPCode))
+	if errors.Is(err, ErrInvalidCode) {
+		if username := r.GetMeta(authn.MetaKeyUsername); username != "" {
+			_ = s.loginAttempts.Add(ctx, username, r.GetMeta(authn.MetaKeyIPAddress))
+		}
+	}
+	return err
+}
+
+func requiresEnrolment(path string) bool {
+	if !strings.HasPrefix(path, "/api/") || enrolmentPaths[path] {
+		return false
+	}
+	return path != apiPath && !strings.HasPrefix(path, apiPath+"/")
+}
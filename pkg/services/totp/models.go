@@ -0,0 +1,70 @@
+package totp
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrCodeRequired       = errutil.Unauthorized("totp.codeRequired", errutil.WithPublicMessage("Two-factor authentication code required"))
+	ErrInvalidCode        = errutil.Unauthorized("totp.invalidCode", errutil.WithPublicMessage("Invalid two-factor authentication code"))
+	ErrEnrolmentRequired  = errutil.Forbidden("totp.enrolmentRequired", errutil.WithPublicMessage("Two-factor authentication must be enabled for your account"))
+	ErrNotEnrolled        = errutil.NotFound("totp.notEnrolled", errutil.WithPublicMessage("Two-factor authentication is not enabled"))
+	ErrAlreadyEnabled     = errutil.Conflict("totp.alreadyEnabled", errutil.WithPublicMessage("Two-factor authentication is already enabled"))
+	ErrConfirmationFailed = errutil.BadRequest("totp.confirmationFailed", errutil.WithPublicMessage("Invalid two-factor authentication code"))
+)
+
+// UserTOTP is the TOTP enrolment of a user. Enrolments are pending until they are confirmed with a code.
+type UserTOTP struct {
+	ID     int64 `xorm:"pk autoincr 'id'"`
+	UserID int64 `xorm:"user_id"`
+	// Secret is the encrypted, base64 encoded secret.
+	Secret  string `xorm:"secret"`
+	Enabled bool   `xorm:"enabled"`
+	// LastUsedStep is the time step of the last code used, to prevent codes from being used twice.
+	LastUsedStep int64     `xorm:"last_used_step"`
+	Created      time.Time `xorm:"created"`
+	Updated      time.Time `xorm:"updated"`
+}
+
+func (u UserTOTP) TableName() string {
+	return "user_totp"
+}
+
+// RecoveryCode is the hash of a single-use recovery code.
+type RecoveryCode struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	UserID   int64     `xorm:"user_id"`
+	CodeHash string    `xorm:"code_hash"`
+	Created  time.Time `xorm:"created"`
+}
+
+func (r RecoveryCode) TableName() string {
+	return "user_totp_recovery_code"
+}
+
+// StatusDTO is the TOTP status of a user.
+type StatusDTO struct {
+	Enabled bool `json:"enabled"`
+	// Required is true if the user must enable two-factor authentication.
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+}
+
+// EnrolmentDTO is the secret of a pending enrolment.
+type EnrolmentDTO struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth URI to show as a QR code to enrol an authenticator app.
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// RecoveryCodesDTO holds new recovery codes. They are only returned once.
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// CodeCommand is the payload of the requests that need a code.
+type CodeCommand struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,323 @@
+package totp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// requirementCacheTTL is how long whether a user must enrol is cached for.
+	requirementCacheTTL = time.Minute
+)
+
+type config struct {
+	enabled           bool
+	issuer            string
+	requiredForAdmins bool
+}
+
+func readConfig(cfg *setting.Cfg) config {
+	section := cfg.SectionWithEnvOverrides("auth.totp")
+	return config{
+		enabled:           section.Key("enabled").MustBool(false),
+		issuer:            section.Key("issuer").MustString("Grafana"),
+		requiredForAdmins: section.Key("required_for_admins").MustBool(false),
+	}
+}
+
+// Service provides time-based one-time passwords (TOTP) as a second factor for users who log in with a password.
+//
+// Users enrol an authenticator app, and get single-use recovery codes for when they lose it. Once enrolled, a code is
+// required to authenticate with the password of the user, both with the login form and with basic auth.
+type Service struct {
+	cfg           config
+	store         *store
+	secrets       secrets.Service
+	authInfo      login.AuthInfoService
+	loginAttempts loginattempt.Service
+	accessControl ac.AccessControl
+	cache         *localcache.CacheService
+	log           log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl,
+	authnService authn.Service, secretsService secrets.Service, authInfoService login.AuthInfoService,
+	loginAttempts loginattempt.Service, cache *localcache.CacheService) *Service {
+	s := &Service{
+		cfg:           readConfig(cfg),
+		store:         &store{db: sqlStore},
+		secrets:       secretsService,
+		authInfo:      authInfoService,
+		loginAttempts: loginAttempts,
+		accessControl: accessControl,
+		cache:         cache,
+		log:           log.New("totp"),
+	}
+
+	if !s.cfg.enabled {
+		return s
+	}
+
+	authnService.RegisterPostAuthHook(s.verifyHook, 130)
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// Status returns whether the user has enabled TOTP, and whether they must.
+func (s *Service) Status(ctx context.Context, usr identity.Requester, userID int64) (*StatusDTO, error) {
+	status := &StatusDTO{}
+	t, err := s.store.get(ctx, userID)
+	if err != nil && !errors.Is(err, ErrNotEnrolled) {
+		return nil, err
+	}
+	if err == nil && t.Enabled {
+		status.Enabled = true
+		if status.RecoveryCodesRemaining, err = s.store.countRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if !status.Enabled {
+		if status.Required, err = s.mustEnrol(ctx, usr, userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enrol starts the enrolment of the user, replacing any pending enrolment. The enrolment must be confirmed
+// with a code before it is enabled.
+func (s *Service) Enrol(ctx context.Context, userID int64, account string) (*EnrolmentDTO, error) {
+	t, err := s.store.get(ctx, userID)
+	if err != nil && !errors.Is(err, ErrNotEnrolled) {
+		return nil, err
+	}
+	if err == nil && t.Enabled {
+		return nil, ErrAlreadyEnabled.Errorf("user %d already has TOTP enabled", userID)
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.store.upsertPending(ctx, &UserTOTP{
+		UserID:  userID,
+		Secret:  base64.StdEncoding.EncodeToString(encrypted),
+		Created: now,
+		Updated: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &EnrolmentDTO{Secret: secret, ProvisioningURI: provisioningURI(s.cfg.issuer, account, secret)}, nil
+}
+
+// Confirm enables the pending enrolment of the user if the code is valid, and returns new recovery codes.
+func (s *Service) Confirm(ctx context.Context, userID int64, code string) (*RecoveryCodesDTO, error) {
+	t, err := s.store.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled {
+		return nil, ErrAlreadyEnabled.Errorf("user %d already has TOTP enabled", userID)
+	}
+
+	secret, err := s.decryptSecret(ctx, t.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := validateCode(secret, code, time.Now(), t.LastUsedStep)
+	if !ok {
+		return nil, ErrConfirmationFailed.Errorf("invalid code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	t.Enabled = true
+	t.LastUsedStep = step
+	t.Updated = time.Now()
+	if err := s.store.enable(ctx, t, hashes); err != nil {
+		return nil, err
+	}
+	s.cache.Delete(requirementCacheKey(userID))
+
+	return &RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user if the code is valid.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) (*RecoveryCodesDTO, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.replaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+// Disable removes the enrolment of the user if the code is valid.
+func (s *Service) Disable(ctx context.Context, userID int64, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return s.Reset(ctx, userID)
+}
+
+// Reset removes the enrolment of the user without a code, for admins to reset the second factor of users
+// who have lost both their authenticator and their recovery codes.
+func (s *Service) Reset(ctx context.Context, userID int64) error {
+	if err := s.store.delete(ctx, userID); err != nil {
+		return err
+	}
+	s.cache.Delete(requirementCacheKey(userID))
+	return nil
+}
+
+// Verify returns nil if the code is a valid TOTP code or an unused recovery code of the user.
+// Each code can only be used once.
+func (s *Service) Verify(ctx context.Context, userID int64, code string) error {
+	t, err := s.store.get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return ErrNotEnrolled.Errorf("user %d has a pending TOTP enrolment", userID)
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrCodeRequired.Errorf("no code provided")
+	}
+
+	if len(code) == digits {
+		secret, err := s.decryptSecret(ctx, t.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := validateCode(secret, code, time.Now(), t.LastUsedStep)
+		if !ok {
+			return ErrInvalidCode.Errorf("invalid code")
+		}
+		used, err := s.store.useStep(ctx, t, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidCode.Errorf("code has already been used")
+		}
+		return nil
+	}
+
+	used, err := s.store.useRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidCode.Errorf("invalid recovery code")
+	}
+	s.log.FromContext(ctx).Info("Recovery code used", "userId", userID)
+	return nil
+}
+
+// enabled returns true if the user has enabled TOTP.
+func (s *Service) enabled(ctx context.Context, userID int64) (bool, error) {
+	t, err := s.store.get(ctx, userID)
+	if errors.Is(err, ErrNotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.Enabled, nil
+}
+
+// mustEnrol returns true if TOTP is required for the user but isn't enabled. It's only required for admins who
+// log in with a password, users of external identity providers are expected to use the second factor of the provider.
+func (s *Service) mustEnrol(ctx context.Context, usr identity.Requester, userID int64) (bool, error) {
+	if !s.cfg.requiredForAdmins || !usr.HasRole(org.RoleAdmin) {
+		return false, nil
+	}
+
+	key := requirementCacheKey(userID)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(bool), nil
+	}
+
+	must, err := s.enabled(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	must = !must
+	if must {
+		_, err := s.authInfo.GetAuthInfo(ctx, &login.GetAuthInfoQuery{UserId: userID})
+		switch {
+		case err == nil:
+			must = false
+		case !errors.Is(err, user.ErrUserNotFound):
+			return false, err
+		}
+	}
+
+	s.cache.Set(key, must, requirementCacheTTL)
+	return must, nil
+}
+
+func requirementCacheKey(userID int64) string {
+	return fmt.Sprintf("totp-required-%d", userID)
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, hashRecoveryCode(c))
+	}
+	return codes, hashes, nil
+}
+
+func (s *Service) decryptSecret(ctx context.Context, secret string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
@@ -0,0 +1,193 @@
+package totp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models/usertoken"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/login/authinfotest"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func setupTestService(t *testing.T, requiredForAdmins bool) *Service {
+	t.Helper()
+	return &Service{
+		cfg:           config{enabled: true, issuer: "Grafana", requiredForAdmins: requiredForAdmins},
+		store:         &store{db: db.InitTestDB(t)},
+		secrets:       fakes.NewFakeSecretsService(),
+		authInfo:      &authinfotest.FakeService{ExpectedError: user.ErrUserNotFound},
+		loginAttempts: &loginattempttest.MockLoginAttemptService{},
+		cache:         localcache.New(time.Minute, time.Minute),
+		log:           log.NewNopLogger(),
+	}
+}
+
+// enable enrols and confirms TOTP for the user, and returns the secret and the recovery codes.
+func enable(t *testing.T, s *Service, userID int64) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrolment, err := s.Enrol(ctx, userID, "user")
+	require.NoError(t, err)
+	code, err := generateCode(enrolment.Secret, timeStep(time.Now())-1)
+	require.NoError(t, err)
+	codes, err := s.Confirm(ctx, userID, code)
+	require.NoError(t, err)
+	return enrolment.Secret, codes.RecoveryCodes
+}
+
+func TestIntegrationTOTP(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+
+	t.Run("enrolment must be confirmed with a valid code", func(t *testing.T) {
+		s := setupTestService(t, false)
+
+		enrolment, err := s.Enrol(ctx, 1, "user")
+		require.NoError(t, err)
+		assert.Contains(t, enrolment.ProvisioningURI, "secret="+enrolment.Secret)
+
+		stored, err := s.store.get(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, stored.Enabled)
+		assert.NotContains(t, stored.Secret, enrolment.Secret)
+
+		_, err = s.Confirm(ctx, 1, "000000")
+		assert.ErrorIs(t, err, ErrConfirmationFailed)
+
+		code, err := generateCode(enrolment.Secret, timeStep(time.Now()))
+		require.NoError(t, err)
+		codes, err := s.Confirm(ctx, 1, code)
+		require.NoError(t, err)
+		assert.Len(t, codes.RecoveryCodes, recoveryCodeCount)
+
+		status, err := s.Status(ctx, &user.SignedInUser{UserID: 1}, 1)
+		require.NoError(t, err)
+		assert.True(t, status.Enabled)
+		assert.Equal(t, recoveryCodeCount, status.RecoveryCodesRemaining)
+
+		_, err = s.Enrol(ctx, 1, "user")
+		assert.ErrorIs(t, err, ErrAlreadyEnabled)
+	})
+
+	t.Run("codes can only be used once", func(t *testing.T) {
+		s := setupTestService(t, false)
+		secret, _ := enable(t, s, 1)
+
+		code, err := generateCode(secret, timeStep(time.Now()))
+		require.NoError(t, err)
+		require.NoError(t, s.Verify(ctx, 1, code))
+		assert.ErrorIs(t, s.Verify(ctx, 1, code), ErrInvalidCode)
+
+		previous, err := generateCode(secret, timeStep(time.Now())-1)
+		require.NoError(t, err)
+		assert.ErrorIs(t, s.Verify(ctx, 1, previous), ErrInvalidCode, "codes older than the last used code are rejected")
+	})
+
+	t.Run("recovery codes can only be used once", func(t *testing.T) {
+		s := setupTestService(t, false)
+		_, recoveryCodes := enable(t, s, 1)
+
+		require.NoError(t, s.Verify(ctx, 1, recoveryCodes[0]))
+		assert.ErrorIs(t, s.Verify(ctx, 1, recoveryCodes[0]), ErrInvalidCode)
+		assert.ErrorIs(t, s.Verify(ctx, 2, recoveryCodes[1]), ErrNotEnrolled)
+
+		regenerated, err := s.RegenerateRecoveryCodes(ctx, 1, recoveryCodes[1])
+		require.NoError(t, err)
+		assert.ErrorIs(t, s.Verify(ctx, 1, recoveryCodes[2]), ErrInvalidCode, "regenerating recovery codes replaces them")
+		require.NoError(t, s.Verify(ctx, 1, regenerated.RecoveryCodes[0]))
+	})
+
+	t.Run("disable requires a code and reset doesn't", func(t *testing.T) {
+		s := setupTestService(t, false)
+		_, recoveryCodes := enable(t, s, 1)
+
+		assert.ErrorIs(t, s.Disable(ctx, 1, "000000"), ErrInvalidCode)
+		require.NoError(t, s.Disable(ctx, 1, recoveryCodes[0]))
+		_, err := s.store.get(ctx, 1)
+		assert.ErrorIs(t, err, ErrNotEnrolled)
+
+		enable(t, s, 1)
+		require.NoError(t, s.Reset(ctx, 1))
+		count, err := s.store.countRecoveryCodes(ctx, 1)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
+func TestIntegrationVerifyHook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+
+	newRequest := func(path, code string) *authn.Request {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		r := &authn.Request{HTTPRequest: req}
+		r.SetMeta(authn.MetaKeyUsername, "user")
+		if code != "" {
+			r.SetMeta(authn.MetaKeyTOTPCode, code)
+		}
+		return r
+	}
+
+	t.Run("password authentication requires a code once enabled", func(t *testing.T) {
+		s := setupTestService(t, false)
+		id := &authn.Identity{ID: "user:1", AuthenticatedBy: login.PasswordAuthModule}
+		require.NoError(t, s.verifyHook(ctx, id, newRequest("/login", "")))
+
+		secret, _ := enable(t, s, 1)
+		assert.ErrorIs(t, s.verifyHook(ctx, id, newRequest("/login", "")), ErrCodeRequired)
+
+		attempts := &loginattempttest.MockLoginAttemptService{}
+		s.loginAttempts = attempts
+		assert.ErrorIs(t, s.verifyHook(ctx, id, newRequest("/login", "000000")), ErrInvalidCode)
+		assert.True(t, attempts.AddCalled, "invalid codes count as failed login attempts")
+
+		code, err := generateCode(secret, timeStep(time.Now()))
+		require.NoError(t, err)
+		require.NoError(t, s.verifyHook(ctx, id, newRequest("/login", code)))
+
+		session := &authn.Identity{ID: "user:1", SessionToken: &usertoken.UserToken{}}
+		require.NoError(t, s.verifyHook(ctx, session, newRequest("/api/dashboards/uid/abc", "")), "sessions don't require a code")
+	})
+
+	t.Run("admins who must enrol can only use the API to enrol", func(t *testing.T) {
+		s := setupTestService(t, true)
+		admin := &authn.Identity{ID: "user:1", OrgID: 1, OrgRoles: map[int64]org.RoleType{1: org.RoleAdmin}, SessionToken: &usertoken.UserToken{}}
+		editor := &authn.Identity{ID: "user:2", OrgID: 1, OrgRoles: map[int64]org.RoleType{1: org.RoleEditor}, SessionToken: &usertoken.UserToken{}}
+
+		assert.ErrorIs(t, s.verifyHook(ctx, admin, newRequest("/api/dashboards/uid/abc", "")), ErrEnrolmentRequired)
+		require.NoError(t, s.verifyHook(ctx, admin, newRequest("/api/user", "")))
+		require.NoError(t, s.verifyHook(ctx, admin, newRequest("/api/user/totp/enrol", "")))
+		require.NoError(t, s.verifyHook(ctx, admin, newRequest("/d/abc", "")))
+		require.NoError(t, s.verifyHook(ctx, editor, newRequest("/api/dashboards/uid/abc", "")))
+
+		enable(t, s, 1)
+		require.NoError(t, s.verifyHook(ctx, admin, newRequest("/api/dashboards/uid/abc", "")))
+	})
+
+	t.Run("admins of external identity providers don't have to enrol", func(t *testing.T) {
+		s := setupTestService(t, true)
+		s.authInfo = &authinfotest.FakeService{ExpectedUserAuth: &login.UserAuth{AuthModule: login.GenericOAuthModule}}
+		admin := &authn.Identity{ID: "user:1", OrgID: 1, OrgRoles: map[int64]org.RoleType{1: org.RoleAdmin}, SessionToken: &usertoken.UserToken{}}
+
+		require.NoError(t, s.verifyHook(ctx, admin, newRequest("/api/dashboards/uid/abc", "")))
+	})
+}
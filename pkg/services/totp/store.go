@@ -0,0 +1,115 @@
+package totp
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store struct {
+	db db.DB
+}
+
+func (s *store) get(ctx context.Context, userID int64) (*UserTOTP, error) {
+	t := &UserTOTP{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("user_id = ?", userID).Get(t)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrNotEnrolled.Errorf("user %d has no TOTP enrolment", userID)
+		}
+		return nil
+	})
+	return t, err
+}
+
+// upsertPending replaces the pending enrolment of the user.
+func (s *store) upsertPending(ctx context.Context, t *UserTOTP) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("user_id = ? AND enabled = ?", t.UserID, false).Delete(&UserTOTP{}); err != nil {
+			return err
+		}
+		_, err := sess.Insert(t)
+		return err
+	})
+}
+
+// enable enables the enrolment and replaces the recovery codes of the user.
+func (s *store) enable(ctx context.Context, t *UserTOTP, recoveryCodeHashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.ID(t.ID).Cols("enabled", "last_used_step", "updated").Update(t); err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(sess, t.UserID, recoveryCodeHashes)
+	})
+}
+
+// useStep records the time step of a code that has been used. It returns false if a code of the step, or a later one,
+// has already been used.
+func (s *store) useStep(ctx context.Context, t *UserTOTP, step int64) (bool, error) {
+	var used bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Table(&UserTOTP{}).Where("id = ? AND last_used_step < ?", t.ID, step).
+			Update(map[string]any{"last_used_step": step, "updated": time.Now()})
+		used = affected == 1
+		return err
+	})
+	return used, err
+}
+
+func (s *store) replaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return replaceRecoveryCodes(sess, userID, hashes)
+	})
+}
+
+func replaceRecoveryCodes(sess *db.Session, userID int64, hashes []string) error {
+	if _, err := sess.Where("user_id = ?", userID).Delete(&RecoveryCode{}); err != nil {
+		return err
+	}
+	now := time.Now()
+	codes := make([]*RecoveryCode, 0, len(hashes))
+	for _, h := range hashes {
+		codes = append(codes, &RecoveryCode{UserID: userID, CodeHash: h, Created: now})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	_, err := sess.InsertMulti(codes)
+	return err
+}
+
+// useRecoveryCode deletes the recovery code of the user with the hash. It returns false if there is none.
+func (s *store) useRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error) {
+	var used bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("user_id = ? AND code_hash = ?", userID, hash).Delete(&RecoveryCode{})
+		used = affected > 0
+		return err
+	})
+	return used, err
+}
+
+func (s *store) countRecoveryCodes(ctx context.Context, userID int64) (int, error) {
+	var count int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		count, err = sess.Where("user_id = ?", userID).Count(&RecoveryCode{})
+		return err
+	})
+	return int(count), err
+}
+
+// delete removes the enrolment and the recovery codes of the user.
+func (s *store) delete(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("user_id = ?", userID).Delete(&UserTOTP{}); err != nil {
+			return err
+		}
+		_, err := sess.Where("user_id = ?", userID).Delete(&RecoveryCode{})
+		return err
+	})
+}
@@ -0,0 +1,92 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 encoding of the SHA1 secret of the test vectors of RFC 6238, "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	// The test vectors of RFC 6238 appendix B, truncated to 6 digits.
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := generateCode(rfcSecret, timeStep(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, code, "time %d", tt.unix)
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := timeStep(now)
+
+	t.Run("accepts codes of the current and adjacent time steps", func(t *testing.T) {
+		for _, s := range []int64{step - 1, step, step + 1} {
+			code, err := generateCode(rfcSecret, s)
+			require.NoError(t, err)
+			validStep, ok := validateCode(rfcSecret, code, now, 0)
+			assert.True(t, ok)
+			assert.Equal(t, s, validStep)
+		}
+	})
+
+	t.Run("rejects codes of other time steps", func(t *testing.T) {
+		code, err := generateCode(rfcSecret, step-2)
+		require.NoError(t, err)
+		_, ok := validateCode(rfcSecret, code, now, 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects codes that have already been used", func(t *testing.T) {
+		code, err := generateCode(rfcSecret, step)
+		require.NoError(t, err)
+		_, ok := validateCode(rfcSecret, code, now, step)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects malformed codes", func(t *testing.T) {
+		_, ok := validateCode(rfcSecret, "12345", now, 0)
+		assert.False(t, ok)
+	})
+}
+
+func TestProvisioningURI(t *testing.T) {
+	secret, err := generateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	u, err := url.Parse(provisioningURI("Grafana", "admin@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Grafana:admin@example.com", u.Path)
+	assert.Equal(t, secret, u.Query().Get("secret"))
+	assert.Equal(t, "Grafana", u.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	for _, c := range codes {
+		assert.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, c)
+	}
+	assert.Equal(t, hashRecoveryCode(codes[0]), hashRecoveryCode(" "+codes[0][:5]+codes[0][6:]+" "))
+	assert.NotEqual(t, hashRecoveryCode(codes[0]), hashRecoveryCode(codes[1]))
+}
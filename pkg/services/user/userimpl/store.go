@@ -73,6 +73,7 @@ func (ss *sqlStore) Insert(ctx context.Context, cmd *user.User) (int64, error) {
 			Name:      cmd.Name,
 			Login:     cmd.Login,
 			Email:     cmd.Email,
+			OrgID:     cmd.OrgID,
 		})
 		return nil
 	})
@@ -115,10 +116,35 @@ func (ss *sqlStore) Get(ctx context.Context, usr *user.User) (*user.User, error)
 }
 
 func (ss *sqlStore) Delete(ctx context.Context, userID int64) error {
-	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+	err := ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		usr := user.User{}
+		has, err := sess.ID(userID).Get(&usr)
+		if err != nil {
+			return err
+		}
+
+		orgIDs, err := userOrgIDs(sess, userID)
+		if err != nil {
+			return err
+		}
+
 		var rawSQL = "DELETE FROM " + ss.dialect.Quote("user") + " WHERE id = ?"
-		_, err := sess.Exec(rawSQL, userID)
-		return err
+		if _, err := sess.Exec(rawSQL, userID); err != nil {
+			return err
+		}
+
+		if has {
+			sess.PublishAfterCommit(&events.UserDeleted{
+				Timestamp: time.Now(),
+				Id:        usr.ID,
+				Name:      usr.Name,
+				Login:     usr.Login,
+				Email:     usr.Email,
+				OrgID:     usr.OrgID,
+				OrgIDs:    orgIDs,
+			})
+		}
+		return nil
 	})
 	if err != nil {
 		return err
@@ -319,6 +345,13 @@ func (ss *sqlStore) loginConflict(ctx context.Context, sess *db.Session, login,
 	return nil
 }
 
+// userOrgIDs returns the organizations the user is a member of.
+func userOrgIDs(sess *db.Session, userID int64) ([]int64, error) {
+	orgIDs := []int64{}
+	err := sess.Table("org_user").Where("user_id = ?", userID).Cols("org_id").Find(&orgIDs)
+	return orgIDs, err
+}
+
 func (ss *sqlStore) Update(ctx context.Context, cmd *user.UpdateUserCommand) error {
 	if ss.cfg.CaseInsensitiveLogin {
 		cmd.Login = strings.ToLower(cmd.Login)
@@ -344,12 +377,23 @@ func (ss *sqlStore) Update(ctx context.Context, cmd *user.UpdateUserCommand) err
 			}
 		}
 
+		var orgID int64
+		if _, err := sess.Table("user").Where("id = ?", cmd.UserID).Cols("org_id").Get(&orgID); err != nil {
+			return err
+		}
+		orgIDs, err := userOrgIDs(sess, cmd.UserID)
+		if err != nil {
+			return err
+		}
+
 		sess.PublishAfterCommit(&events.UserUpdated{
-			Timestamp: user.Created,
-			Id:        user.ID,
+			Timestamp: user.Updated,
+			Id:        cmd.UserID,
 			Name:      user.Name,
 			Login:     user.Login,
 			Email:     user.Email,
+			OrgID:     orgID,
+			OrgIDs:    orgIDs,
 		})
 
 		return nil
@@ -211,6 +211,13 @@ type Cfg struct {
 	// Security
 	DisableInitAdminCreation          bool
 	DisableBruteForceLoginProtection  bool
+	IPLoginProtectionMaxAttempts      int64
+	IPLoginProtectionWindow           time.Duration
+	SubnetLoginProtectionMaxAttempts  int64
+	SubnetLoginProtectionWindow       time.Duration
+	SubnetLoginProtectionIPv4Prefix   int
+	SubnetLoginProtectionIPv6Prefix   int
+	LoginProtectionTrustedProxies     string
 	CookieSecure                      bool
 	CookieSameSiteDisabled            bool
 	CookieSameSiteMode                http.SameSite
@@ -275,6 +282,7 @@ type Cfg struct {
 	LoginCookieName              string
 	LoginMaxInactiveLifetime     time.Duration
 	LoginMaxLifetime             time.Duration
+	LoginMaxConcurrentSessions   int
 	TokenRotationIntervalMinutes int
 	SigV4AuthEnabled             bool
 	SigV4VerboseLogging          bool
@@ -337,11 +345,31 @@ type Cfg struct {
 	JWTAuthAllowAssignGrafanaAdmin bool
 	JWTAuthSkipOrgRoleSync         bool
 
+	// Mutual TLS Auth
+	MTLSAuthEnabled                 bool
+	MTLSAuthCACertFile              string
+	MTLSAuthLoginAttribute          string
+	MTLSAuthEmailAttribute          string
+	MTLSAuthNameAttribute           string
+	MTLSAuthAutoSignUp              bool
+	MTLSAuthAllowServiceAccounts    bool
+	MTLSAuthRoleAttribute           string
+	MTLSAuthRoleMapping             string
+	MTLSAuthRoleAttributeStrict     bool
+	MTLSAuthAllowAssignGrafanaAdmin bool
+	MTLSAuthSkipOrgRoleSync         bool
+	MTLSAuthDenyListFile            string
+
 	// Extended JWT Auth
 	ExtendedJWTAuthEnabled    bool
 	ExtendedJWTExpectIssuer   string
 	ExtendedJWTExpectAudience string
 
+	// Token exchange
+	TokenExchangeEnabled    bool
+	TokenExchangeDefaultTTL time.Duration
+	TokenExchangeMaxTTL     time.Duration
+
 	// Dataproxy
 	SendUserHeader                 bool
 	DataProxyLogging               bool
@@ -384,6 +412,10 @@ type Cfg struct {
 
 	// Service Accounts
 	SATokenExpirationDayLimit int
+	// SATokenRotationGracePeriod is how long a rotated token remains valid, unless set when rotating it.
+	SATokenRotationGracePeriod time.Duration
+	// SATokenExpiryWarningPeriod is how long before a token expires org admins are warned. 0 disables warnings.
+	SATokenExpiryWarningPeriod time.Duration
 
 	// Annotations
 	AnnotationCleanupJobBatchSize      int64
@@ -391,12 +423,20 @@ type Cfg struct {
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
+	// StateHistoryAnnotationCleanupSettings applies to the annotations written by the state history of
+	// Grafana-managed alert rules. MaxCount is the number of annotations kept per alert rule.
+	StateHistoryAnnotationCleanupSettings AnnotationCleanupSettings
 
 	// GrafanaJavascriptAgent config
 	GrafanaJavascriptAgent GrafanaJavascriptAgent
 
 	// Data sources
 	DataSourceLimit int
+	// Maximum number of concurrent queries per data source and traffic lane, 0 means unlimited
+	DataSourceConcurrentQueryLimit                int
+	DataSourceConcurrentQueryLimitAlerting        int
+	DataSourceConcurrentQueryLimitPublicDashboard int
+	DataSourceConcurrentQueryQueueTimeout         time.Duration
 
 	// SQL Data sources
 	SqlDatasourceMaxOpenConnsDefault    int
@@ -450,6 +490,7 @@ type Cfg struct {
 	LDAPAllowSignup       bool
 	LDAPActiveSyncEnabled bool
 	LDAPSyncCron          string
+	LDAPSyncDryRun        bool
 
 	DefaultTheme    string
 	DefaultLanguage string
@@ -720,6 +761,7 @@ func (cfg *Cfg) readAnnotationSettings() error {
 	dashboardAnnotation := cfg.Raw.Section("annotations.dashboard")
 	apiIAnnotation := cfg.Raw.Section("annotations.api")
 	alertingSection := cfg.Raw.Section("alerting")
+	stateHistoryAnnotation := cfg.Raw.Section("unified_alerting.state_history.annotations")
 
 	var newAnnotationCleanupSettings = func(section *ini.Section, maxAgeField string) AnnotationCleanupSettings {
 		maxAge, err := gtime.ParseDuration(section.Key(maxAgeField).MustString(""))
@@ -736,6 +778,7 @@ func (cfg *Cfg) readAnnotationSettings() error {
 	cfg.AlertingAnnotationCleanupSetting = newAnnotationCleanupSettings(alertingSection, "max_annotation_age")
 	cfg.DashboardAnnotationCleanupSettings = newAnnotationCleanupSettings(dashboardAnnotation, "max_age")
 	cfg.APIAnnotationCleanupSettings = newAnnotationCleanupSettings(apiIAnnotation, "max_age")
+	cfg.StateHistoryAnnotationCleanupSettings = newAnnotationCleanupSettings(stateHistoryAnnotation, "max_age")
 
 	return nil
 }
@@ -1294,6 +1337,7 @@ func (cfg *Cfg) readLDAPConfig() {
 	cfg.LDAPAuthEnabled = ldapSec.Key("enabled").MustBool(false)
 	cfg.LDAPSkipOrgRoleSync = ldapSec.Key("skip_org_role_sync").MustBool(false)
 	cfg.LDAPActiveSyncEnabled = ldapSec.Key("active_sync_enabled").MustBool(false)
+	cfg.LDAPSyncDryRun = ldapSec.Key("sync_dry_run").MustBool(false)
 	cfg.LDAPAllowSignup = ldapSec.Key("allow_sign_up").MustBool(true)
 }
 
@@ -1424,6 +1468,13 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	cfg.SecretKey = SecretKey
 	DisableGravatar = security.Key("disable_gravatar").MustBool(true)
 	cfg.DisableBruteForceLoginProtection = security.Key("disable_brute_force_login_protection").MustBool(false)
+	cfg.IPLoginProtectionMaxAttempts = security.Key("ip_login_protection_max_attempts").MustInt64(50)
+	cfg.IPLoginProtectionWindow = security.Key("ip_login_protection_window").MustDuration(5 * time.Minute)
+	cfg.SubnetLoginProtectionMaxAttempts = security.Key("subnet_login_protection_max_attempts").MustInt64(200)
+	cfg.SubnetLoginProtectionWindow = security.Key("subnet_login_protection_window").MustDuration(5 * time.Minute)
+	cfg.SubnetLoginProtectionIPv4Prefix = security.Key("subnet_login_protection_ipv4_prefix").MustInt(24)
+	cfg.SubnetLoginProtectionIPv6Prefix = security.Key("subnet_login_protection_ipv6_prefix").MustInt(64)
+	cfg.LoginProtectionTrustedProxies = valueAsString(security, "login_protection_trusted_proxies", "")
 
 	CookieSecure = security.Key("cookie_secure").MustBool(false)
 	cfg.CookieSecure = CookieSecure
@@ -1513,6 +1564,11 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 		return err
 	}
 
+	cfg.LoginMaxConcurrentSessions = auth.Key("login_maximum_concurrent_sessions").MustInt(0)
+	if cfg.LoginMaxConcurrentSessions < 0 {
+		cfg.LoginMaxConcurrentSessions = 0
+	}
+
 	cfg.ApiKeyMaxSecondsToLive = auth.Key("api_key_max_seconds_to_live").MustInt64(-1)
 
 	cfg.TokenRotationIntervalMinutes = auth.Key("token_rotation_interval_minutes").MustInt(10)
@@ -1592,12 +1648,37 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.JWTAuthAllowAssignGrafanaAdmin = authJWT.Key("allow_assign_grafana_admin").MustBool(false)
 	cfg.JWTAuthSkipOrgRoleSync = authJWT.Key("skip_org_role_sync").MustBool(false)
 
+	// Mutual TLS auth
+	authMTLS := iniFile.Section("auth.mtls")
+	cfg.MTLSAuthEnabled = authMTLS.Key("enabled").MustBool(false)
+	cfg.MTLSAuthCACertFile = valueAsString(authMTLS, "ca_cert_file", "")
+	cfg.MTLSAuthLoginAttribute = valueAsString(authMTLS, "login_attribute", "subject.CN")
+	cfg.MTLSAuthEmailAttribute = valueAsString(authMTLS, "email_attribute", "san.email")
+	cfg.MTLSAuthNameAttribute = valueAsString(authMTLS, "name_attribute", "subject.CN")
+	cfg.MTLSAuthAutoSignUp = authMTLS.Key("auto_sign_up").MustBool(false)
+	cfg.MTLSAuthAllowServiceAccounts = authMTLS.Key("allow_service_accounts").MustBool(false)
+	cfg.MTLSAuthRoleAttribute = valueAsString(authMTLS, "role_attribute", "")
+	cfg.MTLSAuthRoleMapping = valueAsString(authMTLS, "role_mapping", "")
+	cfg.MTLSAuthRoleAttributeStrict = authMTLS.Key("role_attribute_strict").MustBool(false)
+	cfg.MTLSAuthAllowAssignGrafanaAdmin = authMTLS.Key("allow_assign_grafana_admin").MustBool(false)
+	cfg.MTLSAuthSkipOrgRoleSync = authMTLS.Key("skip_org_role_sync").MustBool(false)
+	cfg.MTLSAuthDenyListFile = valueAsString(authMTLS, "deny_list_file", "")
+
 	// Extended JWT auth
 	authExtendedJWT := cfg.SectionWithEnvOverrides("auth.extended_jwt")
 	cfg.ExtendedJWTAuthEnabled = authExtendedJWT.Key("enabled").MustBool(false)
 	cfg.ExtendedJWTExpectAudience = authExtendedJWT.Key("expect_audience").MustString("")
 	cfg.ExtendedJWTExpectIssuer = authExtendedJWT.Key("expect_issuer").MustString("")
 
+	// Token exchange
+	authTokenExchange := cfg.SectionWithEnvOverrides("auth.token_exchange")
+	cfg.TokenExchangeEnabled = authTokenExchange.Key("enabled").MustBool(false)
+	cfg.TokenExchangeMaxTTL = authTokenExchange.Key("max_token_ttl").MustDuration(time.Hour)
+	cfg.TokenExchangeDefaultTTL = authTokenExchange.Key("default_token_ttl").MustDuration(5 * time.Minute)
+	if cfg.TokenExchangeDefaultTTL > cfg.TokenExchangeMaxTTL {
+		cfg.TokenExchangeDefaultTTL = cfg.TokenExchangeMaxTTL
+	}
+
 	// Auth Proxy
 	authProxy := iniFile.Section("auth.proxy")
 	cfg.AuthProxyEnabled = authProxy.Key("enabled").MustBool(false)
@@ -1695,6 +1776,8 @@ func readUserSettings(iniFile *ini.File, cfg *Cfg) error {
 func readServiceAccountSettings(iniFile *ini.File, cfg *Cfg) error {
 	serviceAccount := iniFile.Section("service_accounts")
 	cfg.SATokenExpirationDayLimit = serviceAccount.Key("token_expiration_day_limit").MustInt(-1)
+	cfg.SATokenRotationGracePeriod = serviceAccount.Key("token_rotation_grace_period").MustDuration(24 * time.Hour)
+	cfg.SATokenExpiryWarningPeriod = serviceAccount.Key("token_expiry_warning_period").MustDuration(7 * 24 * time.Hour)
 	return nil
 }
 
@@ -1923,6 +2006,10 @@ func (cfg *Cfg) GetContentDeliveryURL(prefix string) (string, error) {
 func (cfg *Cfg) readDataSourcesSettings() {
 	datasources := cfg.Raw.Section("datasources")
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
+	cfg.DataSourceConcurrentQueryLimit = datasources.Key("concurrent_query_limit").MustInt(0)
+	cfg.DataSourceConcurrentQueryLimitAlerting = datasources.Key("concurrent_query_limit_alerting").MustInt(cfg.DataSourceConcurrentQueryLimit)
+	cfg.DataSourceConcurrentQueryLimitPublicDashboard = datasources.Key("concurrent_query_limit_public_dashboards").MustInt(cfg.DataSourceConcurrentQueryLimit)
+	cfg.DataSourceConcurrentQueryQueueTimeout = datasources.Key("concurrent_query_queue_timeout").MustDuration(30 * time.Second)
 }
 
 func (cfg *Cfg) readSqlDataSourceSettings() {
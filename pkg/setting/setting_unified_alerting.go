@@ -95,6 +95,7 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	StateStore                    UnifiedAlertingStateStoreSettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	Upgrade                       UnifiedAlertingUpgradeSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -122,6 +123,17 @@ type UnifiedAlertingReservedLabelSettings struct {
 	DisabledLabels map[string]struct{}
 }
 
+// UnifiedAlertingStateStoreSettings configures where the state of alert rules is persisted.
+type UnifiedAlertingStateStoreSettings struct {
+	// Backend is one of "database", "snapshot" or "redis".
+	Backend       string
+	RedisAddr     string
+	RedisUsername string
+	RedisPassword string
+	RedisDB       int
+	RedisPrefix   string
+}
+
 type UnifiedAlertingStateHistorySettings struct {
 	Enabled       bool
 	Backend       string
@@ -403,6 +415,16 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
+	stateStore := iniFile.Section("unified_alerting.state_store")
+	uaCfg.StateStore = UnifiedAlertingStateStoreSettings{
+		Backend:       stateStore.Key("backend").MustString("database"),
+		RedisAddr:     stateStore.Key("redis_address").MustString(""),
+		RedisUsername: stateStore.Key("redis_username").MustString(""),
+		RedisPassword: stateStore.Key("redis_password").MustString(""),
+		RedisDB:       stateStore.Key("redis_db").MustInt(0),
+		RedisPrefix:   stateStore.Key("redis_prefix").MustString(""),
+	}
+
 	upgrade := iniFile.Section("unified_alerting.upgrade")
 	uaCfgUpgrade := UnifiedAlertingUpgradeSettings{
 		CleanUpgrade: upgrade.Key("clean_upgrade").MustBool(false),
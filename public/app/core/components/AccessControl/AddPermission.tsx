@@ -8,7 +8,7 @@ import { UserPicker } from 'app/core/components/Select/UserPicker';
 import { Trans, t } from 'app/core/internationalization';
 import { OrgRole } from 'app/types/acl';
 
-import { Assignments, PermissionTarget, SetPermission } from './types';
+import { ANONYMOUS_ROLE, Assignments, PermissionTarget, SetPermission } from './types';
 
 export interface Props {
   title?: string;
@@ -45,7 +45,7 @@ export const AddPermission = ({
     if (assignments.teams) {
       options.push({ value: PermissionTarget.Team, label: t('access-control.add-permission.team-label', 'Team') });
     }
-    if (assignments.builtInRoles) {
+    if (assignments.builtInRoles || assignments.anonymous) {
       options.push({
         value: PermissionTarget.BuiltInRole,
         label: t('access-control.add-permission.role-label', 'Role'),
@@ -54,6 +54,21 @@ export const AddPermission = ({
     return options;
   }, [assignments]);
 
+  const builtInRoleOptions = useMemo(() => {
+    const options: Array<{ value: string; label: string }> = [];
+    if (assignments.builtInRoles) {
+      options.push(
+        ...Object.values(OrgRole)
+          .filter((r) => r !== OrgRole.None)
+          .map((r) => ({ value: r, label: r }))
+      );
+    }
+    if (assignments.anonymous) {
+      options.push({ value: ANONYMOUS_ROLE, label: t('access-control.add-permission.anonymous-label', 'Anonymous') });
+    }
+    return options;
+  }, [assignments]);
+
   useEffect(() => {
     if (permissions.length > 0) {
       setPermission(permissions[0]);
@@ -64,7 +79,7 @@ export const AddPermission = ({
     (target === PermissionTarget.Team && teamId > 0) ||
     (target === PermissionTarget.User && userId > 0) ||
     (target === PermissionTarget.ServiceAccount && userId > 0) ||
-    (PermissionTarget.BuiltInRole && OrgRole.hasOwnProperty(builtInRole));
+    (target === PermissionTarget.BuiltInRole && builtInRoleOptions.some((r) => r.value === builtInRole));
 
   return (
     <div className="cta-form" aria-label="Permissions slider">
@@ -98,9 +113,7 @@ export const AddPermission = ({
             {target === PermissionTarget.BuiltInRole && (
               <Select
                 aria-label={'Built-in role picker'}
-                options={Object.values(OrgRole)
-                  .filter((r) => r !== OrgRole.None)
-                  .map((r) => ({ value: r, label: r }))}
+                options={builtInRoleOptions}
                 onChange={(r) => setBuiltinRole(r.value || '')}
                 width="auto"
               />
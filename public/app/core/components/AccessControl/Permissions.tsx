@@ -23,6 +23,7 @@ const INITIAL_DESCRIPTION: Description = {
     users: false,
     serviceAccounts: false,
     builtInRoles: false,
+    anonymous: false,
   },
 };
 
@@ -40,4 +40,7 @@ export type Assignments = {
   serviceAccounts: boolean;
   teams: boolean;
   builtInRoles: boolean;
+  anonymous: boolean;
 };
+
+export const ANONYMOUS_ROLE = 'Anonymous';
@@ -3,6 +3,7 @@ import React, { PureComponent } from 'react';
 import { FetchError, getBackendSrv, isFetchError } from '@grafana/runtime';
 import config from 'app/core/config';
 import { t } from 'app/core/internationalization';
+import { TOTPStatus } from 'app/features/profile/types';
 
 import { LoginDTO } from './types';
 
@@ -14,6 +15,7 @@ export interface FormModel {
   user: string;
   password: string;
   email: string;
+  totpCode?: string;
 }
 
 interface Props {
@@ -25,6 +27,9 @@ interface Props {
     isChangingPassword: boolean;
     skipPasswordChange: Function;
     login: (data: FormModel) => void;
+    isTOTPRequired: boolean;
+    loginWithTOTPCode: (code: string) => void;
+    cancelTOTP: () => void;
     disableLoginForm: boolean;
     disableUserSignUp: boolean;
     isOauthEnabled: boolean;
@@ -38,18 +43,22 @@ interface Props {
 interface State {
   isLoggingIn: boolean;
   isChangingPassword: boolean;
+  isTOTPRequired: boolean;
   showDefaultPasswordWarning: boolean;
   loginErrorMessage?: string;
 }
 
 export class LoginCtrl extends PureComponent<Props, State> {
   result: LoginDTO | undefined;
+  // the credentials are kept to send them again with the two-factor authentication code
+  formModel: FormModel | undefined;
 
   constructor(props: Props) {
     super(props);
     this.state = {
       isLoggingIn: false,
       isChangingPassword: false,
+      isTOTPRequired: false,
       showDefaultPasswordWarning: false,
       loginErrorMessage: config.loginError,
     };
@@ -90,10 +99,12 @@ export class LoginCtrl extends PureComponent<Props, State> {
       isLoggingIn: true,
     });
 
+    this.formModel = formModel;
     getBackendSrv()
       .post<LoginDTO>('/login', formModel, { showErrorAlert: false })
       .then((result) => {
         this.result = result;
+        this.formModel = undefined;
         if (formModel.password !== 'admin' || config.ldapEnabled || config.authProxyEnabled) {
           this.toGrafana();
           return;
@@ -102,6 +113,10 @@ export class LoginCtrl extends PureComponent<Props, State> {
         }
       })
       .catch((err) => {
+        if (isFetchError(err) && err.data?.messageId === 'totp.codeRequired') {
+          this.setState({ isLoggingIn: false, isTOTPRequired: true });
+          return;
+        }
         const fetchErrorMessage = isFetchError(err) ? getErrorMessage(err) : undefined;
         this.setState({
           isLoggingIn: false,
@@ -110,6 +125,17 @@ export class LoginCtrl extends PureComponent<Props, State> {
       });
   };
 
+  loginWithTOTPCode = (code: string) => {
+    if (this.formModel) {
+      this.login({ ...this.formModel, totpCode: code });
+    }
+  };
+
+  cancelTOTP = () => {
+    this.formModel = undefined;
+    this.setState({ isTOTPRequired: false, loginErrorMessage: undefined });
+  };
+
   changeView = (showDefaultPasswordWarning: boolean) => {
     this.setState({
       isChangingPassword: true,
@@ -117,7 +143,13 @@ export class LoginCtrl extends PureComponent<Props, State> {
     });
   };
 
-  toGrafana = () => {
+  toGrafana = async () => {
+    // users who must enable two-factor authentication can't use Grafana until they have done so
+    if (await requiresTOTPEnrolment()) {
+      window.location.assign(config.appSubUrl + '/profile/totp');
+      return;
+    }
+
     // Use window.location.href to force page reload
     if (this.result?.redirectUrl) {
       if (config.appSubUrl !== '' && !this.result.redirectUrl.startsWith(config.appSubUrl)) {
@@ -132,8 +164,9 @@ export class LoginCtrl extends PureComponent<Props, State> {
 
   render() {
     const { children } = this.props;
-    const { isLoggingIn, isChangingPassword, showDefaultPasswordWarning, loginErrorMessage } = this.state;
-    const { login, toGrafana, changePassword } = this;
+    const { isLoggingIn, isChangingPassword, isTOTPRequired, showDefaultPasswordWarning, loginErrorMessage } =
+      this.state;
+    const { login, loginWithTOTPCode, cancelTOTP, toGrafana, changePassword } = this;
     const { loginHint, passwordHint, disableLoginForm, disableUserSignUp } = config;
 
     return (
@@ -146,6 +179,9 @@ export class LoginCtrl extends PureComponent<Props, State> {
           disableUserSignUp,
           login,
           isLoggingIn,
+          isTOTPRequired,
+          loginWithTOTPCode,
+          cancelTOTP,
           changePassword,
           skipPasswordChange: toGrafana,
           isChangingPassword,
@@ -170,7 +206,21 @@ function getErrorMessage(err: FetchError<undefined | { messageId?: string; messa
         'login.error.blocked',
         'You have exceeded the number of login attempts for this user. Please try again later.'
       );
+    case 'totp.invalidCode':
+      return t('login.error.invalid-totp-code', 'Invalid two-factor authentication code');
     default:
       return err.data?.message;
   }
 }
+
+async function requiresTOTPEnrolment(): Promise<boolean> {
+  try {
+    // the request fails when two-factor authentication is disabled
+    const status = await getBackendSrv().get<TOTPStatus>('/api/user/totp', undefined, undefined, {
+      showErrorAlert: false,
+    });
+    return Boolean(status?.required && !status.enabled);
+  } catch (err) {
+    return false;
+  }
+}
@@ -7,11 +7,13 @@ import * as runtimeMock from '@grafana/runtime';
 import { LoginPage } from './LoginPage';
 
 const postMock = jest.fn();
+const getMock = jest.fn();
 jest.mock('@grafana/runtime', () => ({
   ...jest.requireActual('@grafana/runtime'),
   __esModule: true,
   getBackendSrv: () => ({
     post: postMock,
+    get: getMock,
   }),
   config: {
     loginError: false,
@@ -89,7 +91,89 @@ describe('Login Page', () => {
     await waitFor(() =>
       expect(postMock).toHaveBeenCalledWith('/login', { password: 'test', user: 'admin' }, { showErrorAlert: false })
     );
-    expect(window.location.assign).toHaveBeenCalledWith('/');
+    await waitFor(() => expect(window.location.assign).toHaveBeenCalledWith('/'));
+  });
+
+  it('should navigate to the two-factor authentication page if it must be enabled', async () => {
+    Object.defineProperty(window, 'location', {
+      value: {
+        assign: jest.fn(),
+      },
+    });
+    postMock.mockResolvedValueOnce({ message: 'Logged in' });
+    getMock.mockResolvedValueOnce({ enabled: false, required: true, recoveryCodesRemaining: 0 });
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    fireEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await waitFor(() => expect(window.location.assign).toHaveBeenCalledWith('/profile/totp'));
+    expect(getMock).toHaveBeenCalledWith('/api/user/totp', undefined, undefined, { showErrorAlert: false });
+  });
+
+  it('should ask for a two-factor authentication code if it is required', async () => {
+    Object.defineProperty(window, 'location', {
+      value: {
+        assign: jest.fn(),
+      },
+    });
+    postMock.mockRejectedValueOnce({
+      data: {
+        message: 'Two-factor authentication code required',
+        messageId: 'totp.codeRequired',
+        statusCode: 401,
+      },
+      status: 401,
+      statusText: 'Unauthorized',
+    });
+    postMock.mockResolvedValueOnce({ message: 'Logged in' });
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await userEvent.type(await screen.findByLabelText(/Authentication code/), '123456');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    await waitFor(() =>
+      expect(postMock).toHaveBeenLastCalledWith(
+        '/login',
+        { password: 'test', user: 'admin', totpCode: '123456' },
+        { showErrorAlert: false }
+      )
+    );
+    await waitFor(() => expect(window.location.assign).toHaveBeenCalledWith('/'));
+  });
+
+  it('shows an error with an invalid two-factor authentication code', async () => {
+    postMock.mockRejectedValueOnce({
+      data: { messageId: 'totp.codeRequired', statusCode: 401 },
+      status: 401,
+      statusText: 'Unauthorized',
+    });
+    postMock.mockRejectedValueOnce({
+      data: {
+        message: 'Invalid two-factor authentication code',
+        messageId: 'totp.invalidCode',
+        statusCode: 401,
+      },
+      status: 401,
+      statusText: 'Unauthorized',
+    });
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await userEvent.type(await screen.findByLabelText(/Authentication code/), '000000');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    const alert = await screen.findByRole('alert', { name: 'Login failed' });
+    expect(alert).toHaveTextContent('Invalid two-factor authentication code');
+    expect(screen.getByRole('button', { name: 'Verify' })).toBeInTheDocument();
   });
 
   it('renders social logins correctly', () => {
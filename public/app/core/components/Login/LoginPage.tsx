@@ -15,6 +15,7 @@ import LoginCtrl from './LoginCtrl';
 import { LoginForm } from './LoginForm';
 import { LoginLayout, InnerBox } from './LoginLayout';
 import { LoginServiceButtons } from './LoginServiceButtons';
+import { TOTPForm } from './TOTPForm';
 import { UserSignup } from './UserSignup';
 
 export const LoginPage = () => {
@@ -30,6 +31,9 @@ export const LoginPage = () => {
         disableUserSignUp,
         login,
         isLoggingIn,
+        isTOTPRequired,
+        loginWithTOTPCode,
+        cancelTOTP,
         changePassword,
         skipPasswordChange,
         isChangingPassword,
@@ -45,7 +49,11 @@ export const LoginPage = () => {
                 </Alert>
               )}
 
-              {!disableLoginForm && (
+              {!disableLoginForm && isTOTPRequired && (
+                <TOTPForm onSubmit={loginWithTOTPCode} onCancel={cancelTOTP} isLoggingIn={isLoggingIn} />
+              )}
+
+              {!disableLoginForm && !isTOTPRequired && (
                 <LoginForm onSubmit={login} loginHint={loginHint} passwordHint={passwordHint} isLoggingIn={isLoggingIn}>
                   <HorizontalGroup justify="flex-end">
                     <LinkButton
@@ -58,8 +66,8 @@ export const LoginPage = () => {
                   </HorizontalGroup>
                 </LoginForm>
               )}
-              <LoginServiceButtons />
-              {!disableUserSignUp && <UserSignup />}
+              {!isTOTPRequired && <LoginServiceButtons />}
+              {!disableUserSignUp && !isTOTPRequired && <UserSignup />}
             </InnerBox>
           )}
 
@@ -0,0 +1,59 @@
+import React, { useId } from 'react';
+
+import { Button, Field, Form, HorizontalGroup, Input, useStyles2 } from '@grafana/ui';
+import { t } from 'app/core/internationalization';
+
+import { getStyles } from './LoginForm';
+
+interface TOTPFormModel {
+  code: string;
+}
+
+interface Props {
+  onSubmit: (code: string) => void;
+  onCancel: () => void;
+  isLoggingIn: boolean;
+}
+
+export const TOTPForm = ({ onSubmit, onCancel, isLoggingIn }: Props) => {
+  const styles = useStyles2(getStyles);
+  const codeId = useId();
+
+  return (
+    <div className={styles.wrapper}>
+      <Form<TOTPFormModel> onSubmit={({ code }) => onSubmit(code.trim())}>
+        {({ register, errors }) => (
+          <>
+            <Field
+              label={t('login.totp.code-label', 'Authentication code')}
+              description={t(
+                'login.totp.code-description',
+                'Enter the code from your authenticator app, or one of your recovery codes.'
+              )}
+              invalid={!!errors.code}
+              error={errors.code?.message}
+            >
+              <Input
+                {...register('code', {
+                  required: t('login.totp.code-required', 'Authentication code is required'),
+                })}
+                id={codeId}
+                autoFocus
+                autoComplete="one-time-code"
+                autoCapitalize="none"
+              />
+            </Field>
+            <Button type="submit" className={styles.submitButton} disabled={isLoggingIn}>
+              {isLoggingIn ? t('login.totp.verifying', 'Verifying...') : t('login.totp.verify', 'Verify')}
+            </Button>
+            <HorizontalGroup justify="flex-end">
+              <Button type="button" fill="text" onClick={onCancel}>
+                {t('login.totp.back', 'Back to login')}
+              </Button>
+            </HorizontalGroup>
+          </>
+        )}
+      </Form>
+    </div>
+  );
+};
@@ -0,0 +1,82 @@
+import { render, screen, waitFor } from '@testing-library/react';
+import userEvent from '@testing-library/user-event';
+import React from 'react';
+import { TestProvider } from 'test/helpers/TestProvider';
+
+import { UserTOTPPage } from './UserTOTPPage';
+import { api } from './api';
+
+jest.mock('./api', () => ({
+  api: {
+    loadTOTPStatus: jest.fn(),
+    enrolTOTP: jest.fn(),
+    confirmTOTP: jest.fn(),
+    regenerateTOTPRecoveryCodes: jest.fn(),
+    disableTOTP: jest.fn(),
+  },
+}));
+
+const mockedApi = jest.mocked(api);
+
+function renderPage() {
+  return render(
+    <TestProvider>
+      <UserTOTPPage />
+    </TestProvider>
+  );
+}
+
+describe('UserTOTPPage', () => {
+  beforeEach(() => {
+    jest.resetAllMocks();
+  });
+
+  it('should enrol and enable two-factor authentication', async () => {
+    mockedApi.loadTOTPStatus.mockResolvedValueOnce({ enabled: false, required: true, recoveryCodesRemaining: 0 });
+    mockedApi.enrolTOTP.mockResolvedValueOnce({
+      secret: 'JBSWY3DPEHPK3PXP',
+      provisioningUri: 'otpauth://totp/Grafana:admin?secret=JBSWY3DPEHPK3PXP&issuer=Grafana',
+    });
+    mockedApi.confirmTOTP.mockResolvedValueOnce({ recoveryCodes: ['aaaa-bbbb', 'cccc-dddd'] });
+    mockedApi.loadTOTPStatus.mockResolvedValueOnce({ enabled: true, required: true, recoveryCodesRemaining: 2 });
+    renderPage();
+
+    expect(await screen.findByText('Two-factor authentication required')).toBeInTheDocument();
+    await userEvent.click(screen.getByRole('button', { name: 'Set up two-factor authentication' }));
+
+    expect(await screen.findByTestId('totp-secret')).toHaveTextContent('JBSWY3DPEHPK3PXP');
+    expect(screen.getByRole('link', { name: /Open in authenticator app/ })).toHaveAttribute(
+      'href',
+      'otpauth://totp/Grafana:admin?secret=JBSWY3DPEHPK3PXP&issuer=Grafana'
+    );
+
+    await userEvent.type(screen.getByLabelText(/Authentication code/), '123456');
+    await userEvent.click(screen.getByRole('button', { name: 'Enable two-factor authentication' }));
+
+    await waitFor(() => expect(mockedApi.confirmTOTP).toHaveBeenCalledWith('123456'));
+    expect(await screen.findByTestId('totp-recovery-codes')).toHaveTextContent('aaaa-bbbb');
+  });
+
+  it('should not allow to disable required two-factor authentication', async () => {
+    mockedApi.loadTOTPStatus.mockResolvedValueOnce({ enabled: true, required: true, recoveryCodesRemaining: 10 });
+    renderPage();
+
+    expect(await screen.findByRole('button', { name: 'Generate new recovery codes' })).toBeInTheDocument();
+    expect(screen.queryByRole('button', { name: 'Disable two-factor authentication' })).not.toBeInTheDocument();
+  });
+
+  it('should show the error of an invalid code', async () => {
+    mockedApi.loadTOTPStatus.mockResolvedValueOnce({ enabled: true, required: false, recoveryCodesRemaining: 10 });
+    mockedApi.disableTOTP.mockRejectedValueOnce({
+      data: { message: 'Invalid two-factor authentication code', messageId: 'totp.invalidCode' },
+      status: 401,
+    });
+    renderPage();
+
+    await userEvent.type(await screen.findByLabelText(/Authentication code/), '000000');
+    await userEvent.click(screen.getByRole('button', { name: 'Disable two-factor authentication' }));
+
+    expect(await screen.findByText('Invalid two-factor authentication code')).toBeInTheDocument();
+    expect(mockedApi.loadTOTPStatus).toHaveBeenCalledTimes(1);
+  });
+});
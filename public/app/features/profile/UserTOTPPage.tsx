@@ -0,0 +1,208 @@
+import { css } from '@emotion/css';
+import React, { useState } from 'react';
+import { useAsyncFn, useMount } from 'react-use';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { isFetchError } from '@grafana/runtime';
+import { Alert, Button, ClipboardButton, Field, Input, LinkButton, Stack, Text, useStyles2 } from '@grafana/ui';
+import { Page } from 'app/core/components/Page/Page';
+import config from 'app/core/config';
+import { t } from 'app/core/internationalization';
+
+import { api } from './api';
+import { TOTPEnrolment } from './types';
+
+export function UserTOTPPage() {
+  const styles = useStyles2(getStyles);
+  const [enrolment, setEnrolment] = useState<TOTPEnrolment>();
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>();
+  const [code, setCode] = useState('');
+  const [error, setError] = useState<string>();
+
+  const [{ value: status, loading, error: statusError }, loadStatus] = useAsyncFn(() => api.loadTOTPStatus());
+  useMount(() => loadStatus());
+
+  // run calls an action that needs a code, and shows its error
+  const run = async (action: () => Promise<void>) => {
+    setError(undefined);
+    try {
+      await action();
+      setCode('');
+    } catch (err) {
+      setError(
+        (isFetchError(err) && err.data?.message) ||
+          t('profile.totp.error', 'Failed to update two-factor authentication')
+      );
+    }
+  };
+
+  const onEnrol = () =>
+    run(async () => {
+      setEnrolment(await api.enrolTOTP());
+    });
+
+  const onConfirm = () =>
+    run(async () => {
+      const result = await api.confirmTOTP(code.trim());
+      setEnrolment(undefined);
+      setRecoveryCodes(result.recoveryCodes);
+      await loadStatus();
+    });
+
+  const onRegenerateRecoveryCodes = () =>
+    run(async () => {
+      const result = await api.regenerateTOTPRecoveryCodes(code.trim());
+      setRecoveryCodes(result.recoveryCodes);
+      await loadStatus();
+    });
+
+  const onDisable = () =>
+    run(async () => {
+      await api.disableTOTP(code.trim());
+      await loadStatus();
+    });
+
+  const codeField = (
+    <Field
+      label={t('profile.totp.code-label', 'Authentication code')}
+      description={t('profile.totp.code-description', 'Enter the code from your authenticator app.')}
+    >
+      <Input
+        id="totp-code"
+        value={code}
+        autoComplete="one-time-code"
+        autoCapitalize="none"
+        width={30}
+        onChange={(e) => setCode(e.currentTarget.value)}
+      />
+    </Field>
+  );
+
+  const renderContents = () => {
+    if (statusError || !status) {
+      return (
+        <Alert severity="error" title={t('profile.totp.unavailable', 'Two-factor authentication is not available')} />
+      );
+    }
+
+    if (recoveryCodes) {
+      return (
+        <Stack direction="column" alignItems="flex-start">
+          <Text element="p">
+            {t(
+              'profile.totp.recovery-codes-description',
+              'Store these recovery codes in a safe place. Each code can be used once to log in if you lose access to your authenticator app. They will not be shown again.'
+            )}
+          </Text>
+          <pre className={styles.codes} data-testid="totp-recovery-codes">
+            {recoveryCodes.join('\n')}
+          </pre>
+          <Stack>
+            <ClipboardButton variant="secondary" icon="copy" getText={() => recoveryCodes.join('\n')}>
+              {t('profile.totp.copy-recovery-codes', 'Copy recovery codes')}
+            </ClipboardButton>
+            <LinkButton href={config.appSubUrl + '/'}>{t('profile.totp.continue', 'Continue')}</LinkButton>
+          </Stack>
+        </Stack>
+      );
+    }
+
+    if (status.enabled) {
+      return (
+        <Stack direction="column" alignItems="flex-start">
+          <Text element="p">
+            {t('profile.totp.enabled', 'Two-factor authentication is enabled. Recovery codes remaining: {{count}}', {
+              count: status.recoveryCodesRemaining,
+            })}
+          </Text>
+          {codeField}
+          <Stack>
+            <Button variant="secondary" disabled={!code} onClick={onRegenerateRecoveryCodes}>
+              {t('profile.totp.regenerate-recovery-codes', 'Generate new recovery codes')}
+            </Button>
+            {!status.required && (
+              <Button variant="destructive" disabled={!code} onClick={onDisable}>
+                {t('profile.totp.disable', 'Disable two-factor authentication')}
+              </Button>
+            )}
+          </Stack>
+        </Stack>
+      );
+    }
+
+    if (enrolment) {
+      return (
+        <Stack direction="column" alignItems="flex-start">
+          <Text element="p">
+            {t(
+              'profile.totp.enrolment-description',
+              'Add this account to your authenticator app with the secret below, then enter the code it shows to finish the setup.'
+            )}
+          </Text>
+          <Stack alignItems="center">
+            <code className={styles.secret} data-testid="totp-secret">
+              {enrolment.secret}
+            </code>
+            <ClipboardButton variant="secondary" size="sm" icon="copy" getText={() => enrolment.secret}>
+              {t('profile.totp.copy-secret', 'Copy')}
+            </ClipboardButton>
+          </Stack>
+          <LinkButton href={enrolment.provisioningUri} variant="secondary" fill="text" icon="external-link-alt">
+            {t('profile.totp.open-authenticator', 'Open in authenticator app')}
+          </LinkButton>
+          {codeField}
+          <Button disabled={!code} onClick={onConfirm}>
+            {t('profile.totp.confirm', 'Enable two-factor authentication')}
+          </Button>
+        </Stack>
+      );
+    }
+
+    return (
+      <Stack direction="column" alignItems="flex-start">
+        {status.required && (
+          <Alert severity="warning" title={t('profile.totp.required-title', 'Two-factor authentication required')}>
+            {t(
+              'profile.totp.required',
+              'You must enable two-factor authentication before you can continue to use Grafana.'
+            )}
+          </Alert>
+        )}
+        <Text element="p">
+          {t(
+            'profile.totp.description',
+            'Two-factor authentication asks for a code from an authenticator app when you log in with your password.'
+          )}
+        </Text>
+        <Button onClick={onEnrol}>{t('profile.totp.set-up', 'Set up two-factor authentication')}</Button>
+      </Stack>
+    );
+  };
+
+  return (
+    <Page navId="profile/totp">
+      <Page.Contents isLoading={loading && !status}>
+        {error && (
+          <Alert severity="error" title={t('profile.totp.error-title', 'Two-factor authentication')}>
+            {error}
+          </Alert>
+        )}
+        {!(loading && !status) && renderContents()}
+      </Page.Contents>
+    </Page>
+  );
+}
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  codes: css({
+    fontFamily: theme.typography.fontFamilyMonospace,
+    padding: theme.spacing(2),
+  }),
+  secret: css({
+    fontFamily: theme.typography.fontFamilyMonospace,
+    fontSize: theme.typography.h5.fontSize,
+    letterSpacing: '0.1em',
+  }),
+});
+
+export default UserTOTPPage;
@@ -2,7 +2,7 @@ import { getBackendSrv } from '@grafana/runtime';
 
 import { Team, UserDTO, UserOrg, UserSession } from '../../types';
 
-import { ChangePasswordFields, ProfileUpdateFields } from './types';
+import { ChangePasswordFields, ProfileUpdateFields, TOTPEnrolment, TOTPRecoveryCodes, TOTPStatus } from './types';
 
 async function changePassword(payload: ChangePasswordFields): Promise<void> {
   try {
@@ -46,6 +46,26 @@ async function updateUserProfile(payload: ProfileUpdateFields): Promise<void> {
   }
 }
 
+function loadTOTPStatus(): Promise<TOTPStatus> {
+  return getBackendSrv().get('/api/user/totp', undefined, undefined, { showErrorAlert: false });
+}
+
+function enrolTOTP(): Promise<TOTPEnrolment> {
+  return getBackendSrv().post('/api/user/totp/enrol');
+}
+
+function confirmTOTP(code: string): Promise<TOTPRecoveryCodes> {
+  return getBackendSrv().post('/api/user/totp/confirm', { code }, { showErrorAlert: false });
+}
+
+function regenerateTOTPRecoveryCodes(code: string): Promise<TOTPRecoveryCodes> {
+  return getBackendSrv().post('/api/user/totp/recovery-codes', { code }, { showErrorAlert: false });
+}
+
+async function disableTOTP(code: string): Promise<void> {
+  await getBackendSrv().post('/api/user/totp/disable', { code }, { showErrorAlert: false });
+}
+
 export const api = {
   changePassword,
   revokeUserSession,
@@ -55,4 +75,9 @@ export const api = {
   loadTeams,
   setUserOrg,
   updateUserProfile,
+  loadTOTPStatus,
+  enrolTOTP,
+  confirmTOTP,
+  regenerateTOTPRecoveryCodes,
+  disableTOTP,
 };
@@ -17,6 +17,12 @@ const profileRoutes: RouteDescriptor[] = [
       () => import(/* webPackChunkName: "ChangePasswordPage" */ 'app/features/profile/ChangePasswordPage')
     ),
   },
+  {
+    path: '/profile/totp',
+    component: SafeDynamicImport(
+      () => import(/* webpackChunkName: "UserTOTPPage" */ 'app/features/profile/UserTOTPPage')
+    ),
+  },
   {
     path: '/profile/select-org',
     component: SafeDynamicImport(
@@ -36,11 +42,16 @@ export function getProfileRoutes(cfg = config): RouteDescriptor[] {
     return profileRoutes;
   }
 
-  const uniquePaths = uniq(profileRoutes.map((route) => route.path));
-  return uniquePaths.map((path) => ({
-    path,
-    component: SafeDynamicImport(
-      () => import(/* webpackChunkName: "ProfileFeatureTogglePage"*/ 'app/features/profile/FeatureTogglePage')
-    ),
-  }));
+  // two-factor authentication can be required, so it must stay available when the profile is disabled
+  const totpRoutes = profileRoutes.filter((route) => route.path === '/profile/totp');
+  const uniquePaths = uniq(profileRoutes.map((route) => route.path)).filter((path) => path !== '/profile/totp');
+  return [
+    ...totpRoutes,
+    ...uniquePaths.map((path) => ({
+      path,
+      component: SafeDynamicImport(
+        () => import(/* webpackChunkName: "ProfileFeatureTogglePage"*/ 'app/features/profile/FeatureTogglePage')
+      ),
+    })),
+  ];
 }
@@ -9,3 +9,18 @@ export interface ProfileUpdateFields {
   email: string;
   login: string;
 }
+
+export interface TOTPStatus {
+  enabled: boolean;
+  required: boolean;
+  recoveryCodesRemaining: number;
+}
+
+export interface TOTPEnrolment {
+  secret: string;
+  provisioningUri: string;
+}
+
+export interface TOTPRecoveryCodes {
+  recoveryCodes: string[];
+}